@@ -0,0 +1,347 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned when a JWT is malformed, has an invalid signature or is expired
+var ErrInvalidToken = errors.New("invalid token")
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+
+	// clockSkew tolerates small clock differences between the token issuer and this service
+	clockSkew = time.Minute
+)
+
+// Claims holds the payload of a JWT
+type Claims map[string]interface{}
+
+// jsonWebKey is a single key of a JWKS document (RFC 7517); only oct (HS256) and RSA (RS256) keys are supported
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"` // Symmetric key (oct)
+	N   string `json:"n"` // RSA modulus
+	E   string `json:"e"` // RSA exponent
+}
+
+// verificationKey is a parsed JWKS key ready to verify signatures
+type verificationKey struct {
+	kid       string
+	alg       string
+	secret    []byte
+	publicKey *rsa.PublicKey
+}
+
+// KeySet is a set of keys used to verify JWT signatures
+type KeySet struct {
+	keys []verificationKey
+}
+
+// LoadJWKSFile reads and parses a JWKS document from a file
+func LoadJWKSFile(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file %s: %w", path, err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JWKS document containing oct (HS256) and RSA (RS256) keys
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keySet := &KeySet{}
+	for i, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key := verificationKey{kid: jwk.Kid, alg: jwk.Alg}
+		switch jwk.Kty {
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.K, "="))
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("invalid oct key %d in JWKS", i)
+			}
+			key.secret = secret
+			if key.alg == "" {
+				key.alg = AlgHS256
+			}
+		case "RSA":
+			publicKey, err := parseRSAPublicKey(jwk.N, jwk.E)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RSA key %d in JWKS: %w", i, err)
+			}
+			key.publicKey = publicKey
+			if key.alg == "" {
+				key.alg = AlgRS256
+			}
+		default:
+			continue
+		}
+
+		if key.alg != AlgHS256 && key.alg != AlgRS256 {
+			continue
+		}
+		keySet.keys = append(keySet.keys, key)
+	}
+
+	if len(keySet.keys) == 0 {
+		return nil, errors.New("JWKS contains no supported signing keys")
+	}
+	return keySet, nil
+}
+
+func parseRSAPublicKey(encodedModulus, encodedExponent string) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(encodedModulus)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(encodedExponent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	e := new(big.Int).SetBytes(exponent)
+	if !e.IsInt64() || e.Int64() < 3 {
+		return nil, errors.New("invalid exponent value")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}, nil
+}
+
+// findKey selects the key for a token header: by kid when present, otherwise the only key with the algorithm
+func (ks *KeySet) findKey(kid, alg string) (*verificationKey, error) {
+	var found *verificationKey
+	for i := range ks.keys {
+		key := &ks.keys[i]
+		if key.alg != alg {
+			continue
+		}
+		if kid != "" {
+			if key.kid == kid {
+				return key, nil
+			}
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: token has no kid and several %s keys are configured", ErrInvalidToken, alg)
+		}
+		found = key
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: no %s key found for kid '%s'", ErrInvalidToken, alg, kid)
+	}
+	return found, nil
+}
+
+// HMACSecret returns the secret of the HS256 key with the given kid (or the only HS256 key when kid is empty)
+func (ks *KeySet) HMACSecret(kid string) ([]byte, string, error) {
+	key, err := ks.findKey(kid, AlgHS256)
+	if err != nil {
+		return nil, "", err
+	}
+	return key.secret, key.kid, nil
+}
+
+// Verifier validates JWT signatures against a key set and checks the standard time, issuer and audience claims
+type Verifier struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewVerifier creates a verifier; empty issuer or audience disables the respective check
+func NewVerifier(keys *KeySet, issuer, audience string) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}
+}
+
+// Verify checks the token signature and claims and returns the claims of a valid token
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	if header.Alg != AlgHS256 && header.Alg != AlgRS256 {
+		return nil, fmt.Errorf("%w: unsupported algorithm '%s'", ErrInvalidToken, header.Alg)
+	}
+
+	key, err := v.keys.findKey(header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	signingInput := parts[0] + "." + parts[1]
+	if !key.verify(signingInput, signature) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (key *verificationKey) verify(signingInput string, signature []byte) bool {
+	switch key.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write([]byte(signingInput))
+		return hmac.Equal(signature, mac.Sum(nil))
+	case AlgRS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		return rsa.VerifyPKCS1v15(key.publicKey, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+func (v *Verifier) validateClaims(claims Claims) error {
+	now := v.now()
+
+	exp, ok := claims.numericDate("exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp claim", ErrInvalidToken)
+	}
+	if now.After(exp.Add(clockSkew)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if nbf, ok := claims.numericDate("nbf"); ok && now.Add(clockSkew).Before(nbf) {
+		return fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+
+	if v.issuer != "" && claims.String("iss") != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.audience != "" && !claims.hasAudience(v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+// String returns a string claim or an empty string
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Strings returns a claim holding a list of strings. JSON arrays, comma or space separated strings
+// and the "[a b]" form API Gateway uses to flatten array claims are supported.
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	case []string:
+		return value
+	case string:
+		value = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "["), "]")
+		return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return nil
+}
+
+func (c Claims) numericDate(name string) (time.Time, bool) {
+	switch value := c[name].(type) {
+	case float64:
+		return time.Unix(int64(value), 0), true
+	case json.Number:
+		seconds, err := value.Int64()
+		return time.Unix(seconds, 0), err == nil
+	}
+	return time.Time{}, false
+}
+
+func (c Claims) hasAudience(audience string) bool {
+	// Cognito access tokens carry the app client in client_id instead of aud
+	for _, aud := range append(c.Strings("aud"), c.String("client_id")) {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// SignHS256 creates an HS256 signed token, used to issue tokens for local development
+func SignHS256(claims Claims, kid string, secret []byte) (string, error) {
+	header := map[string]string{"alg": AlgHS256, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+
+	encodedHeader, err := encodeSegment(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodedHeader + "." + encodedClaims
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func encodeSegment(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token segment: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
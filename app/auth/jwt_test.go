@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test-hmac-secret")
+
+// testKeySet builds a key set with an HS256 key "hs" and an RS256 key "rs" and returns the RSA private key
+func testKeySet(t *testing.T) (*KeySet, *rsa.PrivateKey) {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	keySet, err := ParseJWKS([]byte(fmt.Sprintf(`{"keys":[
+		{"kid":"hs","kty":"oct","k":"%s"},
+		{"kid":"rs","kty":"RSA","n":"%s","e":"%s"}
+	]}`,
+		base64.RawURLEncoding.EncodeToString(testSecret),
+		base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+	)))
+	if err != nil {
+		t.Fatalf("Failed to parse JWKS: %v", err)
+	}
+	return keySet, privateKey
+}
+
+// signRS256 creates an RS256 signed token with the given kid
+func signRS256(t *testing.T, claims Claims, kid string, privateKey *rsa.PrivateKey) string {
+	t.Helper()
+	header, err := encodeSegment(map[string]string{"alg": AlgRS256, "typ": "JWT", "kid": kid})
+	if err != nil {
+		t.Fatalf("Failed to encode header: %v", err)
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		t.Fatalf("Failed to encode claims: %v", err)
+	}
+	digest := sha256.Sum256([]byte(header + "." + payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifierVerify(t *testing.T) {
+	keySet, privateKey := testKeySet(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	claims := func(overrides Claims) Claims {
+		c := Claims{
+			"sub": "user",
+			"iss": "https://issuer.example.com",
+			"aud": "client",
+			"exp": float64(now.Add(time.Hour).Unix()),
+		}
+		for name, value := range overrides {
+			if value == nil {
+				delete(c, name)
+				continue
+			}
+			c[name] = value
+		}
+		return c
+	}
+	signHS256 := func(c Claims, kid string, secret []byte) string {
+		token, err := SignHS256(c, kid, secret)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return token
+	}
+	// swapSignature replaces the signature of the token with the valid signature of another token
+	swapSignature := func(token, other string) string {
+		return token[:strings.LastIndex(token, ".")] + other[strings.LastIndex(other, "."):]
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid HS256", signHS256(claims(nil), "hs", testSecret), false},
+		{"valid HS256 without kid", signHS256(claims(nil), "", testSecret), false},
+		{"valid RS256", signRS256(t, claims(nil), "rs", privateKey), false},
+		{"client_id instead of aud", signHS256(claims(Claims{"aud": nil, "client_id": "client"}), "hs", testSecret), false},
+		{"malformed token", "not-a-token", true},
+		{"bad HS256 signature", signHS256(claims(nil), "hs", []byte("another-secret")), true},
+		{"bad RS256 signature", swapSignature(signRS256(t, claims(nil), "rs", privateKey), signRS256(t, claims(Claims{"sub": "other"}), "rs", privateKey)), true},
+		{"kid of a key with another alg", signHS256(claims(nil), "rs", testSecret), true},
+		{"unknown kid", signHS256(claims(nil), "unknown", testSecret), true},
+		{"missing exp", signHS256(claims(Claims{"exp": nil}), "hs", testSecret), true},
+		{"expired within skew", signHS256(claims(Claims{"exp": float64(now.Add(-30 * time.Second).Unix())}), "hs", testSecret), false},
+		{"expired beyond skew", signHS256(claims(Claims{"exp": float64(now.Add(-2 * time.Minute).Unix())}), "hs", testSecret), true},
+		{"nbf within skew", signHS256(claims(Claims{"nbf": float64(now.Add(30 * time.Second).Unix())}), "hs", testSecret), false},
+		{"nbf beyond skew", signHS256(claims(Claims{"nbf": float64(now.Add(2 * time.Minute).Unix())}), "hs", testSecret), true},
+		{"wrong issuer", signHS256(claims(Claims{"iss": "https://other.example.com"}), "hs", testSecret), true},
+		{"wrong audience", signHS256(claims(Claims{"aud": "other"}), "hs", testSecret), true},
+	}
+
+	verifier := NewVerifier(keySet, "https://issuer.example.com", "client")
+	verifier.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Verify(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got.String("sub") != "user" {
+				t.Errorf("Expected sub 'user', got '%s'", got.String("sub"))
+			}
+		})
+	}
+}
+
+func TestVerifierVerifyWithoutIssuerAndAudience(t *testing.T) {
+	keySet, _ := testKeySet(t)
+	token, err := SignHS256(Claims{"sub": "user", "iss": "any", "aud": "any", "exp": float64(time.Now().Add(time.Hour).Unix())}, "hs", testSecret)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	if _, err := NewVerifier(keySet, "", "").Verify(token); err != nil {
+		t.Errorf("Expected empty issuer and audience to disable the checks, got %v", err)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	secret := base64.RawURLEncoding.EncodeToString(testSecret)
+
+	tests := []struct {
+		name     string
+		jwks     string
+		wantKeys int
+		wantErr  bool
+	}{
+		{"oct key", `{"keys":[{"kid":"a","kty":"oct","k":"` + secret + `"}]}`, 1, false},
+		{"padded oct key", `{"keys":[{"kid":"a","kty":"oct","k":"` + base64.URLEncoding.EncodeToString(testSecret) + `"}]}`, 1, false},
+		{"RSA key", `{"keys":[{"kid":"a","kty":"RSA","n":"AQAB","e":"AQAB"}]}`, 1, false},
+		{"encryption keys are skipped", `{"keys":[{"kid":"a","kty":"oct","k":"` + secret + `","use":"enc"},{"kid":"b","kty":"oct","k":"` + secret + `"}]}`, 1, false},
+		{"unsupported key types are skipped", `{"keys":[{"kid":"a","kty":"EC"},{"kid":"b","kty":"oct","k":"` + secret + `"}]}`, 1, false},
+		{"unsupported algorithms are skipped", `{"keys":[{"kid":"a","kty":"oct","alg":"HS512","k":"` + secret + `"},{"kid":"b","kty":"oct","k":"` + secret + `"}]}`, 1, false},
+		{"invalid JSON", `{"keys":`, 0, true},
+		{"no supported keys", `{"keys":[{"kid":"a","kty":"EC"}]}`, 0, true},
+		{"empty oct key", `{"keys":[{"kid":"a","kty":"oct","k":""}]}`, 0, true},
+		{"invalid RSA exponent", `{"keys":[{"kid":"a","kty":"RSA","n":"AQAB","e":"AQ"}]}`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keySet, err := ParseJWKS([]byte(tt.jwks))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(keySet.keys) != tt.wantKeys {
+				t.Errorf("Expected %d keys, got %d", tt.wantKeys, len(keySet.keys))
+			}
+		})
+	}
+}
+
+func TestClaimsStrings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{"API Gateway flattened array", "[a b]", []string{"a", "b"}},
+		{"comma separated", "a,b", []string{"a", "b"}},
+		{"comma and space separated", "a, b", []string{"a", "b"}},
+		{"single value", "a", []string{"a"}},
+		{"JSON array", []interface{}{"a", "", 1, "b"}, []string{"a", "b"}},
+		{"string slice", []string{"a", "b"}, []string{"a", "b"}},
+		{"empty string", "", []string{}},
+		{"missing claim", nil, nil},
+		{"unsupported type", 42.0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := Claims{}
+			if tt.value != nil {
+				claims["groups"] = tt.value
+			}
+			got := claims.Strings("groups")
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
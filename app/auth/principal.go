@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnauthenticated is returned when a request carries no valid credentials
+var ErrUnauthenticated = errors.New("authentication required")
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID   uuid.UUID
+	GroupIDs []uuid.UUID
+	Admin    bool // Member of the admin group, may run admin jobs and access any data
+	System   bool // Trusted internal caller (CLI jobs, local mode without auth), bypasses ownership checks
+}
+
+// SystemPrincipal returns the principal used for trusted internal callers
+func SystemPrincipal() *Principal {
+	return &Principal{System: true}
+}
+
+// Unrestricted reports whether the principal bypasses ownership checks
+func (p *Principal) Unrestricted() bool {
+	return p.System || p.Admin
+}
+
+// HasGroup reports whether the principal is a member of the given group
+func (p *Principal) HasGroup(groupID uuid.UUID) bool {
+	for _, id := range p.GroupIDs {
+		if id == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccess reports whether the principal may access data owned by the given user within the given group.
+// Data is accessible to its owner and to members of the group it belongs to.
+func (p *Principal) CanAccess(userID, groupID uuid.UUID) bool {
+	if p.Unrestricted() {
+		return true
+	}
+	return p.UserID == userID || p.HasGroup(groupID)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the context carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in the context, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// RequirePrincipal returns the principal stored in the context or ErrUnauthenticated
+func RequirePrincipal(ctx context.Context) (*Principal, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return principal, nil
+}
+
+// Claims carrying the caller identity. Group memberships come from a custom claim (custom:groupIds in Cognito),
+// the admin role from membership in a Cognito user pool group.
+const (
+	ClaimSubject        = "sub"
+	ClaimGroupIDs       = "groupIds"
+	ClaimCustomGroupIDs = "custom:groupIds"
+	ClaimCognitoGroups  = "cognito:groups"
+)
+
+// PrincipalFromClaims builds the principal from verified token claims
+func PrincipalFromClaims(claims Claims, adminGroup string) (*Principal, error) {
+	userID, err := uuid.Parse(claims.String(ClaimSubject))
+	if err != nil {
+		return nil, fmt.Errorf("%w: sub claim is not a valid user ID", ErrInvalidToken)
+	}
+
+	principal := &Principal{UserID: userID}
+	for _, claim := range []string{ClaimGroupIDs, ClaimCustomGroupIDs} {
+		for _, value := range claims.Strings(claim) {
+			groupID, err := uuid.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s claim contains an invalid group ID", ErrInvalidToken, claim)
+			}
+			if !principal.HasGroup(groupID) {
+				principal.GroupIDs = append(principal.GroupIDs, groupID)
+			}
+		}
+	}
+
+	if adminGroup != "" {
+		for _, group := range claims.Strings(ClaimCognitoGroups) {
+			if group == adminGroup {
+				principal.Admin = true
+				break
+			}
+		}
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPrincipalFromClaims(t *testing.T) {
+	userID := uuid.New()
+	groupA := uuid.New()
+	groupB := uuid.New()
+
+	tests := []struct {
+		name       string
+		claims     Claims
+		wantGroups []uuid.UUID
+		wantAdmin  bool
+		wantErr    bool
+	}{
+		{
+			name:   "subject only",
+			claims: Claims{ClaimSubject: userID.String()},
+		},
+		{
+			name:       "groupIds JSON array",
+			claims:     Claims{ClaimSubject: userID.String(), ClaimGroupIDs: []interface{}{groupA.String(), groupB.String()}},
+			wantGroups: []uuid.UUID{groupA, groupB},
+		},
+		{
+			name:       "Cognito custom claim merged without duplicates",
+			claims:     Claims{ClaimSubject: userID.String(), ClaimGroupIDs: groupA.String(), ClaimCustomGroupIDs: groupA.String() + "," + groupB.String()},
+			wantGroups: []uuid.UUID{groupA, groupB},
+		},
+		{
+			name:      "admin group member",
+			claims:    Claims{ClaimSubject: userID.String(), ClaimCognitoGroups: "[users admin]"},
+			wantAdmin: true,
+		},
+		{
+			name:   "other Cognito groups",
+			claims: Claims{ClaimSubject: userID.String(), ClaimCognitoGroups: []interface{}{"users"}},
+		},
+		{
+			name:    "missing subject",
+			claims:  Claims{},
+			wantErr: true,
+		},
+		{
+			name:    "subject is not a UUID",
+			claims:  Claims{ClaimSubject: "user"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid group ID",
+			claims:  Claims{ClaimSubject: userID.String(), ClaimCustomGroupIDs: "not-a-uuid"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := PrincipalFromClaims(tt.claims, "admin")
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if principal.UserID != userID {
+				t.Errorf("Expected user ID %s, got %s", userID, principal.UserID)
+			}
+			if !reflect.DeepEqual(principal.GroupIDs, tt.wantGroups) {
+				t.Errorf("Expected groups %v, got %v", tt.wantGroups, principal.GroupIDs)
+			}
+			if principal.Admin != tt.wantAdmin {
+				t.Errorf("Expected admin %v, got %v", tt.wantAdmin, principal.Admin)
+			}
+			if principal.System {
+				t.Error("Expected principal from claims not to be a system principal")
+			}
+		})
+	}
+}
+
+func TestPrincipalFromClaimsWithoutAdminGroup(t *testing.T) {
+	principal, err := PrincipalFromClaims(Claims{ClaimSubject: uuid.NewString(), ClaimCognitoGroups: "admin"}, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if principal.Admin {
+		t.Error("Expected no admin role when the admin group is not configured")
+	}
+}
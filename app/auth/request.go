@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the context carrying the ID of the API request, recorded in audit events
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the ID of the API request stored in the context, empty if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -2,13 +2,18 @@ package aws
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/savak1990/transactions-service/app/config"
-	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/migrations"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -16,327 +21,296 @@ import (
 )
 
 var (
-	gormDB        *gorm.DB
-	gormDBMutex   sync.RWMutex
-	lastAttempt   time.Time
-	retryInterval = 30 * time.Second // Wait 30 seconds before retrying after failure
-	cfg           config.AppConfig
-	isConfigured  bool
+	// ErrDatabaseUnavailable is returned when the database cannot be reached, refuses connections or times out
+	ErrDatabaseUnavailable = errors.New("database unavailable")
+	// ErrDatabaseMaintenance is returned while the database is starting up, shutting down or recovering
+	ErrDatabaseMaintenance = errors.New("database under maintenance")
 )
 
-// DatabaseConnectionError represents a database connection failure
-type DatabaseConnectionError struct {
-	Message string
-	Cause   error
+// healthCheckTimeout bounds a single ping of the database
+const healthCheckTimeout = 2 * time.Second
+
+// ConnectionManager owns the connection pool of the database. The pool is opened on first use and reconnects on its
+// own, pending migrations are applied once. A background health check pings the database periodically, while the
+// last check failed DB returns its error right away instead of every request waiting for the connect timeout. A
+// failure older than the check interval, e.g. because checks are disabled or the Lambda was frozen between
+// invocations, is checked again by the next DB call.
+type ConnectionManager struct {
+	cfg config.AppConfig
+
+	mu        sync.RWMutex
+	db        *gorm.DB
+	migrated  bool
+	healthErr error     // Error of the last health check, nil while the database is healthy
+	healthAt  time.Time // When healthErr was recorded
+	stop      chan struct{}
 }
 
-func (e *DatabaseConnectionError) Error() string {
-	return fmt.Sprintf("database connection failed: %s", e.Message)
+// NewConnectionManager creates a connection manager, no connection is made until the database is first used
+func NewConnectionManager(cfg config.AppConfig) *ConnectionManager {
+	return &ConnectionManager{cfg: cfg}
 }
 
-func (e *DatabaseConnectionError) Unwrap() error {
-	return e.Cause
-}
-
-// SetConfig stores the database configuration without connecting
-func SetConfig(appCfg config.AppConfig) {
-	cfg = appCfg
-	isConfigured = true
-}
-
-func GetGormDB(appCfg config.AppConfig) *gorm.DB {
-	// Store config if not already set
-	if !isConfigured {
-		SetConfig(appCfg)
-	}
-
-	gormDBMutex.RLock()
-
-	// Check if we have a healthy connection
-	if gormDB != nil {
-		sqlDB, err := gormDB.DB()
-		if err == nil {
-			// Quick ping to verify connection is still alive
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-			defer cancel()
-			if err := sqlDB.PingContext(ctx); err == nil {
-				gormDBMutex.RUnlock()
-				return gormDB
-			}
-			log.WithError(err).Warn("Existing database connection is unhealthy, will attempt reconnection")
-		}
-	}
-
-	// Check if we should retry (rate limiting)
-	now := time.Now()
-	if !lastAttempt.IsZero() && now.Sub(lastAttempt) < retryInterval {
-		gormDBMutex.RUnlock()
-		log.WithFields(log.Fields{
-			"last_attempt": lastAttempt,
-			"retry_after":  retryInterval - now.Sub(lastAttempt),
-		}).Warn("Database connection failed recently, not retrying yet")
-		panic(&DatabaseConnectionError{
-			Message: fmt.Sprintf("Database connection failed recently, retry after %v", retryInterval-now.Sub(lastAttempt)),
-			Cause:   fmt.Errorf("rate limited"),
-		})
-	}
-
-	gormDBMutex.RUnlock()
-	gormDBMutex.Lock()
-	defer gormDBMutex.Unlock()
-
-	// Double-check pattern - another goroutine might have established connection
-	if gormDB != nil {
-		sqlDB, err := gormDB.DB()
-		if err == nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-			defer cancel()
-			if err := sqlDB.PingContext(ctx); err == nil {
-				return gormDB
-			}
+// DB returns the connection pool, applying pending migrations on first use. Connection failures are returned as
+// ErrDatabaseUnavailable or ErrDatabaseMaintenance, the same errors are returned by statements run on the pool.
+func (m *ConnectionManager) DB() (*gorm.DB, error) {
+	m.mu.RLock()
+	db, migrated, healthErr, healthAt := m.db, m.migrated, m.healthErr, m.healthAt
+	m.mu.RUnlock()
+	if healthErr != nil {
+		if db == nil || !m.healthErrExpired(healthAt) {
+			return nil, healthErr
 		}
-		// Close broken connection
-		if sqlDB, err := gormDB.DB(); err == nil {
-			sqlDB.Close()
+		if err := m.check(context.Background(), db); err != nil {
+			return nil, err
 		}
-		gormDB = nil
 	}
-
-	lastAttempt = now
-	log.Info("Attempting to establish new database connection...")
-
-	// Get SSL mode from environment variable, default to 'require' for security
-	sslMode := os.Getenv("SSL_MODE")
-	if sslMode == "" {
-		sslMode = "require"
+	if db != nil && (migrated || !m.cfg.DBMigrateOnConnect) {
+		return db, nil
 	}
 
-	// Enhanced DSN with configurable SSL mode
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC connect_timeout=2 statement_timeout=5000",
-		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, sslMode)
-
-	var err error
-	gormDB, err = retryConnect(dsn, 1) // Only 1 attempt for fast-fail
+	db, err := m.pool()
 	if err != nil {
-		// Connection failed
-		gormDB = nil
-		dbErr := &DatabaseConnectionError{
-			Message: fmt.Sprintf("Failed to connect to PostgreSQL database at %s:%d", cfg.DBHost, cfg.DBPort),
-			Cause:   err,
-		}
-		log.WithError(err).Error("Failed to connect to PostgreSQL database with GORM - will return error to caller")
-		panic(dbErr)
+		return nil, err
+	}
+	if !m.cfg.DBMigrateOnConnect {
+		return db, nil
 	}
 
-	log.WithFields(log.Fields{
-		"host":   cfg.DBHost,
-		"port":   cfg.DBPort,
-		"dbname": cfg.DBName,
-	}).Info("Successfully connected to PostgreSQL database with GORM")
-
-	// Auto-migrate the schema
-	if err := autoMigrate(gormDB); err != nil {
-		// Close the connection and reset
-		if sqlDB, sqlErr := gormDB.DB(); sqlErr == nil {
-			sqlDB.Close()
-		}
-		gormDB = nil
-
-		dbErr := &DatabaseConnectionError{
-			Message: "Failed to auto-migrate database schema",
-			Cause:   err,
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.migrated {
+		return db, nil
+	}
+	if err := migrate(db); err != nil {
+		err = classifyError(err)
+		if isConnectionError(err) {
+			// Fail fast until the next health check succeeds instead of retrying the migrations on every request
+			m.healthErr, m.healthAt = err, time.Now()
 		}
-		log.WithError(err).Error("Failed to auto-migrate database schema - will return error to caller")
-		panic(dbErr)
+		log.WithError(err).Error("Failed to migrate database schema")
+		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
 	}
+	m.migrated = true
+	return db, nil
+}
 
-	return gormDB
+// Ping checks the database right away and records the result like a background health check
+func (m *ConnectionManager) Ping(ctx context.Context) error {
+	db, err := m.pool()
+	if err != nil {
+		return err
+	}
+	return m.check(ctx, db)
 }
 
-// retryConnect attempts to establish database connection with fast-fail behavior
-func retryConnect(dsn string, maxRetries int) (*gorm.DB, error) {
-	var lastErr error
-
-	for i := 0; i < maxRetries; i++ {
-		// Create custom logger with configurable log level and higher slow query threshold
-		customLogger := logger.New(
-			log.StandardLogger(), // Use logrus as the underlying logger
-			logger.Config{
-				SlowThreshold:             1 * time.Second,                      // Only log queries slower than 1 second
-				LogLevel:                  config.GetGormLogLevel(cfg.LogLevel), // Use configured log level
-				IgnoreRecordNotFoundError: true,                                 // Don't log "record not found" errors
-				Colorful:                  false,                                // Disable colors for production
-			},
-		)
-
-		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-			Logger:                                   customLogger,
-			DisableForeignKeyConstraintWhenMigrating: true,
-		})
-
-		if err == nil {
-			// Test the connection
-			sqlDB, sqlErr := db.DB()
-			if sqlErr == nil {
-				pingErr := sqlDB.Ping()
-				if pingErr == nil {
-					// Configure connection pool for RDS PostgreSQL
-					sqlDB.SetMaxIdleConns(2)                  // Reduced for cost optimization
-					sqlDB.SetMaxOpenConns(10)                 // Reduced for cost optimization
-					sqlDB.SetConnMaxLifetime(5 * time.Minute) // Shorter for cost optimization
-					sqlDB.SetConnMaxIdleTime(1 * time.Minute) // Shorter for cost optimization
-					return db, nil
-				}
-				lastErr = pingErr
-			} else {
-				lastErr = sqlErr
-			}
-		} else {
-			lastErr = err
-		}
+// check pings the pool and records the result, unless the pool was closed in the meantime
+func (m *ConnectionManager) check(ctx context.Context, db *gorm.DB) error {
+	err := ping(ctx, db)
 
-		// If this isn't the last attempt, wait before retrying (reduced wait time)
-		if i < maxRetries-1 {
-			waitTime := 1 * time.Second // Fast retry for quick failure
-			log.WithFields(log.Fields{
-				"attempt":      i + 1,
-				"max_attempts": maxRetries,
-				"wait_time":    waitTime,
-				"error":        lastErr,
-			}).Warn("Database connection failed, retrying...")
-			time.Sleep(waitTime)
-		}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.db != db {
+		return err
 	}
+	if err != nil && m.healthErr == nil {
+		log.WithError(err).Warn("Database health check failed")
+	} else if err == nil && m.healthErr != nil {
+		log.Info("Database health check recovered")
+	}
+	m.healthErr, m.healthAt = err, time.Now()
+	return err
+}
 
-	return nil, fmt.Errorf("failed to connect after %d attempts, last error: %w", maxRetries, lastErr)
+// healthErrExpired reports whether a health check failure recorded at the given time is older than the health check
+// interval, so no background check has confirmed it since. Without background checks a failure is never trusted.
+func (m *ConnectionManager) healthErrExpired(at time.Time) bool {
+	return m.cfg.DBHealthCheckInterval <= 0 || time.Since(at) >= m.cfg.DBHealthCheckInterval
 }
 
-// autoMigrate runs GORM auto-migration for all models
-func autoMigrate(db *gorm.DB) error {
-	log.Info("Running GORM auto-migration...")
-
-	// Migrate all models (using DAO models for PostgreSQL)
-	err := db.AutoMigrate(
-		&models.Balance{},
-		&models.Merchant{},
-		&models.CategoryGroup{},
-		&models.Category{},
-		&models.Transaction{},
-		&models.TransactionEntry{},
-		&models.TransactionEntryAmount{},
-	)
+// Close closes the connection pool and stops the health checks, the next DB call opens a new pool
+func (m *ConnectionManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
+	if m.db == nil {
+		return nil
+	}
+	close(m.stop)
+	sqlDB, err := m.db.DB()
+	m.db, m.migrated, m.healthErr, m.healthAt, m.stop = nil, false, nil, time.Time{}, nil
 	if err != nil {
-		return fmt.Errorf("auto-migration failed: %w", err)
+		return err
 	}
-
-	log.Info("GORM auto-migration completed successfully")
-	return nil
+	return sqlDB.Close()
 }
 
-// GetPostgreSQLClient returns the GORM DB for consistency with existing code
-func GetPostgreSQLClient(cfg config.AppConfig) *gorm.DB {
-	return GetGormDB(cfg)
-}
+// pool returns the connection pool, opening it and starting the health checks if necessary
+func (m *ConnectionManager) pool() (*gorm.DB, error) {
+	m.mu.RLock()
+	db := m.db
+	m.mu.RUnlock()
+	if db != nil {
+		return db, nil
+	}
 
-// WithDatabaseTimeout wraps a context with a database-specific timeout
-func WithDatabaseTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
-	return context.WithTimeout(ctx, 8*time.Second) // 8 seconds max for DB operations
-}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.db != nil {
+		return m.db, nil
+	}
 
-// TestDatabaseConnection tests if the database is reachable without panicking
-func TestDatabaseConnection(appCfg config.AppConfig) error {
-	// Get SSL mode from environment variable, default to 'require' for security
-	sslMode := os.Getenv("SSL_MODE")
-	if sslMode == "" {
-		sslMode = "require"
+	db, err := openDB(m.cfg)
+	if err != nil {
+		log.WithError(err).Error("Failed to open PostgreSQL connection pool")
+		return nil, err
 	}
+	m.db = db
+	m.stop = make(chan struct{})
+	go m.checkHealth(db, m.stop)
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC connect_timeout=1 statement_timeout=2000",
-		appCfg.DBHost, appCfg.DBPort, appCfg.DBUser, appCfg.DBPassword, appCfg.DBName, sslMode)
+	log.WithFields(log.Fields{
+		"host":           m.cfg.DBHost,
+		"port":           m.cfg.DBPort,
+		"dbname":         m.cfg.DBName,
+		"max_open_conns": m.cfg.DBMaxOpenConns,
+		"max_idle_conns": m.cfg.DBMaxIdleConns,
+	}).Info("Opened PostgreSQL connection pool")
+	return db, nil
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent), // Silent mode for testing
-	})
-	if err != nil {
-		return &DatabaseConnectionError{
-			Message: fmt.Sprintf("Failed to test connection to database at %s:%d", appCfg.DBHost, appCfg.DBPort),
-			Cause:   err,
+// checkHealth pings the pool every health check interval until stop is closed, a zero interval disables the checks
+func (m *ConnectionManager) checkHealth(db *gorm.DB, stop <-chan struct{}) {
+	if m.cfg.DBHealthCheckInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(m.cfg.DBHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.check(context.Background(), db)
 		}
 	}
+}
 
-	// Test the connection with a simple ping
+// ping pings the database with the health check timeout
+func ping(ctx context.Context, db *gorm.DB) error {
 	sqlDB, err := db.DB()
 	if err != nil {
-		return &DatabaseConnectionError{
-			Message: "Failed to get underlying database connection for testing",
-			Cause:   err,
-		}
+		return err
 	}
 
-	if err := sqlDB.Ping(); err != nil {
-		return &DatabaseConnectionError{
-			Message: fmt.Sprintf("Database ping failed for %s:%d", appCfg.DBHost, appCfg.DBPort),
-			Cause:   err,
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		if err = classifyError(err); !isConnectionError(err) {
+			err = fmt.Errorf("%w: %w", ErrDatabaseUnavailable, err)
 		}
+		return err
 	}
-
-	// Close the test connection
-	sqlDB.Close()
 	return nil
 }
 
-// IsHealthy checks if the current database connection is healthy
-func IsHealthy() bool {
-	gormDBMutex.RLock()
-	defer gormDBMutex.RUnlock()
+// openDB creates the connection pool without connecting, connections are established by the first statement
+func openDB(cfg config.AppConfig) (*gorm.DB, error) {
+	// Get SSL mode from environment variable, default to 'require' for security
+	sslMode := os.Getenv("SSL_MODE")
+	if sslMode == "" {
+		sslMode = "require"
+	}
 
-	if gormDB == nil {
-		return false
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC connect_timeout=2 statement_timeout=5000",
+		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, sslMode)
+
+	// Create custom logger with configurable log level and higher slow query threshold
+	customLogger := logger.New(
+		log.StandardLogger(), // Use logrus as the underlying logger
+		logger.Config{
+			SlowThreshold:             1 * time.Second,                      // Only log queries slower than 1 second
+			LogLevel:                  config.GetGormLogLevel(cfg.LogLevel), // Use configured log level
+			IgnoreRecordNotFoundError: true,                                 // Don't log "record not found" errors
+			Colorful:                  false,                                // Disable colors for production
+		},
+	)
+
+	db, err := gorm.Open(&dialector{Dialector: postgres.New(postgres.Config{DSN: dsn}).(*postgres.Dialector)}, &gorm.Config{
+		Logger:               customLogger,
+		TranslateError:       true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	sqlDB, err := gormDB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
-		return false
+		return nil, fmt.Errorf("failed to get database connection pool: %w", err)
 	}
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+	return db, nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+// dialector is the postgres dialector translating connection failures of statements into ErrDatabaseUnavailable and
+// ErrDatabaseMaintenance, on top of the constraint violations translated by the postgres dialector
+type dialector struct {
+	*postgres.Dialector
+}
 
-	return sqlDB.PingContext(ctx) == nil
+// Translate is called by GORM with the error of every failed statement. Unique and foreign key violations become
+// gorm.ErrDuplicatedKey and gorm.ErrForeignKeyViolated.
+func (d *dialector) Translate(err error) error {
+	return classifyError(d.Dialector.Translate(err))
 }
 
-// ResetConnection forces a fresh connection attempt on next request
-// This can be called when you know the database has been restarted
-func ResetConnection() {
-	gormDBMutex.Lock()
-	defer gormDBMutex.Unlock()
+// classifyError wraps connection failures with ErrDatabaseUnavailable or ErrDatabaseMaintenance, other errors are
+// returned unchanged
+func classifyError(err error) error {
+	if err == nil || isConnectionError(err) {
+		return err
+	}
 
-	if gormDB != nil {
-		if sqlDB, err := gormDB.DB(); err == nil {
-			sqlDB.Close()
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "57P01", "57P03": // admin_shutdown, cannot_connect_now: restarting, starting up or in recovery
+			return fmt.Errorf("%w: %w", ErrDatabaseMaintenance, err)
+		case "57P02", "53300", "57014": // crash_shutdown, too_many_connections, statement timeout
+			return fmt.Errorf("%w: %w", ErrDatabaseUnavailable, err)
 		}
-		gormDB = nil
 	}
 
-	lastAttempt = time.Time{} // Reset retry timer
-	log.Info("Database connection reset - next request will attempt fresh connection")
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrDatabaseUnavailable, err)
+	}
+	return err
 }
 
-// GetConnectionStatus returns the current connection status
-func GetConnectionStatus() (bool, time.Time, time.Duration) {
-	gormDBMutex.RLock()
-	defer gormDBMutex.RUnlock()
-
-	hasConnection := gormDB != nil
-	nextRetryIn := time.Duration(0)
+// isConnectionError reports whether the error is ErrDatabaseUnavailable or ErrDatabaseMaintenance
+func isConnectionError(err error) bool {
+	return errors.Is(err, ErrDatabaseUnavailable) || errors.Is(err, ErrDatabaseMaintenance)
+}
 
-	if !lastAttempt.IsZero() {
-		elapsed := time.Since(lastAttempt)
-		if elapsed < retryInterval {
-			nextRetryIn = retryInterval - elapsed
-		}
+// migrate applies the pending schema migrations. Concurrent instances wait for each other on the migrations lock.
+func migrate(db *gorm.DB) error {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
 	}
-
-	return hasConnection, lastAttempt, nextRetryIn
+	applied, err := migrator.Up(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		log.WithField("migrations", len(applied)).Info("Database schema migrated")
+	}
+	return nil
 }
@@ -0,0 +1,98 @@
+package aws
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/savak1990/transactions-service/app/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"starting up", &pgconn.PgError{Code: "57P03", Message: "the database system is starting up"}, ErrDatabaseMaintenance},
+		{"admin shutdown", fmt.Errorf("failed to list: %w", &pgconn.PgError{Code: "57P01"}), ErrDatabaseMaintenance},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, ErrDatabaseUnavailable},
+		{"statement timeout", &pgconn.PgError{Code: "57014"}, ErrDatabaseUnavailable},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, ErrDatabaseUnavailable},
+		{"bad connection", driver.ErrBadConn, ErrDatabaseUnavailable},
+		{"already classified", fmt.Errorf("failed to migrate: %w", ErrDatabaseMaintenance), ErrDatabaseMaintenance},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, nil},
+		{"other", errors.New("record not found"), nil},
+	}
+	for _, tt := range tests {
+		err := classifyError(tt.err)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: classified error %v does not wrap %v", tt.name, err, tt.err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+		if tt.want == nil && isConnectionError(err) {
+			t.Errorf("%s: expected no connection error, got %v", tt.name, err)
+		}
+	}
+
+	if classifyError(nil) != nil {
+		t.Error("Expected nil for nil error")
+	}
+}
+
+func TestDialectorTranslatesConstraintViolations(t *testing.T) {
+	d := &dialector{Dialector: postgres.New(postgres.Config{DSN: "host=localhost"}).(*postgres.Dialector)}
+
+	if err := d.Translate(&pgconn.PgError{Code: "23505"}); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("Expected a unique violation to be gorm.ErrDuplicatedKey, got %v", err)
+	}
+	if err := d.Translate(&pgconn.PgError{Code: "23503"}); !errors.Is(err, gorm.ErrForeignKeyViolated) {
+		t.Errorf("Expected a foreign key violation to be gorm.ErrForeignKeyViolated, got %v", err)
+	}
+	if err := d.Translate(&pgconn.PgError{Code: "57P03"}); !errors.Is(err, ErrDatabaseMaintenance) {
+		t.Errorf("Expected connection failures to still be classified, got %v", err)
+	}
+}
+
+func TestConnectionManagerRechecksExpiredHealthErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		failedAgo time.Duration
+		wantErr   error
+	}{
+		{"recent failure fails fast", time.Hour, 0, ErrDatabaseMaintenance},
+		{"failure older than the interval is checked again", time.Minute, 2 * time.Minute, ErrDatabaseUnavailable},
+		{"failure without background checks is checked again", 0, 0, ErrDatabaseUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Nothing listens on the port, so a new ping fails with ErrDatabaseUnavailable right away
+			cfg := config.AppConfig{DBHost: "127.0.0.1", DBPort: 1, DBName: "test", DBHealthCheckInterval: tt.interval}
+			db, err := openDB(cfg)
+			if err != nil {
+				t.Fatalf("Failed to open connection pool: %v", err)
+			}
+			m := NewConnectionManager(cfg)
+			m.db, m.stop = db, make(chan struct{})
+			m.healthErr, m.healthAt = ErrDatabaseMaintenance, time.Now().Add(-tt.failedAgo)
+			defer m.Close()
+
+			_, err = m.DB()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == ErrDatabaseUnavailable && !errors.Is(m.healthErr, ErrDatabaseUnavailable) {
+				t.Errorf("Expected the new check to be recorded, got %v", m.healthErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/aws"
+	"github.com/savak1990/transactions-service/app/config"
+	"github.com/savak1990/transactions-service/app/migrations"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/repo"
+	"github.com/savak1990/transactions-service/app/service"
+)
+
+// runCommand executes a one-off CLI subcommand of the binary instead of starting the server
+func runCommand(appCfg config.AppConfig, command string, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Commands are run by operators with direct access to the environment, no ownership checks apply
+	ctx = auth.WithPrincipal(ctx, auth.SystemPrincipal())
+
+	switch command {
+	case "reconvert-amounts":
+		return runReconvertAmountsCommand(ctx, appCfg, args)
+	case "run-recurring":
+		return runRecurringTransactionsCommand(ctx, appCfg, args)
+	case "purge-deleted":
+		return runPurgeDeletedDataCommand(ctx, appCfg, args)
+	case "migrate":
+		return runMigrateCommand(ctx, appCfg, args)
+	case "issue-token":
+		return runIssueTokenCommand(appCfg, args)
+	default:
+		return fmt.Errorf("unknown command %q, supported commands: reconvert-amounts, run-recurring, purge-deleted, migrate, issue-token", command)
+	}
+}
+
+// runReconvertAmountsCommand fills in missing (and optionally re-prices existing) transaction entry amounts
+//
+// Usage: bootstrap reconvert-amounts [-batch-size=500] [-max-batches=0] [-after=<entryId>] [-reprice] [-dry-run]
+func runReconvertAmountsCommand(ctx context.Context, appCfg config.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("reconvert-amounts", flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", 500, "number of transaction entries processed per batch")
+	maxBatches := flags.Int("max-batches", 0, "stop after this many batches (0 processes all entries)")
+	afterEntryID := flags.String("after", "", "resume after this transaction entry ID")
+	reprice := flags.Bool("reprice", false, "re-price existing amounts, not only fill in missing currencies")
+	dryRun := flags.Bool("dry-run", false, "only report what would change")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	connections := aws.NewConnectionManager(appCfg)
+	defer connections.Close()
+	svc := newCommandService(appCfg, connections)
+
+	input := models.ReconvertAmountsInput{
+		BatchSize:       *batchSize,
+		MaxBatches:      *maxBatches,
+		AfterEntryID:    *afterEntryID,
+		RepriceExisting: *reprice,
+		DryRun:          *dryRun,
+	}
+
+	report, err := svc.ReconvertEntryAmounts(ctx, input, func(progress models.ReconvertAmountsReportDto) {
+		fmt.Printf("batch %d: scanned=%d changed=%d created=%d updated=%d withoutRates=%d lastEntryId=%s\n",
+			progress.Batches, progress.EntriesScanned, progress.EntriesChanged,
+			progress.AmountsCreated, progress.AmountsUpdated, progress.EntriesWithoutRates, progress.LastEntryID)
+	})
+	if err != nil {
+		if report != nil && report.LastEntryID != "" {
+			fmt.Printf("interrupted, resume with -after=%s\n", report.LastEntryID)
+		}
+		return err
+	}
+
+	fmt.Printf("done (dryRun=%t, completed=%t): scanned=%d changed=%d created=%d updated=%d withoutRates=%d\n",
+		report.DryRun, report.Completed, report.EntriesScanned, report.EntriesChanged,
+		report.AmountsCreated, report.AmountsUpdated, report.EntriesWithoutRates)
+	if !report.Completed {
+		fmt.Printf("not all entries were processed, continue with -after=%s\n", report.LastEntryID)
+	}
+	return nil
+}
+
+// runRecurringTransactionsCommand materializes due recurring transaction occurrences once, or every interval until
+// interrupted when used as a local scheduler
+//
+// Usage: bootstrap run-recurring [-interval=0] [-id=<recurringTransactionId>] [-dry-run]
+func runRecurringTransactionsCommand(ctx context.Context, appCfg config.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("run-recurring", flag.ContinueOnError)
+	interval := flags.Duration("interval", 0, "keep running and materialize due occurrences every interval (0 runs once)")
+	recurringTransactionID := flags.String("id", "", "only process this recurring transaction")
+	dryRun := flags.Bool("dry-run", false, "only report the due occurrences")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	connections := aws.NewConnectionManager(appCfg)
+	defer connections.Close()
+	svc := newCommandService(appCfg, connections)
+	input := models.RunRecurringTransactionsInput{
+		RecurringTransactionID: *recurringTransactionID,
+		DryRun:                 *dryRun,
+	}
+
+	run := func() error {
+		report, err := runRecurringTransactionsJob(ctx, svc, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("run at %s (dryRun=%t): templates=%d created=%d skipped=%d failed=%d\n",
+			report.RunAt, report.DryRun, report.TemplatesProcessed, report.TransactionsCreated,
+			report.OccurrencesSkipped, report.TemplatesFailed)
+		for _, result := range report.Results {
+			if result.Error != "" {
+				fmt.Printf("  %s: %s\n", result.RecurringTransactionID, result.Error)
+			}
+		}
+		return nil
+	}
+
+	if *interval <= 0 {
+		return run()
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if err := run(); err != nil && ctx.Err() == nil {
+			// Keep the scheduler running, the failed occurrences are retried on the next tick
+			fmt.Printf("run failed: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPurgeDeletedDataCommand permanently deletes data soft-deleted longer ago than the retention period
+//
+// Usage: bootstrap purge-deleted [-retention-days=<RETENTION_DAYS>] [-dry-run]
+func runPurgeDeletedDataCommand(ctx context.Context, appCfg config.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("purge-deleted", flag.ContinueOnError)
+	retentionDays := flags.Int("retention-days", appCfg.RetentionDays, "purge rows soft-deleted more than this many days ago")
+	dryRun := flags.Bool("dry-run", false, "only report the rows that would be removed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *retentionDays <= 0 {
+		return fmt.Errorf("-retention-days must be positive")
+	}
+
+	connections := aws.NewConnectionManager(appCfg)
+	defer connections.Close()
+	svc := newCommandService(appCfg, connections)
+	report, err := svc.PurgeDeletedData(ctx, models.PurgeDeletedDataInput{RetentionDays: *retentionDays, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("purged rows deleted before %s (dryRun=%t):\n", report.Cutoff, report.DryRun)
+	for _, table := range report.Tables {
+		fmt.Printf("  %s: %d\n", table.Table, table.Rows)
+	}
+	fmt.Printf("total: %d\n", report.TotalRows)
+	return nil
+}
+
+// runMigrateCommand shows, applies or reverts the schema migrations embedded into the binary
+//
+// Usage: bootstrap migrate status | up [-to=<version>] | down [-steps=1]
+func runMigrateCommand(ctx context.Context, appCfg config.AppConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate action, supported actions: status, up, down")
+	}
+	action := args[0]
+	flags := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	target := flags.Int("to", 0, "apply migrations up to and including this version (0 applies all)")
+	steps := flags.Int("steps", 1, "number of most recently applied migrations to revert")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	// Connect without applying pending migrations, the action decides what is applied
+	appCfg.DBMigrateOnConnect = false
+	connections := aws.NewConnectionManager(appCfg)
+	defer connections.Close()
+	db, err := connections.DB()
+	if err != nil {
+		return err
+	}
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			if status.Unknown {
+				state += " (unknown to this binary)"
+			}
+			fmt.Printf("%04d %-40s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	case "up":
+		applied, err := migrator.Up(ctx, *target)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		if *steps <= 0 {
+			return fmt.Errorf("-steps must be positive")
+		}
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("no applied migrations")
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown migrate action %q, supported actions: status, up, down", action)
+	}
+}
+
+// runIssueTokenCommand prints an HS256 token signed with a key of the local JWKS file, for local development
+//
+// Usage: bootstrap issue-token -user=<userId> [-groups=<groupId>,...] [-admin] [-ttl=24h] [-kid=<keyId>]
+func runIssueTokenCommand(appCfg config.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("issue-token", flag.ContinueOnError)
+	userID := flags.String("user", "", "user ID put into the sub claim (required)")
+	groupIDs := flags.String("groups", "", "comma separated group IDs the user is a member of")
+	admin := flags.Bool("admin", false, "add the user to the admin group")
+	ttl := flags.Duration("ttl", 24*time.Hour, "token lifetime")
+	kid := flags.String("kid", "", "ID of the HS256 key to sign with (defaults to the only HS256 key)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *userID == "" {
+		return fmt.Errorf("-user is required")
+	}
+	if appCfg.AuthJWKSFile == "" {
+		return fmt.Errorf("AUTH_JWKS_FILE is required to issue tokens")
+	}
+
+	keys, err := auth.LoadJWKSFile(appCfg.AuthJWKSFile)
+	if err != nil {
+		return err
+	}
+	secret, keyID, err := keys.HMACSecret(*kid)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	claims := auth.Claims{
+		auth.ClaimSubject: *userID,
+		"iat":             now.Unix(),
+		"exp":             now.Add(*ttl).Unix(),
+	}
+	if *groupIDs != "" {
+		claims[auth.ClaimGroupIDs] = strings.Split(*groupIDs, ",")
+	}
+	if *admin {
+		claims[auth.ClaimCognitoGroups] = []string{appCfg.AuthAdminGroup}
+	}
+	if appCfg.AuthIssuer != "" {
+		claims["iss"] = appCfg.AuthIssuer
+	}
+	if appCfg.AuthAudience != "" {
+		claims["aud"] = appCfg.AuthAudience
+	}
+
+	// Make sure the token is accepted by the service before printing it
+	if _, err := auth.PrincipalFromClaims(claims, appCfg.AuthAdminGroup); err != nil {
+		return err
+	}
+
+	token, err := auth.SignHS256(claims, keyID, secret)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+// newCommandService creates the service used by commands, always with the real exchange rates table since static
+// rates must never be persisted
+func newCommandService(appCfg config.AppConfig, connections *aws.ConnectionManager) service.Service {
+	exchangeRateDbName := appCfg.ExchangeRateDbName
+	if exchangeRateDbName == "" {
+		exchangeRateDbName = "ahorro-exchangerate-stable-db" // Default for local dev
+	}
+	awsConfig := aws.LoadAWSConfig(appCfg.AWSRegion, appCfg.AWSProfile)
+	exchangeRatesDb := repo.NewCachedExchangeRatesDb(repo.NewExchangeRatesDb(exchangeRateDbName, awsConfig), 60*60)
+
+	return service.NewServiceImpl(repo.NewPostgreSQLRepositoryWithConnectionManager(connections), exchangeRatesDb)
+}
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm/logger"
@@ -20,20 +22,73 @@ type AppConfig struct {
 	DBUser     string
 	DBPassword string
 
+	// Apply pending schema migrations when a connection is established, disable to only migrate with "migrate up"
+	DBMigrateOnConnect bool
+
+	// Connection pool of the database and the interval of its background health checks
+	DBMaxOpenConns        int
+	DBMaxIdleConns        int
+	DBConnMaxLifetime     time.Duration
+	DBConnMaxIdleTime     time.Duration
+	DBHealthCheckInterval time.Duration
+
 	// Exchange Rate Configuration
 	ExchangeRateApiKey string
 	ExchangeRateDbName string
 
 	// Application Configuration
 	LogLevel string
+
+	// Secret used to sign pagination cursors (nextKey) returned to clients
+	CursorSigningKey string
+
+	// Days soft-deleted data is kept before the purge job permanently deletes it
+	RetentionDays int
+
+	// Authentication Configuration
+	AuthMode       string // apigateway, jwks or none (see AuthMode* constants)
+	AuthJWKSFile   string // JWKS file with HS256/RS256 keys used in jwks mode
+	AuthIssuer     string // Expected iss claim in jwks mode, empty disables the check
+	AuthAudience   string // Expected aud (or client_id) claim in jwks mode, empty disables the check
+	AuthAdminGroup string // Cognito group whose members may run admin jobs and access any data
 }
 
+const (
+	// AuthModeAPIGateway trusts the JWT claims already validated by the API Gateway authorizer
+	AuthModeAPIGateway = "apigateway"
+	// AuthModeJWKS validates bearer tokens against the keys of a local JWKS file (local development)
+	AuthModeJWKS = "jwks"
+	// AuthModeNone disables authentication, every request runs with unrestricted access (local development only)
+	AuthModeNone = "none"
+)
+
+// localCursorSigningKey signs pagination cursors during local development only, deployed services must set
+// CURSOR_SIGNING_KEY so clients cannot forge cursors with the publicly known key
+const localCursorSigningKey = "ahorro-local-cursor-signing-key"
+
 func LoadConfig() AppConfig {
 	dbPort, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	if err != nil {
 		dbPort = 5432
 	}
 
+	migrateOnConnect, err := strconv.ParseBool(getEnv("DB_MIGRATE_ON_CONNECT", "true"))
+	if err != nil {
+		migrateOnConnect = true
+	}
+
+	// Defaults are kept small for the serverless database, every Lambda instance has its own pool
+	maxOpenConns := getEnvPositiveInt("DB_MAX_OPEN_CONNS", 10)
+	maxIdleConns := getEnvPositiveInt("DB_MAX_IDLE_CONNS", 2)
+	connMaxLifetime := getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute)
+	connMaxIdleTime := getEnvDuration("DB_CONN_MAX_IDLE_TIME", 1*time.Minute)
+	healthCheckInterval := getEnvDuration("DB_HEALTH_CHECK_INTERVAL", 30*time.Second)
+
+	retentionDays, err := strconv.Atoi(getEnv("RETENTION_DAYS", "90"))
+	if err != nil || retentionDays <= 0 {
+		retentionDays = 90
+	}
+
 	return AppConfig{
 		AWSRegion:  getEnv("AWS_REGION", "eu-west-1"),
 		AWSProfile: os.Getenv("AWS_PROFILE"),
@@ -45,12 +100,62 @@ func LoadConfig() AppConfig {
 		DBUser:     os.Getenv("DB_USER"),
 		DBPassword: os.Getenv("DB_PASSWORD"),
 
+		DBMigrateOnConnect: migrateOnConnect,
+
+		DBMaxOpenConns:        maxOpenConns,
+		DBMaxIdleConns:        maxIdleConns,
+		DBConnMaxLifetime:     connMaxLifetime,
+		DBConnMaxIdleTime:     connMaxIdleTime,
+		DBHealthCheckInterval: healthCheckInterval,
+
 		// Currency Exchange Rate Db
 		ExchangeRateDbName: os.Getenv("EXCHANGE_RATE_DB_NAME"),
 
 		// Application Configuration
 		LogLevel: getEnv("LOG_LEVEL", "info"),
+
+		// Pagination
+		CursorSigningKey: getEnv("CURSOR_SIGNING_KEY", defaultCursorSigningKey()),
+
+		// Retention of soft-deleted data
+		RetentionDays: retentionDays,
+
+		// Authentication
+		AuthMode:       getEnv("AUTH_MODE", defaultAuthMode()),
+		AuthJWKSFile:   os.Getenv("AUTH_JWKS_FILE"),
+		AuthIssuer:     os.Getenv("AUTH_ISSUER"),
+		AuthAudience:   os.Getenv("AUTH_AUDIENCE"),
+		AuthAdminGroup: getEnv("AUTH_ADMIN_GROUP", "admin"),
+	}
+}
+
+// Validate reports settings the service cannot safely start without
+func (c AppConfig) Validate() error {
+	if c.CursorSigningKey == "" {
+		return errors.New("CURSOR_SIGNING_KEY is required outside local development")
+	}
+	return nil
+}
+
+// defaultCursorSigningKey falls back to the local key outside Lambda, in Lambda the key must be configured
+func defaultCursorSigningKey() string {
+	if IsLambdaEnvironment() {
+		return ""
+	}
+	return localCursorSigningKey
+}
+
+// defaultAuthMode trusts the API Gateway authorizer in Lambda and validates tokens locally otherwise
+func defaultAuthMode() string {
+	if IsLambdaEnvironment() {
+		return AuthModeAPIGateway
 	}
+	return AuthModeJWKS
+}
+
+// IsLambdaEnvironment reports whether the service runs inside AWS Lambda
+func IsLambdaEnvironment() bool {
+	return os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "" || os.Getenv("_LAMBDA_SERVER_PORT") != ""
 }
 
 func getEnv(key, fallback string) string {
@@ -60,6 +165,25 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvPositiveInt returns the positive integer of the environment variable, or fallback if it is unset or invalid
+func getEnvPositiveInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// getEnvDuration returns the positive duration (e.g. 30s, 5m) of the environment variable, or fallback if it is
+// unset or invalid
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
 // GetLogrusLevel converts string log level to logrus.Level
 func GetLogrusLevel(level string) logrus.Level {
 	switch strings.ToLower(level) {
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/config"
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// publicPaths are served without authentication
+var publicPaths = []string{
+	"/health",
+	"/info",
+	"/docs",
+	"/schema",
+}
+
+// AuthMiddleware resolves the authenticated principal of a request and stores it in the request context.
+// The service layer uses the principal to scope every read and write to the caller's user and groups.
+type AuthMiddleware struct {
+	mode       string
+	verifier   *auth.Verifier
+	adminGroup string
+}
+
+// NewAuthMiddleware creates the authentication middleware for the configured auth mode
+func NewAuthMiddleware(cfg config.AppConfig) (*AuthMiddleware, error) {
+	middleware := &AuthMiddleware{
+		mode:       cfg.AuthMode,
+		adminGroup: cfg.AuthAdminGroup,
+	}
+
+	switch cfg.AuthMode {
+	case config.AuthModeAPIGateway:
+		log.Info("Authentication: using JWT claims validated by API Gateway authorizer")
+	case config.AuthModeJWKS:
+		if cfg.AuthJWKSFile == "" {
+			return nil, fmt.Errorf("AUTH_JWKS_FILE is required for auth mode '%s'", cfg.AuthMode)
+		}
+		keys, err := auth.LoadJWKSFile(cfg.AuthJWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		middleware.verifier = auth.NewVerifier(keys, cfg.AuthIssuer, cfg.AuthAudience)
+		log.WithField("jwks_file", cfg.AuthJWKSFile).Info("Authentication: validating bearer tokens with local JWKS")
+	case config.AuthModeNone:
+		log.Warn("Authentication is DISABLED, all requests run with unrestricted access. Never use this mode outside local development")
+	default:
+		return nil, fmt.Errorf("unsupported auth mode '%s'", cfg.AuthMode)
+	}
+
+	return middleware, nil
+}
+
+// Authenticate rejects requests without valid credentials and puts the principal into the request context
+func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// CORS preflight and public endpoints don't require authentication
+		if r.Method == http.MethodOptions || isPublicPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := m.resolvePrincipal(r)
+		if err != nil {
+			log.WithError(err).WithField("path", r.URL.Path).Warn("Request authentication failed")
+			WriteJSONError(w, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Invalid or missing authentication token")
+			return
+		}
+
+		log.WithFields(log.Fields{
+			"user_id": principal.UserID.String(),
+			"admin":   principal.Admin,
+		}).Debug("Request authenticated")
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+func (m *AuthMiddleware) resolvePrincipal(r *http.Request) (*auth.Principal, error) {
+	switch m.mode {
+	case config.AuthModeAPIGateway:
+		gatewayContext, ok := core.GetAPIGatewayV2ContextFromContext(r.Context())
+		if !ok || gatewayContext.Authorizer == nil || gatewayContext.Authorizer.JWT == nil {
+			return nil, errors.New("request has no API Gateway JWT authorizer context")
+		}
+		claims := make(auth.Claims, len(gatewayContext.Authorizer.JWT.Claims))
+		for name, value := range gatewayContext.Authorizer.JWT.Claims {
+			claims[name] = value
+		}
+		return auth.PrincipalFromClaims(claims, m.adminGroup)
+
+	case config.AuthModeJWKS:
+		token, err := bearerToken(r)
+		if err != nil {
+			return nil, err
+		}
+		claims, err := m.verifier.Verify(token)
+		if err != nil {
+			return nil, err
+		}
+		return auth.PrincipalFromClaims(claims, m.adminGroup)
+
+	case config.AuthModeNone:
+		return auth.SystemPrincipal(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported auth mode '%s'", m.mode)
+}
+
+// bearerToken extracts the token from the Authorization header
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", auth.ErrUnauthenticated
+	}
+	return strings.TrimSpace(token), nil
+}
+
+func isPublicPath(path string) bool {
+	for _, publicPath := range publicPaths {
+		if strings.HasPrefix(path, publicPath) {
+			return true
+		}
+	}
+	return false
+}
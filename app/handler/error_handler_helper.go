@@ -1,82 +1,104 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/savak1990/transactions-service/app/auth"
 	"github.com/savak1990/transactions-service/app/aws"
 	"github.com/savak1990/transactions-service/app/models"
 	"github.com/sirupsen/logrus"
 )
 
 // handleServiceError is a centralized error handler for service layer errors
-// It checks for database connection errors and converts them to proper panics for middleware
 // Returns true if the error was handled (response was written), false if caller should continue
 func (h *HandlerImpl) handleServiceError(w http.ResponseWriter, err error, operation string) bool {
 	if err == nil {
 		return false
 	}
+	writeError(w, err, operation)
+	return true
+}
 
-	logrus.WithError(err).Errorf("%s failed", operation)
-
-	// Check if this is a database connection error - if so, convert to proper panic
-	if isDatabaseConnectionError(err) {
-		// Convert the regular error back to a DatabaseConnectionError panic
-		// so the maintenance middleware can catch it
-		panic(&aws.DatabaseConnectionError{
-			Message: fmt.Sprintf("Database connection failed during %s", operation),
-			Cause:   err,
-		})
+// writeError logs the error and writes it as a problem details response, client errors are logged as warnings
+func writeError(w http.ResponseWriter, err error, operation string) {
+	problem := errorProblem(err)
+	if problem.Status < http.StatusInternalServerError {
+		logrus.WithError(err).Warnf("%s rejected", operation)
+	} else {
+		logrus.WithError(err).Errorf("%s failed", operation)
 	}
+	writeProblem(w, problem)
+}
 
-	// Handle other common error types
-	if isDatabaseMaintenanceError(err) {
-		WriteJSONError(w, http.StatusServiceUnavailable, models.ErrorCodeDbTimeout, "Database is undergoing maintenance, please retry in a few minutes")
-		return true
-	}
+// writeInvalidRequest writes a 400 problem details response for a request body failing validation
+func writeInvalidRequest(w http.ResponseWriter, message string, err error) {
+	writeError(w, fmt.Errorf("%w: %w", &models.ValidationError{Reason: message}, err), "Request validation")
+}
 
-	if isDatabaseTimeoutError(err) {
-		WriteJSONError(w, http.StatusServiceUnavailable, models.ErrorCodeDbTimeout, "Database is temporarily unavailable, please retry in a few moments")
-		return true
-	}
+// errorProblem maps an error of the repo or service layer to the problem details response sent to the client.
+// This is the only place deciding the status code of an error, the layers below return typed errors from models.
+func errorProblem(err error) Problem {
+	var forbidden *models.ForbiddenError
+	var validation *models.ValidationError
+	var notFound *models.NotFoundError
+	var conflict *models.ConflictError
 
-	if isDatabaseError(err) {
-		WriteJSONError(w, http.StatusInternalServerError, models.ErrorCodeDbError, err.Error())
-		return true
+	switch {
+	// Authentication and authorization errors don't reveal why the access was denied
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return Problem{Status: http.StatusUnauthorized, Code: models.ErrorCodeUnauthorized, Detail: "Invalid or missing authentication token"}
+	case errors.As(err, &forbidden):
+		return Problem{Status: http.StatusForbidden, Code: models.ErrorCodeForbidden, Detail: "Access to the requested resource is not allowed"}
+
+	// The database is unreachable or restarting, the request can be retried once it is back
+	case errors.Is(err, aws.ErrDatabaseMaintenance):
+		return Problem{Status: http.StatusServiceUnavailable, Code: models.ErrorCodeDbTimeout, Detail: "Database is undergoing maintenance, please retry in a few minutes"}
+	case errors.Is(err, aws.ErrDatabaseUnavailable):
+		return Problem{Status: http.StatusServiceUnavailable, Code: models.ErrorCodeDbTimeout, Detail: "Database is temporarily unavailable, please retry in a few moments"}
+
+	case errors.As(err, &validation):
+		return Problem{Status: http.StatusBadRequest, Code: models.ErrorCodeBadRequest, Detail: err.Error(), Errors: fieldErrors(err)}
+	case errors.As(err, &notFound):
+		return Problem{Status: http.StatusNotFound, Code: models.ErrorCodeNotFound, Detail: capitalize(notFound.Entity) + " not found"}
+	case errors.As(err, &conflict):
+		return Problem{Status: http.StatusConflict, Code: models.ErrorCodeConflict, Detail: err.Error()}
 	}
 
-	// Default to internal server error
-	WriteJSONError(w, http.StatusInternalServerError, models.ErrorCodeInternalServer, err.Error())
-	return true
+	// Unexpected errors may contain SQL or other internals, they are only logged
+	return Problem{Status: http.StatusInternalServerError, Code: models.ErrorCodeInternalServer, Detail: "An unexpected error occurred"}
 }
 
-// handleNotFoundError handles "not found" errors with a specific pattern
-func (h *HandlerImpl) handleNotFoundError(w http.ResponseWriter, err error, resourceType, resourceID string) bool {
-	expectedMessage := fmt.Sprintf("%s not found: %s", resourceType, resourceID)
-	if err.Error() == expectedMessage {
-		WriteJSONError(w, http.StatusNotFound, models.ErrorCodeNotFound, fmt.Sprintf("%s not found", strings.Title(resourceType)))
-		return true
+// fieldErrors collects the validation errors of individual fields wrapped or joined in err
+func fieldErrors(err error) []FieldError {
+	var fields []FieldError
+	var walk func(err error)
+	walk = func(err error) {
+		if validation, ok := err.(*models.ValidationError); ok && validation.Field != "" {
+			fields = append(fields, FieldError{Field: validation.Field, Reason: validation.Reason})
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			if inner := e.Unwrap(); inner != nil {
+				walk(inner)
+			}
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		}
 	}
-	return false
+	walk(err)
+	return fields
 }
 
-// isDatabaseConnectionError checks if an error is a database connection error
-func isDatabaseConnectionError(err error) bool {
-	if err == nil {
-		return false
+// capitalize upper-cases the first letter of every word of an entity name, e.g. "category group" becomes "Category Group"
+func capitalize(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
 	}
-
-	errStr := strings.ToLower(err.Error())
-	return strings.Contains(errStr, "connection refused") ||
-		strings.Contains(errStr, "dial tcp") ||
-		strings.Contains(errStr, "failed to connect") ||
-		strings.Contains(errStr, "database connection failed") ||
-		strings.Contains(errStr, "no such host") ||
-		strings.Contains(errStr, "i/o timeout") ||
-		strings.Contains(errStr, "context deadline exceeded") ||
-		strings.Contains(errStr, "server closed the connection") ||
-		strings.Contains(errStr, "timeout") ||
-		strings.Contains(errStr, "network is unreachable") ||
-		strings.Contains(errStr, "connection reset by peer")
+	return strings.Join(words, " ")
 }
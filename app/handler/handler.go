@@ -10,12 +10,16 @@ type Handler interface {
 	GetTransaction(http.ResponseWriter, *http.Request)
 	UpdateTransaction(http.ResponseWriter, *http.Request)
 	DeleteTransaction(http.ResponseWriter, *http.Request)
+	RestoreTransaction(http.ResponseWriter, *http.Request)
+	ListDuplicateTransactions(http.ResponseWriter, *http.Request)
+	ExportTransactions(http.ResponseWriter, *http.Request)
 
 	CreateBalance(http.ResponseWriter, *http.Request)
 	ListBalances(http.ResponseWriter, *http.Request)
 	GetBalance(http.ResponseWriter, *http.Request)
 	UpdateBalance(http.ResponseWriter, *http.Request)
 	DeleteBalance(http.ResponseWriter, *http.Request)
+	RestoreBalance(http.ResponseWriter, *http.Request)
 	DeleteBalancesByUserId(http.ResponseWriter, *http.Request)
 
 	CreateCategory(http.ResponseWriter, *http.Request)
@@ -23,6 +27,7 @@ type Handler interface {
 	GetCategory(http.ResponseWriter, *http.Request)
 	UpdateCategory(http.ResponseWriter, *http.Request)
 	DeleteCategory(http.ResponseWriter, *http.Request)
+	RestoreCategory(http.ResponseWriter, *http.Request)
 	DeleteCategoriesByUserId(http.ResponseWriter, *http.Request)
 
 	CreateCategoryGroup(http.ResponseWriter, *http.Request)
@@ -30,14 +35,89 @@ type Handler interface {
 	GetCategoryGroup(http.ResponseWriter, *http.Request)
 	UpdateCategoryGroup(http.ResponseWriter, *http.Request)
 	DeleteCategoryGroup(http.ResponseWriter, *http.Request)
+	RestoreCategoryGroup(http.ResponseWriter, *http.Request)
 
 	CreateMerchant(http.ResponseWriter, *http.Request)
 	ListMerchants(http.ResponseWriter, *http.Request)
 	GetMerchant(http.ResponseWriter, *http.Request)
 	UpdateMerchant(http.ResponseWriter, *http.Request)
 	DeleteMerchant(http.ResponseWriter, *http.Request)
+	RestoreMerchant(http.ResponseWriter, *http.Request)
 	DeleteMerchantsByUserId(http.ResponseWriter, *http.Request)
 
+	// Budgets
+	CreateBudget(http.ResponseWriter, *http.Request)
+	ListBudgets(http.ResponseWriter, *http.Request)
+	GetBudget(http.ResponseWriter, *http.Request)
+	UpdateBudget(http.ResponseWriter, *http.Request)
+	DeleteBudget(http.ResponseWriter, *http.Request)
+	GetBudgetStatus(http.ResponseWriter, *http.Request)
+
+	// Categorization rules
+	CreateCategorizationRule(http.ResponseWriter, *http.Request)
+	ListCategorizationRules(http.ResponseWriter, *http.Request)
+	GetCategorizationRule(http.ResponseWriter, *http.Request)
+	UpdateCategorizationRule(http.ResponseWriter, *http.Request)
+	DeleteCategorizationRule(http.ResponseWriter, *http.Request)
+	ApplyCategorizationRules(http.ResponseWriter, *http.Request)
+
+	// Suggestions
+	GetSuggestions(http.ResponseWriter, *http.Request)
+
+	// Operations
+	GetOperation(http.ResponseWriter, *http.Request)
+	UpdateOperation(http.ResponseWriter, *http.Request)
+	DeleteOperation(http.ResponseWriter, *http.Request)
+
+	// Transfers
+	CreateTransfer(http.ResponseWriter, *http.Request)
+	GetTransfer(http.ResponseWriter, *http.Request)
+	UpdateTransfer(http.ResponseWriter, *http.Request)
+	DeleteTransfer(http.ResponseWriter, *http.Request)
+
+	// Recurring transactions
+	CreateRecurringTransaction(http.ResponseWriter, *http.Request)
+	ListRecurringTransactions(http.ResponseWriter, *http.Request)
+	GetRecurringTransaction(http.ResponseWriter, *http.Request)
+	UpdateRecurringTransaction(http.ResponseWriter, *http.Request)
+	DeleteRecurringTransaction(http.ResponseWriter, *http.Request)
+
+	// Bank statement imports
+	ImportTransactions(http.ResponseWriter, *http.Request)
+	CreateImportProfile(http.ResponseWriter, *http.Request)
+	ListImportProfiles(http.ResponseWriter, *http.Request)
+	GetImportProfile(http.ResponseWriter, *http.Request)
+	UpdateImportProfile(http.ResponseWriter, *http.Request)
+	DeleteImportProfile(http.ResponseWriter, *http.Request)
+
+	// Groups, members and invitations
+	CreateGroup(http.ResponseWriter, *http.Request)
+	ListGroups(http.ResponseWriter, *http.Request)
+	GetGroup(http.ResponseWriter, *http.Request)
+	UpdateGroup(http.ResponseWriter, *http.Request)
+	DeleteGroup(http.ResponseWriter, *http.Request)
+	UpdateGroupMember(http.ResponseWriter, *http.Request)
+	RemoveGroupMember(http.ResponseWriter, *http.Request)
+	CreateGroupInvitation(http.ResponseWriter, *http.Request)
+	ListGroupInvitations(http.ResponseWriter, *http.Request)
+	RevokeGroupInvitation(http.ResponseWriter, *http.Request)
+	ListMyGroupInvitations(http.ResponseWriter, *http.Request)
+	AcceptGroupInvitation(http.ResponseWriter, *http.Request)
+	DeclineGroupInvitation(http.ResponseWriter, *http.Request)
+
 	// Transaction statistics
 	GetTransactionStats(http.ResponseWriter, *http.Request)
+
+	// User data export and erasure
+	ExportUserData(http.ResponseWriter, *http.Request)
+	EraseUserData(http.ResponseWriter, *http.Request)
+
+	// Audit log
+	ListAuditEvents(http.ResponseWriter, *http.Request)
+	GetTransactionHistory(http.ResponseWriter, *http.Request)
+
+	// Admin jobs
+	ReconvertEntryAmounts(http.ResponseWriter, *http.Request)
+	RunRecurringTransactions(http.ResponseWriter, *http.Request)
+	PurgeDeletedData(http.ResponseWriter, *http.Request)
 }
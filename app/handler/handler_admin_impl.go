@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// POST /admin/exchange-rates/reconvert
+func (h *HandlerImpl) ReconvertEntryAmounts(w http.ResponseWriter, r *http.Request) {
+	var requestDto models.ReconvertAmountsRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&requestDto); err != nil && !errors.Is(err, io.EOF) {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if requestDto.BatchSize < 0 || requestDto.MaxBatches < 0 {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "batchSize and maxBatches must not be negative")
+		return
+	}
+
+	input := models.ReconvertAmountsInput{
+		BatchSize:       requestDto.BatchSize,
+		MaxBatches:      requestDto.MaxBatches,
+		AfterEntryID:    requestDto.AfterEntryID,
+		RepriceExisting: requestDto.RepriceExisting,
+		DryRun:          requestDto.DryRun,
+	}
+
+	report, err := h.Service.ReconvertEntryAmounts(r.Context(), input, nil)
+	if err != nil {
+		h.handleServiceError(w, err, "ReconvertEntryAmounts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// POST /admin/recurring-transactions/run
+func (h *HandlerImpl) RunRecurringTransactions(w http.ResponseWriter, r *http.Request) {
+	var requestDto models.RunRecurringTransactionsRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&requestDto); err != nil && !errors.Is(err, io.EOF) {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	input := models.RunRecurringTransactionsInput{
+		RecurringTransactionID: requestDto.RecurringTransactionID,
+		DryRun:                 requestDto.DryRun,
+	}
+	if requestDto.Now != "" {
+		now, err := time.Parse(time.RFC3339, requestDto.Now)
+		if err != nil {
+			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid now, must be RFC3339: "+err.Error())
+			return
+		}
+		input.Now = now
+	}
+
+	report, err := h.Service.RunRecurringTransactions(r.Context(), input)
+	if err != nil {
+		h.handleServiceError(w, err, "RunRecurringTransactions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// POST /admin/deleted-data/purge
+func (h *HandlerImpl) PurgeDeletedData(w http.ResponseWriter, r *http.Request) {
+	var requestDto models.PurgeDeletedDataRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&requestDto); err != nil && !errors.Is(err, io.EOF) {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if requestDto.RetentionDays < 0 {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "retentionDays must not be negative")
+		return
+	}
+
+	input := models.PurgeDeletedDataInput{
+		RetentionDays: requestDto.RetentionDays,
+		DryRun:        requestDto.DryRun,
+	}
+	if input.RetentionDays == 0 {
+		input.RetentionDays = h.retentionDays
+	}
+
+	report, err := h.Service.PurgeDeletedData(r.Context(), input)
+	if err != nil {
+		h.handleServiceError(w, err, "PurgeDeletedData")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// Limits of the number of audit events per request
+const (
+	defaultAuditEventsLimit = 100
+	maxAuditEventsLimit     = 500
+)
+
+// GET /audit?entityId=
+func (h *HandlerImpl) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	entityID := query.Get("entityId")
+	if entityID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing entityId")
+		return
+	}
+	if _, err := uuid.Parse(entityID); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid entityId format")
+		return
+	}
+
+	limit := defaultAuditEventsLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= maxAuditEventsLimit {
+			limit = parsedLimit
+		}
+	}
+
+	events, err := h.Service.ListAuditEvents(r.Context(), models.ListAuditEventsInput{
+		EntityID:   entityID,
+		EntityType: query.Get("entityType"),
+		Limit:      limit,
+	})
+	if err != nil {
+		h.handleServiceError(w, err, "ListAuditEvents")
+		return
+	}
+
+	writeAuditEvents(w, events)
+}
+
+// GET /transactions/{transaction_id}/history
+func (h *HandlerImpl) GetTransactionHistory(w http.ResponseWriter, r *http.Request) {
+	transactionID := mux.Vars(r)["transaction_id"]
+	if _, err := uuid.Parse(transactionID); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid transaction ID format")
+		return
+	}
+
+	events, err := h.Service.GetTransactionHistory(r.Context(), transactionID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetTransactionHistory")
+		return
+	}
+
+	writeAuditEvents(w, events)
+}
+
+// writeAuditEvents writes the events in the order they were recorded as a list response
+func writeAuditEvents(w http.ResponseWriter, events []models.AuditEvent) {
+	eventDtos := make([]models.AuditEventDto, len(events))
+	for i := range events {
+		eventDtos[i] = models.ToAPIAuditEvent(&events[i])
+	}
+	WriteJSONListResponse(w, eventDtos, "")
+}
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -56,6 +58,19 @@ func (h *HandlerImpl) ListBalances(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Parse asOf (defaults to now)
+	asOf, err := parseAsOfParam(query.Get("asOf"))
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	order, err := parseSortOrder(query)
+	if err != nil {
+		writeError(w, err, "ListBalances")
+		return
+	}
+
 	// Extract and validate required parameters
 	userID := query.Get("userId")
 	groupID := query.Get("groupId")
@@ -70,11 +85,20 @@ func (h *HandlerImpl) ListBalances(w http.ResponseWriter, r *http.Request) {
 		UserID:         userID,
 		GroupID:        groupID,
 		IncludeDeleted: includeDeleted,
-		SortBy:         query.Get("sortBy"),
-		Order:          query.Get("order"),
+		AsOf:           asOf,
+		SortBy:         parseSortBy(query, "sortBy"),
+		Order:          order,
 		Limit:          limit,
 	}
 
+	// Parse pagination cursor from a previous page, if provided
+	cursor, err := h.parsePageCursor(query, filter.SortBy, filter.Order)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid parameter 'nextKey': "+err.Error())
+		return
+	}
+	filter.Cursor = cursor
+
 	results, err := h.Service.ListBalances(r.Context(), filter)
 	if err != nil {
 		h.handleServiceError(w, err, "ListBalances")
@@ -87,8 +111,13 @@ func (h *HandlerImpl) ListBalances(w http.ResponseWriter, r *http.Request) {
 		balanceDtos[i] = models.ToAPIBalance(&balance)
 	}
 
-	// Use paginated response structure (without actual pagination for now)
-	WriteJSONListResponse(w, balanceDtos, "")
+	// Cursor for the next page points after the last returned balance
+	nextKey := ""
+	if len(results) > 0 {
+		nextKey = h.encodeNextKey(models.BalanceCursor(&results[len(results)-1], filter.SortBy, filter.Order), len(results), filter.Limit)
+	}
+
+	WriteJSONListResponse(w, balanceDtos, nextKey)
 }
 
 func (h *HandlerImpl) GetBalance(w http.ResponseWriter, r *http.Request) {
@@ -99,13 +128,14 @@ func (h *HandlerImpl) GetBalance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	balance, err := h.Service.GetBalance(r.Context(), balanceID)
+	asOf, err := parseAsOfParam(r.URL.Query().Get("asOf"))
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	balance, err := h.Service.GetBalanceWithAmounts(r.Context(), balanceID, asOf)
 	if err != nil {
-		// Try to handle as "not found" error first
-		if h.handleNotFoundError(w, err, "balance", balanceID) {
-			return
-		}
-		// Handle all other errors (including database connection errors)
 		h.handleServiceError(w, err, "GetBalance")
 		return
 	}
@@ -189,3 +219,15 @@ func (h *HandlerImpl) DeleteBalancesByUserId(w http.ResponseWriter, r *http.Requ
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// parseAsOfParam parses the optional asOf query parameter (RFC3339), returning nil if not provided
+func parseAsOfParam(asOfStr string) (*time.Time, error) {
+	if asOfStr == "" {
+		return nil, nil
+	}
+	asOf, err := time.Parse(time.RFC3339, asOfStr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid parameter 'asOf', must be RFC3339: %w", err)
+	}
+	return &asOf, nil
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/repo"
+	"github.com/savak1990/transactions-service/app/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBalanceAsOf(t *testing.T) {
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantAsOf *time.Time
+	}{
+		{"without asOf", "", http.StatusOK, nil},
+		{"with asOf", "?asOf=2024-03-31T23:59:59Z", http.StatusOK, &asOf},
+		{"invalid asOf", "?asOf=2024-03-31", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			balance := &models.Balance{ID: uuid.New(), UserID: userID, Currency: "EUR"}
+			mockRepo := repo.NewMockRepository()
+			mockRepo.On("GetBalance", mock.Anything, balance.ID.String()).Return(balance, nil)
+			mockRepo.On("GetBalanceAmounts", mock.Anything, []string{balance.ID.String()}, tt.wantAsOf).
+				Return(map[string]models.BalanceAmounts{balance.ID.String(): {Amount: -2500}}, nil)
+			h := NewHandlerImpl(service.NewServiceImpl(mockRepo, repo.NewExchangeRatesStaticDb()))
+
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID})
+			r := httptest.NewRequest(http.MethodGet, "/balances/"+balance.ID.String()+tt.query, nil).WithContext(ctx)
+			r = mux.SetURLVars(r, map[string]string{"balance_id": balance.ID.String()})
+			recorder := httptest.NewRecorder()
+			h.GetBalance(recorder, r)
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, recorder.Code, recorder.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				mockRepo.AssertNotCalled(t, "GetBalanceAmounts", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			mockRepo.AssertCalled(t, "GetBalanceAmounts", mock.Anything, []string{balance.ID.String()}, tt.wantAsOf)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// Budget handlers
+func (h *HandlerImpl) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	var budgetDto models.BudgetDto
+	if err := json.NewDecoder(r.Body).Decode(&budgetDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	budgetDto.BudgetID = "" // Generated by the service
+
+	budget, ok := parseBudget(w, budgetDto)
+	if !ok {
+		return
+	}
+
+	created, err := h.Service.CreateBudget(r.Context(), *budget)
+	if err != nil {
+		h.handleServiceError(w, err, "CreateBudget")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.ToAPIBudget(created))
+}
+
+func (h *HandlerImpl) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ListBudgetsInput{
+		GroupID:   query.Get("groupId"),
+		UserID:    query.Get("userId"),
+		ScopeType: query.Get("scopeType"),
+		ScopeID:   query.Get("scopeId"),
+	}
+
+	results, err := h.Service.ListBudgets(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err, "ListBudgets")
+		return
+	}
+
+	// Convert to DTOs for response
+	budgetDtos := make([]models.BudgetDto, len(results))
+	for i := range results {
+		budgetDtos[i] = models.ToAPIBudget(&results[i])
+	}
+
+	WriteJSONListResponse(w, budgetDtos, "")
+}
+
+func (h *HandlerImpl) GetBudget(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["budget_id"]
+	if budgetID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing budget_id")
+		return
+	}
+
+	budget, err := h.Service.GetBudget(r.Context(), budgetID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetBudget")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIBudget(budget))
+}
+
+func (h *HandlerImpl) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["budget_id"]
+	var budgetDto models.BudgetDto
+	if err := json.NewDecoder(r.Body).Decode(&budgetDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	// Parse budget ID and set it in DTO
+	id, err := uuid.Parse(budgetID)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid budget ID format")
+		return
+	}
+	budgetDto.BudgetID = id.String()
+
+	budget, ok := parseBudget(w, budgetDto)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Service.UpdateBudget(r.Context(), *budget)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateBudget")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIBudget(updated))
+}
+
+func (h *HandlerImpl) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["budget_id"]
+	if budgetID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing budget_id")
+		return
+	}
+
+	if err := h.Service.DeleteBudget(r.Context(), budgetID); err != nil {
+		h.handleServiceError(w, err, "DeleteBudget")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerImpl) GetBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["budget_id"]
+	if budgetID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing budget_id")
+		return
+	}
+
+	asOf, err := parseAsOfParam(r.URL.Query().Get("asOf"))
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	status, err := h.Service.GetBudgetStatus(r.Context(), budgetID, asOf)
+	if err != nil {
+		h.handleServiceError(w, err, "GetBudgetStatus")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// parseBudget converts and validates a budget DTO, writes a 400 response and returns false if it is invalid
+func parseBudget(w http.ResponseWriter, budgetDto models.BudgetDto) (*models.Budget, bool) {
+	budget, err := models.FromAPIBudget(budgetDto)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid budget data: "+err.Error())
+		return nil, false
+	}
+	if err := budget.Validate(); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid budget data: "+err.Error())
+		return nil, false
+	}
+	return budget, true
+}
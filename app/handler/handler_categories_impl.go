@@ -39,17 +39,31 @@ func (h *HandlerImpl) CreateCategory(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HandlerImpl) ListCategories(w http.ResponseWriter, r *http.Request) {
+	order, err := parseSortOrder(r.URL.Query())
+	if err != nil {
+		writeError(w, err, "ListCategories")
+		return
+	}
 	filter := models.ListCategoriesInput{
 		UserID:  r.URL.Query().Get("userId"),
 		GroupBy: r.URL.Query().Get("groupBy"),
-		SortBy:  r.URL.Query().Get("sortBy"),
-		Order:   r.URL.Query().Get("order"),
+		SortBy:  parseSortBy(r.URL.Query(), "sortBy"),
+		Order:   order,
 	}
 	if limit := r.URL.Query().Get("limit"); limit != "" {
 		if n, err := helpers.ParseInt(limit); err == nil {
 			filter.Limit = n
 		}
 	}
+
+	// Parse pagination cursor from a previous page, if provided
+	cursor, err := h.parsePageCursor(r.URL.Query(), filter.SortBy, filter.Order)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid parameter 'nextKey': "+err.Error())
+		return
+	}
+	filter.Cursor = cursor
+
 	results, err := h.Service.ListCategories(r.Context(), filter)
 	if err != nil {
 		h.handleServiceError(w, err, "ListCategories")
@@ -67,8 +81,12 @@ func (h *HandlerImpl) ListCategories(w http.ResponseWriter, r *http.Request) {
 		for i, category := range results {
 			categoryDtos[i] = models.ToAPICategory(&category)
 		}
-		// Use paginated response structure (without actual pagination for now)
-		WriteJSONListResponse(w, categoryDtos, "")
+		// Cursor for the next page points after the last returned category (only when a limit was requested)
+		nextKey := ""
+		if len(results) > 0 {
+			nextKey = h.encodeNextKey(models.CategoryCursor(&results[len(results)-1], filter.SortBy, filter.Order), len(results), filter.Limit)
+		}
+		WriteJSONListResponse(w, categoryDtos, nextKey)
 	}
 }
 
@@ -82,11 +100,6 @@ func (h *HandlerImpl) GetCategory(w http.ResponseWriter, r *http.Request) {
 
 	category, err := h.Service.GetCategory(r.Context(), categoryID)
 	if err != nil {
-		// Try to handle as "not found" error first
-		if h.handleNotFoundError(w, err, "category", categoryID) {
-			return
-		}
-		// Handle all other errors (including database connection errors)
 		h.handleServiceError(w, err, "GetCategory")
 		return
 	}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// Categorization rule handlers
+func (h *HandlerImpl) CreateCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	var ruleDto models.CategorizationRuleDto
+	if err := json.NewDecoder(r.Body).Decode(&ruleDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	ruleDto.RuleID = "" // Generated by the service
+
+	rule, ok := parseCategorizationRule(w, ruleDto)
+	if !ok {
+		return
+	}
+
+	created, err := h.Service.CreateCategorizationRule(r.Context(), *rule)
+	if err != nil {
+		h.handleServiceError(w, err, "CreateCategorizationRule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.ToAPICategorizationRule(created))
+}
+
+func (h *HandlerImpl) ListCategorizationRules(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ListCategorizationRulesInput{
+		GroupID: query.Get("groupId"),
+		UserID:  query.Get("userId"),
+	}
+	if includeDisabledStr := query.Get("includeDisabled"); includeDisabledStr != "" {
+		if includeDisabled, err := strconv.ParseBool(includeDisabledStr); err == nil {
+			filter.IncludeDisabled = includeDisabled
+		}
+	}
+
+	results, err := h.Service.ListCategorizationRules(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err, "ListCategorizationRules")
+		return
+	}
+
+	// Convert to DTOs for response
+	ruleDtos := make([]models.CategorizationRuleDto, len(results))
+	for i := range results {
+		ruleDtos[i] = models.ToAPICategorizationRule(&results[i])
+	}
+
+	WriteJSONListResponse(w, ruleDtos, "")
+}
+
+func (h *HandlerImpl) GetCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["rule_id"]
+	if ruleID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing rule_id")
+		return
+	}
+
+	rule, err := h.Service.GetCategorizationRule(r.Context(), ruleID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetCategorizationRule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPICategorizationRule(rule))
+}
+
+func (h *HandlerImpl) UpdateCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["rule_id"]
+	var ruleDto models.CategorizationRuleDto
+	if err := json.NewDecoder(r.Body).Decode(&ruleDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	// Parse rule ID and set it in DTO
+	id, err := uuid.Parse(ruleID)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid rule ID format")
+		return
+	}
+	ruleDto.RuleID = id.String()
+
+	rule, ok := parseCategorizationRule(w, ruleDto)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Service.UpdateCategorizationRule(r.Context(), *rule)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateCategorizationRule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPICategorizationRule(updated))
+}
+
+func (h *HandlerImpl) DeleteCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["rule_id"]
+	if ruleID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing rule_id")
+		return
+	}
+
+	if err := h.Service.DeleteCategorizationRule(r.Context(), ruleID); err != nil {
+		h.handleServiceError(w, err, "DeleteCategorizationRule")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerImpl) ApplyCategorizationRules(w http.ResponseWriter, r *http.Request) {
+	var applyDto models.ApplyCategorizationRulesDto
+	if err := json.NewDecoder(r.Body).Decode(&applyDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	input, err := models.FromAPIApplyCategorizationRules(applyDto)
+	if err != nil {
+		writeInvalidRequest(w, "Invalid rule application data", err)
+		return
+	}
+
+	report, err := h.Service.ApplyCategorizationRules(r.Context(), *input)
+	if err != nil {
+		h.handleServiceError(w, err, "ApplyCategorizationRules")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseCategorizationRule converts and validates a categorization rule DTO, writes a 400 response and returns false
+// if it is invalid
+func parseCategorizationRule(w http.ResponseWriter, ruleDto models.CategorizationRuleDto) (*models.CategorizationRule, bool) {
+	rule, err := models.FromAPICategorizationRule(ruleDto)
+	if err != nil {
+		writeInvalidRequest(w, "Invalid categorization rule data", err)
+		return nil, false
+	}
+	if err := rule.Validate(); err != nil {
+		writeInvalidRequest(w, "Invalid categorization rule data", err)
+		return nil, false
+	}
+	return rule, true
+}
@@ -39,9 +39,14 @@ func (h *HandlerImpl) CreateCategoryGroup(w http.ResponseWriter, r *http.Request
 }
 
 func (h *HandlerImpl) ListCategoryGroups(w http.ResponseWriter, r *http.Request) {
+	order, err := parseSortOrder(r.URL.Query())
+	if err != nil {
+		writeError(w, err, "ListCategoryGroups")
+		return
+	}
 	filter := models.ListCategoryGroupsInput{
-		SortBy: r.URL.Query().Get("sortBy"),
-		Order:  r.URL.Query().Get("order"),
+		SortBy: parseSortBy(r.URL.Query(), "sortBy"),
+		Order:  order,
 	}
 	if limit := r.URL.Query().Get("limit"); limit != "" {
 		if n, err := helpers.ParseInt(limit); err == nil {
@@ -75,11 +80,6 @@ func (h *HandlerImpl) GetCategoryGroup(w http.ResponseWriter, r *http.Request) {
 
 	categoryGroup, err := h.Service.GetCategoryGroup(r.Context(), categoryGroupID)
 	if err != nil {
-		// Try to handle as "not found" error first
-		if h.handleNotFoundError(w, err, "category group", categoryGroupID) {
-			return
-		}
-		// Handle all other errors (including database connection errors)
 		h.handleServiceError(w, err, "GetCategoryGroup")
 		return
 	}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// Group handlers
+func (h *HandlerImpl) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var groupDto models.GroupDto
+	if err := json.NewDecoder(r.Body).Decode(&groupDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(groupDto.Name) == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Group name is required")
+		return
+	}
+
+	// Convert DTO to DAO model
+	group, err := models.FromAPIGroup(groupDto)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid group data: "+err.Error())
+		return
+	}
+
+	created, err := h.Service.CreateGroup(r.Context(), *group)
+	if err != nil {
+		h.handleServiceError(w, err, "CreateGroup")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toAPIGroupForCaller(r, created))
+}
+
+func (h *HandlerImpl) ListGroups(w http.ResponseWriter, r *http.Request) {
+	results, err := h.Service.ListGroups(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err, "ListGroups")
+		return
+	}
+
+	// Convert to DTOs for response
+	groupDtos := make([]models.GroupDto, len(results))
+	for i := range results {
+		groupDtos[i] = toAPIGroupForCaller(r, &results[i])
+	}
+
+	WriteJSONListResponse(w, groupDtos, "")
+}
+
+func (h *HandlerImpl) GetGroup(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["group_id"]
+	if groupID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing group_id")
+		return
+	}
+
+	group, err := h.Service.GetGroup(r.Context(), groupID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetGroup")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAPIGroupForCaller(r, group))
+}
+
+func (h *HandlerImpl) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["group_id"]
+	var groupDto models.GroupDto
+	if err := json.NewDecoder(r.Body).Decode(&groupDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(groupDto.Name) == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Group name is required")
+		return
+	}
+
+	// Parse group ID and set it in DTO
+	id, err := uuid.Parse(groupID)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid group ID format")
+		return
+	}
+	groupDto.GroupID = id.String()
+
+	group, err := models.FromAPIGroup(groupDto)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid group data: "+err.Error())
+		return
+	}
+
+	updated, err := h.Service.UpdateGroup(r.Context(), *group)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateGroup")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAPIGroupForCaller(r, updated))
+}
+
+func (h *HandlerImpl) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["group_id"]
+	if groupID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing group_id")
+		return
+	}
+
+	if err := h.Service.DeleteGroup(r.Context(), groupID); err != nil {
+		h.handleServiceError(w, err, "DeleteGroup")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Group member handlers
+func (h *HandlerImpl) UpdateGroupMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID := vars["group_id"]
+	userID := vars["user_id"]
+
+	var memberDto models.GroupMemberDto
+	if err := json.NewDecoder(r.Body).Decode(&memberDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if !models.IsValidGroupRole(memberDto.Role) {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid role, must be one of: owner, editor, viewer")
+		return
+	}
+
+	member, err := h.Service.UpdateGroupMember(r.Context(), groupID, userID, memberDto.Role)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateGroupMember")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIGroupMember(member))
+}
+
+func (h *HandlerImpl) RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID := vars["group_id"]
+	userID := vars["user_id"]
+
+	if err := h.Service.RemoveGroupMember(r.Context(), groupID, userID); err != nil {
+		h.handleServiceError(w, err, "RemoveGroupMember")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Group invitation handlers
+func (h *HandlerImpl) CreateGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["group_id"]
+	groupUUID, err := uuid.Parse(groupID)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid group ID format")
+		return
+	}
+
+	var invitationDto models.CreateGroupInvitationDto
+	if err := json.NewDecoder(r.Body).Decode(&invitationDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	userUUID, err := uuid.Parse(invitationDto.UserID)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid user ID format")
+		return
+	}
+	if !models.IsValidGroupRole(invitationDto.Role) {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid role, must be one of: owner, editor, viewer")
+		return
+	}
+
+	created, err := h.Service.CreateGroupInvitation(r.Context(), models.GroupInvitation{
+		GroupID: groupUUID,
+		UserID:  userUUID,
+		Role:    invitationDto.Role,
+	})
+	if err != nil {
+		h.handleServiceError(w, err, "CreateGroupInvitation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.ToAPIGroupInvitation(created))
+}
+
+func (h *HandlerImpl) ListGroupInvitations(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["group_id"]
+
+	results, err := h.Service.ListGroupInvitations(r.Context(), groupID)
+	if err != nil {
+		h.handleServiceError(w, err, "ListGroupInvitations")
+		return
+	}
+
+	WriteJSONListResponse(w, toAPIGroupInvitations(results), "")
+}
+
+func (h *HandlerImpl) RevokeGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID := vars["group_id"]
+	invitationID := vars["invitation_id"]
+
+	if err := h.Service.RevokeGroupInvitation(r.Context(), groupID, invitationID); err != nil {
+		h.handleServiceError(w, err, "RevokeGroupInvitation")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HandlerImpl) ListMyGroupInvitations(w http.ResponseWriter, r *http.Request) {
+	results, err := h.Service.ListMyGroupInvitations(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err, "ListMyGroupInvitations")
+		return
+	}
+
+	WriteJSONListResponse(w, toAPIGroupInvitations(results), "")
+}
+
+func (h *HandlerImpl) AcceptGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationID := mux.Vars(r)["invitation_id"]
+
+	member, err := h.Service.AcceptGroupInvitation(r.Context(), invitationID)
+	if err != nil {
+		h.handleServiceError(w, err, "AcceptGroupInvitation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIGroupMember(member))
+}
+
+func (h *HandlerImpl) DeclineGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationID := mux.Vars(r)["invitation_id"]
+
+	if err := h.Service.DeclineGroupInvitation(r.Context(), invitationID); err != nil {
+		h.handleServiceError(w, err, "DeclineGroupInvitation")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toAPIGroupForCaller converts a group to its DTO with the role of the calling user
+func toAPIGroupForCaller(r *http.Request, group *models.Group) models.GroupDto {
+	role := ""
+	if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+		for _, member := range group.Members {
+			if member.UserID == principal.UserID {
+				role = member.Role
+				break
+			}
+		}
+	}
+	return models.ToAPIGroup(group, role)
+}
+
+func toAPIGroupInvitations(invitations []models.GroupInvitation) []models.GroupInvitationDto {
+	invitationDtos := make([]models.GroupInvitationDto, len(invitations))
+	for i := range invitations {
+		invitationDtos[i] = models.ToAPIGroupInvitation(&invitations[i])
+	}
+	return invitationDtos
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"github.com/savak1990/transactions-service/app/config"
 	"github.com/savak1990/transactions-service/app/service"
 )
 
@@ -8,10 +9,22 @@ import (
 // Add a field for the service dependency
 type HandlerImpl struct {
 	Service service.Service
+
+	cursorSigningKey []byte // Secret used to sign and verify pagination cursors
+	retentionDays    int    // Default retention of soft-deleted data for the purge job
 }
 
 func NewHandlerImpl(svc service.Service) *HandlerImpl {
 	return &HandlerImpl{Service: svc}
 }
 
+// NewHandlerImplWithConfig creates a new HandlerImpl using application configuration
+func NewHandlerImplWithConfig(svc service.Service, cfg config.AppConfig) *HandlerImpl {
+	return &HandlerImpl{
+		Service:          svc,
+		cursorSigningKey: []byte(cfg.CursorSigningKey),
+		retentionDays:    cfg.RetentionDays,
+	}
+}
+
 var _ Handler = (*HandlerImpl)(nil)
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// maxImportBodySize bounds the request body of an import, about 10 MB of statement content once base64 encoded
+const maxImportBodySize = 16 << 20
+
+// POST /imports
+func (h *HandlerImpl) ImportTransactions(w http.ResponseWriter, r *http.Request) {
+	var requestDto models.ImportRequestDto
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBodySize)).Decode(&requestDto); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			WriteJSONError(w, http.StatusRequestEntityTooLarge, models.ErrorCodeBadRequest, "Import file is too large, split it into smaller files")
+			return
+		}
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	input, err := models.FromAPIImportRequest(requestDto)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid import data: "+err.Error())
+		return
+	}
+
+	report, err := h.Service.ImportTransactions(r.Context(), *input)
+	if err != nil {
+		h.handleServiceError(w, err, "ImportTransactions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.DryRun && report.Created > 0 {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// Import profile handlers
+func (h *HandlerImpl) CreateImportProfile(w http.ResponseWriter, r *http.Request) {
+	var profileDto models.ImportProfileDto
+	if err := json.NewDecoder(r.Body).Decode(&profileDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	profileDto.ImportProfileID = "" // Generated by the service
+
+	profile, ok := parseImportProfile(w, profileDto)
+	if !ok {
+		return
+	}
+
+	created, err := h.Service.CreateImportProfile(r.Context(), *profile)
+	if err != nil {
+		h.handleServiceError(w, err, "CreateImportProfile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.ToAPIImportProfile(created))
+}
+
+func (h *HandlerImpl) ListImportProfiles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ListImportProfilesInput{
+		GroupID: query.Get("groupId"),
+		UserID:  query.Get("userId"),
+	}
+
+	results, err := h.Service.ListImportProfiles(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err, "ListImportProfiles")
+		return
+	}
+
+	// Convert to DTOs for response
+	profileDtos := make([]models.ImportProfileDto, len(results))
+	for i := range results {
+		profileDtos[i] = models.ToAPIImportProfile(&results[i])
+	}
+
+	WriteJSONListResponse(w, profileDtos, "")
+}
+
+func (h *HandlerImpl) GetImportProfile(w http.ResponseWriter, r *http.Request) {
+	importProfileID := mux.Vars(r)["import_profile_id"]
+	if importProfileID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing import_profile_id")
+		return
+	}
+
+	profile, err := h.Service.GetImportProfile(r.Context(), importProfileID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetImportProfile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIImportProfile(profile))
+}
+
+func (h *HandlerImpl) UpdateImportProfile(w http.ResponseWriter, r *http.Request) {
+	importProfileID := mux.Vars(r)["import_profile_id"]
+	var profileDto models.ImportProfileDto
+	if err := json.NewDecoder(r.Body).Decode(&profileDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	// Parse import profile ID and set it in DTO
+	id, err := uuid.Parse(importProfileID)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid import profile ID format")
+		return
+	}
+	profileDto.ImportProfileID = id.String()
+
+	profile, ok := parseImportProfile(w, profileDto)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Service.UpdateImportProfile(r.Context(), *profile)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateImportProfile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIImportProfile(updated))
+}
+
+func (h *HandlerImpl) DeleteImportProfile(w http.ResponseWriter, r *http.Request) {
+	importProfileID := mux.Vars(r)["import_profile_id"]
+	if importProfileID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing import_profile_id")
+		return
+	}
+
+	if err := h.Service.DeleteImportProfile(r.Context(), importProfileID); err != nil {
+		h.handleServiceError(w, err, "DeleteImportProfile")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseImportProfile converts and validates an import profile DTO, writes a 400 response and returns false if it
+// is invalid
+func parseImportProfile(w http.ResponseWriter, profileDto models.ImportProfileDto) (*models.ImportProfile, bool) {
+	profile, err := models.FromAPIImportProfile(profileDto)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid import profile data: "+err.Error())
+		return nil, false
+	}
+	if err := profile.Validate(); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid import profile data: "+err.Error())
+		return nil, false
+	}
+	return profile, true
+}
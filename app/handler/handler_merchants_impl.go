@@ -3,7 +3,6 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -28,12 +27,6 @@ func (h *HandlerImpl) CreateMerchant(w http.ResponseWriter, r *http.Request) {
 
 	created, err := h.Service.CreateMerchant(r.Context(), *merchant)
 	if err != nil {
-		// Check if it's a duplicate merchant error
-		errorMsg := err.Error()
-		if strings.Contains(errorMsg, "already exists for this user") {
-			WriteJSONError(w, http.StatusConflict, models.ErrorCodeConflict, err.Error())
-			return
-		}
 		h.handleServiceError(w, err, "CreateMerchant")
 		return
 	}
@@ -46,19 +39,33 @@ func (h *HandlerImpl) CreateMerchant(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HandlerImpl) ListMerchants(w http.ResponseWriter, r *http.Request) {
+	order, err := parseSortOrder(r.URL.Query())
+	if err != nil {
+		writeError(w, err, "ListMerchants")
+		return
+	}
 	filter := models.ListMerchantsInput{
 		GroupID: r.URL.Query().Get("groupId"),
 		UserID:  r.URL.Query().Get("userId"),
 		Name:    r.URL.Query().Get("name"),
-		SortBy:  r.URL.Query().Get("sortBy"),
-		Order:   r.URL.Query().Get("order"),
+		SortBy:  parseSortBy(r.URL.Query(), "sortBy"),
+		Order:   order,
 	}
+	filter.Limit = 50 // default page size
 	if count := r.URL.Query().Get("limit"); count != "" {
-		if n, err := helpers.ParseInt(count); err == nil {
+		if n, err := helpers.ParseInt(count); err == nil && n > 0 && n <= 100 {
 			filter.Limit = n
 		}
 	}
 
+	// Parse pagination cursor from a previous page, if provided
+	cursor, err := h.parsePageCursor(r.URL.Query(), filter.SortBy, filter.Order)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid parameter 'nextKey': "+err.Error())
+		return
+	}
+	filter.Cursor = cursor
+
 	results, err := h.Service.ListMerchants(r.Context(), filter)
 	if err != nil {
 		h.handleServiceError(w, err, "ListMerchants")
@@ -71,8 +78,13 @@ func (h *HandlerImpl) ListMerchants(w http.ResponseWriter, r *http.Request) {
 		merchantDtos[i] = models.ToAPIMerchant(&merchant)
 	}
 
-	// Use paginated response structure (without actual pagination for now)
-	WriteJSONListResponse(w, merchantDtos, "")
+	// Cursor for the next page points after the last returned merchant
+	nextKey := ""
+	if len(results) > 0 {
+		nextKey = h.encodeNextKey(models.MerchantCursor(&results[len(results)-1], filter.SortBy, filter.Order), len(results), filter.Limit)
+	}
+
+	WriteJSONListResponse(w, merchantDtos, nextKey)
 }
 
 func (h *HandlerImpl) GetMerchant(w http.ResponseWriter, r *http.Request) {
@@ -85,11 +97,6 @@ func (h *HandlerImpl) GetMerchant(w http.ResponseWriter, r *http.Request) {
 
 	merchant, err := h.Service.GetMerchant(r.Context(), merchantID)
 	if err != nil {
-		// Try to handle as "not found" error first
-		if h.handleNotFoundError(w, err, "merchant", merchantID) {
-			return
-		}
-		// Handle all other errors (including database connection errors)
 		h.handleServiceError(w, err, "GetMerchant")
 		return
 	}
@@ -102,5 +102,179 @@ func (h *HandlerMock) DeleteMerchantsByUserId(w http.ResponseWriter, r *http.Req
 func (h *HandlerMock) GetTransactionStats(w http.ResponseWriter, r *http.Request) {
 	h.Called(w, r)
 }
+func (h *HandlerMock) ReconvertEntryAmounts(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListGroups(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetGroup(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateGroupMember(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) CreateGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListGroupInvitations(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RevokeGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListMyGroupInvitations(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) AcceptGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeclineGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetBudget(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) CreateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListRecurringTransactions(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeleteRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RunRecurringTransactions(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ImportTransactions(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) CreateImportProfile(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListImportProfiles(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetImportProfile(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateImportProfile(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeleteImportProfile(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListDuplicateTransactions(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateTransfer(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeleteTransfer(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetOperation(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateOperation(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeleteOperation(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RestoreTransaction(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RestoreBalance(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RestoreCategoryGroup(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) RestoreMerchant(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) PurgeDeletedData(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) EraseUserData(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetTransactionHistory(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) CreateCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ListCategorizationRules(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) UpdateCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) DeleteCategorizationRule(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) ApplyCategorizationRules(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
+func (h *HandlerMock) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	h.Called(w, r)
+}
 
 var _ Handler = (*HandlerMock)(nil)
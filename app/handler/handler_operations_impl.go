@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+// GET /operations/{operation_id}
+func (h *HandlerImpl) GetOperation(w http.ResponseWriter, r *http.Request) {
+	operationID := mux.Vars(r)["operation_id"]
+	if operationID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing operation_id")
+		return
+	}
+
+	transactions, err := h.Service.GetOperation(r.Context(), operationID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetOperation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPICreateTransactionsResponse(transactions, &operationID))
+}
+
+// PUT /operations/{operation_id}
+func (h *HandlerImpl) UpdateOperation(w http.ResponseWriter, r *http.Request) {
+	operationID := mux.Vars(r)["operation_id"]
+	if operationID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing operation_id")
+		return
+	}
+
+	var operationReq models.CreateTransactionsRequestDto
+	if err := json.NewDecoder(r.Body).Decode(&operationReq); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if len(operationReq.Transactions) == 0 || len(operationReq.Transactions) > 5 {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "An operation must contain between 1 and 5 transactions")
+		return
+	}
+
+	// The same movement rules as for batch creation apply to the replacement transactions
+	if err := h.validateMovementTransactions(operationReq.Transactions); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	transactions, err := models.FromAPICreateTransactionsRequest(operationReq)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to convert operation request DTOs")
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request: "+err.Error())
+		return
+	}
+
+	updated, err := h.Service.UpdateOperation(r.Context(), operationID, transactions)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateOperation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPICreateTransactionsResponse(updated, &operationID))
+}
+
+// DELETE /operations/{operation_id}
+func (h *HandlerImpl) DeleteOperation(w http.ResponseWriter, r *http.Request) {
+	operationID := mux.Vars(r)["operation_id"]
+	if operationID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing operation_id")
+		return
+	}
+
+	if err := h.Service.DeleteOperation(r.Context(), operationID); err != nil {
+		h.handleServiceError(w, err, "DeleteOperation")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// Recurring transaction handlers
+func (h *HandlerImpl) CreateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	var recurringDto models.RecurringTransactionDto
+	if err := json.NewDecoder(r.Body).Decode(&recurringDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	recurringDto.RecurringTransactionID = "" // Generated by the service
+
+	recurring, ok := parseRecurringTransaction(w, recurringDto)
+	if !ok {
+		return
+	}
+
+	created, err := h.Service.CreateRecurringTransaction(r.Context(), *recurring)
+	if err != nil {
+		h.handleServiceError(w, err, "CreateRecurringTransaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.ToAPIRecurringTransaction(created))
+}
+
+func (h *HandlerImpl) ListRecurringTransactions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ListRecurringTransactionsInput{
+		GroupID:   query.Get("groupId"),
+		UserID:    query.Get("userId"),
+		BalanceID: query.Get("balanceId"),
+	}
+
+	results, err := h.Service.ListRecurringTransactions(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err, "ListRecurringTransactions")
+		return
+	}
+
+	// Convert to DTOs for response
+	recurringDtos := make([]models.RecurringTransactionDto, len(results))
+	for i := range results {
+		recurringDtos[i] = models.ToAPIRecurringTransaction(&results[i])
+	}
+
+	WriteJSONListResponse(w, recurringDtos, "")
+}
+
+func (h *HandlerImpl) GetRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	recurringTransactionID := mux.Vars(r)["recurring_transaction_id"]
+	if recurringTransactionID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing recurring_transaction_id")
+		return
+	}
+
+	recurring, err := h.Service.GetRecurringTransaction(r.Context(), recurringTransactionID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetRecurringTransaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIRecurringTransaction(recurring))
+}
+
+func (h *HandlerImpl) UpdateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	recurringTransactionID := mux.Vars(r)["recurring_transaction_id"]
+	var recurringDto models.RecurringTransactionDto
+	if err := json.NewDecoder(r.Body).Decode(&recurringDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	// Parse recurring transaction ID and set it in DTO
+	id, err := uuid.Parse(recurringTransactionID)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid recurring transaction ID format")
+		return
+	}
+	recurringDto.RecurringTransactionID = id.String()
+
+	recurring, ok := parseRecurringTransaction(w, recurringDto)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Service.UpdateRecurringTransaction(r.Context(), *recurring)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateRecurringTransaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIRecurringTransaction(updated))
+}
+
+func (h *HandlerImpl) DeleteRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	recurringTransactionID := mux.Vars(r)["recurring_transaction_id"]
+	if recurringTransactionID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing recurring_transaction_id")
+		return
+	}
+
+	if err := h.Service.DeleteRecurringTransaction(r.Context(), recurringTransactionID); err != nil {
+		h.handleServiceError(w, err, "DeleteRecurringTransaction")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseRecurringTransaction converts and validates a recurring transaction DTO, writes a 400 response and returns
+// false if it is invalid
+func parseRecurringTransaction(w http.ResponseWriter, recurringDto models.RecurringTransactionDto) (*models.RecurringTransaction, bool) {
+	recurring, err := models.FromAPIRecurringTransaction(recurringDto)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid recurring transaction data: "+err.Error())
+		return nil, false
+	}
+	if err := recurring.Validate(); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid recurring transaction data: "+err.Error())
+		return nil, false
+	}
+	return recurring, true
+}
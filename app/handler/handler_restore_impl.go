@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// POST /transactions/{transaction_id}/restore
+func (h *HandlerImpl) RestoreTransaction(w http.ResponseWriter, r *http.Request) {
+	transactionID := mux.Vars(r)["transaction_id"]
+	if transactionID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing transaction_id")
+		return
+	}
+
+	restored, err := h.Service.RestoreTransaction(r.Context(), transactionID)
+	if err != nil {
+		h.handleServiceError(w, err, "RestoreTransaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restored)
+}
+
+// POST /balances/{balance_id}/restore
+func (h *HandlerImpl) RestoreBalance(w http.ResponseWriter, r *http.Request) {
+	balanceID := mux.Vars(r)["balance_id"]
+	if balanceID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing balance_id")
+		return
+	}
+
+	restored, err := h.Service.RestoreBalance(r.Context(), balanceID)
+	if err != nil {
+		h.handleServiceError(w, err, "RestoreBalance")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIBalance(restored))
+}
+
+// POST /categories/{category_id}/restore
+func (h *HandlerImpl) RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := mux.Vars(r)["category_id"]
+	if categoryID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing category_id")
+		return
+	}
+
+	restored, err := h.Service.RestoreCategory(r.Context(), categoryID)
+	if err != nil {
+		h.handleServiceError(w, err, "RestoreCategory")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPICategory(restored))
+}
+
+// POST /categoryGroups/{category_group_id}/restore
+func (h *HandlerImpl) RestoreCategoryGroup(w http.ResponseWriter, r *http.Request) {
+	categoryGroupID := mux.Vars(r)["category_group_id"]
+	if categoryGroupID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing category_group_id")
+		return
+	}
+
+	restored, err := h.Service.RestoreCategoryGroup(r.Context(), categoryGroupID)
+	if err != nil {
+		h.handleServiceError(w, err, "RestoreCategoryGroup")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPICategoryGroup(restored))
+}
+
+// POST /merchants/{merchant_id}/restore
+func (h *HandlerImpl) RestoreMerchant(w http.ResponseWriter, r *http.Request) {
+	merchantID := mux.Vars(r)["merchant_id"]
+	if merchantID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing merchant_id")
+		return
+	}
+
+	restored, err := h.Service.RestoreMerchant(r.Context(), merchantID)
+	if err != nil {
+		h.handleServiceError(w, err, "RestoreMerchant")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPIMerchant(restored))
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// GET /suggestions
+func (h *HandlerImpl) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := models.SuggestionsInput{
+		UserID:      query.Get("userId"),
+		Description: query.Get("description"),
+	}
+
+	if merchantID := query.Get("merchantId"); merchantID != "" {
+		parsed, err := uuid.Parse(merchantID)
+		if err != nil {
+			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid merchantId format: "+merchantID)
+			return
+		}
+		input.MerchantID = &parsed
+	}
+
+	if amount := query.Get("amount"); amount != "" {
+		parsed, err := strconv.ParseInt(amount, 10, 64)
+		if err != nil {
+			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid amount, must be an integer in cents")
+			return
+		}
+		input.Amount = &parsed
+	}
+
+	// Parse limit, the service applies the default and the maximum
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid limit, must be a positive integer")
+			return
+		}
+		input.Limit = parsed
+	}
+
+	suggestions, err := h.Service.GetSuggestions(r.Context(), input)
+	if err != nil {
+		h.handleServiceError(w, err, "GetSuggestions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestions)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// GET /transactions/duplicates
+func (h *HandlerImpl) ListDuplicateTransactions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ListDuplicateTransactionsInput{
+		UserID:  query.Get("userId"),
+		GroupID: query.Get("groupId"),
+	}
+
+	// Parse multiple balanceIds
+	if balanceIds := ParseQueryStringArray(query, "balanceId"); len(balanceIds) > 0 {
+		for _, balanceId := range balanceIds {
+			if _, err := uuid.Parse(balanceId); err != nil {
+				WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid balanceId format: "+balanceId)
+				return
+			}
+		}
+		filter.BalanceIds = balanceIds
+	}
+
+	// Parse startTime
+	if startTime := query.Get("startTime"); startTime != "" {
+		parsed, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid startTime format, must be RFC3339")
+			return
+		}
+		filter.StartTime = parsed
+	}
+
+	// Parse endTime
+	if endTime := query.Get("endTime"); endTime != "" {
+		parsed, err := time.Parse(time.RFC3339, endTime)
+		if err != nil {
+			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid endTime format, must be RFC3339")
+			return
+		}
+		filter.EndTime = parsed
+	}
+
+	// Parse windowHours, the service applies the default and the maximum
+	if windowHours := query.Get("windowHours"); windowHours != "" {
+		hours, err := strconv.Atoi(windowHours)
+		if err != nil || hours <= 0 {
+			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid windowHours, must be a positive integer")
+			return
+		}
+		filter.Window = time.Duration(hours) * time.Hour
+	}
+
+	clusters, err := h.Service.ListDuplicateTransactions(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err, "ListDuplicateTransactions")
+		return
+	}
+
+	WriteJSONListResponse(w, clusters, "")
+}
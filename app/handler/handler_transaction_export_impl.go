@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/helpers"
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// GET /transactions/export
+func (h *HandlerImpl) ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, err := parseListTransactionsFilter(query)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	format := strings.ToLower(query.Get("format"))
+	if format == "" {
+		format = models.ExportFormatCSV
+	}
+	contentType, ok := models.ExportContentTypes[format]
+	if !ok {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid parameter 'format': must be one of csv, jsonl, xlsx")
+		return
+	}
+
+	currency := query.Get("currency")
+	if currency != "" && !helpers.IsValidCurrencyCode(currency) {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid currency format")
+		return
+	}
+
+	// The response is started with the first page, so errors that happen before it (authorization, database
+	// unavailable) are still reported as JSON errors
+	var writer models.TransactionExportWriter
+	start := func() error {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="transactions-%s.%s"`, time.Now().UTC().Format("20060102"), format))
+		w.WriteHeader(http.StatusOK)
+		writer, err = models.NewTransactionExportWriter(w, format, currency)
+		return err
+	}
+
+	flusher := http.NewResponseController(w)
+	exported := 0
+	err = h.Service.ExportTransactionEntries(r.Context(), filter, func(entries []models.TransactionEntry) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for i := range entries {
+			if err := writer.WriteEntry(models.ToAPITransactionEntry(&entries[i])); err != nil {
+				return err
+			}
+		}
+		exported += len(entries)
+		flusher.Flush() // Not supported by every writer (Lambda buffers the response), the page is sent at the end then
+		return nil
+	})
+	if err != nil {
+		if writer == nil {
+			h.handleServiceError(w, err, "ExportTransactions")
+			return
+		}
+		// The status is already sent, leave the file incomplete so the client doesn't take it for a full export
+		log.WithError(err).WithField("exported", exported).Error("Transaction export failed after streaming started")
+		return
+	}
+
+	if writer == nil {
+		// No matching entries, send a file with the header only
+		if err := start(); err != nil {
+			log.WithError(err).Error("Failed to start transaction export")
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		log.WithError(err).WithField("exported", exported).Error("Failed to complete transaction export")
+	}
+}
@@ -108,6 +108,14 @@ func (h *HandlerImpl) GetTransactionStats(w http.ResponseWriter, r *http.Request
 		input.EndTime = &parsed
 	}
 
+	// Parse amount and attribute filters, shared with the transactions list
+	entryFilter, err := parseTransactionEntryFilter(query)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+	input.TransactionEntryFilter = entryFilter
+
 	// Get grouping query parameter
 	if grouping := query.Get("grouping"); grouping != "" {
 		switch grouping {
@@ -164,14 +172,14 @@ func (h *HandlerImpl) GetTransactionStats(w http.ResponseWriter, r *http.Request
 	}
 
 	// Parse order parameter
+	order, err := parseSortOrder(query)
+	if err != nil {
+		writeError(w, err, "GetTransactionStats")
+		return
+	}
 	input.Order = "desc"
-	if order := query.Get("order"); order != "" {
-		if order == "asc" || order == "desc" {
-			input.Order = order
-		} else {
-			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid order format, must be 'asc' or 'desc'")
-			return
-		}
+	if order != "" {
+		input.Order = order
 	}
 
 	// Parse displayCurrency, nil if not provided
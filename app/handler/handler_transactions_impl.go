@@ -2,9 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -21,6 +24,14 @@ func (h *HandlerImpl) CreateTransaction(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Retries with the same Idempotency-Key replay the response instead of creating the transactions again
+	h.withIdempotency(w, r, rawBody, func(w http.ResponseWriter) {
+		h.createTransactions(w, r, rawBody)
+	})
+}
+
+// createTransactions creates a single transaction or a batch of transactions from the request body
+func (h *HandlerImpl) createTransactions(w http.ResponseWriter, r *http.Request, rawBody json.RawMessage) {
 	// Try to parse as batch request first
 	var batchReq models.CreateTransactionsRequestDto
 	if err := json.Unmarshal(rawBody, &batchReq); err == nil && len(batchReq.Transactions) > 0 {
@@ -101,69 +112,120 @@ func (h *HandlerImpl) handleBatchTransactions(w http.ResponseWriter, r *http.Req
 
 // GET /transactions
 func (h *HandlerImpl) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseListTransactionsFilter(r.URL.Query())
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	filter.Limit = 50 // default page size
+	if count := r.URL.Query().Get("count"); count != "" {
+		// parse count as int
+		if n, err := helpers.ParseInt(count); err == nil && n > 0 && n <= 100 {
+			filter.Limit = n
+		}
+	}
+
+	// Parse pagination cursor from a previous page, if provided
+	cursor, err := h.parsePageCursor(r.URL.Query(), filter.SortBy, filter.Order)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid parameter 'nextKey': "+err.Error())
+		return
+	}
+	filter.Cursor = cursor
+
+	entries, err := h.Service.ListTransactionEntries(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err, "ListTransactionEntries")
+		return
+	}
+
+	// Convert to DTOs for response
+	entryDtos := make([]models.TransactionEntryDto, len(entries))
+	for i, entry := range entries {
+		entryDtos[i] = models.ToAPITransactionEntry(&entry)
+	}
+
+	// Cursor for the next page points after the last returned entry
+	nextKey := ""
+	if len(entries) > 0 {
+		lastEntry := entries[len(entries)-1]
+		nextKey = h.encodeNextKey(models.TransactionEntryCursor(&lastEntry, filter.SortBy, filter.Order), len(entries), filter.Limit)
+	}
+
+	WriteJSONListResponse(w, entryDtos, nextKey)
+}
+
+// parseListTransactionsFilter parses the filters shared by the transaction list and export endpoints
+func parseListTransactionsFilter(query url.Values) (models.ListTransactionsInput, error) {
+	order, err := parseSortOrder(query)
+	if err != nil {
+		return models.ListTransactionsInput{}, err
+	}
 	filter := models.ListTransactionsInput{
-		UserID:  r.URL.Query().Get("userId"),
-		GroupID: r.URL.Query().Get("groupId"),
-		SortBy:  r.URL.Query().Get("sortedBy"),
-		Order:   r.URL.Query().Get("order"),
+		UserID:  query.Get("userId"),
+		GroupID: query.Get("groupId"),
+		SortBy:  parseSortBy(query, "sortedBy"),
+		Order:   order,
 	}
 
 	// Parse types array from query parameter - support both formats:
 	// 1. type=expense,income (comma-separated)
 	// 2. type=expense&type=income (multiple parameters)
-	filter.BalanceIds = ParseQueryStringArray(r.URL.Query(), "balanceId")
-	filter.Types = ParseQueryStringArray(r.URL.Query(), "type")
-	filter.CategoryIds = ParseQueryStringArray(r.URL.Query(), "categoryId")
-	filter.CategoryGroupIds = ParseQueryStringArray(r.URL.Query(), "categoryGroupId")
-	filter.TransactionIds = ParseQueryStringArray(r.URL.Query(), "transactionId")
-	filter.MerchantIds = ParseQueryStringArray(r.URL.Query(), "merchantId")
-	filter.OperationIds = ParseQueryStringArray(r.URL.Query(), "operationId")
+	filter.BalanceIds = ParseQueryStringArray(query, "balanceId")
+	filter.Types = ParseQueryStringArray(query, "type")
+	filter.CategoryIds = ParseQueryStringArray(query, "categoryId")
+	filter.CategoryGroupIds = ParseQueryStringArray(query, "categoryGroupId")
+	filter.TransactionIds = ParseQueryStringArray(query, "transactionId")
+	filter.MerchantIds = ParseQueryStringArray(query, "merchantId")
+	filter.OperationIds = ParseQueryStringArray(query, "operationId")
 
 	// Parse startTime if provided
-	if startTimeStr := r.URL.Query().Get("startTime"); startTimeStr != "" {
+	if startTimeStr := query.Get("startTime"); startTimeStr != "" {
 		if startTime, err := time.Parse(time.RFC3339, startTimeStr); err == nil {
 			filter.StartTime = startTime
 		}
 	}
 
 	// Parse endTime if provided
-	if endTimeStr := r.URL.Query().Get("endTime"); endTimeStr != "" {
+	if endTimeStr := query.Get("endTime"); endTimeStr != "" {
 		if endTime, err := time.Parse(time.RFC3339, endTimeStr); err == nil {
 			filter.EndTime = endTime
 		}
 	}
 
 	// Parse includeDeleted parameter
-	if includeDeletedStr := r.URL.Query().Get("includeDeleted"); includeDeletedStr != "" {
+	if includeDeletedStr := query.Get("includeDeleted"); includeDeletedStr != "" {
 		if includeDeleted, err := strconv.ParseBool(includeDeletedStr); err == nil {
 			filter.IncludeDeleted = includeDeleted
 		} else {
-			WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid parameter 'includeDeleted': "+err.Error())
-			return
-		}
-	}
-
-	if count := r.URL.Query().Get("count"); count != "" {
-		// parse count as int
-		if n, err := helpers.ParseInt(count); err == nil {
-			filter.Limit = n
+			return filter, fmt.Errorf("Invalid parameter 'includeDeleted': %w", err)
 		}
 	}
 
-	entries, err := h.Service.ListTransactionEntries(r.Context(), filter)
+	// Parse amount and attribute filters
+	entryFilter, err := parseTransactionEntryFilter(query)
 	if err != nil {
-		h.handleServiceError(w, err, "ListTransactionEntries")
-		return
+		return filter, err
 	}
+	filter.TransactionEntryFilter = entryFilter
 
-	// Convert to DTOs for response
-	entryDtos := make([]models.TransactionEntryDto, len(entries))
-	for i, entry := range entries {
-		entryDtos[i] = models.ToAPITransactionEntry(&entry)
+	// Parse full-text search, results are sorted by relevance unless another sorting is requested
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		if len([]rune(q)) > models.MaxSearchQueryLength {
+			return filter, fmt.Errorf("Invalid parameter 'q': must be at most %d characters", models.MaxSearchQueryLength)
+		}
+		if filter.Search = models.BuildSearchQuery(q); filter.Search == "" {
+			return filter, errors.New("Invalid parameter 'q': must contain at least one word")
+		}
+		if filter.SortBy == "" {
+			filter.SortBy = models.SortByRelevance
+		}
+	} else if filter.SortBy == models.SortByRelevance {
+		return filter, errors.New("Invalid parameter 'sortedBy': relevance requires the q parameter")
 	}
 
-	// Use paginated response structure (without actual pagination for now)
-	WriteJSONListResponse(w, entryDtos, "")
+	return filter, nil
 }
 
 // GET /transactions/{transaction_id}
@@ -177,11 +239,6 @@ func (h *HandlerImpl) GetTransaction(w http.ResponseWriter, r *http.Request) {
 
 	singleTransactionDto, err := h.Service.GetTransaction(r.Context(), transactionID)
 	if err != nil {
-		// Try to handle as "not found" error first
-		if h.handleNotFoundError(w, err, "transaction", transactionID) {
-			return
-		}
-		// Handle all other errors (including database connection errors)
 		h.handleServiceError(w, err, "GetTransaction")
 		return
 	}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/repo"
+	"github.com/savak1990/transactions-service/app/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateTransactionRejectsInvalidFieldsAsProblem(t *testing.T) {
+	tests := []struct {
+		body  string
+		field string
+	}{
+		{`{"operationId":"op-1"}`, "operationId"},
+		{`{"approvedAt":"2024-03-15"}`, "approvedAt"},
+		{`{"transactedAt":"yesterday"}`, "transactedAt"},
+		{`{"transactionEntries":[{"id":"entry-1","amount":1000}]}`, "transactionEntries[0].id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			userID := uuid.New()
+			existing := &models.Transaction{ID: models.NewTransactionID(), UserID: userID, BalanceID: uuid.New(), Type: "expense"}
+			mockRepo := repo.NewMockRepository()
+			mockRepo.On("GetTransaction", mock.Anything, existing.ID.String()).Return(existing, nil)
+			mockRepo.On("FindTransferByTransactionID", mock.Anything, existing.ID.String()).Return(nil, nil)
+			mockRepo.On("GetBalance", mock.Anything, existing.BalanceID.String()).
+				Return(&models.Balance{ID: existing.BalanceID, UserID: userID, Currency: "EUR"}, nil)
+			h := NewHandlerImpl(service.NewServiceImpl(mockRepo, repo.NewExchangeRatesStaticDb()))
+
+			ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID})
+			r := httptest.NewRequest(http.MethodPut, "/transactions/"+existing.ID.String(), strings.NewReader(tt.body)).WithContext(ctx)
+			r = mux.SetURLVars(r, map[string]string{"transaction_id": existing.ID.String()})
+			recorder := httptest.NewRecorder()
+			h.UpdateTransaction(recorder, r)
+
+			if recorder.Code != http.StatusBadRequest || recorder.Header().Get("Content-Type") != problemContentType {
+				t.Fatalf("Expected a 400 problem, got %d %s: %s", recorder.Code, recorder.Header().Get("Content-Type"), recorder.Body.String())
+			}
+			var problem Problem
+			if err := json.NewDecoder(recorder.Body).Decode(&problem); err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+			if len(problem.Errors) != 1 || problem.Errors[0].Field != tt.field {
+				t.Errorf("Expected field %s to be reported, got %+v", tt.field, problem.Errors)
+			}
+			mockRepo.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything)
+		})
+	}
+}
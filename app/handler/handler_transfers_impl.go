@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// Transfer handlers
+func (h *HandlerImpl) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	input, ok := parseTransfer(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.Service.CreateTransfer(r.Context(), *input)
+	if err != nil {
+		h.handleServiceError(w, err, "CreateTransfer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.ToAPITransfer(created))
+}
+
+func (h *HandlerImpl) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	transferID := mux.Vars(r)["transfer_id"]
+	if transferID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing transfer_id")
+		return
+	}
+
+	transfer, err := h.Service.GetTransfer(r.Context(), transferID)
+	if err != nil {
+		h.handleServiceError(w, err, "GetTransfer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPITransfer(transfer))
+}
+
+func (h *HandlerImpl) UpdateTransfer(w http.ResponseWriter, r *http.Request) {
+	transferID := mux.Vars(r)["transfer_id"]
+	if transferID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing transfer_id")
+		return
+	}
+
+	input, ok := parseTransfer(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Service.UpdateTransfer(r.Context(), transferID, *input)
+	if err != nil {
+		h.handleServiceError(w, err, "UpdateTransfer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ToAPITransfer(updated))
+}
+
+func (h *HandlerImpl) DeleteTransfer(w http.ResponseWriter, r *http.Request) {
+	transferID := mux.Vars(r)["transfer_id"]
+	if transferID == "" {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Missing transfer_id")
+		return
+	}
+
+	if err := h.Service.DeleteTransfer(r.Context(), transferID); err != nil {
+		h.handleServiceError(w, err, "DeleteTransfer")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseTransfer decodes and validates a transfer request body, writes a 400 response and returns false if it is invalid
+func parseTransfer(w http.ResponseWriter, r *http.Request) (*models.TransferInput, bool) {
+	var transferDto models.TransferDto
+	if err := json.NewDecoder(r.Body).Decode(&transferDto); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid request body: "+err.Error())
+		return nil, false
+	}
+
+	input, err := models.FromAPITransfer(transferDto)
+	if err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid transfer data: "+err.Error())
+		return nil, false
+	}
+	if err := input.Validate(); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid transfer data: "+err.Error())
+		return nil, false
+	}
+	return input, true
+}
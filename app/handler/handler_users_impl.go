@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// GET /users/{user_id}/export
+func (h *HandlerImpl) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["user_id"]
+	if _, err := uuid.Parse(userID); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid user ID format")
+		return
+	}
+
+	data, err := h.Service.ExportUserData(r.Context(), userID)
+	if err != nil {
+		h.handleServiceError(w, err, "ExportUserData")
+		return
+	}
+
+	exportedAt := time.Now().UTC()
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%s-%s.zip"`, userID, exportedAt.Format("20060102")))
+	w.WriteHeader(http.StatusOK)
+	if err := models.WriteUserDataExport(w, data, exportedAt); err != nil {
+		// The status is already sent, the client gets an incomplete archive that fails to open
+		log.WithError(err).WithField("user_id", userID).Error("User data export failed after streaming started")
+	}
+}
+
+// DELETE /users/{user_id}
+func (h *HandlerImpl) EraseUserData(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["user_id"]
+	if _, err := uuid.Parse(userID); err != nil {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Invalid user ID format")
+		return
+	}
+
+	report, err := h.Service.EraseUserData(r.Context(), userID)
+	if err != nil {
+		h.handleServiceError(w, err, "EraseUserData")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+
+	"github.com/savak1990/transactions-service/app/helpers"
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
 )
 
 // ParseQueryStringArray parses query parameters that can be provided in multiple formats:
@@ -44,3 +51,122 @@ func ParseQueryStringArray(query url.Values, paramName string) []string {
 
 	return result
 }
+
+// legacySortKeys maps the snake_case sort keys accepted by earlier versions to the API field names, which are the sort
+// keys of the repositories and the pagination cursors
+var legacySortKeys = map[string]string{"created_at": "createdAt", "updated_at": "updatedAt"}
+
+// parseSortBy reads a sort key query parameter as API field name
+func parseSortBy(query url.Values, param string) string {
+	sortBy := strings.TrimSpace(query.Get(param))
+	if key, ok := legacySortKeys[sortBy]; ok {
+		return key
+	}
+	return sortBy
+}
+
+// parseSortOrder reads the optional order query parameter in lowercase, so the query and the pagination cursor agree
+// on the direction. Empty means the default order of the list.
+func parseSortOrder(query url.Values) (string, error) {
+	order := strings.ToLower(strings.TrimSpace(query.Get("order")))
+	if order != "" && order != "asc" && order != "desc" {
+		return "", &models.ValidationError{Field: "order", Reason: "must be asc or desc"}
+	}
+	return order, nil
+}
+
+// parsePageCursor decodes the optional nextKey query parameter and verifies that it was issued
+// for the same sorting as the current request. Returns nil if no cursor was provided.
+func (h *HandlerImpl) parsePageCursor(query url.Values, sortBy, order string) (*models.PageCursor, error) {
+	token := query.Get("nextKey")
+	if token == "" {
+		return nil, nil
+	}
+
+	cursor, err := models.DecodePageCursor(token, h.cursorSigningKey)
+	if err != nil {
+		return nil, err
+	}
+	if !cursor.Matches(sortBy, order) {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", models.ErrInvalidCursor)
+	}
+	return cursor, nil
+}
+
+// encodeNextKey returns the signed cursor for the next page, or an empty string if the page
+// was not full and therefore there are no more items to fetch
+func (h *HandlerImpl) encodeNextKey(cursor models.PageCursor, itemCount, limit int) string {
+	if limit <= 0 || itemCount < limit {
+		return ""
+	}
+
+	nextKey, err := models.EncodePageCursor(cursor, h.cursorSigningKey)
+	if err != nil {
+		log.WithError(err).Error("Failed to encode pagination cursor")
+		return ""
+	}
+	return nextKey
+}
+
+// maxDescriptionContainsLength bounds the descriptionContains filter
+const maxDescriptionContainsLength = 200
+
+// parseTransactionEntryFilter parses the amount and attribute filters shared by the transactions list, export and
+// stats: minAmount, maxAmount, currency, hasMerchant, hasCategory and descriptionContains
+func parseTransactionEntryFilter(query url.Values) (models.TransactionEntryFilter, error) {
+	var filter models.TransactionEntryFilter
+
+	parseAmount := func(name string) (*int64, error) {
+		value := query.Get(name)
+		if value == "" {
+			return nil, nil
+		}
+		amount, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || amount < 0 {
+			return nil, fmt.Errorf("Invalid parameter '%s': must be a non-negative amount in cents", name)
+		}
+		return &amount, nil
+	}
+	var err error
+	if filter.MinAmount, err = parseAmount("minAmount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = parseAmount("maxAmount"); err != nil {
+		return filter, err
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return filter, errors.New("Invalid parameters: minAmount must not be greater than maxAmount")
+	}
+
+	if currency := query.Get("currency"); currency != "" {
+		if !helpers.IsValidCurrencyCode(currency) {
+			return filter, errors.New("Invalid currency format")
+		}
+		filter.AmountCurrency = strings.ToUpper(currency)
+	}
+
+	parseBool := func(name string) (*bool, error) {
+		value := query.Get(name)
+		if value == "" {
+			return nil, nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid parameter '%s': %w", name, err)
+		}
+		return &b, nil
+	}
+	if filter.HasMerchant, err = parseBool("hasMerchant"); err != nil {
+		return filter, err
+	}
+	if filter.HasCategory, err = parseBool("hasCategory"); err != nil {
+		return filter, err
+	}
+
+	filter.DescriptionContains = strings.TrimSpace(query.Get("descriptionContains"))
+	if len([]rune(filter.DescriptionContains)) > maxDescriptionContainsLength {
+		return filter, fmt.Errorf("Invalid parameter 'descriptionContains': must be at most %d characters", maxDescriptionContainsLength)
+	}
+
+	return filter, nil
+}
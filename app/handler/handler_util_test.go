@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseTransactionEntryFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantMin  *int64
+		wantMax  *int64
+		currency string
+		wantErr  bool
+	}{
+		{name: "no filter"},
+		{name: "min amount", query: "minAmount=1000", wantMin: int64Ptr(1000)},
+		{name: "max amount", query: "maxAmount=2500", wantMax: int64Ptr(2500)},
+		{name: "amount range in currency", query: "minAmount=0&maxAmount=2500&currency=usd", wantMin: int64Ptr(0), wantMax: int64Ptr(2500), currency: "USD"},
+		{name: "equal min and max", query: "minAmount=100&maxAmount=100", wantMin: int64Ptr(100), wantMax: int64Ptr(100)},
+		{name: "surrounding spaces", query: "minAmount=+100+", wantMin: int64Ptr(100)},
+		{name: "negative amount", query: "minAmount=-1", wantErr: true},
+		{name: "decimal amount", query: "maxAmount=10.50", wantErr: true},
+		{name: "not a number", query: "minAmount=ten", wantErr: true},
+		{name: "min greater than max", query: "minAmount=200&maxAmount=100", wantErr: true},
+		{name: "invalid currency", query: "minAmount=100&currency=EURO", wantErr: true},
+		{name: "invalid boolean", query: "hasMerchant=maybe", wantErr: true},
+		{name: "description too long", query: "descriptionContains=" + strings.Repeat("a", maxDescriptionContainsLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("Invalid test query: %v", err)
+			}
+
+			filter, err := parseTransactionEntryFilter(query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !equalInt64Ptr(filter.MinAmount, tt.wantMin) {
+				t.Errorf("Expected minAmount %v, got %v", formatInt64Ptr(tt.wantMin), formatInt64Ptr(filter.MinAmount))
+			}
+			if !equalInt64Ptr(filter.MaxAmount, tt.wantMax) {
+				t.Errorf("Expected maxAmount %v, got %v", formatInt64Ptr(tt.wantMax), formatInt64Ptr(filter.MaxAmount))
+			}
+			if filter.AmountCurrency != tt.currency {
+				t.Errorf("Expected currency '%s', got '%s'", tt.currency, filter.AmountCurrency)
+			}
+		})
+	}
+}
+
+func TestListTransactionsRejectsInvalidEntryFilter(t *testing.T) {
+	for _, query := range []string{"minAmount=-5", "maxAmount=abc", "minAmount=10&maxAmount=5", "hasCategory=maybe"} {
+		t.Run(query, func(t *testing.T) {
+			svc := &service.MockService{}
+			h := NewHandlerImpl(svc)
+
+			recorder := httptest.NewRecorder()
+			h.ListTransactions(recorder, httptest.NewRequest(http.MethodGet, "/transactions?"+query, nil))
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", recorder.Code)
+			}
+			svc.AssertNotCalled(t, "ListTransactionEntries", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestParseSorting(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantBy    string
+		wantOrder string
+		wantErr   bool
+	}{
+		{query: ""},
+		{query: "sortBy=updatedAt&order=desc", wantBy: "updatedAt", wantOrder: "desc"},
+		{query: "sortBy=updated_at&order=Desc", wantBy: "updatedAt", wantOrder: "desc"},
+		{query: "sortBy=created_at&order=ASC", wantBy: "createdAt", wantOrder: "asc"},
+		{query: "sortBy=rank&order=+asc+", wantBy: "rank", wantOrder: "asc"},
+		{query: "order=descending", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("Invalid test query: %v", err)
+			}
+
+			if sortBy := parseSortBy(query, "sortBy"); sortBy != tt.wantBy {
+				t.Errorf("Expected sort key '%s', got '%s'", tt.wantBy, sortBy)
+			}
+			order, err := parseSortOrder(query)
+			if tt.wantErr {
+				var validation *models.ValidationError
+				if !errors.As(err, &validation) || validation.Field != "order" {
+					t.Fatalf("Expected a validation error of order, got %v", err)
+				}
+				return
+			}
+			if err != nil || order != tt.wantOrder {
+				t.Errorf("Expected order '%s', got '%s' (%v)", tt.wantOrder, order, err)
+			}
+		})
+	}
+}
+
+func TestListMerchantsNormalisesSorting(t *testing.T) {
+	svc := &service.MockService{}
+	var filter models.ListMerchantsInput
+	svc.On("ListMerchants", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { filter = args.Get(1).(models.ListMerchantsInput) }).
+		Return([]models.Merchant{}, nil)
+	h := NewHandlerImpl(svc)
+
+	recorder := httptest.NewRecorder()
+	h.ListMerchants(recorder, httptest.NewRequest(http.MethodGet, "/merchants?sortBy=updated_at&order=Desc", nil))
+	if recorder.Code != http.StatusOK || filter.SortBy != "updatedAt" || filter.Order != "desc" {
+		t.Errorf("Expected sorting by updatedAt desc, got %d with %q %q", recorder.Code, filter.SortBy, filter.Order)
+	}
+
+	svc = &service.MockService{}
+	h = NewHandlerImpl(svc)
+	recorder = httptest.NewRecorder()
+	h.ListMerchants(recorder, httptest.NewRequest(http.MethodGet, "/merchants?order=up", nil))
+	if recorder.Code != http.StatusBadRequest || recorder.Header().Get("Content-Type") != problemContentType {
+		t.Errorf("Expected a 400 problem for an unknown order, got %d", recorder.Code)
+	}
+	svc.AssertNotCalled(t, "ListMerchants", mock.Anything, mock.Anything)
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func equalInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatInt64Ptr(value *int64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
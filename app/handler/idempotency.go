@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/service"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-generated key identifying retries of the same request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a retried request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// withIdempotency runs a handler honoring the Idempotency-Key header. The first request with a key is processed and
+// its successful response stored, retries with the same key and body replay the stored response. Failed requests
+// release the key so that they can be retried. Requests without the header are processed as usual.
+func (h *HandlerImpl) withIdempotency(w http.ResponseWriter, r *http.Request, body []byte, handle func(http.ResponseWriter)) {
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		handle(w)
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		WriteJSONError(w, http.StatusBadRequest, models.ErrorCodeBadRequest, "Idempotency-Key must not be longer than 255 characters")
+		return
+	}
+
+	requestHash := models.IdempotencyRequestHash(r.Method, r.URL.Path, body)
+	stored, err := h.Service.BeginIdempotentRequest(r.Context(), key, requestHash)
+	if err != nil {
+		// A key reused for a different request is documented as 422 rather than the 409 of other conflicts
+		if errors.Is(err, service.ErrIdempotencyKeyReused) {
+			WriteJSONError(w, http.StatusUnprocessableEntity, models.ErrorCodeUnprocessable, err.Error())
+			return
+		}
+		h.handleServiceError(w, err, "BeginIdempotentRequest")
+		return
+	}
+
+	if stored != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(stored.StatusCode)
+		w.Write([]byte(stored.ResponseBody))
+		return
+	}
+
+	// The claim is renewed, completed or released even if the client disconnects or the request times out, otherwise
+	// a committed request would leave the key claimed and a retry would take it over and process the request again
+	claimCtx := context.WithoutCancel(r.Context())
+	recorder := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	completed := false
+	stopRenewing := h.keepIdempotencyKeyClaimed(claimCtx, key)
+	defer func() {
+		stopRenewing()
+		// Also runs when the handler panics (e.g. database unavailable), the retry must not find the key claimed
+		if completed {
+			return
+		}
+		if err := h.Service.ReleaseIdempotentRequest(claimCtx, key, requestHash); err != nil {
+			logrus.WithError(err).Warn("Failed to release idempotency key")
+		}
+	}()
+
+	handle(recorder)
+	stopRenewing()
+
+	if recorder.statusCode >= 200 && recorder.statusCode < 300 {
+		if err := h.Service.CompleteIdempotentRequest(claimCtx, key, requestHash, recorder.statusCode, recorder.body.String()); err != nil {
+			// The response was already sent, a retry processes the request again once the key is released
+			logrus.WithError(err).Error("Failed to store idempotent response")
+			return
+		}
+		completed = true
+	}
+}
+
+// keepIdempotencyKeyClaimed renews the claim of the key in the background while the request is processed, so that a
+// retry doesn't take over a slow request. The returned function stops renewing and may be called more than once.
+func (h *HandlerImpl) keepIdempotencyKeyClaimed(ctx context.Context, key string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(service.IdempotencyLockRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := h.Service.RenewIdempotentRequest(ctx, key); err != nil {
+					logrus.WithError(err).Warn("Failed to renew idempotency key")
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// recordingResponseWriter passes the response through and keeps a copy of its status code and body
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.statusCode = code
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
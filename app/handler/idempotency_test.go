@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/service"
+	"github.com/stretchr/testify/mock"
+)
+
+// activeContext matches contexts that aren't cancelled when the mocked call is made
+var activeContext = mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+
+func TestIdempotencyKeyOutlivesTheRequestContext(t *testing.T) {
+	body := []byte(`{"amount":1000}`)
+	newRequest := func() (*http.Request, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		r := httptest.NewRequest(http.MethodPost, "/transactions", nil).WithContext(ctx)
+		r.Header.Set(IdempotencyKeyHeader, "key-1")
+		return r, cancel
+	}
+	requestHash := models.IdempotencyRequestHash(http.MethodPost, "/transactions", body)
+
+	t.Run("complete after the client disconnected", func(t *testing.T) {
+		svc := &service.MockService{}
+		svc.On("BeginIdempotentRequest", mock.Anything, "key-1", requestHash).Return(nil, nil)
+		svc.On("CompleteIdempotentRequest", activeContext, "key-1", requestHash, http.StatusCreated, `{"id":"1"}`).Return(nil)
+		h := NewHandlerImpl(svc)
+
+		r, cancel := newRequest()
+		h.withIdempotency(httptest.NewRecorder(), r, body, func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"1"}`))
+			cancel() // The transaction was committed, then the client went away
+		})
+
+		svc.AssertCalled(t, "CompleteIdempotentRequest", activeContext, "key-1", requestHash, http.StatusCreated, `{"id":"1"}`)
+		svc.AssertNotCalled(t, "ReleaseIdempotentRequest", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("release after a timeout", func(t *testing.T) {
+		svc := &service.MockService{}
+		svc.On("BeginIdempotentRequest", mock.Anything, "key-1", requestHash).Return(nil, nil)
+		svc.On("ReleaseIdempotentRequest", activeContext, "key-1", requestHash).Return(nil)
+		h := NewHandlerImpl(svc)
+
+		r, cancel := newRequest()
+		h.withIdempotency(httptest.NewRecorder(), r, body, func(w http.ResponseWriter) {
+			cancel()
+			w.WriteHeader(http.StatusGatewayTimeout)
+		})
+
+		svc.AssertCalled(t, "ReleaseIdempotentRequest", activeContext, "key-1", requestHash)
+	})
+}
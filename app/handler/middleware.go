@@ -1,6 +1,12 @@
 package handler
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/awslabs/aws-lambda-go-api-proxy/core"
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+)
 
 func EnsureAwsRegionHeader(region string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -12,3 +18,23 @@ func EnsureAwsRegionHeader(region string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequestIDHeader carries the ID of a request, echoed in responses and recorded in audit events
+const RequestIDHeader = "X-Request-Id"
+
+// RequestID puts the ID of the request into the request context and the response headers. The API Gateway request
+// ID is used in Lambda, otherwise the X-Request-Id header of the client or a new ID.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if gatewayContext, ok := core.GetAPIGatewayV2ContextFromContext(r.Context()); ok && gatewayContext.RequestID != "" {
+			requestID = gatewayContext.RequestID
+		}
+		if requestID == "" || len(requestID) > 100 {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(auth.WithRequestID(r.Context(), requestID)))
+	})
+}
@@ -3,27 +3,52 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
+
+	log "github.com/sirupsen/logrus"
 )
 
-type Error struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+// Problem is an RFC 7807 problem details response, extended with the error code and the invalid fields of a request
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Code   string       `json:"code"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is an invalid field of a request
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
 }
 
+// problemContentType is the media type of problem details responses
+const problemContentType = "application/problem+json"
+
 type PaginatedResponse[T any] struct {
 	Items   []T    `json:"items"`
 	NextKey string `json:"nextKey,omitempty"`
 }
 
+// WriteJSONError writes a problem details response with the given status, error code and detail message
 func WriteJSONError(w http.ResponseWriter, status int, code string, errMsg string) {
+	writeProblem(w, Problem{Status: status, Code: code, Detail: errMsg})
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	response := map[string]string{"code": code, "error": errMsg}
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode error response", http.StatusInternalServerError)
-		w.Write([]byte(`{"code":"InternalServerError","error":"Failed to encode error response"}`))
+// writeProblem writes a problem details response, the type defaults to about:blank and the title to the status text
+func writeProblem(w http.ResponseWriter, problem Problem) {
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.WithError(err).Error("Failed to encode problem details response")
 	}
 }
 
@@ -40,66 +65,3 @@ func WriteJSONListResponse[T any](w http.ResponseWriter, items []T, nextKey stri
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
-
-// isDatabaseError checks if the error is related to database operations
-func isDatabaseError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := strings.ToLower(err.Error())
-	return strings.Contains(errStr, "sqlstate") ||
-		strings.Contains(errStr, "constraint") ||
-		strings.Contains(errStr, "foreign key") ||
-		strings.Contains(errStr, "database") ||
-		strings.Contains(errStr, "gorm") ||
-		strings.Contains(errStr, "postgres")
-}
-
-// isDatabaseTimeoutError checks if the error is specifically a connection timeout
-func isDatabaseTimeoutError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := strings.ToLower(err.Error())
-	return strings.Contains(errStr, "timeout") ||
-		strings.Contains(errStr, "connection refused") ||
-		strings.Contains(errStr, "no such host") ||
-		strings.Contains(errStr, "i/o timeout") ||
-		strings.Contains(errStr, "context deadline exceeded") ||
-		strings.Contains(errStr, "dial tcp") ||
-		strings.Contains(errStr, "connect: connection refused") ||
-		strings.Contains(errStr, "failed to connect after") ||
-		strings.Contains(errStr, "database connection failed")
-}
-
-// isDatabaseMaintenanceError checks if the error is due to database maintenance/upgrade
-func isDatabaseMaintenanceError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := strings.ToLower(err.Error())
-	return strings.Contains(errStr, "maintenance") ||
-		strings.Contains(errStr, "upgrade") ||
-		strings.Contains(errStr, "unavailable") ||
-		strings.Contains(errStr, "temporarily unavailable") ||
-		strings.Contains(errStr, "server is not currently available") ||
-		strings.Contains(errStr, "cluster is not available") ||
-		strings.Contains(errStr, "database system is starting up") ||
-		strings.Contains(errStr, "database system is shutting down") ||
-		strings.Contains(errStr, "connection to server") ||
-		strings.Contains(errStr, "could not connect to server") ||
-		strings.Contains(errStr, "server closed the connection unexpectedly") ||
-		strings.Contains(errStr, "aurora cluster") ||
-		strings.Contains(errStr, "cluster endpoint") ||
-		strings.Contains(errStr, "aurora serverless") ||
-		strings.Contains(errStr, "serverless cluster") ||
-		strings.Contains(errStr, "scaling") ||
-		strings.Contains(errStr, "cold start") ||
-		strings.Contains(errStr, "warming up") ||
-		strings.Contains(errStr, "paused") ||
-		strings.Contains(errStr, "resuming") ||
-		strings.Contains(errStr, "dial tcp") ||
-		strings.Contains(errStr, "connection refused") ||
-		strings.Contains(errStr, "failed to connect after") ||
-		strings.Contains(errStr, "database connection failed")
-}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -118,12 +119,18 @@ func (v *ValidationMiddleware) handleValidationError(w http.ResponseWriter, err
 
 	// Extract meaningful error message
 	errorMessage := "Request validation failed"
+	var fields []FieldError
 
 	if requestErr, ok := err.(*openapi3filter.RequestError); ok {
+		var schemaErr *openapi3.SchemaError
 		if requestErr.Parameter != nil {
 			errorMessage = fmt.Sprintf("Invalid parameter '%s': %s", requestErr.Parameter.Name, requestErr.Reason)
+			fields = append(fields, FieldError{Field: requestErr.Parameter.Name, Reason: requestErr.Reason})
 		} else if requestErr.RequestBody != nil {
 			errorMessage = fmt.Sprintf("Invalid request body: %s", requestErr.Reason)
+			if errors.As(requestErr.Err, &schemaErr) {
+				fields = append(fields, FieldError{Field: strings.Join(schemaErr.JSONPointer(), "."), Reason: schemaErr.Reason})
+			}
 		} else {
 			errorMessage = requestErr.Reason
 		}
@@ -134,7 +141,7 @@ func (v *ValidationMiddleware) handleValidationError(w http.ResponseWriter, err
 	}
 
 	// Return standardized error response
-	WriteJSONError(w, http.StatusBadRequest, "SchemaValidationError", errorMessage)
+	writeProblem(w, Problem{Status: http.StatusBadRequest, Code: "SchemaValidationError", Detail: errorMessage, Errors: fields})
 }
 
 // ValidateResponse validates HTTP responses against the OpenAPI schema (optional)
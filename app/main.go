@@ -80,14 +80,25 @@ func main() {
 		"db_port":   appCfg.DBPort,
 		"db_name":   appCfg.DBName,
 		"log_level": appCfg.LogLevel,
+		"auth_mode": appCfg.AuthMode,
 	}).Info("Loaded config")
 
-	// Set database config for lazy initialization - DO NOT connect here
-	aws.SetConfig(appCfg)
+	// One-off CLI subcommands (e.g. `bootstrap reconvert-amounts -dry-run`) run and exit without starting the server
+	if len(os.Args) > 1 {
+		if err := runCommand(appCfg, os.Args[1], os.Args[2:]); err != nil {
+			log.WithError(err).Fatalf("Command %s failed", os.Args[1])
+		}
+		return
+	}
+
+	if err := appCfg.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid configuration")
+	}
 
 	// Initialize repositories and services with lazy DB connection
-	// These will only connect when first used
-	repository := repo.NewPostgreSQLRepositoryWithConfig(appCfg)
+	// These will only connect when first used - DO NOT connect here
+	connections := aws.NewConnectionManager(appCfg)
+	repository := repo.NewPostgreSQLRepositoryWithConnectionManager(connections)
 
 	// Initialize exchange rates database based on environment
 	var exchangeRatesDb repo.ExchangeRatesDb
@@ -108,9 +119,15 @@ func main() {
 	}
 
 	service := service.NewServiceImpl(repository, exchangeRatesDb)
-	serviceHandler := handler.NewHandlerImpl(service)
+	serviceHandler := handler.NewHandlerImplWithConfig(service, appCfg)
 
-	commonHandler := handler.NewCommonHandlerImplWithConfig(appCfg)
+	commonHandler := handler.NewCommonHandlerImpl(connections)
+
+	// Initialize authentication middleware
+	authMiddleware, err := handler.NewAuthMiddleware(appCfg)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize authentication middleware")
+	}
 
 	// Initialize validation middleware
 	validationMiddleware, err := handler.NewValidationMiddleware()
@@ -121,7 +138,8 @@ func main() {
 	router := mux.NewRouter()
 	router.Use(mux.CORSMethodMiddleware(router))
 	router.Use(handler.EnsureAwsRegionHeader(appCfg.AWSRegion))
-	router.Use(handler.DatabaseMaintenanceMiddleware) // Add maintenance error handling
+	router.Use(handler.RequestID)
+	router.Use(authMiddleware.Authenticate)
 	router.Use(validationMiddleware.ValidateRequest)
 
 	// Common APIs
@@ -140,9 +158,24 @@ func main() {
 	router.HandleFunc("/transactions", serviceHandler.CreateTransaction).Methods("POST")
 	router.HandleFunc("/transactions", serviceHandler.ListTransactions).Methods("GET")
 	router.HandleFunc("/transactions/stats", serviceHandler.GetTransactionStats).Methods("GET")
+	router.HandleFunc("/transactions/duplicates", serviceHandler.ListDuplicateTransactions).Methods("GET")
+	router.HandleFunc("/transactions/export", serviceHandler.ExportTransactions).Methods("GET")
+	router.HandleFunc("/transactions/{transaction_id}/history", serviceHandler.GetTransactionHistory).Methods("GET")
 	router.HandleFunc("/transactions/{transaction_id}", serviceHandler.GetTransaction).Methods("GET")
 	router.HandleFunc("/transactions/{transaction_id}", serviceHandler.UpdateTransaction).Methods("PUT")
 	router.HandleFunc("/transactions/{transaction_id}", serviceHandler.DeleteTransaction).Methods("DELETE")
+	router.HandleFunc("/transactions/{transaction_id}/restore", serviceHandler.RestoreTransaction).Methods("POST")
+
+	// Operations APIs
+	router.HandleFunc("/operations/{operation_id}", serviceHandler.GetOperation).Methods("GET")
+	router.HandleFunc("/operations/{operation_id}", serviceHandler.UpdateOperation).Methods("PUT")
+	router.HandleFunc("/operations/{operation_id}", serviceHandler.DeleteOperation).Methods("DELETE")
+
+	// Transfers APIs
+	router.HandleFunc("/transfers", serviceHandler.CreateTransfer).Methods("POST")
+	router.HandleFunc("/transfers/{transfer_id}", serviceHandler.GetTransfer).Methods("GET")
+	router.HandleFunc("/transfers/{transfer_id}", serviceHandler.UpdateTransfer).Methods("PUT")
+	router.HandleFunc("/transfers/{transfer_id}", serviceHandler.DeleteTransfer).Methods("DELETE")
 
 	// Balances APIsте
 	router.HandleFunc("/balances", serviceHandler.CreateBalance).Methods("POST")
@@ -151,6 +184,7 @@ func main() {
 	router.HandleFunc("/balances/{balance_id}", serviceHandler.GetBalance).Methods("GET")
 	router.HandleFunc("/balances/{balance_id}", serviceHandler.UpdateBalance).Methods("PUT")
 	router.HandleFunc("/balances/{balance_id}", serviceHandler.DeleteBalance).Methods("DELETE") // Single delete by ID
+	router.HandleFunc("/balances/{balance_id}/restore", serviceHandler.RestoreBalance).Methods("POST")
 
 	// Categories APIs
 	router.HandleFunc("/categories", serviceHandler.CreateCategory).Methods("POST")
@@ -159,6 +193,7 @@ func main() {
 	router.HandleFunc("/categories/{category_id}", serviceHandler.GetCategory).Methods("GET")
 	router.HandleFunc("/categories/{category_id}", serviceHandler.UpdateCategory).Methods("PUT")
 	router.HandleFunc("/categories/{category_id}", serviceHandler.DeleteCategory).Methods("DELETE") // Single delete by ID
+	router.HandleFunc("/categories/{category_id}/restore", serviceHandler.RestoreCategory).Methods("POST")
 
 	// Category Groups APIs
 	router.HandleFunc("/categoryGroups", serviceHandler.CreateCategoryGroup).Methods("POST")
@@ -166,6 +201,7 @@ func main() {
 	router.HandleFunc("/categoryGroups/{category_group_id}", serviceHandler.GetCategoryGroup).Methods("GET")
 	router.HandleFunc("/categoryGroups/{category_group_id}", serviceHandler.UpdateCategoryGroup).Methods("PUT")
 	router.HandleFunc("/categoryGroups/{category_group_id}", serviceHandler.DeleteCategoryGroup).Methods("DELETE")
+	router.HandleFunc("/categoryGroups/{category_group_id}/restore", serviceHandler.RestoreCategoryGroup).Methods("POST")
 
 	// Merchants APIs
 	router.HandleFunc("/merchants", serviceHandler.CreateMerchant).Methods("POST")
@@ -174,11 +210,75 @@ func main() {
 	router.HandleFunc("/merchants/{merchant_id}", serviceHandler.GetMerchant).Methods("GET")
 	router.HandleFunc("/merchants/{merchant_id}", serviceHandler.UpdateMerchant).Methods("PUT")
 	router.HandleFunc("/merchants/{merchant_id}", serviceHandler.DeleteMerchant).Methods("DELETE") // Single delete by ID
+	router.HandleFunc("/merchants/{merchant_id}/restore", serviceHandler.RestoreMerchant).Methods("POST")
+
+	// Budgets APIs
+	router.HandleFunc("/budgets", serviceHandler.CreateBudget).Methods("POST")
+	router.HandleFunc("/budgets", serviceHandler.ListBudgets).Methods("GET")
+	router.HandleFunc("/budgets/{budget_id}", serviceHandler.GetBudget).Methods("GET")
+	router.HandleFunc("/budgets/{budget_id}", serviceHandler.UpdateBudget).Methods("PUT")
+	router.HandleFunc("/budgets/{budget_id}", serviceHandler.DeleteBudget).Methods("DELETE")
+	router.HandleFunc("/budgets/{budget_id}/status", serviceHandler.GetBudgetStatus).Methods("GET")
+
+	// Categorization Rules APIs
+	router.HandleFunc("/rules", serviceHandler.CreateCategorizationRule).Methods("POST")
+	router.HandleFunc("/rules", serviceHandler.ListCategorizationRules).Methods("GET")
+	router.HandleFunc("/rules/apply", serviceHandler.ApplyCategorizationRules).Methods("POST")
+	router.HandleFunc("/rules/{rule_id}", serviceHandler.GetCategorizationRule).Methods("GET")
+	router.HandleFunc("/rules/{rule_id}", serviceHandler.UpdateCategorizationRule).Methods("PUT")
+	router.HandleFunc("/rules/{rule_id}", serviceHandler.DeleteCategorizationRule).Methods("DELETE")
+
+	// Suggestions APIs
+	router.HandleFunc("/suggestions", serviceHandler.GetSuggestions).Methods("GET")
+
+	// Recurring Transactions APIs
+	router.HandleFunc("/recurring-transactions", serviceHandler.CreateRecurringTransaction).Methods("POST")
+	router.HandleFunc("/recurring-transactions", serviceHandler.ListRecurringTransactions).Methods("GET")
+	router.HandleFunc("/recurring-transactions/{recurring_transaction_id}", serviceHandler.GetRecurringTransaction).Methods("GET")
+	router.HandleFunc("/recurring-transactions/{recurring_transaction_id}", serviceHandler.UpdateRecurringTransaction).Methods("PUT")
+	router.HandleFunc("/recurring-transactions/{recurring_transaction_id}", serviceHandler.DeleteRecurringTransaction).Methods("DELETE")
+
+	// Imports APIs
+	router.HandleFunc("/imports", serviceHandler.ImportTransactions).Methods("POST")
+	router.HandleFunc("/import-profiles", serviceHandler.CreateImportProfile).Methods("POST")
+	router.HandleFunc("/import-profiles", serviceHandler.ListImportProfiles).Methods("GET")
+	router.HandleFunc("/import-profiles/{import_profile_id}", serviceHandler.GetImportProfile).Methods("GET")
+	router.HandleFunc("/import-profiles/{import_profile_id}", serviceHandler.UpdateImportProfile).Methods("PUT")
+	router.HandleFunc("/import-profiles/{import_profile_id}", serviceHandler.DeleteImportProfile).Methods("DELETE")
+
+	// Groups APIs
+	router.HandleFunc("/groups", serviceHandler.CreateGroup).Methods("POST")
+	router.HandleFunc("/groups", serviceHandler.ListGroups).Methods("GET")
+	router.HandleFunc("/groups/{group_id}", serviceHandler.GetGroup).Methods("GET")
+	router.HandleFunc("/groups/{group_id}", serviceHandler.UpdateGroup).Methods("PUT")
+	router.HandleFunc("/groups/{group_id}", serviceHandler.DeleteGroup).Methods("DELETE")
+	router.HandleFunc("/groups/{group_id}/members/{user_id}", serviceHandler.UpdateGroupMember).Methods("PUT")
+	router.HandleFunc("/groups/{group_id}/members/{user_id}", serviceHandler.RemoveGroupMember).Methods("DELETE")
+	router.HandleFunc("/groups/{group_id}/invitations", serviceHandler.CreateGroupInvitation).Methods("POST")
+	router.HandleFunc("/groups/{group_id}/invitations", serviceHandler.ListGroupInvitations).Methods("GET")
+	router.HandleFunc("/groups/{group_id}/invitations/{invitation_id}", serviceHandler.RevokeGroupInvitation).Methods("DELETE")
+
+	// Invitations of the current user
+	router.HandleFunc("/invitations", serviceHandler.ListMyGroupInvitations).Methods("GET")
+	router.HandleFunc("/invitations/{invitation_id}/accept", serviceHandler.AcceptGroupInvitation).Methods("POST")
+	router.HandleFunc("/invitations/{invitation_id}/decline", serviceHandler.DeclineGroupInvitation).Methods("POST")
+
+	// Users APIs, export and erasure of all data of a user
+	router.HandleFunc("/users/{user_id}/export", serviceHandler.ExportUserData).Methods("GET")
+	router.HandleFunc("/users/{user_id}", serviceHandler.EraseUserData).Methods("DELETE")
+
+	// Audit APIs
+	router.HandleFunc("/audit", serviceHandler.ListAuditEvents).Methods("GET")
+
+	// Admin APIs
+	router.HandleFunc("/admin/exchange-rates/reconvert", serviceHandler.ReconvertEntryAmounts).Methods("POST")
+	router.HandleFunc("/admin/recurring-transactions/run", serviceHandler.RunRecurringTransactions).Methods("POST")
+	router.HandleFunc("/admin/deleted-data/purge", serviceHandler.PurgeDeletedData).Methods("POST")
 
 	// Lambda/API Gateway integration: use the muxadapter if running in Lambda
 	if os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "" || os.Getenv("_LAMBDA_SERVER_PORT") != "" {
 		adapter := gorillamux.New(router)
-		lambda.Start(lambdaHandler(adapter))
+		lambda.Start(lambdaEventHandler(adapter, service, appCfg.RetentionDays))
 		return
 	}
 
@@ -0,0 +1,16 @@
+-- Drops all tables of the initial schema and their data
+DROP TABLE IF EXISTS transfer;
+DROP TABLE IF EXISTS idempotency_key;
+DROP TABLE IF EXISTS import_profile;
+DROP TABLE IF EXISTS recurring_transaction;
+DROP TABLE IF EXISTS budget;
+DROP TABLE IF EXISTS group_invitation;
+DROP TABLE IF EXISTS group_member;
+DROP TABLE IF EXISTS user_group;
+DROP TABLE IF EXISTS transaction_entry_amount;
+DROP TABLE IF EXISTS transaction_entry;
+DROP TABLE IF EXISTS transaction;
+DROP TABLE IF EXISTS category;
+DROP TABLE IF EXISTS category_group;
+DROP TABLE IF EXISTS merchant;
+DROP TABLE IF EXISTS balance;
@@ -0,0 +1,265 @@
+-- Schema of the last release migrated with GORM AutoMigrate. Every statement is idempotent so that databases
+-- created by AutoMigrate are adopted as they are. The foreign keys AutoMigrate created are added by
+-- 0007_foreign_keys, which keeps the existing ones.
+
+CREATE TABLE IF NOT EXISTS balance (
+	id          uuid DEFAULT gen_random_uuid(),
+	group_id    uuid NOT NULL,
+	user_id     uuid NOT NULL,
+	currency    varchar(3) NOT NULL DEFAULT 'EUR',
+	title       varchar(100) NOT NULL,
+	description varchar(500),
+	rank        bigint,
+	created_at  timestamptz DEFAULT now(),
+	updated_at  timestamptz DEFAULT now(),
+	deleted_at  timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_balance_deleted_at ON balance (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_balance_user_id ON balance (user_id);
+CREATE INDEX IF NOT EXISTS idx_balance_group_id ON balance (group_id);
+
+CREATE TABLE IF NOT EXISTS merchant (
+	id          uuid DEFAULT gen_random_uuid(),
+	group_id    uuid NOT NULL,
+	user_id     uuid NOT NULL,
+	name        text NOT NULL,
+	description varchar(255),
+	image_url   varchar(255),
+	rank        bigint,
+	created_at  timestamptz DEFAULT now(),
+	updated_at  timestamptz DEFAULT now(),
+	deleted_at  timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_merchant_deleted_at ON merchant (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_merchant_user_id ON merchant (user_id);
+CREATE INDEX IF NOT EXISTS idx_merchant_group_id ON merchant (group_id);
+
+CREATE TABLE IF NOT EXISTS category_group (
+	id         uuid DEFAULT gen_random_uuid(),
+	name       text NOT NULL,
+	rank       bigint,
+	image_url  varchar(255),
+	created_at timestamptz DEFAULT now(),
+	updated_at timestamptz DEFAULT now(),
+	deleted_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_category_group_deleted_at ON category_group (deleted_at);
+
+CREATE TABLE IF NOT EXISTS category (
+	id                uuid DEFAULT gen_random_uuid(),
+	user_id           uuid NOT NULL,
+	group_id          uuid NOT NULL,
+	category_group_id uuid NOT NULL,
+	name              text NOT NULL,
+	"group"           text NOT NULL,
+	description       varchar(255),
+	rank              bigint,
+	image_url         varchar(255),
+	created_at        timestamptz DEFAULT now(),
+	updated_at        timestamptz DEFAULT now(),
+	deleted_at        timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_category_deleted_at ON category (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_category_group_id ON category (group_id, category_group_id);
+CREATE INDEX IF NOT EXISTS idx_category_user_id ON category (user_id);
+
+CREATE TABLE IF NOT EXISTS transaction (
+	id                       uuid DEFAULT gen_random_uuid(),
+	group_id                 uuid NOT NULL,
+	user_id                  uuid NOT NULL,
+	balance_id               uuid NOT NULL,
+	merchant_id              uuid,
+	type                     varchar(20) NOT NULL,
+	operation_id             uuid,
+	approved_at              timestamptz NOT NULL,
+	transacted_at            timestamptz NOT NULL,
+	recurring_transaction_id uuid,
+	recurring_occurrence_at  timestamptz,
+	external_id              varchar(255),
+	fingerprint              varchar(128),
+	created_at               timestamptz DEFAULT now(),
+	updated_at               timestamptz DEFAULT now(),
+	deleted_at               timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_deleted_at ON transaction (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_fingerprint ON transaction (fingerprint);
+CREATE INDEX IF NOT EXISTS idx_transaction_external_id ON transaction (external_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transaction_recurring_occurrence ON transaction (recurring_transaction_id, recurring_occurrence_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_transacted_at ON transaction (transacted_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_operation_id ON transaction (operation_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_type ON transaction (type);
+CREATE INDEX IF NOT EXISTS idx_transaction_merchant_id ON transaction (merchant_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_balance_id ON transaction (balance_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_user_id ON transaction (user_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_group_id ON transaction (group_id);
+
+CREATE TABLE IF NOT EXISTS transaction_entry (
+	id             uuid DEFAULT gen_random_uuid(),
+	transaction_id uuid NOT NULL,
+	description    text,
+	amount         bigint NOT NULL,
+	category_id    uuid,
+	created_at     timestamptz DEFAULT now(),
+	updated_at     timestamptz DEFAULT now(),
+	deleted_at     timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_entry_deleted_at ON transaction_entry (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_entry_category_id ON transaction_entry (category_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_entry_transaction_id ON transaction_entry (transaction_id);
+
+CREATE TABLE IF NOT EXISTS transaction_entry_amount (
+	transaction_entry_id uuid NOT NULL,
+	currency             varchar(3) NOT NULL,
+	amount               bigint NOT NULL,
+	exchange_rate        decimal(10,6) NOT NULL,
+	exchange_rate_date   date,
+	created_at           timestamptz DEFAULT now(),
+	updated_at           timestamptz DEFAULT now(),
+	PRIMARY KEY (transaction_entry_id, currency)
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_entry_amount_entry_id ON transaction_entry_amount (transaction_entry_id);
+
+CREATE TABLE IF NOT EXISTS user_group (
+	id          uuid DEFAULT gen_random_uuid(),
+	name        varchar(100) NOT NULL,
+	description varchar(500),
+	created_by  uuid NOT NULL,
+	created_at  timestamptz DEFAULT now(),
+	updated_at  timestamptz DEFAULT now(),
+	deleted_at  timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_group_deleted_at ON user_group (deleted_at);
+
+CREATE TABLE IF NOT EXISTS group_member (
+	group_id   uuid NOT NULL,
+	user_id    uuid NOT NULL,
+	role       varchar(10) NOT NULL,
+	created_at timestamptz DEFAULT now(),
+	updated_at timestamptz DEFAULT now(),
+	PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_member_user_id ON group_member (user_id);
+
+CREATE TABLE IF NOT EXISTS group_invitation (
+	id           uuid DEFAULT gen_random_uuid(),
+	group_id     uuid NOT NULL,
+	user_id      uuid NOT NULL,
+	role         varchar(10) NOT NULL,
+	status       varchar(10) NOT NULL DEFAULT 'pending',
+	invited_by   uuid NOT NULL,
+	expires_at   timestamptz NOT NULL,
+	responded_at timestamptz,
+	created_at   timestamptz DEFAULT now(),
+	updated_at   timestamptz DEFAULT now(),
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_invitation_user_id ON group_invitation (user_id);
+CREATE INDEX IF NOT EXISTS idx_group_invitation_group_id ON group_invitation (group_id);
+
+CREATE TABLE IF NOT EXISTS budget (
+	id         uuid DEFAULT gen_random_uuid(),
+	group_id   uuid NOT NULL,
+	user_id    uuid NOT NULL,
+	name       varchar(100) NOT NULL,
+	scope_type varchar(20) NOT NULL,
+	scope_id   uuid,
+	period     varchar(10) NOT NULL,
+	start_date timestamptz NOT NULL,
+	end_date   timestamptz,
+	amount     bigint NOT NULL,
+	currency   varchar(3) NOT NULL,
+	created_at timestamptz DEFAULT now(),
+	updated_at timestamptz DEFAULT now(),
+	deleted_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_budget_deleted_at ON budget (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_budget_scope_id ON budget (scope_id);
+CREATE INDEX IF NOT EXISTS idx_budget_user_id ON budget (user_id);
+CREATE INDEX IF NOT EXISTS idx_budget_group_id ON budget (group_id);
+
+CREATE TABLE IF NOT EXISTS recurring_transaction (
+	id                 uuid DEFAULT gen_random_uuid(),
+	group_id           uuid NOT NULL,
+	user_id            uuid NOT NULL,
+	balance_id         uuid NOT NULL,
+	name               varchar(100) NOT NULL,
+	template           jsonb NOT NULL,
+	schedule           varchar(255) NOT NULL,
+	start_at           timestamptz NOT NULL,
+	paused             boolean NOT NULL DEFAULT false,
+	next_occurrence_at timestamptz,
+	last_occurrence_at timestamptz,
+	occurrences_count  bigint NOT NULL DEFAULT 0,
+	created_at         timestamptz DEFAULT now(),
+	updated_at         timestamptz DEFAULT now(),
+	deleted_at         timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_recurring_transaction_deleted_at ON recurring_transaction (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_recurring_transaction_next_occurrence_at ON recurring_transaction (next_occurrence_at);
+CREATE INDEX IF NOT EXISTS idx_recurring_transaction_balance_id ON recurring_transaction (balance_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_transaction_user_id ON recurring_transaction (user_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_transaction_group_id ON recurring_transaction (group_id);
+
+CREATE TABLE IF NOT EXISTS import_profile (
+	id                 uuid DEFAULT gen_random_uuid(),
+	group_id           uuid NOT NULL,
+	user_id            uuid NOT NULL,
+	name               varchar(100) NOT NULL,
+	delimiter          varchar(1) NOT NULL DEFAULT ',',
+	has_header         boolean NOT NULL DEFAULT true,
+	skip_rows          bigint NOT NULL DEFAULT 0,
+	date_column        varchar(100) NOT NULL,
+	date_format        varchar(50),
+	amount_column      varchar(100),
+	debit_column       varchar(100),
+	credit_column      varchar(100),
+	decimal_separator  varchar(1) NOT NULL DEFAULT '.',
+	invert_sign        boolean NOT NULL DEFAULT false,
+	description_column varchar(100),
+	merchant_column    varchar(100),
+	id_column          varchar(100),
+	created_at         timestamptz DEFAULT now(),
+	updated_at         timestamptz DEFAULT now(),
+	deleted_at         timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_import_profile_deleted_at ON import_profile (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_import_profile_user_id ON import_profile (user_id);
+CREATE INDEX IF NOT EXISTS idx_import_profile_group_id ON import_profile (group_id);
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+	user_id       uuid NOT NULL,
+	key           varchar(255) NOT NULL,
+	request_hash  varchar(64) NOT NULL,
+	status_code   bigint NOT NULL DEFAULT 0,
+	response_body text,
+	created_at    timestamptz DEFAULT now(),
+	updated_at    timestamptz DEFAULT now(),
+	expires_at    timestamptz NOT NULL,
+	PRIMARY KEY (user_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires_at ON idempotency_key (expires_at);
+
+CREATE TABLE IF NOT EXISTS transfer (
+	id                      uuid,
+	group_id                uuid NOT NULL,
+	user_id                 uuid NOT NULL,
+	move_out_transaction_id uuid NOT NULL,
+	move_in_transaction_id  uuid NOT NULL,
+	created_at              timestamptz DEFAULT now(),
+	updated_at              timestamptz DEFAULT now(),
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_move_in_transaction_id ON transfer (move_in_transaction_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_move_out_transaction_id ON transfer (move_out_transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_user_id ON transfer (user_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_group_id ON transfer (group_id);
@@ -0,0 +1 @@
+-- Backfilled fingerprints can't be told apart from those written by the service and are kept
@@ -0,0 +1,6 @@
+-- Computes missing transaction fingerprints in the format of models.TransactionFingerprint: balance, type, total
+-- amount of the non-deleted entries and merchant
+UPDATE transaction t SET fingerprint = t.balance_id::text || ':' || t.type || ':' ||
+	COALESCE((SELECT SUM(e.amount) FROM transaction_entry e WHERE e.transaction_id = t.id AND e.deleted_at IS NULL), 0) ||
+	':' || COALESCE(t.merchant_id::text, '')
+WHERE t.fingerprint IS NULL;
@@ -0,0 +1,4 @@
+-- Dropping the columns drops their indexes too
+ALTER TABLE category DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE merchant DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE transaction_entry DROP COLUMN IF EXISTS search_vector;
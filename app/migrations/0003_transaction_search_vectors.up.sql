@@ -0,0 +1,14 @@
+-- Full-text search vectors of entry descriptions, merchant names and category names used by the q parameter of the
+-- transactions list. The 'simple' configuration doesn't stem words, which keeps search working the same for all
+-- languages of the descriptions.
+ALTER TABLE transaction_entry ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(description, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_transaction_entry_search_vector ON transaction_entry USING GIN (search_vector);
+
+ALTER TABLE merchant ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(name, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_merchant_search_vector ON merchant USING GIN (search_vector);
+
+ALTER TABLE category ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(name, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_category_search_vector ON category USING GIN (search_vector);
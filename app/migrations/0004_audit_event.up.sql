@@ -0,0 +1,18 @@
+-- Audit log of entity mutations, written in the database transaction of the change
+CREATE TABLE IF NOT EXISTS audit_event (
+	id          uuid DEFAULT gen_random_uuid(),
+	entity_type varchar(30) NOT NULL,
+	entity_id   uuid NOT NULL,
+	action      varchar(10) NOT NULL,
+	user_id     uuid NOT NULL,
+	group_id    uuid NOT NULL,
+	actor       varchar(255) NOT NULL,
+	request_id  varchar(100),
+	before      jsonb,
+	after       jsonb,
+	created_at  timestamptz NOT NULL,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_audit_event_created_at ON audit_event (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_event_user_id ON audit_event (user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_event_entity ON audit_event (entity_type, entity_id);
@@ -0,0 +1,25 @@
+-- Rules categorizing transaction entries on create, import and retroactive application
+CREATE TABLE IF NOT EXISTS categorization_rule (
+	id                   uuid DEFAULT gen_random_uuid(),
+	group_id             uuid NOT NULL,
+	user_id              uuid NOT NULL,
+	name                 varchar(100) NOT NULL,
+	priority             bigint NOT NULL DEFAULT 0,
+	disabled             boolean NOT NULL DEFAULT false,
+	merchant_id          uuid,
+	balance_id           uuid,
+	description_contains varchar(255),
+	description_pattern  varchar(255),
+	min_amount           bigint,
+	max_amount           bigint,
+	set_category_id      uuid,
+	set_merchant_id      uuid,
+	append_description   varchar(255),
+	created_at           timestamptz DEFAULT now(),
+	updated_at           timestamptz DEFAULT now(),
+	deleted_at           timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_categorization_rule_deleted_at ON categorization_rule (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_categorization_rule_group_id ON categorization_rule (group_id);
+CREATE INDEX IF NOT EXISTS idx_categorization_rule_user_id ON categorization_rule (user_id);
@@ -0,0 +1,4 @@
+-- Soft-deleted transfers would become active again without the column, their legs stay deleted
+DELETE FROM transfer WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_transfer_deleted_at;
+ALTER TABLE transfer DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+-- Transfers are soft deleted together with their legs, so restoring a leg restores the transfer linking them
+ALTER TABLE transfer ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_transfer_deleted_at ON transfer (deleted_at);
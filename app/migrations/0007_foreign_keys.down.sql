@@ -0,0 +1,11 @@
+-- Only the keys added by the up migration are dropped, keys created by AutoMigrate are kept
+ALTER TABLE transaction DROP CONSTRAINT IF EXISTS fk_transaction_balance_id;
+ALTER TABLE transaction DROP CONSTRAINT IF EXISTS fk_transaction_merchant_id;
+ALTER TABLE transaction_entry DROP CONSTRAINT IF EXISTS fk_transaction_entry_transaction_id;
+ALTER TABLE transaction_entry DROP CONSTRAINT IF EXISTS fk_transaction_entry_category_id;
+ALTER TABLE transaction_entry_amount DROP CONSTRAINT IF EXISTS fk_transaction_entry_amount_transaction_entry_id;
+ALTER TABLE category DROP CONSTRAINT IF EXISTS fk_category_category_group_id;
+ALTER TABLE transfer DROP CONSTRAINT IF EXISTS fk_transfer_move_out_transaction_id;
+ALTER TABLE transfer DROP CONSTRAINT IF EXISTS fk_transfer_move_in_transaction_id;
+ALTER TABLE group_member DROP CONSTRAINT IF EXISTS fk_group_member_group_id;
+ALTER TABLE group_invitation DROP CONSTRAINT IF EXISTS fk_group_invitation_group_id;
@@ -0,0 +1,34 @@
+-- Foreign keys of the relations declared on the models. AutoMigrate created them under its own names, databases
+-- created by 0001 have none. A key is only added when the column has no foreign key yet, so both are adopted.
+-- Keys are added NOT VALID: they are enforced for new writes without failing on orphans left in existing data.
+DO $$
+DECLARE
+	fk record;
+BEGIN
+	FOR fk IN SELECT * FROM (VALUES
+		('transaction', 'balance_id', 'balance', 'NO ACTION'),
+		('transaction', 'merchant_id', 'merchant', 'SET NULL'),
+		('transaction_entry', 'transaction_id', 'transaction', 'NO ACTION'),
+		('transaction_entry', 'category_id', 'category', 'NO ACTION'),
+		('transaction_entry_amount', 'transaction_entry_id', 'transaction_entry', 'NO ACTION'),
+		('category', 'category_group_id', 'category_group', 'NO ACTION'),
+		('transfer', 'move_out_transaction_id', 'transaction', 'NO ACTION'),
+		('transfer', 'move_in_transaction_id', 'transaction', 'NO ACTION'),
+		('group_member', 'group_id', 'user_group', 'NO ACTION'),
+		('group_invitation', 'group_id', 'user_group', 'NO ACTION')
+	) AS keys (table_name, column_name, referenced_table, on_delete)
+	LOOP
+		IF NOT EXISTS (
+			SELECT 1 FROM pg_constraint
+			JOIN pg_attribute ON pg_attribute.attrelid = pg_constraint.conrelid
+				AND pg_attribute.attnum = pg_constraint.conkey[1]
+			WHERE pg_constraint.contype = 'f'
+				AND pg_constraint.conrelid = fk.table_name::regclass
+				AND cardinality(pg_constraint.conkey) = 1
+				AND pg_attribute.attname = fk.column_name
+		) THEN
+			EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I FOREIGN KEY (%I) REFERENCES %I (id) ON DELETE %s NOT VALID',
+				fk.table_name, 'fk_' || fk.table_name || '_' || fk.column_name, fk.column_name, fk.referenced_table, fk.on_delete);
+		END IF;
+	END LOOP;
+END $$;
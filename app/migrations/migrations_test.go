@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAllEmbeddedMigrations(t *testing.T) {
+	migrations, err := All()
+	if err != nil {
+		t.Fatalf("Failed to load embedded migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Expected embedded migrations")
+	}
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("Expected version %d, got %d_%s", i+1, migration.Version, migration.Name)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("Migration %d_%s has an empty up or down file", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestLoadOrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_second.up.sql":   {Data: []byte("CREATE TABLE b (id int);")},
+		"0010_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"0002_first.up.sql":    {Data: []byte("CREATE TABLE a (id int);")},
+		"0002_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+	}
+
+	migrations, err := load(fsys)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Version != 10 {
+		t.Fatalf("Unexpected migrations %+v", migrations)
+	}
+	if migrations[0].Name != "first" || migrations[0].Up != "CREATE TABLE a (id int);" || migrations[0].Down != "DROP TABLE a;" {
+		t.Errorf("Unexpected migration %+v", migrations[0])
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"0001_init.up.sql": {Data: []byte("SELECT 1;")},
+		},
+		"mismatched names": {
+			"0001_init.up.sql":    {Data: []byte("SELECT 1;")},
+			"0001_other.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"invalid file name": {
+			"init.sql": {Data: []byte("SELECT 1;")},
+		},
+		"zero version": {
+			"0000_init.up.sql":   {Data: []byte("SELECT 1;")},
+			"0000_init.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range tests {
+		if _, err := load(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestForeignKeysDownDropsTheAddedKeys(t *testing.T) {
+	migrations, err := All()
+	if err != nil {
+		t.Fatalf("Failed to load embedded migrations: %v", err)
+	}
+	var foreignKeys *Migration
+	for i := range migrations {
+		if migrations[i].Name == "foreign_keys" {
+			foreignKeys = &migrations[i]
+		}
+	}
+	if foreignKeys == nil {
+		t.Fatal("Expected the foreign_keys migration")
+	}
+
+	keys := regexp.MustCompile(`\('(\w+)', '(\w+)', '\w+', '(?:NO ACTION|SET NULL)'\)`).FindAllStringSubmatch(foreignKeys.Up, -1)
+	if len(keys) == 0 {
+		t.Fatal("Expected foreign keys in the up migration")
+	}
+	for _, key := range keys {
+		drop := "ALTER TABLE " + key[1] + " DROP CONSTRAINT IF EXISTS fk_" + key[1] + "_" + key[2] + ";"
+		if !strings.Contains(foreignKeys.Down, drop) {
+			t.Errorf("Expected the down migration to contain %q", drop)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// advisoryLockKey identifies the PostgreSQL advisory lock serializing migrations of concurrent service instances
+const advisoryLockKey int64 = 0x61686f72726f // "ahorro"
+
+const createSchemaMigrationsSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    bigint PRIMARY KEY,
+	name       varchar(255) NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`
+
+// Status is a migration with the time it was applied, nil if it is pending
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+	Unknown   bool // Applied to the database but not embedded in this binary
+}
+
+// Migrator applies and reverts the embedded migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator of the embedded migrations
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Status lists all migrations in version order with the time they were applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(tx *gorm.DB, applied map[int]appliedMigration) error {
+		known := make(map[int]bool, len(m.migrations))
+		for _, migration := range m.migrations {
+			known[migration.Version] = true
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if record, ok := applied[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		for version, record := range applied {
+			if !known[version] {
+				appliedAt := record.AppliedAt
+				statuses = append(statuses, Status{Version: version, Name: record.Name, AppliedAt: &appliedAt, Unknown: true})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Up applies the pending migrations up to and including the target version, all of them if target is 0, and
+// returns the applied migrations. Each migration is applied in its own transaction, so a failing migration leaves
+// the ones before it applied.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	var done []Migration
+	for {
+		var next *Migration
+		err := m.locked(ctx, func(tx *gorm.DB, applied map[int]appliedMigration) error {
+			for i := range m.migrations {
+				migration := m.migrations[i]
+				if _, ok := applied[migration.Version]; ok || (target > 0 && migration.Version > target) {
+					continue
+				}
+				next = &migration
+				break
+			}
+			if next == nil {
+				return nil
+			}
+
+			if err := tx.Exec(next.Up).Error; err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", next.Version, next.Name, err)
+			}
+			return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				next.Version, next.Name, time.Now().UTC()).Error
+		})
+		if err != nil {
+			return done, err
+		}
+		if next == nil {
+			return done, nil
+		}
+
+		log.WithFields(log.Fields{"version": next.Version, "name": next.Name}).Info("Applied database migration")
+		done = append(done, *next)
+	}
+}
+
+// Down reverts the given number of most recently applied migrations in reverse version order and returns the
+// reverted migrations
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	for len(done) < steps {
+		var last *Migration
+		err := m.locked(ctx, func(tx *gorm.DB, applied map[int]appliedMigration) error {
+			latest := 0
+			for version := range applied {
+				if version > latest {
+					latest = version
+				}
+			}
+			if latest == 0 {
+				return nil
+			}
+
+			for i := range m.migrations {
+				if m.migrations[i].Version == latest {
+					migration := m.migrations[i]
+					last = &migration
+				}
+			}
+			if last == nil {
+				return fmt.Errorf("applied migration %d_%s is unknown to this binary", latest, applied[latest].Name)
+			}
+
+			if err := tx.Exec(last.Down).Error; err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", last.Version, last.Name, err)
+			}
+			return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", last.Version).Error
+		})
+		if err != nil {
+			return done, err
+		}
+		if last == nil {
+			return done, nil
+		}
+
+		log.WithFields(log.Fields{"version": last.Version, "name": last.Name}).Warn("Reverted database migration")
+		done = append(done, *last)
+	}
+	return done, nil
+}
+
+// appliedMigration is a row of the schema_migrations table
+type appliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// locked runs fn in a transaction holding the migrations advisory lock, with the migrations applied so far.
+// Instances starting concurrently wait for the lock instead of applying the same migration twice.
+func (m *Migrator) locked(ctx context.Context, fn func(tx *gorm.DB, applied map[int]appliedMigration) error) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Migrations and waiting for the lock may take longer than the statement timeout of the connection
+		if err := tx.Exec("SET LOCAL statement_timeout = 0").Error; err != nil {
+			return fmt.Errorf("failed to disable statement timeout: %w", err)
+		}
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", advisoryLockKey).Error; err != nil {
+			return fmt.Errorf("failed to acquire migrations lock: %w", err)
+		}
+		if err := tx.Exec(createSchemaMigrationsSQL).Error; err != nil {
+			return fmt.Errorf("failed to create schema_migrations table: %w", err)
+		}
+
+		var records []appliedMigration
+		if err := tx.Raw("SELECT version, name, applied_at FROM schema_migrations").Scan(&records).Error; err != nil {
+			return fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied := make(map[int]appliedMigration, len(records))
+		for _, record := range records {
+			applied[record.Version] = record
+		}
+		return fn(tx, applied)
+	})
+}
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit event actions
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionErase   = "erase" // Permanent erasure of all data of a user
+	AuditActionPurge   = "purge" // Permanent deletion of a soft-deleted row by the purge job
+)
+
+// AuditActorSystem is recorded as the actor of mutations by trusted internal callers (CLI jobs, schedules)
+const AuditActorSystem = "system"
+
+// AuditActorErased replaces the actor of audit events recorded by a user whose data was erased
+const AuditActorErased = "erased"
+
+// AuditEvent records a create, update, delete, restore or purge of an entity with JSON snapshots of the entity
+// before and after the change. Events are written in the database transaction of the change they record.
+type AuditEvent struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	EntityType string    `gorm:"type:varchar(30);not null;index:idx_audit_event_entity"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index:idx_audit_event_entity"`
+	Action     string    `gorm:"type:varchar(10);not null"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index:idx_audit_event_user_id"` // Owner of the entity, used to authorize reads
+	GroupID    uuid.UUID `gorm:"type:uuid;not null"`                               // Group of the entity, nil UUID for entities without group
+	Actor      string    `gorm:"type:varchar(255);not null"`                       // JWT sub of the caller or AuditActorSystem
+	RequestID  string    `gorm:"type:varchar(100)"`
+	Before     *string   `gorm:"type:jsonb"` // Nil for creates
+	After      *string   `gorm:"type:jsonb"` // Nil for deletes
+	CreatedAt  time.Time `gorm:"not null;index:idx_audit_event_created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (AuditEvent) TableName() string {
+	return "audit_event"
+}
+
+// AuditEventDto is a recorded create, update, delete, restore, erase or purge of an entity with its API
+// representation before and after the change
+type AuditEventDto struct {
+	EventID    string          `json:"eventId"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Action     string          `json:"action"`
+	UserID     string          `json:"userId,omitempty"`
+	GroupID    string          `json:"groupId,omitempty"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"requestId,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"` // Absent for creates
+	After      json.RawMessage `json:"after,omitempty"`  // Absent for deletes
+	CreatedAt  string          `json:"createdAt"`
+}
+
+// ToAPIAuditEvent converts AuditEvent (DAO) to AuditEventDto (API model)
+func ToAPIAuditEvent(e *AuditEvent) AuditEventDto {
+	if e == nil {
+		return AuditEventDto{}
+	}
+
+	dto := AuditEventDto{
+		EventID:    e.ID.String(),
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID.String(),
+		Action:     e.Action,
+		Actor:      e.Actor,
+		RequestID:  e.RequestID,
+		CreatedAt:  e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if e.UserID != uuid.Nil {
+		dto.UserID = e.UserID.String()
+	}
+	if e.GroupID != uuid.Nil {
+		dto.GroupID = e.GroupID.String()
+	}
+	if e.Before != nil {
+		dto.Before = json.RawMessage(*e.Before)
+	}
+	if e.After != nil {
+		dto.After = json.RawMessage(*e.After)
+	}
+	return dto
+}
+
+// auditSubject describes the audited entity of an event and its snapshot
+type auditSubject struct {
+	EntityType string
+	EntityID   uuid.UUID
+	UserID     uuid.UUID
+	GroupID    uuid.UUID
+}
+
+// auditSnapshot returns the subject of the audited entity and its API representation, which is stored as snapshot
+func auditSnapshot(entity interface{}) (auditSubject, interface{}, error) {
+	switch e := entity.(type) {
+	case *Transaction:
+		return auditSubject{"transaction", e.ID, e.UserID, e.GroupID}, ToAPICreateTransaction(e), nil
+	case *Balance:
+		return auditSubject{"balance", e.ID, e.UserID, e.GroupID}, ToAPIBalance(e), nil
+	case *Category:
+		return auditSubject{"category", e.ID, e.UserId, e.GroupId}, ToAPICategory(e), nil
+	case *CategoryGroup:
+		return auditSubject{"category_group", e.ID, uuid.Nil, uuid.Nil}, ToAPICategoryGroup(e), nil
+	case *Merchant:
+		return auditSubject{"merchant", e.ID, e.UserID, e.GroupID}, ToAPIMerchant(e), nil
+	case *Budget:
+		return auditSubject{"budget", e.ID, e.UserID, e.GroupID}, ToAPIBudget(e), nil
+	case *CategorizationRule:
+		return auditSubject{"categorization_rule", e.ID, e.UserID, e.GroupID}, ToAPICategorizationRule(e), nil
+	case *RecurringTransaction:
+		return auditSubject{"recurring_transaction", e.ID, e.UserID, e.GroupID}, ToAPIRecurringTransaction(e), nil
+	case *ImportProfile:
+		return auditSubject{"import_profile", e.ID, e.UserID, e.GroupID}, ToAPIImportProfile(e), nil
+	case *Transfer:
+		return auditSubject{"transfer", e.ID, e.UserID, e.GroupID}, ToAPITransfer(e), nil
+	case *Group:
+		return auditSubject{"group", e.ID, e.CreatedBy, e.ID}, ToAPIGroup(e, ""), nil
+	case *GroupMember:
+		return auditSubject{"group_member", e.UserID, e.UserID, e.GroupID}, ToAPIGroupMember(e), nil
+	case *GroupInvitation:
+		return auditSubject{"group_invitation", e.ID, e.UserID, e.GroupID}, ToAPIGroupInvitation(e), nil
+	case *UserErasureReportDto:
+		userID, err := uuid.Parse(e.UserID)
+		if err != nil {
+			return auditSubject{}, nil, fmt.Errorf("invalid user ID %s: %w", e.UserID, err)
+		}
+		// The event of an erasure must not keep the ID of the erased user
+		erasedID := ErasedUserAuditID(userID)
+		snapshot := *e
+		snapshot.UserID = erasedID.String()
+		return auditSubject{"user", erasedID, uuid.Nil, uuid.Nil}, &snapshot, nil
+	}
+	return auditSubject{}, nil, fmt.Errorf("unsupported audited entity %T", entity)
+}
+
+// NewAuditEvent creates the audit event of a change from the entity before and after it, either of which is nil
+// for creates and deletes. Both must be pointers to the same entity type.
+func NewAuditEvent(action string, before, after interface{}, actor, requestID string, at time.Time) (*AuditEvent, error) {
+	entity := after
+	if entity == nil {
+		entity = before
+	}
+	if entity == nil {
+		return nil, fmt.Errorf("audit event %s has no entity", action)
+	}
+	subject, _, err := auditSnapshot(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &AuditEvent{
+		ID:         uuid.New(),
+		EntityType: subject.EntityType,
+		EntityID:   subject.EntityID,
+		Action:     action,
+		UserID:     subject.UserID,
+		GroupID:    subject.GroupID,
+		Actor:      actor,
+		RequestID:  requestID,
+		CreatedAt:  at.UTC(),
+	}
+	if event.Before, err = marshalAuditSnapshot(before); err != nil {
+		return nil, err
+	}
+	if event.After, err = marshalAuditSnapshot(after); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewPurgeAuditEvent creates the audit event of a permanently deleted entity. It records which entity was purged
+// without any snapshot, so that no data of the entity is kept.
+func NewPurgeAuditEvent(entity interface{}, actor, requestID string, at time.Time) (*AuditEvent, error) {
+	subject, _, err := auditSnapshot(entity)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditEvent{
+		ID:         uuid.New(),
+		EntityType: subject.EntityType,
+		EntityID:   subject.EntityID,
+		Action:     AuditActionPurge,
+		UserID:     subject.UserID,
+		GroupID:    subject.GroupID,
+		Actor:      actor,
+		RequestID:  requestID,
+		CreatedAt:  at.UTC(),
+	}, nil
+}
+
+// marshalAuditSnapshot returns the JSON snapshot of the entity, nil if there is no entity
+func marshalAuditSnapshot(entity interface{}) (*string, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	_, snapshot, err := auditSnapshot(entity)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit snapshot: %w", err)
+	}
+	value := string(data)
+	return &value, nil
+}
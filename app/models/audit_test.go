@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewAuditEvent(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	before := &Balance{ID: uuid.New(), UserID: userID, GroupID: groupID, Title: "Cash", Currency: "EUR"}
+	after := *before
+	after.Title = "Wallet"
+
+	at := time.Date(2026, 10, 17, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	event, err := NewAuditEvent(AuditActionUpdate, before, &after, "user-sub", "req-1", at)
+	if err != nil {
+		t.Fatalf("Failed to create audit event: %v", err)
+	}
+	if event.EntityType != "balance" || event.EntityID != before.ID || event.UserID != userID || event.GroupID != groupID {
+		t.Errorf("Unexpected subject %+v", event)
+	}
+	if event.Actor != "user-sub" || event.RequestID != "req-1" || !event.CreatedAt.Equal(at) || event.CreatedAt.Location() != time.UTC {
+		t.Errorf("Unexpected actor, request or time %+v", event)
+	}
+
+	var snapshot BalanceDto
+	if event.Before == nil || json.Unmarshal([]byte(*event.Before), &snapshot) != nil || snapshot.Title != "Cash" {
+		t.Errorf("Unexpected before snapshot %v", event.Before)
+	}
+	if event.After == nil || json.Unmarshal([]byte(*event.After), &snapshot) != nil || snapshot.Title != "Wallet" {
+		t.Errorf("Unexpected after snapshot %v", event.After)
+	}
+}
+
+func TestNewAuditEventCreateAndDelete(t *testing.T) {
+	member := &GroupMember{GroupID: uuid.New(), UserID: uuid.New(), Role: GroupRoleEditor}
+
+	created, err := NewAuditEvent(AuditActionCreate, nil, member, AuditActorSystem, "", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to create audit event: %v", err)
+	}
+	if created.Before != nil || created.After == nil {
+		t.Errorf("Expected only an after snapshot for a create")
+	}
+	if created.EntityType != "group_member" || created.EntityID != member.UserID || created.GroupID != member.GroupID {
+		t.Errorf("Unexpected subject %+v", created)
+	}
+
+	deleted, err := NewAuditEvent(AuditActionDelete, member, nil, AuditActorSystem, "", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to create audit event: %v", err)
+	}
+	if deleted.Before == nil || deleted.After != nil {
+		t.Errorf("Expected only a before snapshot for a delete")
+	}
+}
+
+func TestNewAuditEventEraseKeepsNoUserID(t *testing.T) {
+	userID := uuid.New()
+	report := &UserErasureReportDto{UserID: userID.String(), Tables: []PurgedTableRowsDto{{Table: "balance", Rows: 2}}}
+
+	event, err := NewAuditEvent(AuditActionErase, nil, report, AuditActorSystem, "", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to create audit event: %v", err)
+	}
+	erasedID := ErasedUserAuditID(userID)
+	if event.EntityType != "user" || event.EntityID != erasedID || event.UserID != uuid.Nil || event.GroupID != uuid.Nil {
+		t.Errorf("Expected the erased user to be replaced by %s, got %+v", erasedID, event)
+	}
+	if event.After == nil || strings.Contains(*event.After, userID.String()) || !strings.Contains(*event.After, erasedID.String()) {
+		t.Errorf("Expected a snapshot without the user ID, got %v", event.After)
+	}
+	if report.UserID != userID.String() {
+		t.Errorf("Expected the report itself to be left unchanged, got %s", report.UserID)
+	}
+	if ErasedUserAuditID(userID) != erasedID || ErasedUserAuditID(uuid.New()) == erasedID {
+		t.Error("Expected the erased ID to be derived from the user ID only")
+	}
+}
+
+func TestNewAuditEventErrors(t *testing.T) {
+	if _, err := NewAuditEvent(AuditActionCreate, nil, nil, AuditActorSystem, "", time.Now()); err == nil {
+		t.Error("Expected error without entity")
+	}
+	if _, err := NewAuditEvent(AuditActionCreate, nil, &TransactionEntry{}, AuditActorSystem, "", time.Now()); err == nil {
+		t.Error("Expected error for unsupported entity")
+	}
+}
+
+func TestToAPIAuditEvent(t *testing.T) {
+	group := &CategoryGroup{ID: uuid.New(), Name: "Home"}
+	event, err := NewAuditEvent(AuditActionDelete, group, nil, "user-sub", "req-2", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to create audit event: %v", err)
+	}
+
+	dto := ToAPIAuditEvent(event)
+	if dto.EntityType != "category_group" || dto.EntityID != group.ID.String() || dto.Action != AuditActionDelete {
+		t.Errorf("Unexpected event %+v", dto)
+	}
+	if dto.UserID != "" || dto.GroupID != "" {
+		t.Errorf("Expected no owner for category group, got %q/%q", dto.UserID, dto.GroupID)
+	}
+	if dto.After != nil || !json.Valid(dto.Before) {
+		t.Errorf("Unexpected snapshots before=%s after=%s", dto.Before, dto.After)
+	}
+
+	data, err := json.Marshal(dto)
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+	if _, ok := decoded["before"].(map[string]interface{}); !ok {
+		t.Errorf("Expected before as JSON object, got %v", decoded["before"])
+	}
+	if _, ok := decoded["after"]; ok {
+		t.Error("Expected no after for a delete")
+	}
+}
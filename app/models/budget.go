@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Budget scopes
+const (
+	BudgetScopeCategory      = "category"      // Expenses of one category
+	BudgetScopeCategoryGroup = "categoryGroup" // Expenses of all categories of a category group
+	BudgetScopeMerchant      = "merchant"      // Expenses at one merchant
+	BudgetScopeGroup         = "group"         // All expenses of the group
+)
+
+// Budget periods. Recurring periods follow calendar boundaries in UTC, weeks start on Monday.
+const (
+	BudgetPeriodWeekly  = "weekly"
+	BudgetPeriodMonthly = "monthly"
+	BudgetPeriodYearly  = "yearly"
+	BudgetPeriodCustom  = "custom" // Single period from StartDate to EndDate
+)
+
+// Validate checks the scope, period, amount and currency of a budget
+func (b *Budget) Validate() error {
+	if b.Name == "" {
+		return &ValidationError{Field: "name", Reason: "is required"}
+	}
+
+	switch b.ScopeType {
+	case BudgetScopeCategory, BudgetScopeCategoryGroup, BudgetScopeMerchant:
+		if b.ScopeID == nil {
+			return &ValidationError{Field: "scopeId", Reason: fmt.Sprintf("is required for scope '%s'", b.ScopeType)}
+		}
+	case BudgetScopeGroup:
+		if b.ScopeID != nil {
+			return &ValidationError{Field: "scopeId", Reason: "must be empty for scope 'group'"}
+		}
+	default:
+		return &ValidationError{Field: "scopeType", Reason: fmt.Sprintf("'%s' is invalid, must be one of: category, categoryGroup, merchant, group", b.ScopeType)}
+	}
+
+	switch b.Period {
+	case BudgetPeriodWeekly, BudgetPeriodMonthly, BudgetPeriodYearly:
+		if b.EndDate != nil {
+			return &ValidationError{Field: "endDate", Reason: fmt.Sprintf("is only supported for period '%s'", BudgetPeriodCustom)}
+		}
+	case BudgetPeriodCustom:
+		if b.EndDate == nil || !b.EndDate.After(b.StartDate) {
+			return &ValidationError{Field: "endDate", Reason: fmt.Sprintf("after startDate is required for period '%s'", BudgetPeriodCustom)}
+		}
+	default:
+		return &ValidationError{Field: "period", Reason: fmt.Sprintf("'%s' is invalid, must be one of: weekly, monthly, yearly, custom", b.Period)}
+	}
+
+	if b.StartDate.IsZero() {
+		return &ValidationError{Field: "startDate", Reason: "is required"}
+	}
+	if b.Amount <= 0 {
+		return &ValidationError{Field: "amount", Reason: "must be greater than zero"}
+	}
+	if len(b.Currency) != 3 {
+		return &ValidationError{Field: "currency", Reason: "must be a 3-letter ISO 4217 code"}
+	}
+	return nil
+}
+
+// PeriodAt returns the budget period [start, end) containing the given time. Times before the budget start
+// fall into the first period.
+func (b *Budget) PeriodAt(t time.Time) (time.Time, time.Time) {
+	if t.Before(b.StartDate) {
+		t = b.StartDate
+	}
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch b.Period {
+	case BudgetPeriodWeekly:
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -daysSinceMonday)
+		return start, start.AddDate(0, 0, 7)
+	case BudgetPeriodMonthly:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	case BudgetPeriodYearly:
+		start := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0)
+	}
+
+	// Custom period
+	end := b.StartDate
+	if b.EndDate != nil {
+		end = *b.EndDate
+	}
+	return b.StartDate.UTC(), end.UTC()
+}
+
+// NewBudgetStatus reports the spending of a budget period as of the given time. The projected spend extrapolates
+// the spending so far linearly to the end of the period.
+func NewBudgetStatus(b *Budget, periodStart, periodEnd, asOf time.Time, spent int64, transactionsCount int) BudgetStatusDto {
+	projected := spent
+	if asOf.Before(periodEnd) {
+		elapsed := asOf.Sub(periodStart)
+		if elapsed > 0 {
+			ratio := float64(periodEnd.Sub(periodStart)) / float64(elapsed)
+			projected = int64(math.Round(float64(spent) * ratio))
+		}
+	}
+
+	percentUsed := 0.0
+	if b.Amount > 0 {
+		percentUsed = math.Round(float64(spent)*10000/float64(b.Amount)) / 100
+	}
+
+	return BudgetStatusDto{
+		BudgetID:          b.ID.String(),
+		Currency:          b.Currency,
+		PeriodStart:       periodStart.Format(time.RFC3339),
+		PeriodEnd:         periodEnd.Format(time.RFC3339),
+		AsOf:              asOf.UTC().Format(time.RFC3339),
+		Amount:            int(b.Amount),
+		Spent:             int(spent),
+		Remaining:         int(b.Amount - spent),
+		PercentUsed:       percentUsed,
+		ProjectedSpent:    int(projected),
+		TransactionsCount: transactionsCount,
+	}
+}
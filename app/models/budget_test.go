@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudget_PeriodAt(t *testing.T) {
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 3, 14, 18, 30, 0, 0, time.UTC) // Thursday
+
+	tests := []struct {
+		period        string
+		at            time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{BudgetPeriodWeekly, at, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)},
+		{BudgetPeriodMonthly, at, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{BudgetPeriodYearly, at, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{BudgetPeriodCustom, at, startDate, endDate},
+		{BudgetPeriodMonthly, time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			budget := &Budget{Period: tt.period, StartDate: startDate}
+			if tt.period == BudgetPeriodCustom {
+				budget.EndDate = &endDate
+			}
+
+			start, end := budget.PeriodAt(tt.at)
+			if !start.Equal(tt.expectedStart) || !end.Equal(tt.expectedEnd) {
+				t.Errorf("Expected period [%v, %v), got [%v, %v)", tt.expectedStart, tt.expectedEnd, start, end)
+			}
+		})
+	}
+}
+
+func TestNewBudgetStatus(t *testing.T) {
+	budget := &Budget{ID: NewBudgetID(), Amount: 60000, Currency: "EUR"}
+	periodStart := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// 10 of 30 days elapsed
+	status := NewBudgetStatus(budget, periodStart, periodEnd, time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC), 25000, 7)
+	if status.Spent != 25000 || status.Remaining != 35000 {
+		t.Errorf("Expected spent 25000 and remaining 35000, got %d and %d", status.Spent, status.Remaining)
+	}
+	if status.PercentUsed != 41.67 {
+		t.Errorf("Expected 41.67 percent used, got %v", status.PercentUsed)
+	}
+	if status.ProjectedSpent != 75000 {
+		t.Errorf("Expected projected spend 75000, got %d", status.ProjectedSpent)
+	}
+
+	// Finished period is not extrapolated
+	status = NewBudgetStatus(budget, periodStart, periodEnd, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), 70000, 12)
+	if status.ProjectedSpent != 70000 || status.Remaining != -10000 {
+		t.Errorf("Expected projected spend 70000 and remaining -10000, got %d and %d", status.ProjectedSpent, status.Remaining)
+	}
+}
+
+func TestBudget_Validate(t *testing.T) {
+	scopeID := NewCategoryID()
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	valid := Budget{Name: "Groceries", ScopeType: BudgetScopeCategory, ScopeID: &scopeID, Period: BudgetPeriodMonthly, StartDate: startDate, Amount: 40000, Currency: "EUR"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected budget to be valid, got error: %v", err)
+	}
+
+	missingScope := valid
+	missingScope.ScopeID = nil
+	if err := missingScope.Validate(); err == nil {
+		t.Error("Expected error for category scope without scopeId")
+	}
+
+	customWithoutEnd := valid
+	customWithoutEnd.Period = BudgetPeriodCustom
+	if err := customWithoutEnd.Validate(); err == nil {
+		t.Error("Expected error for custom period without endDate")
+	}
+
+	zeroAmount := valid
+	zeroAmount.Amount = 0
+	if err := zeroAmount.Validate(); err == nil {
+		t.Error("Expected error for zero amount")
+	}
+}
@@ -0,0 +1,230 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Validate checks that a categorization rule has a name, at least one condition, at least one action and a valid
+// description pattern and amount range
+func (r *CategorizationRule) Validate() error {
+	if r.Name == "" {
+		return &ValidationError{Field: "name", Reason: "is required"}
+	}
+
+	if r.MerchantID == nil && r.BalanceID == nil && r.DescriptionContains == nil && r.DescriptionPattern == nil &&
+		r.MinAmount == nil && r.MaxAmount == nil {
+		return &ValidationError{Field: "conditions", Reason: "must contain at least one condition"}
+	}
+	if r.DescriptionContains != nil && strings.TrimSpace(*r.DescriptionContains) == "" {
+		return &ValidationError{Field: "conditions.descriptionContains", Reason: "must not be blank"}
+	}
+	if r.DescriptionPattern != nil {
+		if _, err := regexp.Compile(*r.DescriptionPattern); err != nil {
+			return &ValidationError{Field: "conditions.descriptionPattern", Reason: fmt.Sprintf("is not a valid regular expression: %v", err)}
+		}
+	}
+	if r.MinAmount != nil && *r.MinAmount < 0 {
+		return &ValidationError{Field: "conditions.minAmount", Reason: "must not be negative"}
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MaxAmount < *r.MinAmount {
+		return &ValidationError{Field: "conditions.maxAmount", Reason: "must not be less than minAmount"}
+	}
+
+	if r.SetCategoryID == nil && r.SetMerchantID == nil && r.AppendDescription == nil {
+		return &ValidationError{Field: "actions", Reason: "must contain at least one action"}
+	}
+	if r.AppendDescription != nil && strings.TrimSpace(*r.AppendDescription) == "" {
+		return &ValidationError{Field: "actions.appendDescription", Reason: "must not be blank"}
+	}
+	return nil
+}
+
+// CategorizedTransactionTypes are the types of transactions categorization rules apply to, transfers and initial
+// balances are left alone
+var CategorizedTransactionTypes = []string{"income", "expense"}
+
+// CategorizationChange is the change a categorization rule makes to a transaction entry, fields that stay the same
+// are nil
+type CategorizationChange struct {
+	TransactionID uuid.UUID
+	EntryID       uuid.UUID
+	RuleID        uuid.UUID
+
+	CategoryID          *uuid.UUID // New category of the entry
+	PreviousCategoryID  *uuid.UUID
+	MerchantID          *uuid.UUID // New merchant of the transaction
+	PreviousMerchantID  *uuid.UUID
+	Description         *string // New description of the entry
+	PreviousDescription *string
+}
+
+// CategorizationRuleSet evaluates the enabled rules of a group in priority order, the first matching rule of an
+// entry is applied
+type CategorizationRuleSet struct {
+	rules []compiledCategorizationRule
+}
+
+// compiledCategorizationRule is a rule with its description pattern compiled once for all evaluated entries
+type compiledCategorizationRule struct {
+	rule    *CategorizationRule
+	pattern *regexp.Regexp
+}
+
+// NewCategorizationRuleSet compiles the enabled rules, ordered by priority and creation time
+func NewCategorizationRuleSet(rules []CategorizationRule) (*CategorizationRuleSet, error) {
+	set := &CategorizationRuleSet{}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Disabled {
+			continue
+		}
+		compiled := compiledCategorizationRule{rule: rule}
+		if rule.DescriptionPattern != nil {
+			pattern, err := regexp.Compile(*rule.DescriptionPattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid description pattern of categorization rule %s: %w", rule.ID, err)
+			}
+			compiled.pattern = pattern
+		}
+		set.rules = append(set.rules, compiled)
+	}
+	sort.SliceStable(set.rules, func(i, j int) bool {
+		a, b := set.rules[i].rule, set.rules[j].rule
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return set, nil
+}
+
+// Empty reports whether the set has no enabled rules
+func (s *CategorizationRuleSet) Empty() bool {
+	return s == nil || len(s.rules) == 0
+}
+
+// Match returns the first rule matching the entry of the transaction, nil if no rule matches
+func (s *CategorizationRuleSet) Match(tx *Transaction, entry *TransactionEntry) *CategorizationRule {
+	if s == nil {
+		return nil
+	}
+	for _, compiled := range s.rules {
+		if compiled.matches(tx, entry) {
+			return compiled.rule
+		}
+	}
+	return nil
+}
+
+// Categorize applies the rules to the entries without category of a new transaction and returns the changes made.
+// The merchant is only set if the transaction has none, a merchant set by the rule of one entry also applies to the
+// following entries.
+func (s *CategorizationRuleSet) Categorize(tx *Transaction) []CategorizationChange {
+	if s.Empty() || !slices.Contains(CategorizedTransactionTypes, tx.Type) {
+		return nil
+	}
+	var changes []CategorizationChange
+	for i := range tx.TransactionEntries {
+		entry := &tx.TransactionEntries[i]
+		if entry.CategoryID != nil {
+			continue
+		}
+		rule := s.Match(tx, entry)
+		if rule == nil {
+			continue
+		}
+		if change, ok := rule.Change(tx, entry, false); ok {
+			change.Apply(tx, entry)
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Change returns the change the rule makes to the entry of the transaction, false if the entry already looks the way
+// the rule would leave it. The category and merchant are only replaced if overwrite is set, the description text is
+// only appended once.
+func (r *CategorizationRule) Change(tx *Transaction, entry *TransactionEntry, overwrite bool) (CategorizationChange, bool) {
+	change := CategorizationChange{
+		TransactionID:       tx.ID,
+		EntryID:             entry.ID,
+		RuleID:              r.ID,
+		PreviousCategoryID:  entry.CategoryID,
+		PreviousMerchantID:  tx.MerchantID,
+		PreviousDescription: entry.Description,
+	}
+	changed := false
+
+	if r.SetCategoryID != nil && (entry.CategoryID == nil || (overwrite && *entry.CategoryID != *r.SetCategoryID)) {
+		categoryID := *r.SetCategoryID
+		change.CategoryID = &categoryID
+		changed = true
+	}
+	if r.SetMerchantID != nil && (tx.MerchantID == nil || (overwrite && *tx.MerchantID != *r.SetMerchantID)) {
+		merchantID := *r.SetMerchantID
+		change.MerchantID = &merchantID
+		changed = true
+	}
+	if r.AppendDescription != nil {
+		text := strings.TrimSpace(*r.AppendDescription)
+		description := ""
+		if entry.Description != nil {
+			description = *entry.Description
+		}
+		if !strings.Contains(description, text) {
+			description = strings.TrimSpace(description + " " + text)
+			change.Description = &description
+			changed = true
+		}
+	}
+	return change, changed
+}
+
+// Apply sets the new category, merchant and description of the change on the transaction and its entry
+func (c CategorizationChange) Apply(tx *Transaction, entry *TransactionEntry) {
+	if c.CategoryID != nil {
+		entry.CategoryID = c.CategoryID
+	}
+	if c.MerchantID != nil {
+		tx.MerchantID = c.MerchantID
+	}
+	if c.Description != nil {
+		entry.Description = c.Description
+	}
+}
+
+// matches reports whether the entry of the transaction meets all conditions of the rule. Description conditions are
+// case-insensitive substrings or regular expressions, amounts are compared in cents of the balance currency.
+func (c compiledCategorizationRule) matches(tx *Transaction, entry *TransactionEntry) bool {
+	rule := c.rule
+	if rule.MerchantID != nil && (tx.MerchantID == nil || *tx.MerchantID != *rule.MerchantID) {
+		return false
+	}
+	if rule.BalanceID != nil && tx.BalanceID != *rule.BalanceID {
+		return false
+	}
+	if rule.MinAmount != nil && entry.Amount < *rule.MinAmount {
+		return false
+	}
+	if rule.MaxAmount != nil && entry.Amount > *rule.MaxAmount {
+		return false
+	}
+
+	description := ""
+	if entry.Description != nil {
+		description = *entry.Description
+	}
+	if rule.DescriptionContains != nil && !strings.Contains(strings.ToLower(description), strings.ToLower(*rule.DescriptionContains)) {
+		return false
+	}
+	if c.pattern != nil && !c.pattern.MatchString(description) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestCategorizationRule_Validate(t *testing.T) {
+	categoryID := NewCategoryID()
+	valid := CategorizationRule{Name: "Supermarket", DescriptionContains: stringPtr("mercadona"), SetCategoryID: &categoryID}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected rule to be valid, got error: %v", err)
+	}
+
+	tests := map[string]struct {
+		modify func(r *CategorizationRule)
+		field  string
+	}{
+		"no conditions":   {func(r *CategorizationRule) { r.DescriptionContains = nil }, "conditions"},
+		"no actions":      {func(r *CategorizationRule) { r.SetCategoryID = nil }, "actions"},
+		"invalid pattern": {func(r *CategorizationRule) { r.DescriptionPattern = stringPtr("(") }, "conditions.descriptionPattern"},
+		"inverted range": {func(r *CategorizationRule) {
+			r.MinAmount, r.MaxAmount = int64Ptr(500), int64Ptr(100)
+		}, "conditions.maxAmount"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := valid
+			tt.modify(&rule)
+			var validation *ValidationError
+			if err := rule.Validate(); !errors.As(err, &validation) || validation.Field != tt.field {
+				t.Errorf("Expected a ValidationError of %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestCategorizationRuleSet_Categorize(t *testing.T) {
+	groceries, transport := NewCategoryID(), NewCategoryID()
+	merchantID := NewMerchantID()
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rules := []CategorizationRule{
+		{ID: NewCategorizationRuleID(), Name: "Fallback", Priority: 10, CreatedAt: createdAt,
+			MinAmount: int64Ptr(0), SetCategoryID: &transport},
+		{ID: NewCategorizationRuleID(), Name: "Supermarket", Priority: 1, CreatedAt: createdAt.Add(time.Hour),
+			DescriptionPattern: stringPtr(`(?i)mercadona|lidl`), MaxAmount: int64Ptr(20000),
+			SetCategoryID: &groceries, SetMerchantID: &merchantID, AppendDescription: stringPtr("#groceries")},
+		{ID: NewCategorizationRuleID(), Name: "Disabled", Priority: 0, Disabled: true,
+			MinAmount: int64Ptr(0), SetCategoryID: &transport},
+	}
+	ruleSet, err := NewCategorizationRuleSet(rules)
+	if err != nil {
+		t.Fatalf("Failed to compile rules: %v", err)
+	}
+
+	existingCategory := NewCategoryID()
+	tx := Transaction{
+		ID:   NewTransactionID(),
+		Type: "expense",
+		TransactionEntries: []TransactionEntry{
+			{ID: NewTransactionEntryID(), Amount: 4210, Description: stringPtr("Card payment MERCADONA")},
+			{ID: NewTransactionEntryID(), Amount: 50000, Description: stringPtr("Card payment Lidl")},
+			{ID: NewTransactionEntryID(), Amount: 100, CategoryID: &existingCategory},
+		},
+	}
+
+	changes := ruleSet.Categorize(&tx)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(changes))
+	}
+
+	first := tx.TransactionEntries[0]
+	if changes[0].RuleID != rules[1].ID || *first.CategoryID != groceries || *first.Description != "Card payment MERCADONA #groceries" {
+		t.Errorf("Expected the supermarket rule to categorize the first entry, got %+v", first)
+	}
+	if tx.MerchantID == nil || *tx.MerchantID != merchantID || changes[0].MerchantID == nil {
+		t.Errorf("Expected the supermarket rule to set the merchant, got %v", tx.MerchantID)
+	}
+
+	// Above the maximum amount of the supermarket rule, the fallback rule applies
+	second := tx.TransactionEntries[1]
+	if changes[1].RuleID != rules[0].ID || *second.CategoryID != transport || changes[1].MerchantID != nil {
+		t.Errorf("Expected the fallback rule to categorize the second entry, got %+v", changes[1])
+	}
+
+	if *tx.TransactionEntries[2].CategoryID != existingCategory {
+		t.Error("Expected the categorized entry to keep its category")
+	}
+
+	transfer := Transaction{Type: "move_out", TransactionEntries: []TransactionEntry{{Amount: 100}}}
+	if changes := ruleSet.Categorize(&transfer); len(changes) != 0 {
+		t.Errorf("Expected transfers not to be categorized, got %+v", changes)
+	}
+}
+
+func TestCategorizationRule_Change(t *testing.T) {
+	current, replacement := NewCategoryID(), NewCategoryID()
+	rule := CategorizationRule{ID: uuid.New(), SetCategoryID: &replacement, AppendDescription: stringPtr("#food")}
+	tx := Transaction{ID: NewTransactionID()}
+	entry := TransactionEntry{ID: NewTransactionEntryID(), CategoryID: &current, Description: stringPtr("Lunch #food")}
+
+	if _, ok := rule.Change(&tx, &entry, false); ok {
+		t.Error("Expected no change to a categorized entry already containing the text without overwrite")
+	}
+
+	change, ok := rule.Change(&tx, &entry, true)
+	if !ok || change.CategoryID == nil || *change.CategoryID != replacement || *change.PreviousCategoryID != current {
+		t.Errorf("Expected overwrite to replace the category, got %+v", change)
+	}
+	if change.Description != nil {
+		t.Errorf("Expected the description to stay the same, got %q", *change.Description)
+	}
+}
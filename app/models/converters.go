@@ -1,7 +1,9 @@
 package models
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,6 +23,16 @@ func buildCurrencyAmountsMap(amounts []TransactionEntryAmount) map[string]int {
 	return currencyAmounts
 }
 
+// exchangeRateDate returns the day of the exchange rates used for the amounts (YYYY-MM-DD), or empty string if unknown
+func exchangeRateDate(amounts []TransactionEntryAmount) string {
+	for _, amount := range amounts {
+		if amount.ExchangeRateDate != nil {
+			return amount.ExchangeRateDate.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
 // Conversion methods between DAO models (database) and DTO models (API)
 
 // ToAPICategory converts Category (DAO) to CategoryDto (API model)
@@ -105,7 +117,7 @@ func ToAPIBalance(b *Balance) BalanceDto {
 		desc = *b.Description
 	}
 
-	return BalanceDto{
+	dto := BalanceDto{
 		BalanceID:   b.ID.String(),
 		GroupID:     b.GroupID.String(),
 		UserID:      b.UserID.String(),
@@ -117,6 +129,20 @@ func ToAPIBalance(b *Balance) BalanceDto {
 		UpdatedAt:   b.UpdatedAt.Format(time.RFC3339),
 		DeletedAt:   formatTimePtr(b.DeletedAt),
 	}
+
+	// Add computed amounts if they were loaded
+	if b.Amount != nil {
+		amount := int(*b.Amount)
+		dto.Amount = &amount
+	}
+	if len(b.CurrencyAmounts) > 0 {
+		dto.CurrencyAmounts = make(map[string]int, len(b.CurrencyAmounts))
+		for currency, amount := range b.CurrencyAmounts {
+			dto.CurrencyAmounts[currency] = int(amount)
+		}
+	}
+
+	return dto
 }
 
 // ToAPICreateTransaction converts Transaction (DAO) to CreateTransactionDto (API model)
@@ -149,20 +175,27 @@ func ToAPICreateTransaction(t *Transaction) CreateTransactionDto {
 		approvedAt = t.ApprovedAt.Format(time.RFC3339)
 	}
 
+	// Convert the duplicate hint to string if available
+	var possibleDuplicateOf string
+	if t.PossibleDuplicateOf != nil {
+		possibleDuplicateOf = t.PossibleDuplicateOf.String()
+	}
+
 	return CreateTransactionDto{
-		TransactionID:      t.ID.String(),
-		UserID:             t.UserID.String(),
-		GroupID:            t.GroupID.String(),
-		BalanceID:          t.BalanceID.String(),
-		Type:               t.Type,
-		MerchantID:         merchantID,
-		OperationID:        operationID,
-		ApprovedAt:         approvedAt,
-		TransactedAt:       t.TransactedAt.Format(time.RFC3339),
-		CreatedAt:          t.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:          t.UpdatedAt.Format(time.RFC3339),
-		DeletedAt:          formatTimePtr(t.DeletedAt),
-		TransactionEntries: entryDtos,
+		TransactionID:       t.ID.String(),
+		UserID:              t.UserID.String(),
+		GroupID:             t.GroupID.String(),
+		BalanceID:           t.BalanceID.String(),
+		Type:                t.Type,
+		MerchantID:          merchantID,
+		OperationID:         operationID,
+		ApprovedAt:          approvedAt,
+		TransactedAt:        t.TransactedAt.Format(time.RFC3339),
+		CreatedAt:           t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           t.UpdatedAt.Format(time.RFC3339),
+		DeletedAt:           formatTimePtr(t.DeletedAt),
+		TransactionEntries:  entryDtos,
+		PossibleDuplicateOf: possibleDuplicateOf,
 	}
 }
 
@@ -568,6 +601,7 @@ func ToAPITransactionEntry(te *TransactionEntry) TransactionEntryDto {
 	merchantName := ""
 	merchantImageUrl := ""
 	operationID := ""
+	recurringTransactionID := ""
 	approvedAt := ""
 	transactedAt := ""
 
@@ -586,6 +620,9 @@ func ToAPITransactionEntry(te *TransactionEntry) TransactionEntryDto {
 		if te.Transaction.OperationID != nil {
 			operationID = te.Transaction.OperationID.String()
 		}
+		if te.Transaction.RecurringTransactionID != nil {
+			recurringTransactionID = te.Transaction.RecurringTransactionID.String()
+		}
 		if !te.Transaction.ApprovedAt.IsZero() {
 			approvedAt = te.Transaction.ApprovedAt.Format(time.RFC3339)
 		}
@@ -639,41 +676,61 @@ func ToAPITransactionEntry(te *TransactionEntry) TransactionEntryDto {
 	amountCents := te.Amount
 
 	return TransactionEntryDto{
-		GroupID:               groupID,
-		UserID:                userID,
-		BalanceID:             balanceID,
-		TransactionID:         transactionID,
-		TransactionEntryID:    te.ID.String(),
-		Type:                  transactionType,
-		Amount:                int(amountCents),
-		CurrencyAmounts:       buildCurrencyAmountsMap(te.TransactionEntryAmounts),
-		BalanceTitle:          balanceTitle,
-		BalanceCurrency:       balanceCurrency,
-		BalanceDeleted:        balanceDeleted,
-		CategoryID:            categoryID,
-		CategoryName:          categoryName,
-		CategoryImageUrl:      categoryImageUrl,
-		CategoryGroupName:     categoryGroupName,
-		CategoryGroupImageUrl: &categoryGroupImageUrl,
-		CategoryGroupID:       categoryGroupID,
-		CategoryIsDeleted:     categoryIsDeleted,
-		CategoryGroupDeleted:  categoryGroupDeleted,
-		MerchantName:          merchantName,
-		MerchantImageUrl:      merchantImageUrl,
-		OperationID:           operationID,
-		ApprovedAt:            approvedAt,
-		TransactedAt:          transactedAt,
-		CreatedAt:             te.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:             te.UpdatedAt.Format(time.RFC3339),
+		GroupID:                groupID,
+		UserID:                 userID,
+		BalanceID:              balanceID,
+		TransactionID:          transactionID,
+		TransactionEntryID:     te.ID.String(),
+		Type:                   transactionType,
+		Amount:                 int(amountCents),
+		CurrencyAmounts:        buildCurrencyAmountsMap(te.TransactionEntryAmounts),
+		BalanceTitle:           balanceTitle,
+		BalanceCurrency:        balanceCurrency,
+		BalanceDeleted:         balanceDeleted,
+		CategoryID:             categoryID,
+		CategoryName:           categoryName,
+		CategoryImageUrl:       categoryImageUrl,
+		CategoryGroupName:      categoryGroupName,
+		CategoryGroupImageUrl:  &categoryGroupImageUrl,
+		CategoryGroupID:        categoryGroupID,
+		CategoryIsDeleted:      categoryIsDeleted,
+		CategoryGroupDeleted:   categoryGroupDeleted,
+		MerchantName:           merchantName,
+		MerchantImageUrl:       merchantImageUrl,
+		OperationID:            operationID,
+		RecurringTransactionID: recurringTransactionID,
+		ApprovedAt:             approvedAt,
+		TransactedAt:           transactedAt,
+		CreatedAt:              te.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              te.UpdatedAt.Format(time.RFC3339),
 		DeletedAt: func() string {
 			if te.DeletedAt != nil {
 				return te.DeletedAt.Format(time.RFC3339)
 			}
 			return ""
 		}(),
+		Search: toAPISearchMatch(te.SearchMatch),
 	}
 }
 
+// toAPISearchMatch converts SearchMatch to SearchMatchDto, nil if the entry wasn't listed with a search
+func toAPISearchMatch(match *SearchMatch) *SearchMatchDto {
+	if match == nil {
+		return nil
+	}
+	dto := &SearchMatchDto{Rank: match.Rank}
+	if match.DescriptionSnippet != nil {
+		dto.Description = *match.DescriptionSnippet
+	}
+	if match.MerchantSnippet != nil {
+		dto.MerchantName = *match.MerchantSnippet
+	}
+	if match.CategorySnippet != nil {
+		dto.CategoryName = *match.CategorySnippet
+	}
+	return dto
+}
+
 // ToAPIMerchant converts Merchant (DAO) to MerchantDto (API model)
 func ToAPIMerchant(m *Merchant) MerchantDto {
 	if m == nil {
@@ -920,6 +977,7 @@ func ToAPISingleTransactionEntry(te *TransactionEntry, category *Category) Singl
 		TransactionEntryID: te.ID.String(),
 		Amount:             int(te.Amount),
 		CurrencyAmounts:    buildCurrencyAmountsMap(te.TransactionEntryAmounts),
+		ExchangeRateDate:   exchangeRateDate(te.TransactionEntryAmounts),
 		CreatedAt:          te.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:          te.UpdatedAt.Format(time.RFC3339),
 		DeletedAt:          formatTimePtr(te.DeletedAt),
@@ -950,3 +1008,681 @@ func ToAPISingleTransactionEntry(te *TransactionEntry, category *Category) Singl
 
 	return dto
 }
+
+// ToAPIGroup converts Group (DAO) to GroupDto (API model), the role is the role of the caller in the group
+func ToAPIGroup(g *Group, role string) GroupDto {
+	if g == nil {
+		return GroupDto{}
+	}
+
+	desc := ""
+	if g.Description != nil {
+		desc = *g.Description
+	}
+
+	var members []GroupMemberDto
+	for i := range g.Members {
+		members = append(members, ToAPIGroupMember(&g.Members[i]))
+	}
+
+	return GroupDto{
+		GroupID:     g.ID.String(),
+		Name:        g.Name,
+		Description: desc,
+		CreatedBy:   g.CreatedBy.String(),
+		Role:        role,
+		Members:     members,
+		CreatedAt:   g.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   g.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// FromAPIGroup converts GroupDto (API model) to Group (DAO). An empty group ID is left unset.
+func FromAPIGroup(g GroupDto) (*Group, error) {
+	id, err := parseUUID(g.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group ID format: %w", err)
+	}
+
+	var desc *string
+	if g.Description != "" {
+		desc = &g.Description
+	}
+
+	return &Group{
+		ID:          id,
+		Name:        g.Name,
+		Description: desc,
+	}, nil
+}
+
+// ToAPIGroupMember converts GroupMember (DAO) to GroupMemberDto (API model)
+func ToAPIGroupMember(m *GroupMember) GroupMemberDto {
+	if m == nil {
+		return GroupMemberDto{}
+	}
+	return GroupMemberDto{
+		UserID:    m.UserID.String(),
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAPIGroupInvitation converts GroupInvitation (DAO) to GroupInvitationDto (API model)
+func ToAPIGroupInvitation(i *GroupInvitation) GroupInvitationDto {
+	if i == nil {
+		return GroupInvitationDto{}
+	}
+
+	groupName := ""
+	if i.Group != nil {
+		groupName = i.Group.Name
+	}
+
+	return GroupInvitationDto{
+		InvitationID: i.ID.String(),
+		GroupID:      i.GroupID.String(),
+		GroupName:    groupName,
+		UserID:       i.UserID.String(),
+		Role:         i.Role,
+		Status:       i.Status,
+		InvitedBy:    i.InvitedBy.String(),
+		ExpiresAt:    i.ExpiresAt.Format(time.RFC3339),
+		RespondedAt:  formatTimePtr(i.RespondedAt),
+		CreatedAt:    i.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAPIBudget converts Budget (DAO) to BudgetDto (API model)
+func ToAPIBudget(b *Budget) BudgetDto {
+	if b == nil {
+		return BudgetDto{}
+	}
+
+	scopeID := ""
+	if b.ScopeID != nil {
+		scopeID = b.ScopeID.String()
+	}
+
+	return BudgetDto{
+		BudgetID:  b.ID.String(),
+		GroupID:   b.GroupID.String(),
+		UserID:    b.UserID.String(),
+		Name:      b.Name,
+		ScopeType: b.ScopeType,
+		ScopeID:   scopeID,
+		Period:    b.Period,
+		StartDate: b.StartDate.Format(time.RFC3339),
+		EndDate:   formatTimePtr(b.EndDate),
+		Amount:    int(b.Amount),
+		Currency:  b.Currency,
+		CreatedAt: b.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: b.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// FromAPIBudget converts BudgetDto (API model) to Budget (DAO). An empty budget ID is left unset.
+func FromAPIBudget(b BudgetDto) (*Budget, error) {
+	id, err := parseUUID(b.BudgetID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid budget ID format: %w", err)
+	}
+
+	userID, err := uuid.Parse(b.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	groupID, err := uuid.Parse(b.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group ID format: %w", err)
+	}
+
+	var scopeID *uuid.UUID
+	if b.ScopeID != "" {
+		parsed, err := uuid.Parse(b.ScopeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope ID format: %w", err)
+		}
+		scopeID = &parsed
+	}
+
+	startDate, err := parseBudgetDate(b.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startDate: %w", err)
+	}
+
+	var endDate *time.Time
+	if b.EndDate != "" {
+		parsed, err := parseBudgetDate(b.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endDate: %w", err)
+		}
+		endDate = &parsed
+	}
+
+	return &Budget{
+		ID:        id,
+		GroupID:   groupID,
+		UserID:    userID,
+		Name:      b.Name,
+		ScopeType: b.ScopeType,
+		ScopeID:   scopeID,
+		Period:    b.Period,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Amount:    int64(b.Amount),
+		Currency:  strings.ToUpper(b.Currency),
+	}, nil
+}
+
+// parseBudgetDate accepts an RFC 3339 timestamp or a plain date (YYYY-MM-DD, midnight UTC)
+func parseBudgetDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// ToAPIRecurringTransaction converts RecurringTransaction (DAO) to RecurringTransactionDto (API model)
+func ToAPIRecurringTransaction(rt *RecurringTransaction) RecurringTransactionDto {
+	if rt == nil {
+		return RecurringTransactionDto{}
+	}
+
+	// A stored template is always valid, it was validated before it was saved
+	template, _ := rt.TransactionTemplate()
+
+	return RecurringTransactionDto{
+		RecurringTransactionID: rt.ID.String(),
+		GroupID:                rt.GroupID.String(),
+		UserID:                 rt.UserID.String(),
+		Name:                   rt.Name,
+		Schedule:               rt.Schedule,
+		StartAt:                rt.StartAt.Format(time.RFC3339),
+		Paused:                 rt.Paused,
+		NextOccurrenceAt:       formatTimePtr(rt.NextOccurrenceAt),
+		LastOccurrenceAt:       formatTimePtr(rt.LastOccurrenceAt),
+		OccurrencesCount:       rt.OccurrencesCount,
+		Template:               template,
+		CreatedAt:              rt.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              rt.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// FromAPIRecurringTransaction converts RecurringTransactionDto (API model) to RecurringTransaction (DAO).
+// An empty recurring transaction ID is left unset, the group and user are taken from the template.
+func FromAPIRecurringTransaction(rt RecurringTransactionDto) (*RecurringTransaction, error) {
+	id, err := parseUUID(rt.RecurringTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurring transaction ID format: %w", err)
+	}
+
+	startAt, err := parseBudgetDate(rt.StartAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startAt: %w", err)
+	}
+
+	recurring := &RecurringTransaction{
+		ID:       id,
+		Name:     rt.Name,
+		Schedule: strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(rt.Schedule), "RRULE:")),
+		StartAt:  startAt,
+		Paused:   rt.Paused,
+	}
+	if err := recurring.SetTransactionTemplate(rt.Template); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// ToAPIImportProfile converts ImportProfile (DAO) to ImportProfileDto (API model)
+func ToAPIImportProfile(p *ImportProfile) ImportProfileDto {
+	if p == nil {
+		return ImportProfileDto{}
+	}
+
+	return ImportProfileDto{
+		ImportProfileID:   p.ID.String(),
+		GroupID:           p.GroupID.String(),
+		UserID:            p.UserID.String(),
+		Name:              p.Name,
+		Delimiter:         p.Delimiter,
+		HasHeader:         p.HasHeader,
+		SkipRows:          p.SkipRows,
+		DateColumn:        p.DateColumn,
+		DateFormat:        p.DateFormat,
+		AmountColumn:      p.AmountColumn,
+		DebitColumn:       p.DebitColumn,
+		CreditColumn:      p.CreditColumn,
+		DecimalSeparator:  p.DecimalSeparator,
+		InvertSign:        p.InvertSign,
+		DescriptionColumn: p.DescriptionColumn,
+		MerchantColumn:    p.MerchantColumn,
+		IDColumn:          p.IDColumn,
+		CreatedAt:         p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         p.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// FromAPIImportProfile converts ImportProfileDto (API model) to ImportProfile (DAO).
+// The delimiter defaults to "," and the decimal separator to ".".
+func FromAPIImportProfile(p ImportProfileDto) (*ImportProfile, error) {
+	id, err := parseUUID(p.ImportProfileID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import profile ID format: %w", err)
+	}
+
+	userID, err := uuid.Parse(p.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	groupID, err := uuid.Parse(p.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group ID format: %w", err)
+	}
+
+	delimiter := p.Delimiter
+	if delimiter == "" {
+		delimiter = ","
+	}
+	decimalSeparator := p.DecimalSeparator
+	if decimalSeparator == "" {
+		decimalSeparator = "."
+	}
+
+	return &ImportProfile{
+		ID:                id,
+		GroupID:           groupID,
+		UserID:            userID,
+		Name:              strings.TrimSpace(p.Name),
+		Delimiter:         delimiter,
+		HasHeader:         p.HasHeader,
+		SkipRows:          p.SkipRows,
+		DateColumn:        strings.TrimSpace(p.DateColumn),
+		DateFormat:        strings.TrimSpace(p.DateFormat),
+		AmountColumn:      strings.TrimSpace(p.AmountColumn),
+		DebitColumn:       strings.TrimSpace(p.DebitColumn),
+		CreditColumn:      strings.TrimSpace(p.CreditColumn),
+		DecimalSeparator:  decimalSeparator,
+		InvertSign:        p.InvertSign,
+		DescriptionColumn: strings.TrimSpace(p.DescriptionColumn),
+		MerchantColumn:    strings.TrimSpace(p.MerchantColumn),
+		IDColumn:          strings.TrimSpace(p.IDColumn),
+	}, nil
+}
+
+// FromAPIImportRequest converts ImportRequestDto (API model) to ImportTransactionsInput, decoding base64 content
+func FromAPIImportRequest(r ImportRequestDto) (*ImportTransactionsInput, error) {
+	userID, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	groupID, err := uuid.Parse(r.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group ID format: %w", err)
+	}
+
+	balanceID, err := uuid.Parse(r.BalanceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance ID format: %w", err)
+	}
+
+	var categoryID *uuid.UUID
+	if r.CategoryID != "" {
+		parsed, err := uuid.Parse(r.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid category ID format: %w", err)
+		}
+		categoryID = &parsed
+	}
+
+	format := strings.ToLower(strings.TrimSpace(r.Format))
+	switch format {
+	case ImportFormatCSV, ImportFormatOFX, ImportFormatQFX:
+	default:
+		return nil, fmt.Errorf("invalid format '%s', supported: csv, ofx, qfx", r.Format)
+	}
+
+	content := r.Content
+	switch r.ContentEncoding {
+	case "":
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(r.Content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 content: %w", err)
+		}
+		content = string(decoded)
+	default:
+		return nil, fmt.Errorf("invalid contentEncoding '%s', supported: base64", r.ContentEncoding)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	return &ImportTransactionsInput{
+		GroupID:    groupID,
+		UserID:     userID,
+		BalanceID:  balanceID,
+		Format:     format,
+		ProfileID:  r.ProfileID,
+		CategoryID: categoryID,
+		Content:    content,
+		DryRun:     r.DryRun,
+	}, nil
+}
+
+// ToAPIDuplicateCluster converts a cluster of duplicate candidates to DuplicateClusterDto (API model)
+func ToAPIDuplicateCluster(cluster []DuplicateCandidate) DuplicateClusterDto {
+	if len(cluster) == 0 {
+		return DuplicateClusterDto{}
+	}
+
+	first := cluster[0]
+	var merchantID string
+	if first.MerchantID != nil {
+		merchantID = first.MerchantID.String()
+	}
+
+	transactions := make([]DuplicateTransactionDto, len(cluster))
+	for i, candidate := range cluster {
+		var externalID string
+		if candidate.ExternalID != nil {
+			externalID = *candidate.ExternalID
+		}
+		transactions[i] = DuplicateTransactionDto{
+			TransactionID: candidate.TransactionID.String(),
+			GroupID:       candidate.GroupID.String(),
+			UserID:        candidate.UserID.String(),
+			Description:   candidate.Description,
+			ExternalID:    externalID,
+			TransactedAt:  candidate.TransactedAt.Format(time.RFC3339),
+			CreatedAt:     candidate.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return DuplicateClusterDto{
+		Fingerprint:  first.Fingerprint,
+		BalanceID:    first.BalanceID.String(),
+		Type:         first.Type,
+		Amount:       int(first.Amount),
+		MerchantID:   merchantID,
+		Transactions: transactions,
+	}
+}
+
+// FromAPITransfer converts TransferDto (API model) to TransferInput, transactedAt defaults to now
+func FromAPITransfer(t TransferDto) (*TransferInput, error) {
+	groupID, err := uuid.Parse(t.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group ID format: %w", err)
+	}
+
+	userID, err := uuid.Parse(t.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	fromBalanceID, err := uuid.Parse(t.FromBalanceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fromBalanceId format: %w", err)
+	}
+
+	toBalanceID, err := uuid.Parse(t.ToBalanceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid toBalanceId format: %w", err)
+	}
+
+	transactedAt := time.Now().UTC()
+	if t.TransactedAt != "" {
+		if transactedAt, err = time.Parse(time.RFC3339, t.TransactedAt); err != nil {
+			return nil, fmt.Errorf("invalid transactedAt format: %w", err)
+		}
+	}
+
+	var receivedAmount *int64
+	if t.ReceivedAmount != nil {
+		amount := int64(*t.ReceivedAmount)
+		receivedAmount = &amount
+	}
+
+	return &TransferInput{
+		GroupID:        groupID,
+		UserID:         userID,
+		FromBalanceID:  fromBalanceID,
+		ToBalanceID:    toBalanceID,
+		Amount:         int64(t.Amount),
+		ReceivedAmount: receivedAmount,
+		Description:    strings.TrimSpace(t.Description),
+		TransactedAt:   transactedAt,
+	}, nil
+}
+
+// ToAPITransfer converts Transfer (DAO) with its loaded legs to TransferDto (API model)
+func ToAPITransfer(t *Transfer) TransferDto {
+	if t == nil {
+		return TransferDto{}
+	}
+
+	dto := TransferDto{
+		TransferID:           t.ID.String(),
+		GroupID:              t.GroupID.String(),
+		UserID:               t.UserID.String(),
+		MoveOutTransactionID: t.MoveOutTransactionID.String(),
+		MoveInTransactionID:  t.MoveInTransactionID.String(),
+		CreatedAt:            t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:            t.UpdatedAt.Format(time.RFC3339),
+	}
+
+	var amount, receivedAmount int64
+	if t.MoveOut != nil {
+		amount = transferLegAmount(t.MoveOut)
+		dto.FromBalanceID = t.MoveOut.BalanceID.String()
+		dto.Description = transferLegDescription(t.MoveOut)
+		dto.TransactedAt = t.MoveOut.TransactedAt.Format(time.RFC3339)
+		if t.MoveOut.Balance != nil {
+			dto.FromCurrency = t.MoveOut.Balance.Currency
+		}
+	}
+	if t.MoveIn != nil {
+		receivedAmount = transferLegAmount(t.MoveIn)
+		dto.ToBalanceID = t.MoveIn.BalanceID.String()
+		if t.MoveIn.Balance != nil {
+			dto.ToCurrency = t.MoveIn.Balance.Currency
+		}
+	}
+
+	dto.Amount = int(amount)
+	received := int(receivedAmount)
+	dto.ReceivedAmount = &received
+	dto.Rate = TransferRate(amount, receivedAmount)
+	return dto
+}
+
+// ToAPICategorizationRule converts CategorizationRule (DAO) to CategorizationRuleDto (API model)
+func ToAPICategorizationRule(r *CategorizationRule) CategorizationRuleDto {
+	if r == nil {
+		return CategorizationRuleDto{}
+	}
+
+	dto := CategorizationRuleDto{
+		RuleID:   r.ID.String(),
+		GroupID:  r.GroupID.String(),
+		UserID:   r.UserID.String(),
+		Name:     r.Name,
+		Priority: r.Priority,
+		Disabled: r.Disabled,
+		Conditions: CategorizationRuleConditionsDto{
+			MerchantID:          formatUUIDPtr(r.MerchantID),
+			BalanceID:           formatUUIDPtr(r.BalanceID),
+			DescriptionContains: formatStringPtr(r.DescriptionContains),
+			DescriptionPattern:  formatStringPtr(r.DescriptionPattern),
+		},
+		Actions: CategorizationRuleActionsDto{
+			CategoryID:        formatUUIDPtr(r.SetCategoryID),
+			MerchantID:        formatUUIDPtr(r.SetMerchantID),
+			AppendDescription: formatStringPtr(r.AppendDescription),
+		},
+		CreatedAt: r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: r.UpdatedAt.Format(time.RFC3339),
+	}
+	if r.MinAmount != nil {
+		minAmount := int(*r.MinAmount)
+		dto.Conditions.MinAmount = &minAmount
+	}
+	if r.MaxAmount != nil {
+		maxAmount := int(*r.MaxAmount)
+		dto.Conditions.MaxAmount = &maxAmount
+	}
+	return dto
+}
+
+// FromAPICategorizationRule converts CategorizationRuleDto (API model) to CategorizationRule (DAO). An empty rule ID
+// is left unset.
+func FromAPICategorizationRule(r CategorizationRuleDto) (*CategorizationRule, error) {
+	id, err := parseUUID(r.RuleID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule ID format: %w", err)
+	}
+
+	userID, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	groupID, err := uuid.Parse(r.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group ID format: %w", err)
+	}
+
+	rule := &CategorizationRule{
+		ID:                  id,
+		GroupID:             groupID,
+		UserID:              userID,
+		Name:                strings.TrimSpace(r.Name),
+		Priority:            r.Priority,
+		Disabled:            r.Disabled,
+		DescriptionContains: optionalString(r.Conditions.DescriptionContains),
+		DescriptionPattern:  optionalString(r.Conditions.DescriptionPattern),
+		AppendDescription:   optionalString(r.Actions.AppendDescription),
+	}
+
+	uuidFields := []struct {
+		Name  string
+		Value string
+		Dest  **uuid.UUID
+	}{
+		{"conditions.merchantId", r.Conditions.MerchantID, &rule.MerchantID},
+		{"conditions.balanceId", r.Conditions.BalanceID, &rule.BalanceID},
+		{"actions.categoryId", r.Actions.CategoryID, &rule.SetCategoryID},
+		{"actions.merchantId", r.Actions.MerchantID, &rule.SetMerchantID},
+	}
+	for _, field := range uuidFields {
+		if field.Value == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s format: %w", field.Name, err)
+		}
+		*field.Dest = &parsed
+	}
+
+	if r.Conditions.MinAmount != nil {
+		minAmount := int64(*r.Conditions.MinAmount)
+		rule.MinAmount = &minAmount
+	}
+	if r.Conditions.MaxAmount != nil {
+		maxAmount := int64(*r.Conditions.MaxAmount)
+		rule.MaxAmount = &maxAmount
+	}
+	return rule, nil
+}
+
+// FromAPIApplyCategorizationRules converts ApplyCategorizationRulesDto (API model) to ApplyCategorizationRulesInput
+func FromAPIApplyCategorizationRules(r ApplyCategorizationRulesDto) (*ApplyCategorizationRulesInput, error) {
+	if _, err := uuid.Parse(r.GroupID); err != nil {
+		return nil, fmt.Errorf("invalid group ID format: %w", err)
+	}
+	if r.UserID != "" {
+		if _, err := uuid.Parse(r.UserID); err != nil {
+			return nil, fmt.Errorf("invalid user ID format: %w", err)
+		}
+	}
+
+	startTime, err := parseBudgetDate(r.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startTime: %w", err)
+	}
+	endTime, err := parseBudgetDate(r.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endTime: %w", err)
+	}
+
+	return &ApplyCategorizationRulesInput{
+		GroupID:            r.GroupID,
+		UserID:             r.UserID,
+		RuleIDs:            r.RuleIDs,
+		BalanceIDs:         r.BalanceIDs,
+		StartTime:          startTime,
+		EndTime:            endTime,
+		IncludeCategorized: r.IncludeCategorized,
+		Preview:            r.Preview,
+	}, nil
+}
+
+// ToAPICategorizationChange converts a CategorizationChange to CategorizationChangeDto (API model)
+func ToAPICategorizationChange(c CategorizationChange) CategorizationChangeDto {
+	dto := CategorizationChangeDto{
+		TransactionID:      c.TransactionID.String(),
+		TransactionEntryID: c.EntryID.String(),
+		RuleID:             c.RuleID.String(),
+	}
+	if c.CategoryID != nil {
+		dto.CategoryID = c.CategoryID.String()
+		dto.PreviousCategoryID = formatUUIDPtr(c.PreviousCategoryID)
+	}
+	if c.MerchantID != nil {
+		dto.MerchantID = c.MerchantID.String()
+		dto.PreviousMerchantID = formatUUIDPtr(c.PreviousMerchantID)
+	}
+	if c.Description != nil {
+		dto.Description = *c.Description
+		dto.PreviousDescription = formatStringPtr(c.PreviousDescription)
+	}
+	return dto
+}
+
+// formatUUIDPtr returns the string form of an optional UUID, empty if it is nil
+func formatUUIDPtr(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// formatStringPtr returns the value of an optional string, empty if it is nil
+func formatStringPtr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// optionalString returns nil for an empty string, a pointer to the string otherwise
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
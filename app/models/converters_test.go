@@ -232,6 +232,41 @@ func TestToAPITransactionEntry_SoftDeletedCategoryGroup(t *testing.T) {
 	}
 }
 
+func TestToAPIBalance_WithAmounts(t *testing.T) {
+	amount := int64(125050)
+	balance := &Balance{
+		ID:       uuid.New(),
+		Currency: "EUR",
+		Amount:   &amount,
+		CurrencyAmounts: map[string]int64{
+			"EUR": 125050,
+			"USD": 135600,
+		},
+	}
+
+	result := ToAPIBalance(balance)
+
+	if result.Amount == nil || *result.Amount != 125050 {
+		t.Errorf("Expected amount to be 125050 cents, got %v", result.Amount)
+	}
+
+	if result.CurrencyAmounts["USD"] != 135600 {
+		t.Errorf("Expected USD amount to be 135600 cents, got %d", result.CurrencyAmounts["USD"])
+	}
+}
+
+func TestToAPIBalance_WithoutAmounts(t *testing.T) {
+	result := ToAPIBalance(&Balance{ID: uuid.New(), Currency: "EUR"})
+
+	if result.Amount != nil {
+		t.Errorf("Expected amount to be omitted, got %d", *result.Amount)
+	}
+
+	if result.CurrencyAmounts != nil {
+		t.Errorf("Expected currencyAmounts to be omitted, got %v", result.CurrencyAmounts)
+	}
+}
+
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
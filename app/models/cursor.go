@@ -0,0 +1,180 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or its signature does not match
+var ErrInvalidCursor error = &ValidationError{Field: "nextKey", Reason: "is not a valid pagination cursor"}
+
+// PageCursor identifies the last item of a page for keyset pagination.
+// It holds the value of the sort key plus the item ID used as a tie-breaker,
+// together with the sorting the cursor was issued for.
+type PageCursor struct {
+	SortBy string `json:"s,omitempty"`
+	Order  string `json:"o,omitempty"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+// NewPageCursor creates a cursor from a sort key value (time.Time, int, int64 or string) and an item ID
+func NewPageCursor(sortBy, order string, value interface{}, id uuid.UUID) PageCursor {
+	cursor := PageCursor{
+		SortBy: sortBy,
+		Order:  strings.ToLower(order),
+		ID:     id.String(),
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		cursor.Value = v.UTC().Format(time.RFC3339Nano)
+	case int:
+		cursor.Value = strconv.Itoa(v)
+	case int64:
+		cursor.Value = strconv.FormatInt(v, 10)
+	default:
+		cursor.Value = fmt.Sprint(v)
+	}
+
+	return cursor
+}
+
+// Matches reports whether the cursor was issued for the given sorting
+func (c PageCursor) Matches(sortBy, order string) bool {
+	return c.SortBy == sortBy && c.Order == strings.ToLower(order)
+}
+
+// TimeValue returns the sort key value as time
+func (c PageCursor) TimeValue() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, c.Value)
+}
+
+// IntValue returns the sort key value as integer
+func (c PageCursor) IntValue() (int64, error) {
+	return strconv.ParseInt(c.Value, 10, 64)
+}
+
+// FloatValue returns the sort key value as floating point number
+func (c PageCursor) FloatValue() (float64, error) {
+	return strconv.ParseFloat(c.Value, 64)
+}
+
+// EncodePageCursor serializes the cursor into an opaque, URL-safe token signed with HMAC-SHA256
+func EncodePageCursor(cursor PageCursor, secret []byte) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pagination cursor: %w", err)
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	signature := base64.RawURLEncoding.EncodeToString(signCursorPayload(encodedPayload, secret))
+	return encodedPayload + "." + signature, nil
+}
+
+// DecodePageCursor verifies the token signature and deserializes the cursor
+func DecodePageCursor(token string, secret []byte) (*PageCursor, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signCursorPayload(encodedPayload, secret)) {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor PageCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(cursor.ID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func signCursorPayload(encodedPayload string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encodedPayload))
+	return mac.Sum(nil)
+}
+
+// TransactionEntryCursor creates the cursor pointing after the given entry for the sorting used by ListTransactionEntries
+func TransactionEntryCursor(entry *TransactionEntry, sortBy, order string) PageCursor {
+	var value interface{} = entry.CreatedAt
+	switch sortBy {
+	case "transactedAt":
+		value = entry.Transaction.TransactedAt
+	case "amount":
+		value = entry.Amount
+	case SortByRelevance:
+		// The shortest 'g' format parses back to the identical float64, so the (rank, id) keyset comparison finds
+		// entries tied on rank. The rank must come from the same float8 expression when the page is read and when
+		// the cursor is compared, a rank recomputed with another precision (e.g. float4) would skip or repeat ties.
+		if entry.SearchMatch != nil {
+			value = strconv.FormatFloat(entry.SearchMatch.Rank, 'g', -1, 64)
+		}
+	}
+	return NewPageCursor(sortBy, order, value, entry.ID)
+}
+
+// BalanceCursor creates the cursor pointing after the given balance for the sorting used by ListBalances
+func BalanceCursor(balance *Balance, sortBy, order string) PageCursor {
+	var value interface{} = balance.CreatedAt
+	switch sortBy {
+	case "rank":
+		value = balance.Rank
+	case "updatedAt":
+		value = balance.UpdatedAt
+	case "title", "name":
+		value = balance.Title
+	}
+	return NewPageCursor(sortBy, order, value, balance.ID)
+}
+
+// MerchantCursor creates the cursor pointing after the given merchant for the sorting used by ListMerchants
+func MerchantCursor(merchant *Merchant, sortBy, order string) PageCursor {
+	var value interface{} = merchant.CreatedAt
+	switch sortBy {
+	case "rank":
+		value = merchant.Rank
+	case "name":
+		value = merchant.Name
+	case "updatedAt":
+		value = merchant.UpdatedAt
+	}
+	return NewPageCursor(sortBy, order, value, merchant.ID)
+}
+
+// CategoryCursor creates the cursor pointing after the given category for the sorting used by ListCategories
+func CategoryCursor(category *Category, sortBy, order string) PageCursor {
+	var value interface{} = category.Name
+	switch sortBy {
+	case "rank":
+		rank := 0
+		if category.Rank != nil {
+			rank = *category.Rank
+		}
+		value = rank
+	case "createdAt":
+		value = category.CreatedAt
+	case "updatedAt":
+		value = category.UpdatedAt
+	}
+	return NewPageCursor(sortBy, order, value, category.ID)
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPageCursor_EncodeDecode(t *testing.T) {
+	secret := []byte("test-secret")
+	transactedAt := time.Date(2024, 3, 15, 10, 30, 0, 123456000, time.UTC)
+	entryID := NewTransactionEntryID()
+
+	token, err := EncodePageCursor(NewPageCursor("transactedAt", "DESC", transactedAt, entryID), secret)
+	if err != nil {
+		t.Fatalf("Expected cursor to be encoded, got error: %v", err)
+	}
+
+	cursor, err := DecodePageCursor(token, secret)
+	if err != nil {
+		t.Fatalf("Expected cursor to be decoded, got error: %v", err)
+	}
+
+	if cursor.ID != entryID.String() {
+		t.Errorf("Expected cursor ID to be '%s', got '%s'", entryID.String(), cursor.ID)
+	}
+
+	if !cursor.Matches("transactedAt", "desc") {
+		t.Errorf("Expected cursor to match sorting transactedAt/desc, got %s/%s", cursor.SortBy, cursor.Order)
+	}
+
+	value, err := cursor.TimeValue()
+	if err != nil || !value.Equal(transactedAt) {
+		t.Errorf("Expected cursor value to be %v, got %v (error: %v)", transactedAt, value, err)
+	}
+}
+
+func TestPageCursor_IntValue(t *testing.T) {
+	cursor := NewPageCursor("amount", "", int64(4550), uuid.New())
+
+	value, err := cursor.IntValue()
+	if err != nil || value != 4550 {
+		t.Errorf("Expected cursor value to be 4550, got %d (error: %v)", value, err)
+	}
+}
+
+func TestPageCursor_RejectsTamperedToken(t *testing.T) {
+	secret := []byte("test-secret")
+	token, err := EncodePageCursor(NewPageCursor("amount", "asc", 100, uuid.New()), secret)
+	if err != nil {
+		t.Fatalf("Expected cursor to be encoded, got error: %v", err)
+	}
+
+	// Wrong secret
+	if _, err := DecodePageCursor(token, []byte("other-secret")); err == nil {
+		t.Error("Expected cursor signed with another secret to be rejected")
+	}
+
+	// Modified payload
+	payload, signature, _ := strings.Cut(token, ".")
+	forged, _ := EncodePageCursor(NewPageCursor("amount", "asc", 1, uuid.New()), []byte("other-secret"))
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	if _, err := DecodePageCursor(forgedPayload+"."+signature, secret); err == nil {
+		t.Error("Expected cursor with modified payload to be rejected")
+	}
+
+	// Malformed token
+	if _, err := DecodePageCursor(payload, secret); err == nil {
+		t.Error("Expected cursor without signature to be rejected")
+	}
+}
+
+func TestTransactionEntryCursor_RankTieRoundTrip(t *testing.T) {
+	// The keyset predicate built from the cursor is tested with the repository's pagination
+	secret := []byte("test-secret")
+	rank := 0.0607927106320858 // Typical ts_rank value, not exactly representable in decimal
+	entry := &TransactionEntry{ID: uuid.MustParse("00000000-0000-0000-0000-0000000000b0"), SearchMatch: &SearchMatch{Rank: rank}}
+
+	token, err := EncodePageCursor(TransactionEntryCursor(entry, SortByRelevance, "DESC"), secret)
+	if err != nil {
+		t.Fatalf("Expected cursor to be encoded, got error: %v", err)
+	}
+	cursor, err := DecodePageCursor(token, secret)
+	if err != nil {
+		t.Fatalf("Expected cursor to be decoded, got error: %v", err)
+	}
+
+	value, err := cursor.FloatValue()
+	if err != nil {
+		t.Fatalf("Expected cursor value to be a float, got error: %v", err)
+	}
+	if value != rank {
+		t.Fatalf("Expected cursor rank %v to round-trip exactly, got %v", rank, value)
+	}
+	if cursor.ID != entry.ID.String() {
+		t.Errorf("Expected cursor ID %s, got %s", entry.ID, cursor.ID)
+	}
+}
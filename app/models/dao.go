@@ -23,6 +23,10 @@ type Balance struct {
 	UpdatedAt   time.Time  `gorm:"default:now()"`
 	DeletedAt   *time.Time `gorm:"index"`
 
+	// Computed fields (not persisted), populated when the balance is read with amounts
+	Amount          *int64           `gorm:"-"` // Running amount in cents in the balance currency
+	CurrencyAmounts map[string]int64 `gorm:"-"` // Running amount in cents per currency from transaction_entry_amount
+
 	// Relationships
 	Transactions []Transaction `gorm:"foreignKey:BalanceID"`
 }
@@ -105,14 +109,31 @@ type Transaction struct {
 	OperationID  *uuid.UUID `gorm:"type:uuid;index:idx_transaction_operation_id"`
 	ApprovedAt   time.Time  `gorm:"not null"`
 	TransactedAt time.Time  `gorm:"not null;index:idx_transaction_transacted_at"`
-	CreatedAt    time.Time  `gorm:"default:now()"`
-	UpdatedAt    time.Time  `gorm:"default:now()"`
-	DeletedAt    *time.Time `gorm:"index"`
+
+	// Recurring transaction template that generated the transaction and the occurrence it was generated for,
+	// unique together so an occurrence is never materialized twice
+	RecurringTransactionID *uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_transaction_recurring_occurrence"`
+	RecurringOccurrenceAt  *time.Time `gorm:"uniqueIndex:idx_transaction_recurring_occurrence"`
+
+	// Bank reference of an imported transaction (OFX FITID, CSV ID column or a fingerprint of the CSV row), used to
+	// skip rows of a statement that were already imported into the balance
+	ExternalID *string `gorm:"type:varchar(255);index:idx_transaction_external_id"`
+
+	// Balance, type, total amount and merchant of the transaction, transactions with the same fingerprint close in
+	// time are suspected duplicates (see TransactionFingerprint)
+	Fingerprint *string `gorm:"type:varchar(128);index:idx_transaction_fingerprint"`
+
+	CreatedAt time.Time  `gorm:"default:now()"`
+	UpdatedAt time.Time  `gorm:"default:now()"`
+	DeletedAt *time.Time `gorm:"index"`
 
 	// Relationships
 	Merchant           *Merchant          `gorm:"foreignKey:MerchantID;constraint:OnDelete:SET NULL"`
 	Balance            *Balance           `gorm:"foreignKey:BalanceID"`
 	TransactionEntries []TransactionEntry `gorm:"foreignKey:TransactionID"`
+
+	// Earlier transaction with the same fingerprint close in time, set by CreateTransaction only (not persisted)
+	PossibleDuplicateOf *uuid.UUID `gorm:"-"`
 }
 
 // TableName specifies the table name for GORM
@@ -135,16 +156,19 @@ type TransactionEntry struct {
 	Transaction             *Transaction             `gorm:"foreignKey:TransactionID"`
 	Category                *Category                `gorm:"foreignKey:CategoryID;references:ID"`
 	TransactionEntryAmounts []TransactionEntryAmount `gorm:"foreignKey:TransactionEntryID"`
+
+	SearchMatch *SearchMatch `gorm:"-"` // Set when listed with a full-text search
 }
 
 // TransactionEntryAmount represents amounts in different currencies for a transaction entry
 type TransactionEntryAmount struct {
-	TransactionEntryID uuid.UUID `gorm:"type:uuid;not null;primaryKey;index:idx_transaction_entry_amount_entry_id"`
-	Currency           string    `gorm:"type:varchar(3);not null;primaryKey"` // ISO 4217 currency codes (3 chars)
-	Amount             int64     `gorm:"type:bigint;not null"`                // Amount in cents for the specific currency
-	ExchangeRate       float64   `gorm:"type:decimal(10,6);not null"`         // Exchange rate used for conversion
-	CreatedAt          time.Time `gorm:"default:now()"`
-	UpdatedAt          time.Time `gorm:"default:now()"`
+	TransactionEntryID uuid.UUID  `gorm:"type:uuid;not null;primaryKey;index:idx_transaction_entry_amount_entry_id"`
+	Currency           string     `gorm:"type:varchar(3);not null;primaryKey"` // ISO 4217 currency codes (3 chars)
+	Amount             int64      `gorm:"type:bigint;not null"`                // Amount in cents for the specific currency
+	ExchangeRate       float64    `gorm:"type:decimal(10,6);not null"`         // Exchange rate used for conversion
+	ExchangeRateDate   *time.Time `gorm:"type:date"`                           // Day of the exchange rate actually used (nearest available on or before transactedAt)
+	CreatedAt          time.Time  `gorm:"default:now()"`
+	UpdatedAt          time.Time  `gorm:"default:now()"`
 
 	// Relationships
 	TransactionEntry *TransactionEntry `gorm:"foreignKey:TransactionEntryID"`
@@ -160,6 +184,242 @@ func (TransactionEntryAmount) TableName() string {
 	return "transaction_entry_amount"
 }
 
+// Group represents a household (or any other set of users) sharing balances, transactions, categories and merchants.
+// Data is linked to a group by its GroupID column.
+type Group struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string     `gorm:"type:varchar(100);not null"`
+	Description *string    `gorm:"type:varchar(500)"`
+	CreatedBy   uuid.UUID  `gorm:"type:uuid;not null"` // User who created the group
+	CreatedAt   time.Time  `gorm:"default:now()"`
+	UpdatedAt   time.Time  `gorm:"default:now()"`
+	DeletedAt   *time.Time `gorm:"index"`
+
+	// Relationships
+	Members []GroupMember `gorm:"foreignKey:GroupID"`
+}
+
+// TableName specifies the table name for GORM ("group" is a reserved word in SQL)
+func (Group) TableName() string {
+	return "user_group"
+}
+
+// Group member roles
+const (
+	GroupRoleOwner  = "owner"  // Manages the group, its members and invitations, full access to data
+	GroupRoleEditor = "editor" // Creates, updates and deletes data of the group
+	GroupRoleViewer = "viewer" // Read-only access to data of the group
+)
+
+// IsValidGroupRole checks whether the role is one of the supported group member roles
+func IsValidGroupRole(role string) bool {
+	switch role {
+	case GroupRoleOwner, GroupRoleEditor, GroupRoleViewer:
+		return true
+	}
+	return false
+}
+
+// GroupMember represents the membership of a user in a group with a role
+type GroupMember struct {
+	GroupID   uuid.UUID `gorm:"type:uuid;not null;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;primaryKey;index:idx_group_member_user_id"`
+	Role      string    `gorm:"type:varchar(10);not null"`
+	CreatedAt time.Time `gorm:"default:now()"`
+	UpdatedAt time.Time `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (GroupMember) TableName() string {
+	return "group_member"
+}
+
+// Group invitation statuses
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// GroupInvitation represents an invitation of a user to join a group with a role
+type GroupInvitation struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GroupID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_group_invitation_group_id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index:idx_group_invitation_user_id"` // Invited user
+	Role        string     `gorm:"type:varchar(10);not null"`
+	Status      string     `gorm:"type:varchar(10);not null;default:'pending'"`
+	InvitedBy   uuid.UUID  `gorm:"type:uuid;not null"`
+	ExpiresAt   time.Time  `gorm:"not null"`
+	RespondedAt *time.Time // When the invitation was accepted, declined or revoked
+	CreatedAt   time.Time  `gorm:"default:now()"`
+	UpdatedAt   time.Time  `gorm:"default:now()"`
+
+	// Relationships
+	Group *Group `gorm:"foreignKey:GroupID"`
+}
+
+// TableName specifies the table name for GORM
+func (GroupInvitation) TableName() string {
+	return "group_invitation"
+}
+
+// IsPending reports whether the invitation can still be accepted or declined
+func (i *GroupInvitation) IsPending(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.Before(i.ExpiresAt)
+}
+
+// Budget represents the amount a group intends to spend per period on a category, a category group, a merchant
+// or on all of its expenses
+type Budget struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GroupID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_budget_group_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_budget_user_id"`
+	Name      string     `gorm:"type:varchar(100);not null"`
+	ScopeType string     `gorm:"type:varchar(20);not null"`           // category, categoryGroup, merchant or group
+	ScopeID   *uuid.UUID `gorm:"type:uuid;index:idx_budget_scope_id"` // Category, category group or merchant ID, nil for the group scope
+	Period    string     `gorm:"type:varchar(10);not null"`           // weekly, monthly, yearly or custom
+	StartDate time.Time  `gorm:"not null"`                            // Budget is effective from this date, start of a custom period
+	EndDate   *time.Time // End of a custom period (exclusive), nil for recurring periods
+	Amount    int64      `gorm:"type:bigint;not null"`     // Limit in cents per period
+	Currency  string     `gorm:"type:varchar(3);not null"` // ISO 4217 currency code of the limit
+	CreatedAt time.Time  `gorm:"default:now()"`
+	UpdatedAt time.Time  `gorm:"default:now()"`
+	DeletedAt *time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for GORM
+func (Budget) TableName() string {
+	return "budget"
+}
+
+// RecurringTransaction is a template of a transaction that is created on every occurrence of an RRULE-style schedule
+type RecurringTransaction struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GroupID          uuid.UUID  `gorm:"type:uuid;not null;index:idx_recurring_transaction_group_id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index:idx_recurring_transaction_user_id"`
+	BalanceID        uuid.UUID  `gorm:"type:uuid;not null;index:idx_recurring_transaction_balance_id"`
+	Name             string     `gorm:"type:varchar(100);not null"`
+	Template         string     `gorm:"type:jsonb;not null"`        // CreateTransactionDto of the generated transactions
+	Schedule         string     `gorm:"type:varchar(255);not null"` // RRULE, e.g. FREQ=MONTHLY;BYMONTHDAY=1
+	StartAt          time.Time  `gorm:"not null"`                   // DTSTART of the schedule, also sets the time of day of occurrences
+	Paused           bool       `gorm:"not null;default:false"`
+	NextOccurrenceAt *time.Time `gorm:"index:idx_recurring_transaction_next_occurrence_at"` // First occurrence not materialized yet, nil when the schedule has ended
+	LastOccurrenceAt *time.Time // Last materialized occurrence
+	OccurrencesCount int        `gorm:"not null;default:0"` // Number of materialized occurrences
+	CreatedAt        time.Time  `gorm:"default:now()"`
+	UpdatedAt        time.Time  `gorm:"default:now()"`
+	DeletedAt        *time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for GORM
+func (RecurringTransaction) TableName() string {
+	return "recurring_transaction"
+}
+
+// ImportProfile is a saved column mapping used to import CSV bank statements. Columns are referenced by their header
+// name, or by their 1-based position for files without a header row.
+type ImportProfile struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GroupID           uuid.UUID  `gorm:"type:uuid;not null;index:idx_import_profile_group_id"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;index:idx_import_profile_user_id"`
+	Name              string     `gorm:"type:varchar(100);not null"`
+	Delimiter         string     `gorm:"type:varchar(1);not null;default:','"`
+	HasHeader         bool       `gorm:"not null;default:true"`
+	SkipRows          int        `gorm:"not null;default:0"` // Lines skipped before the header or the first row
+	DateColumn        string     `gorm:"type:varchar(100);not null"`
+	DateFormat        string     `gorm:"type:varchar(50)"`  // e.g. DD/MM/YYYY, defaults to YYYY-MM-DD or RFC 3339
+	AmountColumn      string     `gorm:"type:varchar(100)"` // Signed amount, negative for expenses
+	DebitColumn       string     `gorm:"type:varchar(100)"` // Alternative to the amount column: money out
+	CreditColumn      string     `gorm:"type:varchar(100)"` // Alternative to the amount column: money in
+	DecimalSeparator  string     `gorm:"type:varchar(1);not null;default:'.'"`
+	InvertSign        bool       `gorm:"not null;default:false"` // Amounts are positive for expenses
+	DescriptionColumn string     `gorm:"type:varchar(100)"`
+	MerchantColumn    string     `gorm:"type:varchar(100)"`
+	IDColumn          string     `gorm:"type:varchar(100)"` // Bank reference of the row, used to skip rows imported before
+	CreatedAt         time.Time  `gorm:"default:now()"`
+	UpdatedAt         time.Time  `gorm:"default:now()"`
+	DeletedAt         *time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for GORM
+func (ImportProfile) TableName() string {
+	return "import_profile"
+}
+
+// CategorizationRule assigns a category, a merchant or a description suffix to transaction entries matching its
+// conditions. Rules are shared by the group, set conditions must all match and nil conditions match every entry.
+type CategorizationRule struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	GroupID  uuid.UUID `gorm:"type:uuid;not null;index:idx_categorization_rule_group_id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index:idx_categorization_rule_user_id"`
+	Name     string    `gorm:"type:varchar(100);not null"`
+	Priority int       `gorm:"not null;default:0"` // Rules are evaluated in ascending priority, the first matching rule applies
+	Disabled bool      `gorm:"not null;default:false"`
+
+	// Conditions
+	MerchantID          *uuid.UUID `gorm:"type:uuid"`
+	BalanceID           *uuid.UUID `gorm:"type:uuid"`
+	DescriptionContains *string    `gorm:"type:varchar(255)"` // Case-insensitive substring of the entry description
+	DescriptionPattern  *string    `gorm:"type:varchar(255)"` // RE2 regular expression matched against the entry description
+	MinAmount           *int64     `gorm:"type:bigint"`       // Inclusive, in cents of the balance currency
+	MaxAmount           *int64     `gorm:"type:bigint"`       // Inclusive, in cents of the balance currency
+
+	// Actions
+	SetCategoryID     *uuid.UUID `gorm:"type:uuid"`
+	SetMerchantID     *uuid.UUID `gorm:"type:uuid"`         // Only set on transactions without merchant
+	AppendDescription *string    `gorm:"type:varchar(255)"` // Appended to the entry description unless it already contains it
+
+	CreatedAt time.Time  `gorm:"default:now()"`
+	UpdatedAt time.Time  `gorm:"default:now()"`
+	DeletedAt *time.Time `gorm:"index"`
+}
+
+// TableName specifies the table name for GORM
+func (CategorizationRule) TableName() string {
+	return "categorization_rule"
+}
+
+// IdempotencyKey stores the response of a request sent with an Idempotency-Key header, retries with the same key
+// replay the stored response instead of repeating the request. Keys are scoped per caller.
+type IdempotencyKey struct {
+	UserID       uuid.UUID `gorm:"type:uuid;not null;primaryKey"` // Caller of the request, nil UUID for system callers
+	Key          string    `gorm:"type:varchar(255);not null;primaryKey"`
+	RequestHash  string    `gorm:"type:varchar(64);not null"` // Hash of method, path and body, see IdempotencyRequestHash
+	StatusCode   int       `gorm:"not null;default:0"`        // 0 while the request is in progress
+	ResponseBody string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"default:now()"`
+	UpdatedAt    time.Time `gorm:"default:now()"`
+	ExpiresAt    time.Time `gorm:"not null;index:idx_idempotency_key_expires_at"`
+}
+
+// TableName specifies the table name for GORM
+func (IdempotencyKey) TableName() string {
+	return "idempotency_key"
+}
+
+// Transfer links the move_out and move_in legs of a movement of money between two balances. The transfer ID is
+// the operation ID of both legs, so legs are edited and deleted together through the transfer.
+type Transfer struct {
+	ID                   uuid.UUID  `gorm:"type:uuid;primary_key"`
+	GroupID              uuid.UUID  `gorm:"type:uuid;not null;index:idx_transfer_group_id"`
+	UserID               uuid.UUID  `gorm:"type:uuid;not null;index:idx_transfer_user_id"`
+	MoveOutTransactionID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_transfer_move_out_transaction_id"`
+	MoveInTransactionID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_transfer_move_in_transaction_id"`
+	CreatedAt            time.Time  `gorm:"default:now()"`
+	UpdatedAt            time.Time  `gorm:"default:now()"`
+	DeletedAt            *time.Time `gorm:"index"` // Set together with the deletedAt of both legs
+
+	// Relationships
+	MoveOut *Transaction `gorm:"foreignKey:MoveOutTransactionID"`
+	MoveIn  *Transaction `gorm:"foreignKey:MoveInTransactionID"`
+}
+
+// TableName specifies the table name for GORM
+func (Transfer) TableName() string {
+	return "transfer"
+}
+
 // GORM Hooks for automatic timestamp updates
 func (b *Balance) BeforeUpdate(tx *gorm.DB) error {
 	b.UpdatedAt = time.Now()
@@ -182,6 +442,8 @@ func (t *Transaction) BeforeUpdate(tx *gorm.DB) error {
 }
 
 func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
+	fingerprint := t.ComputeFingerprint()
+	t.Fingerprint = &fingerprint
 	return t.validateType()
 }
 
@@ -200,6 +462,51 @@ func (t *Transaction) validateType() error {
 	return fmt.Errorf("invalid transaction type: %s. Must be one of: %v", t.Type, validTypes)
 }
 
+func (g *Group) BeforeUpdate(tx *gorm.DB) error {
+	g.UpdatedAt = time.Now()
+	return nil
+}
+
+func (gm *GroupMember) BeforeUpdate(tx *gorm.DB) error {
+	gm.UpdatedAt = time.Now()
+	return nil
+}
+
+func (gi *GroupInvitation) BeforeUpdate(tx *gorm.DB) error {
+	gi.UpdatedAt = time.Now()
+	return nil
+}
+
+func (b *Budget) BeforeUpdate(tx *gorm.DB) error {
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+func (rt *RecurringTransaction) BeforeUpdate(tx *gorm.DB) error {
+	rt.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ip *ImportProfile) BeforeUpdate(tx *gorm.DB) error {
+	ip.UpdatedAt = time.Now()
+	return nil
+}
+
+func (cr *CategorizationRule) BeforeUpdate(tx *gorm.DB) error {
+	cr.UpdatedAt = time.Now()
+	return nil
+}
+
+func (ik *IdempotencyKey) BeforeUpdate(tx *gorm.DB) error {
+	ik.UpdatedAt = time.Now()
+	return nil
+}
+
+func (tr *Transfer) BeforeUpdate(tx *gorm.DB) error {
+	tr.UpdatedAt = time.Now()
+	return nil
+}
+
 func (te *TransactionEntry) BeforeUpdate(tx *gorm.DB) error {
 	te.UpdatedAt = time.Now()
 	return nil
@@ -225,6 +532,12 @@ func formatTimePtr(t *time.Time) string {
 	return t.Format(time.RFC3339)
 }
 
+// BalanceAmounts represents the computed running amount of a balance
+type BalanceAmounts struct {
+	Amount          int64            // Amount in cents in the balance currency
+	CurrencyAmounts map[string]int64 // Amount in cents per currency
+}
+
 // TransactionStatsRaw represents raw statistics data from database aggregation
 type TransactionStatsRaw struct {
 	TransactionType         string `gorm:"column:transaction_type"`
@@ -15,6 +15,9 @@ type CreateTransactionDto struct {
 	UpdatedAt          string                      `json:"updatedAt,omitempty"`
 	DeletedAt          string                      `json:"deletedAt,omitempty"`
 	TransactionEntries []CreateTransactionEntryDto `json:"transactionEntries"`
+
+	// Earlier transaction with the same balance, type, amount and merchant close in time (output only)
+	PossibleDuplicateOf string `json:"possibleDuplicateOf,omitempty"`
 }
 
 // CreateTransactionsRequestDto represents a batch transaction creation request
@@ -53,47 +56,60 @@ type UpdateTransactionEntryDto struct {
 
 // TransactionDto represents a financial transaction for API responses.
 type TransactionEntryDto struct {
-	GroupID               string         `json:"groupId"`
-	UserID                string         `json:"userId"`
-	BalanceID             string         `json:"balanceId"`
-	TransactionID         string         `json:"transactionId"`
-	TransactionEntryID    string         `json:"transactionEntryId"`
-	Type                  string         `json:"type"` // Supported: init, income, expense, movement, move_in, move_out
-	Amount                int            `json:"amount"`
-	CurrencyAmounts       map[string]int `json:"currencyAmounts,omitempty"` // Map of currency code to amount in that currency
-	BalanceTitle          string         `json:"balanceTitle"`
-	BalanceCurrency       string         `json:"balanceCurrency"`
-	BalanceDeleted        bool           `json:"balanceDeleted,omitempty"`
-	CategoryID            string         `json:"categoryId,omitempty"`
-	CategoryName          string         `json:"categoryName"`
-	CategoryImageUrl      string         `json:"categoryImageUrl,omitempty"`
-	CategoryGroupName     string         `json:"categoryGroupName,omitempty"`
-	CategoryGroupImageUrl *string        `json:"categoryGroupImageUrl,omitempty"`
-	CategoryGroupID       string         `json:"categoryGroupId,omitempty"`
-	CategoryIsDeleted     bool           `json:"categoryIsDeleted,omitempty"`
-	CategoryGroupDeleted  bool           `json:"categoryGroupDeleted,omitempty"`
-	MerchantName          string         `json:"merchantName,omitempty"`
-	MerchantImageUrl      string         `json:"merchantImageUrl,omitempty"`
-	OperationID           string         `json:"operationId,omitempty"`
-	ApprovedAt            string         `json:"approvedAt,omitempty"`
-	TransactedAt          string         `json:"transactedAt"`
-	CreatedAt             string         `json:"createdAt"`
-	UpdatedAt             string         `json:"updatedAt"`
-	DeletedAt             string         `json:"deletedAt,omitempty"`
+	GroupID                string          `json:"groupId"`
+	UserID                 string          `json:"userId"`
+	BalanceID              string          `json:"balanceId"`
+	TransactionID          string          `json:"transactionId"`
+	TransactionEntryID     string          `json:"transactionEntryId"`
+	Type                   string          `json:"type"` // Supported: init, income, expense, movement, move_in, move_out
+	Amount                 int             `json:"amount"`
+	CurrencyAmounts        map[string]int  `json:"currencyAmounts,omitempty"` // Map of currency code to amount in that currency
+	BalanceTitle           string          `json:"balanceTitle"`
+	BalanceCurrency        string          `json:"balanceCurrency"`
+	BalanceDeleted         bool            `json:"balanceDeleted,omitempty"`
+	CategoryID             string          `json:"categoryId,omitempty"`
+	CategoryName           string          `json:"categoryName"`
+	CategoryImageUrl       string          `json:"categoryImageUrl,omitempty"`
+	CategoryGroupName      string          `json:"categoryGroupName,omitempty"`
+	CategoryGroupImageUrl  *string         `json:"categoryGroupImageUrl,omitempty"`
+	CategoryGroupID        string          `json:"categoryGroupId,omitempty"`
+	CategoryIsDeleted      bool            `json:"categoryIsDeleted,omitempty"`
+	CategoryGroupDeleted   bool            `json:"categoryGroupDeleted,omitempty"`
+	MerchantName           string          `json:"merchantName,omitempty"`
+	MerchantImageUrl       string          `json:"merchantImageUrl,omitempty"`
+	OperationID            string          `json:"operationId,omitempty"`
+	RecurringTransactionID string          `json:"recurringTransactionId,omitempty"`
+	ApprovedAt             string          `json:"approvedAt,omitempty"`
+	TransactedAt           string          `json:"transactedAt"`
+	CreatedAt              string          `json:"createdAt"`
+	UpdatedAt              string          `json:"updatedAt"`
+	DeletedAt              string          `json:"deletedAt,omitempty"`
+	Search                 *SearchMatchDto `json:"search,omitempty"` // Set when listed with the q parameter
+}
+
+// SearchMatchDto holds the rank of a search result and the snippets of the fields that matched, with the matched
+// words wrapped in <mark></mark>
+type SearchMatchDto struct {
+	Rank         float64 `json:"rank"`
+	Description  string  `json:"description,omitempty"`
+	MerchantName string  `json:"merchantName,omitempty"`
+	CategoryName string  `json:"categoryName,omitempty"`
 }
 
 // Balance represents a user's balance/account for API responses.
 type BalanceDto struct {
-	BalanceID   string `json:"balanceId"`
-	GroupID     string `json:"groupId"`
-	UserID      string `json:"userId"`
-	Currency    string `json:"currency"`
-	Title       string `json:"title"`
-	Description string `json:"description,omitempty"`
-	Rank        *int   `json:"rank,omitempty"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
-	DeletedAt   string `json:"deletedAt,omitempty"`
+	BalanceID       string         `json:"balanceId"`
+	GroupID         string         `json:"groupId"`
+	UserID          string         `json:"userId"`
+	Currency        string         `json:"currency"`
+	Title           string         `json:"title"`
+	Description     string         `json:"description,omitempty"`
+	Rank            *int           `json:"rank,omitempty"`
+	Amount          *int           `json:"amount,omitempty"`          // Computed running amount in balance currency (output only)
+	CurrencyAmounts map[string]int `json:"currencyAmounts,omitempty"` // Computed running amount per currency (output only)
+	CreatedAt       string         `json:"createdAt"`
+	UpdatedAt       string         `json:"updatedAt"`
+	DeletedAt       string         `json:"deletedAt,omitempty"`
 }
 
 // CategoryGroup represents a group of categories for API responses.
@@ -166,6 +182,284 @@ type MerchantDto struct {
 	DeletedAt   string `json:"deletedAt,omitempty"`
 }
 
+// GroupDto represents a group (household) sharing data between its members.
+type GroupDto struct {
+	GroupID     string           `json:"groupId"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	CreatedBy   string           `json:"createdBy,omitempty"`
+	Role        string           `json:"role,omitempty"` // Role of the caller in the group (output only)
+	Members     []GroupMemberDto `json:"members,omitempty"`
+	CreatedAt   string           `json:"createdAt"`
+	UpdatedAt   string           `json:"updatedAt"`
+}
+
+// GroupMemberDto represents a member of a group with its role.
+type GroupMemberDto struct {
+	UserID    string `json:"userId"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+// GroupInvitationDto represents an invitation of a user to join a group.
+type GroupInvitationDto struct {
+	InvitationID string `json:"invitationId"`
+	GroupID      string `json:"groupId"`
+	GroupName    string `json:"groupName,omitempty"`
+	UserID       string `json:"userId"`
+	Role         string `json:"role"`
+	Status       string `json:"status"`
+	InvitedBy    string `json:"invitedBy"`
+	ExpiresAt    string `json:"expiresAt"`
+	RespondedAt  string `json:"respondedAt,omitempty"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+// CreateGroupInvitationDto represents the request to invite a user to a group.
+type CreateGroupInvitationDto struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// BudgetDto represents a spending limit per period for a category, category group, merchant or the whole group.
+type BudgetDto struct {
+	BudgetID  string `json:"budgetId"`
+	GroupID   string `json:"groupId"`
+	UserID    string `json:"userId"`
+	Name      string `json:"name"`
+	ScopeType string `json:"scopeType"`         // Supported: category, categoryGroup, merchant, group
+	ScopeID   string `json:"scopeId,omitempty"` // Category, category group or merchant ID, empty for the group scope
+	Period    string `json:"period"`            // Supported: weekly, monthly, yearly, custom
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate,omitempty"` // Required for the custom period
+	Amount    int    `json:"amount"`            // Limit in cents per period
+	Currency  string `json:"currency"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+// BudgetStatusDto represents the spending of the current budget period in the budget currency.
+type BudgetStatusDto struct {
+	BudgetID          string  `json:"budgetId"`
+	Currency          string  `json:"currency"`
+	PeriodStart       string  `json:"periodStart"`
+	PeriodEnd         string  `json:"periodEnd"` // Exclusive
+	AsOf              string  `json:"asOf"`
+	Amount            int     `json:"amount"`
+	Spent             int     `json:"spent"`
+	Remaining         int     `json:"remaining"` // Negative when the budget is exceeded
+	PercentUsed       float64 `json:"percentUsed"`
+	ProjectedSpent    int     `json:"projectedSpent"` // Spending extrapolated to the end of the period
+	TransactionsCount int     `json:"transactionsCount"`
+}
+
+// CategorizationRuleDto represents a rule assigning a category, a merchant or a description suffix to transaction
+// entries matching all of its conditions.
+type CategorizationRuleDto struct {
+	RuleID     string                          `json:"ruleId"`
+	GroupID    string                          `json:"groupId"`
+	UserID     string                          `json:"userId"`
+	Name       string                          `json:"name"`
+	Priority   int                             `json:"priority"` // Lower priorities are evaluated first
+	Disabled   bool                            `json:"disabled"`
+	Conditions CategorizationRuleConditionsDto `json:"conditions"`
+	Actions    CategorizationRuleActionsDto    `json:"actions"`
+	CreatedAt  string                          `json:"createdAt,omitempty"`
+	UpdatedAt  string                          `json:"updatedAt,omitempty"`
+}
+
+// CategorizationRuleConditionsDto represents the conditions of a categorization rule, an entry matches if it meets all
+// given conditions.
+type CategorizationRuleConditionsDto struct {
+	MerchantID          string `json:"merchantId,omitempty"`
+	BalanceID           string `json:"balanceId,omitempty"`
+	DescriptionContains string `json:"descriptionContains,omitempty"` // Case-insensitive substring
+	DescriptionPattern  string `json:"descriptionPattern,omitempty"`  // RE2 regular expression
+	MinAmount           *int   `json:"minAmount,omitempty"`           // Inclusive, in cents
+	MaxAmount           *int   `json:"maxAmount,omitempty"`           // Inclusive, in cents
+}
+
+// CategorizationRuleActionsDto represents the changes a categorization rule makes to matching entries.
+type CategorizationRuleActionsDto struct {
+	CategoryID        string `json:"categoryId,omitempty"`
+	MerchantID        string `json:"merchantId,omitempty"` // Only set on transactions without merchant
+	AppendDescription string `json:"appendDescription,omitempty"`
+}
+
+// ApplyCategorizationRulesDto represents the request to apply categorization rules to existing transaction entries.
+type ApplyCategorizationRulesDto struct {
+	GroupID            string   `json:"groupId"`
+	UserID             string   `json:"userId,omitempty"`
+	RuleIDs            []string `json:"ruleIds,omitempty"` // Only apply these rules, all enabled rules of the group if empty
+	BalanceIDs         []string `json:"balanceIds,omitempty"`
+	StartTime          string   `json:"startTime,omitempty"`
+	EndTime            string   `json:"endTime,omitempty"`
+	IncludeCategorized bool     `json:"includeCategorized"` // Also evaluate entries with a category and replace it
+	Preview            bool     `json:"preview"`            // Only report the changes without saving them
+}
+
+// CategorizationRulesReportDto represents the result of applying categorization rules to existing entries.
+type CategorizationRulesReportDto struct {
+	Preview        bool                      `json:"preview"`
+	EntriesScanned int                       `json:"entriesScanned"`
+	EntriesChanged int                       `json:"entriesChanged"`
+	Truncated      bool                      `json:"truncated"` // More entries match the filter than a single request evaluates
+	Changes        []CategorizationChangeDto `json:"changes"`
+}
+
+// CategorizationChangeDto represents the change a categorization rule makes to a transaction entry, unchanged fields
+// are omitted.
+type CategorizationChangeDto struct {
+	TransactionID       string `json:"transactionId"`
+	TransactionEntryID  string `json:"transactionEntryId"`
+	RuleID              string `json:"ruleId"`
+	CategoryID          string `json:"categoryId,omitempty"`
+	PreviousCategoryID  string `json:"previousCategoryId,omitempty"`
+	MerchantID          string `json:"merchantId,omitempty"`
+	PreviousMerchantID  string `json:"previousMerchantId,omitempty"`
+	Description         string `json:"description,omitempty"`
+	PreviousDescription string `json:"previousDescription,omitempty"`
+}
+
+// SuggestionsDto represents the categories and merchants suggested for a new entry, best first.
+type SuggestionsDto struct {
+	Categories []SuggestionDto `json:"categories"`
+	Merchants  []SuggestionDto `json:"merchants"`
+}
+
+// SuggestionDto represents a suggested category or merchant learned from past entries.
+type SuggestionDto struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Score      float64 `json:"score"`      // Share of the relevance of all matching past entries, between 0 and 1
+	Count      int     `json:"count"`      // Matching past entries with the category or merchant
+	LastUsedAt string  `json:"lastUsedAt"` // Transaction date of the latest matching entry
+}
+
+// RecurringTransactionDto represents a transaction template created on every occurrence of an RRULE-style schedule.
+type RecurringTransactionDto struct {
+	RecurringTransactionID string               `json:"recurringTransactionId"`
+	GroupID                string               `json:"groupId,omitempty"` // Taken from the template
+	UserID                 string               `json:"userId,omitempty"`  // Taken from the template
+	Name                   string               `json:"name"`
+	Schedule               string               `json:"schedule"` // RRULE, e.g. FREQ=MONTHLY;BYMONTHDAY=1
+	StartAt                string               `json:"startAt"`  // DTSTART, also sets the time of day of occurrences
+	Paused                 bool                 `json:"paused"`
+	NextOccurrenceAt       string               `json:"nextOccurrenceAt,omitempty"` // Empty when the schedule has ended
+	LastOccurrenceAt       string               `json:"lastOccurrenceAt,omitempty"`
+	OccurrencesCount       int                  `json:"occurrencesCount"`
+	Template               CreateTransactionDto `json:"template"`
+	CreatedAt              string               `json:"createdAt,omitempty"`
+	UpdatedAt              string               `json:"updatedAt,omitempty"`
+}
+
+// RecurringTransactionsRunDto reports the result of materializing due recurring transaction occurrences
+type RecurringTransactionsRunDto struct {
+	RunAt               string                             `json:"runAt"`
+	DryRun              bool                               `json:"dryRun"`
+	TemplatesProcessed  int                                `json:"templatesProcessed"`
+	TransactionsCreated int                                `json:"transactionsCreated"`
+	OccurrencesSkipped  int                                `json:"occurrencesSkipped"` // Occurrences that already had a transaction
+	TemplatesFailed     int                                `json:"templatesFailed"`
+	Results             []RecurringTransactionRunResultDto `json:"results"`
+}
+
+// RecurringTransactionRunResultDto reports the materialized occurrences of one recurring transaction
+type RecurringTransactionRunResultDto struct {
+	RecurringTransactionID string   `json:"recurringTransactionId"`
+	Occurrences            []string `json:"occurrences"`
+	TransactionIDs         []string `json:"transactionIds"`
+	OccurrencesSkipped     int      `json:"occurrencesSkipped"`
+	NextOccurrenceAt       string   `json:"nextOccurrenceAt,omitempty"`
+	Error                  string   `json:"error,omitempty"` // Processing stopped at the failed occurrence, it is retried on the next run
+}
+
+// ImportProfileDto represents a saved column mapping of a bank's CSV export.
+// Columns are referenced by header name or, for files without a header, by 1-based position.
+type ImportProfileDto struct {
+	ImportProfileID   string `json:"importProfileId"`
+	GroupID           string `json:"groupId"`
+	UserID            string `json:"userId"`
+	Name              string `json:"name"`
+	Delimiter         string `json:"delimiter,omitempty"` // Defaults to ","
+	HasHeader         bool   `json:"hasHeader"`
+	SkipRows          int    `json:"skipRows,omitempty"`   // Lines skipped before the header or the first row
+	DateColumn        string `json:"dateColumn"`           // Required
+	DateFormat        string `json:"dateFormat,omitempty"` // e.g. DD/MM/YYYY, defaults to YYYY-MM-DD or RFC 3339
+	AmountColumn      string `json:"amountColumn,omitempty"`
+	DebitColumn       string `json:"debitColumn,omitempty"`      // Alternative to amountColumn, together with creditColumn
+	CreditColumn      string `json:"creditColumn,omitempty"`     // Alternative to amountColumn, together with debitColumn
+	DecimalSeparator  string `json:"decimalSeparator,omitempty"` // Supported: "." (default), ","
+	InvertSign        bool   `json:"invertSign"`                 // Amounts are positive for expenses
+	DescriptionColumn string `json:"descriptionColumn,omitempty"`
+	MerchantColumn    string `json:"merchantColumn,omitempty"`
+	IDColumn          string `json:"idColumn,omitempty"` // Bank reference of the row, rows are fingerprinted without it
+	CreatedAt         string `json:"createdAt,omitempty"`
+	UpdatedAt         string `json:"updatedAt,omitempty"`
+}
+
+// ImportRequestDto represents a bank statement file to import into a balance
+type ImportRequestDto struct {
+	GroupID         string `json:"groupId"`
+	UserID          string `json:"userId"`
+	BalanceID       string `json:"balanceId"`
+	Format          string `json:"format"`                    // Supported: csv, ofx, qfx
+	ProfileID       string `json:"profileId,omitempty"`       // Import profile with the column mapping, required for csv
+	CategoryID      string `json:"categoryId,omitempty"`      // Category of all imported entries
+	Content         string `json:"content"`                   // File content
+	ContentEncoding string `json:"contentEncoding,omitempty"` // Supported: base64, defaults to plain text
+	DryRun          bool   `json:"dryRun"`
+}
+
+// ImportReportDto reports the outcome of every row of an imported file
+type ImportReportDto struct {
+	Format    string               `json:"format"`
+	DryRun    bool                 `json:"dryRun"`
+	TotalRows int                  `json:"totalRows"`
+	Created   int                  `json:"created"` // Rows that would be created on dry runs
+	Skipped   int                  `json:"skipped"`
+	Failed    int                  `json:"failed"`
+	Rows      []ImportRowResultDto `json:"rows"`
+}
+
+// ImportRowResultDto reports the outcome of one row of an imported file
+type ImportRowResultDto struct {
+	Line          int    `json:"line"`
+	Status        string `json:"status"` // created, skipped, failed, or pending on dry runs
+	TransactionID string `json:"transactionId,omitempty"`
+	ExternalID    string `json:"externalId,omitempty"`
+	TransactedAt  string `json:"transactedAt,omitempty"`
+	Amount        int    `json:"amount"` // Signed amount in cents, negative for expenses
+	Description   string `json:"description,omitempty"`
+	MerchantID    string `json:"merchantId,omitempty"`
+	CategoryID    string `json:"categoryId,omitempty"`
+	RuleID        string `json:"ruleId,omitempty"` // Categorization rule applied to the row
+	Reason        string `json:"reason,omitempty"` // Why the row was skipped or failed
+}
+
+// DuplicateClusterDto represents transactions with the same balance, type, amount and merchant close in time,
+// suspected to be duplicates of each other
+type DuplicateClusterDto struct {
+	Fingerprint  string                    `json:"fingerprint"`
+	BalanceID    string                    `json:"balanceId"`
+	Type         string                    `json:"type"`
+	Amount       int                       `json:"amount"` // Total amount of the entries in cents
+	MerchantID   string                    `json:"merchantId,omitempty"`
+	Transactions []DuplicateTransactionDto `json:"transactions"` // Sorted by transaction date
+}
+
+// DuplicateTransactionDto represents a transaction of a duplicate cluster
+type DuplicateTransactionDto struct {
+	TransactionID string `json:"transactionId"`
+	GroupID       string `json:"groupId"`
+	UserID        string `json:"userId"`
+	Description   string `json:"description,omitempty"` // Description of the first entry
+	ExternalID    string `json:"externalId,omitempty"`  // Bank reference of imported transactions
+	TransactedAt  string `json:"transactedAt"`
+	CreatedAt     string `json:"createdAt"`
+}
+
 // TransactionStatsItemDto represents a single item in transaction statistics.
 type TransactionStatsItemDto struct {
 	Label    string  `json:"label"`
@@ -194,23 +488,24 @@ type UpdateTransactionDto struct {
 
 // SingleTransactionDto represents a single transaction with detailed information for GET requests.
 type SingleTransactionDto struct {
-	TransactionID      string                      `json:"transactionId"`
-	GroupID            string                      `json:"groupId"`
-	UserID             string                      `json:"userId"`
-	BalanceID          string                      `json:"balanceId"`
-	BalanceTitle       string                      `json:"balanceTitle"`
-	BalanceCurrency    string                      `json:"balanceCurrency"`
-	BalanceDeleted     bool                        `json:"balanceDeleted,omitempty"`
-	Type               string                      `json:"type"`
-	MerchantID         string                      `json:"merchantId,omitempty"`
-	MerchantName       string                      `json:"merchantName,omitempty"`
-	MerchantLogo       string                      `json:"merchantLogo,omitempty"`
-	OperationID        string                      `json:"operationId,omitempty"`
-	ApprovedAt         string                      `json:"approvedAt"`
-	TransactedAt       string                      `json:"transactedAt"`
-	CreatedAt          string                      `json:"createdAt"`
-	UpdatedAt          string                      `json:"updatedAt"`
-	TransactionEntries []SingleTransactionEntryDto `json:"transactionEntries"`
+	TransactionID          string                      `json:"transactionId"`
+	GroupID                string                      `json:"groupId"`
+	UserID                 string                      `json:"userId"`
+	BalanceID              string                      `json:"balanceId"`
+	BalanceTitle           string                      `json:"balanceTitle"`
+	BalanceCurrency        string                      `json:"balanceCurrency"`
+	BalanceDeleted         bool                        `json:"balanceDeleted,omitempty"`
+	Type                   string                      `json:"type"`
+	MerchantID             string                      `json:"merchantId,omitempty"`
+	MerchantName           string                      `json:"merchantName,omitempty"`
+	MerchantLogo           string                      `json:"merchantLogo,omitempty"`
+	OperationID            string                      `json:"operationId,omitempty"`
+	RecurringTransactionID string                      `json:"recurringTransactionId,omitempty"`
+	ApprovedAt             string                      `json:"approvedAt"`
+	TransactedAt           string                      `json:"transactedAt"`
+	CreatedAt              string                      `json:"createdAt"`
+	UpdatedAt              string                      `json:"updatedAt"`
+	TransactionEntries     []SingleTransactionEntryDto `json:"transactionEntries"`
 }
 
 // SingleTransactionEntryDto represents a single transaction entry with detailed information.
@@ -218,7 +513,8 @@ type SingleTransactionEntryDto struct {
 	TransactionEntryID string         `json:"transactionEntryId"`
 	Description        string         `json:"description"`
 	Amount             int            `json:"amount"`
-	CurrencyAmounts    map[string]int `json:"currencyAmounts,omitempty"` // Map of currency code to amount in that currency
+	CurrencyAmounts    map[string]int `json:"currencyAmounts,omitempty"`  // Map of currency code to amount in that currency
+	ExchangeRateDate   string         `json:"exchangeRateDate,omitempty"` // Day of the exchange rates used for currencyAmounts (YYYY-MM-DD)
 	CategoryID         string         `json:"categoryId,omitempty"`
 	CategoryName       string         `json:"categoryName,omitempty"`
 	CategoryIcon       string         `json:"categoryIcon,omitempty"`
@@ -229,3 +525,82 @@ type SingleTransactionEntryDto struct {
 	UpdatedAt          string         `json:"updatedAt"`
 	DeletedAt          string         `json:"deletedAt,omitempty"`
 }
+
+// ReconvertAmountsRequestDto represents the request body of the exchange-rate re-conversion job
+type ReconvertAmountsRequestDto struct {
+	BatchSize       int    `json:"batchSize,omitempty"`
+	MaxBatches      int    `json:"maxBatches,omitempty"`
+	AfterEntryID    string `json:"afterEntryId,omitempty"`
+	RepriceExisting bool   `json:"repriceExisting"`
+	DryRun          bool   `json:"dryRun"`
+}
+
+// RunRecurringTransactionsRequestDto represents the request body of the recurring transactions job
+type RunRecurringTransactionsRequestDto struct {
+	Now                    string `json:"now,omitempty"` // Materialize occurrences up to this time (RFC3339, defaults to now)
+	RecurringTransactionID string `json:"recurringTransactionId,omitempty"`
+	DryRun                 bool   `json:"dryRun"`
+}
+
+// PurgeDeletedDataRequestDto represents the request body of the soft-deleted data purge job
+type PurgeDeletedDataRequestDto struct {
+	RetentionDays int  `json:"retentionDays,omitempty"` // Defaults to the configured retention policy
+	DryRun        bool `json:"dryRun"`
+}
+
+// PurgeDeletedDataReportDto reports the rows removed per table by the soft-deleted data purge job
+type PurgeDeletedDataReportDto struct {
+	DryRun        bool                 `json:"dryRun"`
+	RetentionDays int                  `json:"retentionDays"`
+	Cutoff        string               `json:"cutoff"` // Rows soft-deleted before this time were purged (RFC3339)
+	Tables        []PurgedTableRowsDto `json:"tables"` // In the order the tables were purged
+	TotalRows     int64                `json:"totalRows"`
+}
+
+// UserErasureReportDto reports the rows removed per table by the erasure of all data of a user
+type UserErasureReportDto struct {
+	UserID    string               `json:"userId"`
+	ErasedAt  string               `json:"erasedAt"`
+	ErasedBy  string               `json:"erasedBy,omitempty"` // Caller that requested the erasure, empty for system callers
+	Tables    []PurgedTableRowsDto `json:"tables"`             // In the order the tables were erased
+	TotalRows int64                `json:"totalRows"`
+}
+
+// PurgedTableRowsDto is the number of rows removed from one table by the purge job or a user data erasure
+type PurgedTableRowsDto struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// ReconvertAmountsReportDto reports the progress and result of the exchange-rate re-conversion job
+type ReconvertAmountsReportDto struct {
+	DryRun              bool   `json:"dryRun"`
+	Batches             int    `json:"batches"`
+	EntriesScanned      int    `json:"entriesScanned"`
+	EntriesChanged      int    `json:"entriesChanged"`
+	EntriesWithoutRates int    `json:"entriesWithoutRates"` // Entries for which no exchange rates were found within the lookback window
+	AmountsCreated      int    `json:"amountsCreated"`
+	AmountsUpdated      int    `json:"amountsUpdated"`
+	LastEntryID         string `json:"lastEntryId,omitempty"` // Pass as afterEntryId to resume
+	Completed           bool   `json:"completed"`             // True when all entries have been processed
+}
+
+// TransferDto represents a movement of money between two balances for API requests and responses
+type TransferDto struct {
+	TransferID           string  `json:"transferId,omitempty"` // Output only, also the operationId of both legs
+	GroupID              string  `json:"groupId"`
+	UserID               string  `json:"userId"`
+	FromBalanceID        string  `json:"fromBalanceId"`
+	ToBalanceID          string  `json:"toBalanceId"`
+	Amount               int     `json:"amount"`                   // Sent amount in cents of the source balance currency
+	ReceivedAmount       *int    `json:"receivedAmount,omitempty"` // Cents of the destination balance currency, required between currencies
+	FromCurrency         string  `json:"fromCurrency,omitempty"`   // Output only
+	ToCurrency           string  `json:"toCurrency,omitempty"`     // Output only
+	Rate                 float64 `json:"rate,omitempty"`           // Output only, received amount per unit sent
+	Description          string  `json:"description,omitempty"`
+	TransactedAt         string  `json:"transactedAt,omitempty"` // RFC3339, defaults to now
+	MoveOutTransactionID string  `json:"moveOutTransactionId,omitempty"`
+	MoveInTransactionID  string  `json:"moveInTransactionId,omitempty"`
+	CreatedAt            string  `json:"createdAt,omitempty"`
+	UpdatedAt            string  `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultDuplicateWindow is the time window in which transactions with the same fingerprint are suspected duplicates
+const DefaultDuplicateWindow = 72 * time.Hour
+
+// TransactionFingerprint identifies transactions that look the same: same balance, type, total amount and merchant.
+// The format is mirrored by the SQL backfill of existing transactions, keep both in sync.
+func TransactionFingerprint(balanceID uuid.UUID, txType string, amount int64, merchantID *uuid.UUID) string {
+	merchant := ""
+	if merchantID != nil {
+		merchant = merchantID.String()
+	}
+	return fmt.Sprintf("%s:%s:%d:%s", balanceID.String(), txType, amount, merchant)
+}
+
+// ComputeFingerprint returns the fingerprint of the transaction, summing the amounts of its non-deleted entries
+func (t *Transaction) ComputeFingerprint() string {
+	var amount int64
+	for _, entry := range t.TransactionEntries {
+		if entry.DeletedAt == nil {
+			amount += entry.Amount
+		}
+	}
+	return TransactionFingerprint(t.BalanceID, t.Type, amount, t.MerchantID)
+}
+
+// DuplicateCandidate is a transaction sharing its fingerprint with at least one other transaction
+type DuplicateCandidate struct {
+	TransactionID uuid.UUID
+	GroupID       uuid.UUID
+	UserID        uuid.UUID
+	BalanceID     uuid.UUID
+	MerchantID    *uuid.UUID
+	Type          string
+	Amount        int64 // Total amount of the entries in cents
+	Description   string
+	ExternalID    *string
+	Fingerprint   string
+	TransactedAt  time.Time
+	CreatedAt     time.Time
+}
+
+// ClusterDuplicates groups candidates with the same fingerprint whose transaction dates are chained within the
+// window of each other, clusters with a single transaction are dropped. Clusters and their transactions are sorted
+// by transaction date.
+func ClusterDuplicates(candidates []DuplicateCandidate, window time.Duration) [][]DuplicateCandidate {
+	sorted := make([]DuplicateCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Fingerprint != sorted[j].Fingerprint {
+			return sorted[i].Fingerprint < sorted[j].Fingerprint
+		}
+		return sorted[i].TransactedAt.Before(sorted[j].TransactedAt)
+	})
+
+	var clusters [][]DuplicateCandidate
+	var current []DuplicateCandidate
+	flush := func() {
+		if len(current) > 1 {
+			clusters = append(clusters, current)
+		}
+		current = nil
+	}
+	for _, candidate := range sorted {
+		if len(current) > 0 {
+			last := current[len(current)-1]
+			if last.Fingerprint != candidate.Fingerprint || candidate.TransactedAt.Sub(last.TransactedAt) > window {
+				flush()
+			}
+		}
+		current = append(current, candidate)
+	}
+	flush()
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i][0].TransactedAt.Before(clusters[j][0].TransactedAt)
+	})
+	return clusters
+}
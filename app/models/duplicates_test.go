@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestComputeFingerprint(t *testing.T) {
+	balanceID := uuid.MustParse("ba001111-1111-1111-1111-111111111111")
+	merchantID := uuid.MustParse("4e001111-1111-1111-1111-111111111111")
+	deletedAt := time.Now()
+
+	tx := Transaction{
+		BalanceID:  balanceID,
+		MerchantID: &merchantID,
+		Type:       "expense",
+		TransactionEntries: []TransactionEntry{
+			{Amount: 1000},
+			{Amount: 250},
+			{Amount: 999, DeletedAt: &deletedAt},
+		},
+	}
+
+	expected := "ba001111-1111-1111-1111-111111111111:expense:1250:4e001111-1111-1111-1111-111111111111"
+	if got := tx.ComputeFingerprint(); got != expected {
+		t.Errorf("Expected fingerprint %s, got %s", expected, got)
+	}
+
+	tx.MerchantID = nil
+	if got := tx.ComputeFingerprint(); got != "ba001111-1111-1111-1111-111111111111:expense:1250:" {
+		t.Errorf("Unexpected fingerprint without merchant: %s", got)
+	}
+}
+
+func TestClusterDuplicates(t *testing.T) {
+	day := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	candidate := func(id byte, fingerprint string, at time.Time) DuplicateCandidate {
+		return DuplicateCandidate{TransactionID: uuid.UUID{id}, Fingerprint: fingerprint, TransactedAt: at}
+	}
+
+	clusters := ClusterDuplicates([]DuplicateCandidate{
+		candidate(1, "a", day.Add(48*time.Hour)),
+		candidate(2, "a", day),
+		candidate(3, "a", day.Add(24*time.Hour)),
+		candidate(4, "a", day.Add(30*24*time.Hour)), // Too far from the others
+		candidate(5, "b", day.Add(-time.Hour)),
+		candidate(6, "b", day.Add(-time.Hour)),
+		candidate(7, "c", day),
+	}, DefaultDuplicateWindow)
+
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 clusters, got %d: %+v", len(clusters), clusters)
+	}
+	if len(clusters[0]) != 2 || clusters[0][0].Fingerprint != "b" {
+		t.Errorf("Expected the earliest cluster to be b with 2 transactions, got %+v", clusters[0])
+	}
+	if len(clusters[1]) != 3 || clusters[1][0].TransactionID != (uuid.UUID{2}) || clusters[1][2].TransactionID != (uuid.UUID{1}) {
+		t.Errorf("Expected cluster a with 3 transactions sorted by date, got %+v", clusters[1])
+	}
+}
@@ -1,11 +1,65 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+)
+
 const (
 	ErrorCodeBadRequest     = "BadRequest"
 	ErrorCodeInternalServer = "InternalServerError"
 	ErrorCodeNotFound       = "NotFound"
 	ErrorCodeBadResponse    = "BadResponse"
-	ErrorCodeDbError        = "DatabaseError"
 	ErrorCodeDbTimeout      = "DatabaseTimeout"
 	ErrorCodeConflict       = "Conflict"
+	ErrorCodeUnauthorized   = "Unauthorized"
+	ErrorCodeForbidden      = "Forbidden"
+	ErrorCodeUnprocessable  = "UnprocessableEntity"
 )
+
+// NotFoundError is returned when an entity doesn't exist, or is deleted where only active entities are expected
+type NotFoundError struct {
+	Entity string // e.g. "transaction" or "category group"
+	ID     string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found: %s", e.Entity, e.ID)
+}
+
+// ValidationError is returned when a field of a request is invalid, or the request as a whole if Field is empty
+type ValidationError struct {
+	Field  string // JSON path of the field, e.g. "balanceId" or "transactionEntries[1].amount"
+	Reason string // e.g. "is required"
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s %s", e.Field, e.Reason)
+}
+
+// ConflictError is returned when a request conflicts with the current state of the data, e.g. a duplicate name
+type ConflictError struct {
+	Reason string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Reason
+}
+
+// ForbiddenError is returned when the caller isn't allowed to access an entity or run an operation
+type ForbiddenError struct {
+	Reason string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("access denied: %s", e.Reason)
+}
+
+// IsNotFound reports whether err is or wraps a NotFoundError
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorMessages(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{&NotFoundError{Entity: "category group", ID: "42"}, "category group not found: 42"},
+		{&ValidationError{Field: "amount", Reason: "must be greater than 0"}, "amount must be greater than 0"},
+		{&ValidationError{Reason: "invalid transfer"}, "invalid transfer"},
+		{&ConflictError{Reason: "merchant already exists"}, "merchant already exists"},
+		{&ForbiddenError{Reason: "not a member of the group"}, "access denied: not a member of the group"},
+	}
+	for _, tc := range cases {
+		if got := tc.err.Error(); got != tc.want {
+			t.Errorf("Error() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	wrapped := fmt.Errorf("failed to get budget: %w", &NotFoundError{Entity: "budget", ID: "42"})
+	if !IsNotFound(wrapped) {
+		t.Error("Expected wrapped NotFoundError to be reported as not found")
+	}
+	if IsNotFound(errors.New("budget not found: 42")) {
+		t.Error("Expected untyped error not to be reported as not found")
+	}
+}
+
+func TestBudgetValidateReturnsFieldError(t *testing.T) {
+	budget := Budget{Name: "Groceries"}
+	var validation *ValidationError
+	if err := budget.Validate(); !errors.As(err, &validation) || validation.Field == "" {
+		t.Fatalf("Expected a ValidationError of a field, got %v", err)
+	}
+}
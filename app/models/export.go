@@ -0,0 +1,274 @@
+package models
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Export file formats
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+	ExportFormatXLSX  = "xlsx"
+)
+
+// ExportContentTypes maps export formats to the Content-Type of the produced file
+var ExportContentTypes = map[string]string{
+	ExportFormatCSV:   "text/csv; charset=utf-8",
+	ExportFormatJSONL: "application/x-ndjson",
+	ExportFormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// TransactionExportWriter writes transaction entries to an export file, Close must be called to complete the file
+type TransactionExportWriter interface {
+	WriteEntry(entry TransactionEntryDto) error
+	Close() error
+}
+
+// exportColumn is a column of the tabular (csv, xlsx) export formats
+type exportColumn struct {
+	Header  string
+	Numeric bool
+	Value   func(e *TransactionEntryDto) string
+}
+
+var transactionExportColumns = []exportColumn{
+	{Header: "transactionEntryId", Value: func(e *TransactionEntryDto) string { return e.TransactionEntryID }},
+	{Header: "transactionId", Value: func(e *TransactionEntryDto) string { return e.TransactionID }},
+	{Header: "operationId", Value: func(e *TransactionEntryDto) string { return e.OperationID }},
+	{Header: "transactedAt", Value: func(e *TransactionEntryDto) string { return e.TransactedAt }},
+	{Header: "type", Value: func(e *TransactionEntryDto) string { return e.Type }},
+	{Header: "amount", Numeric: true, Value: func(e *TransactionEntryDto) string { return strconv.Itoa(e.Amount) }},
+	{Header: "balanceCurrency", Value: func(e *TransactionEntryDto) string { return e.BalanceCurrency }},
+	{Header: "balanceId", Value: func(e *TransactionEntryDto) string { return e.BalanceID }},
+	{Header: "balanceTitle", Value: func(e *TransactionEntryDto) string { return e.BalanceTitle }},
+	{Header: "categoryId", Value: func(e *TransactionEntryDto) string { return e.CategoryID }},
+	{Header: "categoryName", Value: func(e *TransactionEntryDto) string { return e.CategoryName }},
+	{Header: "categoryGroupName", Value: func(e *TransactionEntryDto) string { return e.CategoryGroupName }},
+	{Header: "merchantName", Value: func(e *TransactionEntryDto) string { return e.MerchantName }},
+	{Header: "groupId", Value: func(e *TransactionEntryDto) string { return e.GroupID }},
+	{Header: "userId", Value: func(e *TransactionEntryDto) string { return e.UserID }},
+	{Header: "createdAt", Value: func(e *TransactionEntryDto) string { return e.CreatedAt }},
+	{Header: "updatedAt", Value: func(e *TransactionEntryDto) string { return e.UpdatedAt }},
+	{Header: "deletedAt", Value: func(e *TransactionEntryDto) string { return e.DeletedAt }},
+}
+
+// NewTransactionExportWriter creates the writer for the given format. If currency is set, csv and xlsx get an
+// additional "amount<CURRENCY>" column and jsonl lines keep only that currency in currencyAmounts.
+func NewTransactionExportWriter(w io.Writer, format, currency string) (TransactionExportWriter, error) {
+	currency = strings.ToUpper(currency)
+	columns := transactionExportColumns
+	if currency != "" {
+		columns = append(columns[:len(columns):len(columns)], exportColumn{
+			Header:  "amount" + currency,
+			Numeric: true,
+			Value: func(e *TransactionEntryDto) string {
+				if amount, ok := e.CurrencyAmounts[currency]; ok {
+					return strconv.Itoa(amount)
+				}
+				return "" // Not converted to this currency
+			},
+		})
+	}
+
+	switch format {
+	case ExportFormatCSV:
+		return newCSVExportWriter(w, columns)
+	case ExportFormatJSONL:
+		return &jsonlExportWriter{encoder: json.NewEncoder(w), currency: currency}, nil
+	case ExportFormatXLSX:
+		return newXLSXExportWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s', must be one of: csv, jsonl, xlsx", format)
+	}
+}
+
+// csvExportWriter writes one row per entry after a header row
+type csvExportWriter struct {
+	writer  *csv.Writer
+	columns []exportColumn
+	row     []string
+}
+
+func newCSVExportWriter(w io.Writer, columns []exportColumn) (*csvExportWriter, error) {
+	cw := &csvExportWriter{writer: csv.NewWriter(w), columns: columns, row: make([]string, len(columns))}
+	for i, column := range columns {
+		cw.row[i] = column.Header
+	}
+	if err := cw.writer.Write(cw.row); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvExportWriter) WriteEntry(entry TransactionEntryDto) error {
+	for i, column := range cw.columns {
+		value := column.Value(&entry)
+		if !column.Numeric {
+			value = escapeSpreadsheetFormula(value)
+		}
+		cw.row[i] = value
+	}
+	if err := cw.writer.Write(cw.row); err != nil {
+		return err
+	}
+	// Flush every row so the file is streamed to the client instead of buffered
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+func (cw *csvExportWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// escapeSpreadsheetFormula prefixes text that spreadsheet applications would evaluate as a formula, so that
+// user provided names like "=HYPERLINK(...)" are shown as typed
+func escapeSpreadsheetFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// jsonlExportWriter writes one TransactionEntryDto JSON document per line
+type jsonlExportWriter struct {
+	encoder  *json.Encoder
+	currency string
+}
+
+func (jw *jsonlExportWriter) WriteEntry(entry TransactionEntryDto) error {
+	if jw.currency != "" {
+		if amount, ok := entry.CurrencyAmounts[jw.currency]; ok {
+			entry.CurrencyAmounts = map[string]int{jw.currency: amount}
+		} else {
+			entry.CurrencyAmounts = nil
+		}
+	}
+	return jw.encoder.Encode(entry)
+}
+
+func (jw *jsonlExportWriter) Close() error {
+	return nil
+}
+
+// xlsxExportWriter writes a single sheet workbook. The static parts of the package are written upfront and the
+// sheet is the last zip entry, so rows are streamed without holding the file in memory.
+type xlsxExportWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	columns []exportColumn
+	rowNum  int
+}
+
+var xlsxStaticParts = []struct{ Name, Content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Transactions" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+func newXLSXExportWriter(w io.Writer, columns []exportColumn) (*xlsxExportWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		pw, err := archive.Create(part.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(pw, part.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	sw, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw := &xlsxExportWriter{archive: archive, sheet: bufio.NewWriter(sw), columns: columns}
+	xw.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	if err := xw.writeRow(headers, nil); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxExportWriter) WriteEntry(entry TransactionEntryDto) error {
+	values := make([]string, len(xw.columns))
+	for i, column := range xw.columns {
+		values[i] = column.Value(&entry)
+	}
+	if err := xw.writeRow(values, xw.columns); err != nil {
+		return err
+	}
+	// Compress and pass on the buffered rows so the file is streamed to the client
+	return xw.archive.Flush()
+}
+
+// writeRow writes a sheet row, cells of numeric columns are written as numbers and all others as inline strings
+func (xw *xlsxExportWriter) writeRow(values []string, columns []exportColumn) error {
+	xw.rowNum++
+	fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.rowNum)
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		ref := xlsxColumnName(i) + strconv.Itoa(xw.rowNum)
+		if columns != nil && columns[i].Numeric {
+			fmt.Fprintf(xw.sheet, `<c r="%s"><v>%s</v></c>`, ref, value)
+			continue
+		}
+		fmt.Fprintf(xw.sheet, `<c r="%s" t="inlineStr"><is><t>`, ref)
+		if err := xml.EscapeText(xw.sheet, []byte(value)); err != nil {
+			return err
+		}
+		xw.sheet.WriteString(`</t></is></c>`)
+	}
+	xw.sheet.WriteString(`</row>`)
+	return xw.sheet.Flush()
+}
+
+func (xw *xlsxExportWriter) Close() error {
+	xw.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.archive.Close()
+}
+
+// xlsxColumnName converts a zero based column index to its spreadsheet name (A, B, ..., Z, AA, ...)
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func exportTestEntries() []TransactionEntryDto {
+	return []TransactionEntryDto{
+		{TransactionEntryID: "e1", Type: "expense", Amount: -1250, BalanceCurrency: "EUR", MerchantName: "=HYPERLINK(\"x\")", CurrencyAmounts: map[string]int{"EUR": -1250, "USD": -1362}},
+		{TransactionEntryID: "e2", Type: "income", Amount: 100000, BalanceCurrency: "EUR", MerchantName: "Acme & Sons"},
+	}
+}
+
+func writeTestExport(t *testing.T, format, currency string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewTransactionExportWriter(&buf, format, currency)
+	if err != nil {
+		t.Fatalf("Failed to create %s writer: %v", format, err)
+	}
+	for _, entry := range exportTestEntries() {
+		if err := writer.WriteEntry(entry); err != nil {
+			t.Fatalf("Failed to write entry: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCSVExport(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(writeTestExport(t, ExportFormatCSV, "usd"))).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d records", len(records))
+	}
+
+	header := records[0]
+	column := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("Missing column %s in %v", name, header)
+		return -1
+	}
+	if got := records[1][column("amount")]; got != "-1250" {
+		t.Errorf("Expected amount -1250, got %s", got)
+	}
+	if got := records[1][column("amountUSD")]; got != "-1362" {
+		t.Errorf("Expected amountUSD -1362, got %s", got)
+	}
+	if got := records[2][column("amountUSD")]; got != "" {
+		t.Errorf("Expected empty amountUSD for unconverted entry, got %s", got)
+	}
+	if got := records[1][column("merchantName")]; !strings.HasPrefix(got, "'=") {
+		t.Errorf("Expected formula to be escaped, got %s", got)
+	}
+}
+
+func TestJSONLExport(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(writeTestExport(t, ExportFormatJSONL, "USD"))), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	var entry TransactionEntryDto
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Failed to decode line: %v", err)
+	}
+	if len(entry.CurrencyAmounts) != 1 || entry.CurrencyAmounts["USD"] != -1362 {
+		t.Errorf("Expected only the USD amount, got %v", entry.CurrencyAmounts)
+	}
+	if entry.MerchantName != "=HYPERLINK(\"x\")" {
+		t.Errorf("Expected merchant name unchanged, got %s", entry.MerchantName)
+	}
+}
+
+func TestXLSXExport(t *testing.T) {
+	content := writeTestExport(t, ExportFormatXLSX, "")
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("Failed to open XLSX archive: %v", err)
+	}
+
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open sheet: %v", err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		sheet = string(data)
+	}
+	if sheet == "" {
+		t.Fatal("Sheet not found in archive")
+	}
+	if !strings.Contains(sheet, `<row r="3">`) || strings.Contains(sheet, `<row r="4">`) {
+		t.Errorf("Expected header and 2 rows in sheet")
+	}
+	if !strings.Contains(sheet, "<v>-1250</v>") {
+		t.Errorf("Expected numeric amount cell")
+	}
+	if !strings.Contains(sheet, "Acme &amp; Sons") {
+		t.Errorf("Expected escaped text cell")
+	}
+	if strings.Contains(sheet, "amountUSD") {
+		t.Errorf("Expected no currency column without currency parameter")
+	}
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, expected := range cases {
+		if got := xlsxColumnName(index); got != expected {
+			t.Errorf("xlsxColumnName(%d) = %s, expected %s", index, got, expected)
+		}
+	}
+}
+
+func TestUnsupportedExportFormat(t *testing.T) {
+	if _, err := NewTransactionExportWriter(io.Discard, "pdf", ""); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// IdempotencyRequestHash identifies the request an idempotency key was used for. JSON bodies are compacted first so
+// that retries differing only in formatting are still recognized as the same request.
+func IdempotencyRequestHash(method, path string, body []byte) string {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, body); err == nil {
+		body = compacted.Bytes()
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestIdempotencyRequestHash(t *testing.T) {
+	hash := IdempotencyRequestHash("POST", "/transactions", []byte(`{"type":"expense","amount":100}`))
+	if len(hash) != 64 {
+		t.Errorf("Expected a 64 character hex hash, got %s", hash)
+	}
+
+	reformatted := IdempotencyRequestHash("POST", "/transactions", []byte("{\n  \"type\": \"expense\",\n  \"amount\": 100\n}"))
+	if reformatted != hash {
+		t.Error("Expected the same hash for a reformatted body")
+	}
+
+	if IdempotencyRequestHash("POST", "/transactions", []byte(`{"type":"expense","amount":101}`)) == hash {
+		t.Error("Expected a different hash for a different body")
+	}
+	if IdempotencyRequestHash("PUT", "/transactions", []byte(`{"type":"expense","amount":100}`)) == hash {
+		t.Error("Expected a different hash for a different method")
+	}
+}
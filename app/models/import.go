@@ -0,0 +1,383 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Import file formats
+const (
+	ImportFormatCSV = "csv"
+	ImportFormatOFX = "ofx"
+	ImportFormatQFX = "qfx" // OFX with Intuit extensions, parsed like OFX
+)
+
+// Import row statuses
+const (
+	ImportRowCreated = "created"
+	ImportRowSkipped = "skipped"
+	ImportRowFailed  = "failed"
+	ImportRowPending = "pending" // Would be created, reported by dry runs
+)
+
+// ImportRow is a bank statement line parsed from an imported file
+type ImportRow struct {
+	Line         int       // Line of the row in the file
+	TransactedAt time.Time // Date of the row, midnight UTC for date-only values
+	Amount       int64     // Signed amount in cents, negative for money out
+	Description  string
+	MerchantName string
+	ExternalID   string // Bank reference of the row
+	Err          error  // Set when the row couldn't be parsed
+}
+
+var importDateTokens = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02", "HH", "15", "mm", "04", "ss", "05")
+
+// Validate checks that the profile maps a date and an amount (or debit/credit) column
+func (p *ImportProfile) Validate() error {
+	if p.Name == "" {
+		return &ValidationError{Field: "name", Reason: "is required"}
+	}
+	if len([]rune(p.Delimiter)) != 1 {
+		return &ValidationError{Field: "delimiter", Reason: "must be a single character"}
+	}
+	if p.DecimalSeparator != "." && p.DecimalSeparator != "," {
+		return &ValidationError{Field: "decimalSeparator", Reason: "must be '.' or ','"}
+	}
+	if p.SkipRows < 0 {
+		return &ValidationError{Field: "skipRows", Reason: "must not be negative"}
+	}
+	if p.DateColumn == "" {
+		return &ValidationError{Field: "dateColumn", Reason: "is required"}
+	}
+	if p.AmountColumn == "" && p.DebitColumn == "" && p.CreditColumn == "" {
+		return &ValidationError{Field: "amountColumn", Reason: "is required unless debitColumn and creditColumn are given"}
+	}
+	if p.AmountColumn != "" && (p.DebitColumn != "" || p.CreditColumn != "") {
+		return &ValidationError{Field: "amountColumn", Reason: "must not be combined with debitColumn or creditColumn"}
+	}
+	if !p.HasHeader {
+		for _, column := range []string{p.DateColumn, p.AmountColumn, p.DebitColumn, p.CreditColumn, p.DescriptionColumn, p.MerchantColumn, p.IDColumn} {
+			if column == "" {
+				continue
+			}
+			if position, err := strconv.Atoi(column); err != nil || position < 1 {
+				return fmt.Errorf("column '%s' must be a 1-based position for files without a header row", column)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseCSVImport parses a CSV bank statement with the column mapping of the profile. An error is returned when the
+// file can't be read at all, rows that can't be parsed are returned with Err set.
+func ParseCSVImport(content string, profile ImportProfile) ([]ImportRow, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(content, "\ufeff")))
+	reader.Comma = []rune(profile.Delimiter)[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	for i := 0; i < profile.SkipRows; i++ {
+		if _, err := reader.Read(); err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+	}
+
+	var header []string
+	if profile.HasHeader {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		header = record
+	}
+
+	columns, err := resolveImportColumns(profile, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ImportRow
+	fingerprints := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, ImportRow{Line: parseErr.StartLine, Err: err})
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		row := parseCSVImportRow(record, columns, profile)
+		row.Line = line
+		if row.Err == nil && row.ExternalID == "" {
+			row.ExternalID = csvRowFingerprint(row, fingerprints)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// importColumns holds the 0-based positions of the mapped columns, -1 for unmapped ones
+type importColumns struct {
+	date, amount, debit, credit, description, merchant, id int
+}
+
+func resolveImportColumns(profile ImportProfile, header []string) (importColumns, error) {
+	resolve := func(column string) (int, error) {
+		if column == "" {
+			return -1, nil
+		}
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+				return i, nil
+			}
+		}
+		if position, err := strconv.Atoi(column); err == nil && position >= 1 {
+			return position - 1, nil
+		}
+		return -1, fmt.Errorf("column '%s' not found in the CSV header", column)
+	}
+
+	var columns importColumns
+	var err error
+	targets := []struct {
+		column string
+		index  *int
+	}{
+		{profile.DateColumn, &columns.date},
+		{profile.AmountColumn, &columns.amount},
+		{profile.DebitColumn, &columns.debit},
+		{profile.CreditColumn, &columns.credit},
+		{profile.DescriptionColumn, &columns.description},
+		{profile.MerchantColumn, &columns.merchant},
+		{profile.IDColumn, &columns.id},
+	}
+	for _, target := range targets {
+		if *target.index, err = resolve(target.column); err != nil {
+			return importColumns{}, err
+		}
+	}
+	return columns, nil
+}
+
+func parseCSVImportRow(record []string, columns importColumns, profile ImportProfile) ImportRow {
+	field := func(index int) string {
+		if index < 0 || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	var row ImportRow
+	row.Description = field(columns.description)
+	row.MerchantName = field(columns.merchant)
+	row.ExternalID = field(columns.id)
+
+	transactedAt, err := parseImportDate(field(columns.date), profile.DateFormat)
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	row.TransactedAt = transactedAt
+
+	if columns.amount >= 0 {
+		row.Amount, err = parseImportAmount(field(columns.amount), profile.DecimalSeparator)
+	} else {
+		var debit, credit int64
+		if debit, err = parseImportAmount(field(columns.debit), profile.DecimalSeparator); err == nil {
+			credit, err = parseImportAmount(field(columns.credit), profile.DecimalSeparator)
+		}
+		row.Amount = abs64(credit) - abs64(debit)
+	}
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	if profile.InvertSign {
+		row.Amount = -row.Amount
+	}
+	return row
+}
+
+// parseImportDate parses a date with a format made of YYYY, YY, MM, DD, HH, mm and ss tokens, or as YYYY-MM-DD or
+// RFC 3339 when no format is given
+func parseImportDate(value, format string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("date is empty")
+	}
+	if format != "" {
+		t, err := time.Parse(importDateTokens.Replace(format), value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date '%s', expected format %s", value, format)
+		}
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s', expected YYYY-MM-DD or RFC3339", value)
+	}
+	return t.UTC(), nil
+}
+
+// parseImportAmount parses an amount such as "-1,234.56", "1.234,56" (with decimalSeparator ","), "(12.00)" or
+// "12.00-" into cents. An empty value is zero.
+func parseImportAmount(value, decimalSeparator string) (int64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',', r == '-', r == '+', r == '(', r == ')':
+			return r
+		}
+		return -1 // Drop currency symbols and spaces
+	}, value)
+	if cleaned == "" {
+		if strings.TrimSpace(value) != "" {
+			return 0, fmt.Errorf("invalid amount '%s'", value)
+		}
+		return 0, nil
+	}
+
+	negative := false
+	if strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")") {
+		negative = true
+		cleaned = strings.Trim(cleaned, "()")
+	}
+	if strings.HasSuffix(cleaned, "-") {
+		negative = true
+		cleaned = strings.TrimSuffix(cleaned, "-")
+	}
+
+	thousandsSeparator := ","
+	if decimalSeparator == "," {
+		thousandsSeparator = "."
+	}
+	cleaned = strings.ReplaceAll(cleaned, thousandsSeparator, "")
+	cleaned = strings.Replace(cleaned, decimalSeparator, ".", 1)
+
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount '%s'", value)
+	}
+	cents := int64(math.Round(amount * 100))
+	if negative {
+		cents = -abs64(cents)
+	}
+	return cents, nil
+}
+
+// csvRowFingerprint identifies a CSV row without a bank reference by its date, amount and description. Identical
+// rows of the same file are told apart by their position among each other.
+func csvRowFingerprint(row ImportRow, seen map[string]int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s", row.TransactedAt.Format(time.RFC3339), row.Amount, row.Description)))
+	key := hex.EncodeToString(sum[:16])
+	seen[key]++
+	return fmt.Sprintf("csv:%s:%d", key, seen[key])
+}
+
+var (
+	ofxTransactionPattern = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxFieldPattern       = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+)
+
+// ParseOFXImport parses the statement transactions (STMTTRN) of an OFX or QFX file, both the SGML (OFX 1.x) and
+// the XML (OFX 2.x) variants. Rows that can't be parsed are returned with Err set.
+func ParseOFXImport(content string) ([]ImportRow, error) {
+	if !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, errors.New("not an OFX file: <OFX> element not found")
+	}
+
+	var rows []ImportRow
+	for _, match := range ofxTransactionPattern.FindAllStringSubmatchIndex(content, -1) {
+		fields := map[string]string{}
+		for _, field := range ofxFieldPattern.FindAllStringSubmatch(content[match[2]:match[3]], -1) {
+			fields[strings.ToUpper(field[1])] = strings.TrimSpace(field[2])
+		}
+
+		row := ImportRow{
+			Line:         strings.Count(content[:match[0]], "\n") + 1,
+			MerchantName: ofxUnescape(fields["NAME"]),
+			Description:  ofxUnescape(fields["MEMO"]),
+			ExternalID:   fields["FITID"],
+		}
+		if row.Description == "" {
+			row.Description = row.MerchantName
+		}
+
+		var err error
+		if row.TransactedAt, err = parseOFXDate(fields["DTPOSTED"]); err != nil {
+			row.Err = err
+		} else if row.Amount, err = parseImportAmount(fields["TRNAMT"], "."); err != nil {
+			row.Err = err
+		} else if fields["TRNAMT"] == "" {
+			row.Err = errors.New("amount is empty")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseOFXDate parses OFX dates such as 20240115, 20240115120000 or 20240115120000.000[-5:EST]
+func parseOFXDate(value string) (time.Time, error) {
+	if i := strings.IndexAny(value, ".["); i >= 0 {
+		value = value[:i]
+	}
+	switch len(value) {
+	case 8:
+		return time.Parse("20060102", value)
+	case 14:
+		return time.Parse("20060102150405", value)
+	case 12:
+		return time.Parse("200601021504", value)
+	}
+	return time.Time{}, fmt.Errorf("invalid OFX date '%s'", value)
+}
+
+var ofxEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'")
+
+func ofxUnescape(value string) string {
+	return ofxEntities.Replace(value)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
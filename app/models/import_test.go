@@ -0,0 +1,133 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCSVImport(t *testing.T) {
+	profile := ImportProfile{
+		Name:              "Bank",
+		Delimiter:         ";",
+		HasHeader:         true,
+		SkipRows:          1,
+		DateColumn:        "Date",
+		DateFormat:        "DD/MM/YYYY",
+		DebitColumn:       "Debit",
+		CreditColumn:      "Credit",
+		DecimalSeparator:  ",",
+		DescriptionColumn: "Concept",
+	}
+	if err := profile.Validate(); err != nil {
+		t.Fatalf("Expected profile to be valid, got error: %v", err)
+	}
+
+	content := "Account statement\n" +
+		"Date;Concept;Debit;Credit\n" +
+		"15/01/2024;Mercadona;1.234,56;\n" +
+		"16/01/2024;Salary;;2.100,00\n" +
+		"\n" +
+		"16/01/2024;Mercadona;1.234,56;\n" +
+		"15/01/2024;Mercadona;1.234,56;\n" +
+		"not a date;Broken;1,00;\n"
+
+	rows, err := ParseCSVImport(content, profile)
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("Expected 5 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.Line != 3 || first.Amount != -123456 || first.Description != "Mercadona" || !first.TransactedAt.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected first row: %+v", first)
+	}
+	if rows[1].Amount != 210000 {
+		t.Errorf("Expected credit of 210000, got %d", rows[1].Amount)
+	}
+	if rows[2].Line != 6 {
+		t.Errorf("Expected line 6 after the blank line, got %d", rows[2].Line)
+	}
+
+	// Identical rows get different fingerprints, rows with another date don't collide
+	if rows[0].ExternalID == rows[3].ExternalID || rows[0].ExternalID == rows[2].ExternalID {
+		t.Errorf("Expected distinct fingerprints, got %s, %s and %s", rows[0].ExternalID, rows[2].ExternalID, rows[3].ExternalID)
+	}
+	if rows[4].Err == nil {
+		t.Error("Expected error for an invalid date")
+	}
+
+	again, _ := ParseCSVImport(content, profile)
+	if again[3].ExternalID != rows[3].ExternalID {
+		t.Error("Expected fingerprints to be stable across imports")
+	}
+}
+
+func TestParseCSVImport_InvalidRowAndMissingColumn(t *testing.T) {
+	profile := ImportProfile{Name: "Bank", Delimiter: ",", HasHeader: true, DateColumn: "Date", AmountColumn: "Amount", DecimalSeparator: "."}
+
+	rows, err := ParseCSVImport("Date,Amount\n2024-01-15,abc\n2024-01-16,(12.50)\n", profile)
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Err == nil || rows[1].Amount != -1250 {
+		t.Errorf("Expected a failed row and an amount of -1250, got %+v", rows)
+	}
+
+	if _, err := ParseCSVImport("When,Amount\n2024-01-15,1\n", profile); err == nil {
+		t.Error("Expected error for a missing date column")
+	}
+}
+
+func TestParseOFXImport(t *testing.T) {
+	content := `OFXHEADER:100
+DATA:OFXSGML
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240115120000.000[-5:EST]
+<TRNAMT>-42.10
+<FITID>2024011501
+<NAME>Mercadona &amp; Co
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240116
+<TRNAMT>2100.00
+<FITID>2024011601
+<NAME>ACME Corp
+<MEMO>Salary January
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>yesterday
+<TRNAMT>-1.00
+<FITID>2024011602
+</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+	rows, err := ParseOFXImport(content)
+	if err != nil {
+		t.Fatalf("Failed to parse OFX: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Amount != -4210 || rows[0].MerchantName != "Mercadona & Co" || rows[0].ExternalID != "2024011501" || rows[0].Line != 6 {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Description != "Salary January" || !rows[1].TransactedAt.Equal(time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected second row: %+v", rows[1])
+	}
+	if rows[2].Err == nil {
+		t.Error("Expected error for an invalid date")
+	}
+
+	if _, err := ParseOFXImport("Date,Amount\n"); err == nil {
+		t.Error("Expected error for a file that isn't OFX")
+	}
+}
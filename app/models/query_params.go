@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // ListCategoryGroupsInput defines the input for only category groups
 type ListCategoryGroupsInput struct {
@@ -18,6 +22,7 @@ type ListCategoriesInput struct {
 	GroupBy string
 	SortBy  string
 	Order   string
+	Cursor  *PageCursor // Continue listing after this position
 }
 
 // ListTransactionsInput defines the filter and pagination options for listing transactions.
@@ -34,9 +39,12 @@ type ListTransactionsInput struct {
 	StartTime        time.Time
 	EndTime          time.Time
 	IncludeDeleted   bool
+	Search           string // Full-text search tsquery built by BuildSearchQuery, empty for no search
 	SortBy           string
 	Order            string
 	Limit            int
+	Cursor           *PageCursor // Continue listing after this position
+	TransactionEntryFilter
 }
 
 // ListBalancesInput defines the filter and pagination options for list of balances
@@ -44,9 +52,11 @@ type ListBalancesInput struct {
 	GroupID        string
 	UserID         string
 	IncludeDeleted bool
+	AsOf           *time.Time // Compute balance amounts as of this point in time (nil means now)
 	SortBy         string
 	Order          string
 	Limit          int
+	Cursor         *PageCursor // Continue listing after this position
 }
 
 // ListMerchantsInput defines the filter and pagination options for list of merchants
@@ -58,6 +68,41 @@ type ListMerchantsInput struct {
 	SortBy     string
 	Order      string
 	Limit      int
+	Cursor     *PageCursor // Continue listing after this position
+}
+
+// ListGroupInvitationsInput defines the filter options for listing group invitations
+type ListGroupInvitationsInput struct {
+	GroupID    string
+	UserID     string // Invited user
+	Status     string
+	ActiveOnly bool // Only invitations that haven't expired yet
+}
+
+// ReconvertAmountsInput defines the options of the job re-converting transaction entry amounts into supported currencies
+type ReconvertAmountsInput struct {
+	BatchSize       int    // Number of entries processed per batch
+	MaxBatches      int    // Stop after this many batches, 0 means until all entries are processed
+	AfterEntryID    string // Resume processing after this entry ID
+	RepriceExisting bool   // Re-price existing amounts too, not only fill in missing currencies
+	DryRun          bool   // Only report what would change without writing anything
+}
+
+// ListAuditEventsInput defines the filter options for the audit events of an entity
+type ListAuditEventsInput struct {
+	EntityID   string
+	EntityType string // Optional, e.g. transaction or balance
+	Limit      int
+}
+
+// DefaultRetentionDays is how long soft-deleted data is kept before it is purged, if no retention is configured
+const DefaultRetentionDays = 90
+
+// PurgeDeletedDataInput defines the options of the job permanently deleting soft-deleted data
+type PurgeDeletedDataInput struct {
+	RetentionDays int       // Purge rows soft-deleted more than this many days before Now
+	Now           time.Time // Defaults to the current time
+	DryRun        bool      // Only count the rows that would be removed
 }
 
 const (
@@ -90,4 +135,106 @@ type TransactionStatsInput struct {
 	DisplayCurrency string
 	StartTime       time.Time
 	EndTime         *time.Time
+	TransactionEntryFilter
+}
+
+// ListBudgetsInput defines the filter options for listing budgets
+type ListBudgetsInput struct {
+	GroupID   string
+	UserID    string
+	ScopeType string
+	ScopeID   string
+}
+
+// ListCategorizationRulesInput defines the filter options for listing categorization rules
+type ListCategorizationRulesInput struct {
+	GroupID         string
+	UserID          string
+	IncludeDisabled bool
+}
+
+// ApplyCategorizationRulesInput defines the rules and the existing transaction entries they are applied to
+type ApplyCategorizationRulesInput struct {
+	GroupID            string
+	UserID             string
+	RuleIDs            []string
+	BalanceIDs         []string
+	StartTime          time.Time
+	EndTime            time.Time
+	IncludeCategorized bool // Also evaluate entries with a category and replace it
+	Preview            bool // Only report the changes without saving them
+}
+
+// ListRecurringTransactionsInput defines the filter options for listing recurring transactions
+type ListRecurringTransactionsInput struct {
+	GroupID   string
+	UserID    string
+	BalanceID string
+}
+
+// RunRecurringTransactionsInput defines the options of the job materializing due recurring transaction occurrences
+type RunRecurringTransactionsInput struct {
+	Now                    time.Time // Materialize occurrences up to this time
+	RecurringTransactionID string    // Only process this recurring transaction
+	DryRun                 bool      // Only report the due occurrences without creating transactions
+}
+
+// ListImportProfilesInput defines the filter options for listing import profiles
+type ListImportProfilesInput struct {
+	GroupID string
+	UserID  string
+}
+
+// ImportTransactionsInput defines a bank statement file to import into a balance
+type ImportTransactionsInput struct {
+	GroupID    uuid.UUID
+	UserID     uuid.UUID
+	BalanceID  uuid.UUID
+	Format     string     // csv, ofx or qfx
+	ProfileID  string     // Import profile with the column mapping, required for csv
+	CategoryID *uuid.UUID // Category of all imported entries
+	Content    string
+	DryRun     bool // Only report the rows without creating transactions
+}
+
+// TransferInput defines a transfer to create or the new state of an updated transfer
+type TransferInput struct {
+	GroupID        uuid.UUID
+	UserID         uuid.UUID
+	FromBalanceID  uuid.UUID
+	ToBalanceID    uuid.UUID
+	Amount         int64  // Sent amount in cents of the source balance currency
+	ReceivedAmount *int64 // Received amount in cents of the destination balance currency, nil for the same currency
+	Description    string
+	TransactedAt   time.Time
+}
+
+// ListDuplicateTransactionsInput defines the filter options for listing suspected duplicate transactions
+type ListDuplicateTransactionsInput struct {
+	GroupID    string
+	UserID     string
+	BalanceIds []string
+	StartTime  time.Time
+	EndTime    time.Time
+	Window     time.Duration // Maximum time between two transactions of a cluster, defaults to DefaultDuplicateWindow
+}
+
+// TransactionEntryFilter defines the entry amount and attribute filters applied in the same way by the transactions
+// list and the transaction stats, so that list and chart views agree
+type TransactionEntryFilter struct {
+	MinAmount           *int64 // Inclusive, in cents of AmountCurrency or of the balance currency
+	MaxAmount           *int64 // Inclusive, in cents of AmountCurrency or of the balance currency
+	AmountCurrency      string // Compare the converted amounts in this currency, entries without one don't match
+	HasMerchant         *bool
+	HasCategory         *bool
+	DescriptionContains string // Case-insensitive substring of the entry description
+}
+
+// SuggestionsInput defines the partially filled entry that categories and merchants are suggested for
+type SuggestionsInput struct {
+	UserID      string
+	Description string
+	MerchantID  *uuid.UUID
+	Amount      *int64 // In cents
+	Limit       int    // Suggestions per kind, defaults to DefaultSuggestionsLimit
 }
@@ -0,0 +1,265 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies (RFC 5545 FREQ values)
+const (
+	RecurrenceDaily   = "DAILY"
+	RecurrenceWeekly  = "WEEKLY"
+	RecurrenceMonthly = "MONTHLY"
+	RecurrenceYearly  = "YEARLY"
+)
+
+// maxRecurrencePeriods bounds the number of periods scanned for occurrences of a schedule (about 270 years of days)
+const maxRecurrencePeriods = 100000
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RecurrenceRule is the supported subset of an RFC 5545 RRULE: FREQ, INTERVAL, BYDAY (DAILY and WEEKLY only),
+// BYMONTHDAY (MONTHLY only), COUNT and UNTIL. Occurrences are computed in UTC and take the time of day of the
+// schedule start. Unlike RFC 5545, month days that don't exist in a month (e.g. 31 or Feb 29) fall on the last
+// day of that month instead of being skipped.
+type RecurrenceRule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int // 1..31, or -1..-31 counting from the end of the month
+	Count      int   // Total number of occurrences, 0 means unlimited
+	Until      *time.Time
+}
+
+// ParseRecurrenceRule parses an RRULE string such as "FREQ=MONTHLY;BYMONTHDAY=1" (an "RRULE:" prefix is allowed)
+func ParseRecurrenceRule(rule string) (*RecurrenceRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, errors.New("schedule is required")
+	}
+
+	r := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid schedule part '%s'", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL '%s', must be a positive number", value)
+			}
+			r.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := recurrenceWeekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value '%s', must be one of: MO, TU, WE, TH, FR, SA, SU", day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY value '%s', must be 1..31 or -31..-1", day)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, monthDay)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid COUNT '%s', must be a positive number", value)
+			}
+			r.Count = count
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported schedule part '%s'", name)
+		}
+	}
+
+	switch r.Freq {
+	case RecurrenceDaily, RecurrenceWeekly:
+		if len(r.ByMonthDay) > 0 {
+			return nil, fmt.Errorf("BYMONTHDAY is not supported with FREQ=%s", r.Freq)
+		}
+	case RecurrenceMonthly:
+		if len(r.ByDay) > 0 {
+			return nil, fmt.Errorf("BYDAY is not supported with FREQ=%s", r.Freq)
+		}
+	case RecurrenceYearly:
+		if len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 {
+			return nil, fmt.Errorf("BYDAY and BYMONTHDAY are not supported with FREQ=%s", r.Freq)
+		}
+	case "":
+		return nil, errors.New("FREQ is required")
+	default:
+		return nil, fmt.Errorf("invalid FREQ '%s', must be one of: DAILY, WEEKLY, MONTHLY, YEARLY", r.Freq)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT and UNTIL must not be used together")
+	}
+	return r, nil
+}
+
+// parseRecurrenceUntil accepts the RFC 5545 UTC date-time (20240131T000000Z) and date (20240131) forms
+func parseRecurrenceUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	until, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UNTIL '%s', must be YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+	}
+	// A date includes occurrences during the whole day
+	return until.Add(24*time.Hour - time.Second), nil
+}
+
+// Next returns the first occurrence of a schedule starting at start that is strictly after the given time,
+// false when the schedule has no further occurrences
+func (r *RecurrenceRule) Next(start, after time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.iterate(start, func(occurrence time.Time) bool {
+		if occurrence.After(after) {
+			next, found = occurrence, true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// Between returns up to limit occurrences of a schedule starting at start in the range (after, until]
+func (r *RecurrenceRule) Between(start, after, until time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	r.iterate(start, func(occurrence time.Time) bool {
+		if occurrence.After(until) || len(occurrences) >= limit {
+			return false
+		}
+		if occurrence.After(after) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// iterate calls fn with the occurrences of a schedule starting at start in ascending order until fn returns false
+// or the schedule ends
+func (r *RecurrenceRule) iterate(start time.Time, fn func(time.Time) bool) {
+	start = start.UTC()
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	emitted := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, occurrence := range r.periodCandidates(start, period*interval) {
+			if occurrence.Before(start) {
+				continue
+			}
+			if r.Until != nil && occurrence.After(*r.Until) {
+				return
+			}
+			if !fn(occurrence) {
+				return
+			}
+			emitted++
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// periodCandidates returns the sorted candidate occurrences of the period offset periods after the start period
+func (r *RecurrenceRule) periodCandidates(start time.Time, offset int) []time.Time {
+	hour, minute, second := start.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, time.UTC)
+	}
+
+	switch r.Freq {
+	case RecurrenceDaily:
+		day := start.AddDate(0, 0, offset)
+		if len(r.ByDay) > 0 && !containsWeekday(r.ByDay, day.Weekday()) {
+			return nil
+		}
+		return []time.Time{day}
+	case RecurrenceWeekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{start.AddDate(0, 0, 7*offset)}
+		}
+		// Weeks start on Monday
+		monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*offset)
+		candidates := make([]time.Time, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			candidates = append(candidates, monday.AddDate(0, 0, (int(weekday)+6)%7))
+		}
+		return sortedUniqueTimes(candidates)
+	case RecurrenceMonthly:
+		year, month := start.Year(), start.Month()+time.Month(offset)
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{start.Day()}
+		}
+		lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		candidates := make([]time.Time, 0, len(days))
+		for _, day := range days {
+			if day < 0 {
+				day = lastDay + day + 1
+			}
+			day = max(1, min(day, lastDay))
+			candidates = append(candidates, at(year, month, day))
+		}
+		return sortedUniqueTimes(candidates)
+	case RecurrenceYearly:
+		year := start.Year() + offset
+		lastDay := time.Date(year, start.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		return []time.Time{at(year, start.Month(), min(start.Day(), lastDay))}
+	}
+	return nil
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedUniqueTimes(times []time.Time) []time.Time {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	unique := times[:0]
+	for _, t := range times {
+		if len(unique) == 0 || !t.Equal(unique[len(unique)-1]) {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
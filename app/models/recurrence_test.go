@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	valid := []string{
+		"FREQ=DAILY",
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+		"FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=12",
+		"FREQ=YEARLY;UNTIL=20301231",
+	}
+	for _, rule := range valid {
+		if _, err := ParseRecurrenceRule(rule); err != nil {
+			t.Errorf("Expected rule %q to be valid, got error: %v", rule, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=3;UNTIL=20301231",
+		"FREQ=DAILY;BYSETPOS=1",
+	}
+	for _, rule := range invalid {
+		if _, err := ParseRecurrenceRule(rule); err == nil {
+			t.Errorf("Expected error for rule %q", rule)
+		}
+	}
+}
+
+func TestRecurrenceRule_Between(t *testing.T) {
+	start := time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC) // Wednesday
+	until := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule     string
+		limit    int
+		expected []time.Time
+	}{
+		{
+			// Missing month days fall on the last day of the month
+			"FREQ=MONTHLY;COUNT=3", 10,
+			[]time.Time{start, time.Date(2024, 2, 29, 9, 30, 0, 0, time.UTC), time.Date(2024, 3, 31, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			// Days before the start in the first week are skipped
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", 3,
+			[]time.Time{time.Date(2024, 2, 2, 9, 30, 0, 0, time.UTC), time.Date(2024, 2, 12, 9, 30, 0, 0, time.UTC), time.Date(2024, 2, 16, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			"FREQ=DAILY;BYDAY=SA;UNTIL=20240210", 10,
+			[]time.Time{time.Date(2024, 2, 3, 9, 30, 0, 0, time.UTC), time.Date(2024, 2, 10, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			"FREQ=MONTHLY;BYMONTHDAY=-1,15", 3,
+			[]time.Time{start, time.Date(2024, 2, 15, 9, 30, 0, 0, time.UTC), time.Date(2024, 2, 29, 9, 30, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("Failed to parse rule: %v", err)
+			}
+
+			occurrences := rule.Between(start, start.Add(-time.Nanosecond), until, tt.limit)
+			if len(occurrences) != len(tt.expected) {
+				t.Fatalf("Expected %d occurrences, got %d: %v", len(tt.expected), len(occurrences), occurrences)
+			}
+			for i := range occurrences {
+				if !occurrences[i].Equal(tt.expected[i]) {
+					t.Errorf("Expected occurrence %d to be %v, got %v", i, tt.expected[i], occurrences[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceRule_Next(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule, err := ParseRecurrenceRule("FREQ=YEARLY;COUNT=2")
+	if err != nil {
+		t.Fatalf("Failed to parse rule: %v", err)
+	}
+
+	next, ok := rule.Next(start, start)
+	if !ok || !next.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected next occurrence 2025-01-01, got %v (%t)", next, ok)
+	}
+	if _, ok := rule.Next(start, next); ok {
+		t.Error("Expected no occurrence after the last one")
+	}
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Validate checks the name, schedule and transaction template of a recurring transaction
+func (rt *RecurringTransaction) Validate() error {
+	if rt.Name == "" {
+		return &ValidationError{Field: "name", Reason: "is required"}
+	}
+	if rt.StartAt.IsZero() {
+		return &ValidationError{Field: "startAt", Reason: "is required"}
+	}
+	if _, err := ParseRecurrenceRule(rt.Schedule); err != nil {
+		return &ValidationError{Field: "schedule", Reason: fmt.Sprintf("is invalid: %v", err)}
+	}
+
+	template, err := rt.TransactionTemplate()
+	if err != nil {
+		return err
+	}
+	// Movements are created in pairs sharing an operation and can't be generated from a single template
+	if template.Type != "income" && template.Type != "expense" {
+		return &ValidationError{Field: "template.type", Reason: fmt.Sprintf("'%s' is invalid, must be one of: income, expense", template.Type)}
+	}
+	if len(template.TransactionEntries) == 0 {
+		return &ValidationError{Field: "template.transactionEntries", Reason: "must have at least one transaction entry"}
+	}
+	if _, err := FromAPICreateTransaction(template); err != nil {
+		return &ValidationError{Field: "template", Reason: fmt.Sprintf("is invalid: %v", err)}
+	}
+	return nil
+}
+
+// TransactionTemplate decodes the template of the generated transactions
+func (rt *RecurringTransaction) TransactionTemplate() (CreateTransactionDto, error) {
+	var template CreateTransactionDto
+	if err := json.Unmarshal([]byte(rt.Template), &template); err != nil {
+		return CreateTransactionDto{}, fmt.Errorf("invalid template: %w", err)
+	}
+	return template, nil
+}
+
+// SetTransactionTemplate stores the template of the generated transactions. Transaction IDs, operation IDs and
+// timestamps are dropped since every occurrence gets its own; the group, user and balance of the recurring
+// transaction are taken from the template.
+func (rt *RecurringTransaction) SetTransactionTemplate(template CreateTransactionDto) error {
+	template.TransactionID = ""
+	template.OperationID = ""
+	template.ApprovedAt = ""
+	template.TransactedAt = ""
+	template.CreatedAt = ""
+	template.UpdatedAt = ""
+	template.DeletedAt = ""
+	for i := range template.TransactionEntries {
+		entry := &template.TransactionEntries[i]
+		entry.ID = ""
+		entry.CurrencyAmounts = nil
+		entry.CreatedAt = ""
+		entry.UpdatedAt = ""
+		entry.DeletedAt = ""
+	}
+
+	var err error
+	if rt.GroupID, err = uuid.Parse(template.GroupID); err != nil {
+		return fmt.Errorf("invalid template group ID format: %w", err)
+	}
+	if rt.UserID, err = uuid.Parse(template.UserID); err != nil {
+		return fmt.Errorf("invalid template user ID format: %w", err)
+	}
+	if rt.BalanceID, err = uuid.Parse(template.BalanceID); err != nil {
+		return fmt.Errorf("invalid template balance ID format: %w", err)
+	}
+
+	data, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+	rt.Template = string(data)
+	return nil
+}
+
+// NewOccurrenceTransaction builds the transaction of one occurrence from the template, tagged with the recurring
+// transaction ID and the occurrence time
+func (rt *RecurringTransaction) NewOccurrenceTransaction(occurrence time.Time) (*Transaction, error) {
+	template, err := rt.TransactionTemplate()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := FromAPICreateTransaction(template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	occurrence = occurrence.UTC()
+	recurringTransactionID := rt.ID
+	tx.TransactedAt = occurrence
+	tx.ApprovedAt = occurrence
+	tx.RecurringTransactionID = &recurringTransactionID
+	tx.RecurringOccurrenceAt = &occurrence
+	return tx, nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Full-text search limits
+const (
+	MaxSearchQueryLength = 200 // Characters of the q parameter
+	maxSearchTerms       = 10
+)
+
+// SortByRelevance sorts search results by rank, the default sorting when searching
+const SortByRelevance = "relevance"
+
+// SearchMatch holds the rank of a transaction entry matching a full-text search and the highlighted snippets
+// of the fields that matched, nil for fields that didn't
+type SearchMatch struct {
+	Rank               float64
+	DescriptionSnippet *string
+	MerchantSnippet    *string
+	CategorySnippet    *string
+}
+
+// Highlight markers of search snippets
+const (
+	SearchHighlightStart = "<mark>"
+	SearchHighlightStop  = "</mark>"
+)
+
+// BuildSearchQuery converts user input into a PostgreSQL tsquery matching entries that contain all words, each
+// word also matching as a prefix ("pharm" finds "pharmacy"). Punctuation separates words and is never passed to
+// the query, so the result is safe for to_tsquery. Returns an empty string if the input has no words.
+func BuildSearchQuery(q string) string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
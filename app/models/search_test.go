@@ -0,0 +1,20 @@
+package models
+
+import "testing"
+
+func TestBuildSearchQuery(t *testing.T) {
+	cases := map[string]string{
+		"pharmacy":                        "pharmacy:*",
+		"  Pharmacy   Spring ":            "pharmacy:* & spring:*",
+		"o'reilly & sons | !x":            "o:* & reilly:* & sons:* & x:*",
+		"Café Ñandú 24h":                  "café:* & ñandú:* & 24h:*",
+		"":                                "",
+		"&|!():*":                         "",
+		"a b c d e f g h i j k l m n o p": "a:* & b:* & c:* & d:* & e:* & f:* & g:* & h:* & i:* & j:*",
+	}
+	for input, expected := range cases {
+		if got := BuildSearchQuery(input); got != expected {
+			t.Errorf("BuildSearchQuery(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultSuggestionsLimit is the number of category and merchant suggestions returned when no limit is given
+	DefaultSuggestionsLimit = 5
+
+	// MaxSuggestionsLimit bounds the number of category and merchant suggestions
+	MaxSuggestionsLimit = 20
+
+	// suggestionRecencyHalfLife is the age at which a past entry counts half as much as an entry of today
+	suggestionRecencyHalfLife = 90 * 24 * time.Hour
+)
+
+// Weights of the signals of a request, an entry's relevance is the weighted average of the signals that were given
+const (
+	suggestionDescriptionWeight = 0.6
+	suggestionMerchantWeight    = 0.3
+	suggestionAmountWeight      = 0.1
+)
+
+// SuggestionHistoryEntry is a past income or expense entry of a user that suggestions are learned from. Deleted
+// categories and merchants are left out.
+type SuggestionHistoryEntry struct {
+	Description  string
+	MerchantID   *uuid.UUID
+	MerchantName string
+	CategoryID   *uuid.UUID
+	CategoryName string
+	Amount       int64 // In cents of the balance currency
+	TransactedAt time.Time
+}
+
+// SuggestionHistory is the tokenized entry history of a user, built once and reused by every suggestion request
+// until it is reloaded
+type SuggestionHistory struct {
+	entries []SuggestionHistoryEntry
+	tokens  []map[string]bool // Description tokens, by entry index
+}
+
+// NewSuggestionHistory tokenizes the descriptions of the entries
+func NewSuggestionHistory(entries []SuggestionHistoryEntry) *SuggestionHistory {
+	history := &SuggestionHistory{
+		entries: entries,
+		tokens:  make([]map[string]bool, len(entries)),
+	}
+	for i, entry := range entries {
+		history.tokens[i] = suggestionTokens(entry.Description)
+	}
+	return history
+}
+
+// Len returns the number of entries of the history
+func (h *SuggestionHistory) Len() int {
+	if h == nil {
+		return 0
+	}
+	return len(h.entries)
+}
+
+// Suggest ranks the categories and merchants of past entries for a new entry. Every past entry votes for its
+// category and merchant with its relevance to the request (description token similarity, same merchant, similar
+// amount) times its recency, so frequently and recently used choices rank first. Without description and merchant
+// all entries are relevant and the ranking is by frequency and recency only. Scores are the share of the votes of
+// all relevant entries, between 0 and 1.
+func (h *SuggestionHistory) Suggest(input SuggestionsInput, now time.Time) SuggestionsDto {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultSuggestionsLimit
+	}
+	limit = min(limit, MaxSuggestionsLimit)
+
+	categories := map[uuid.UUID]*suggestionScore{}
+	merchants := map[uuid.UUID]*suggestionScore{}
+	var total float64
+
+	queryTokens := suggestionTokens(input.Description)
+	for i := 0; i < h.Len(); i++ {
+		entry := h.entries[i]
+		relevance := h.relevance(i, queryTokens, input)
+		if relevance == 0 {
+			continue
+		}
+
+		weight := relevance * suggestionRecency(entry.TransactedAt, now)
+		total += weight
+		if entry.CategoryID != nil {
+			addSuggestionVote(categories, *entry.CategoryID, entry.CategoryName, weight, entry.TransactedAt)
+		}
+		if entry.MerchantID != nil {
+			addSuggestionVote(merchants, *entry.MerchantID, entry.MerchantName, weight, entry.TransactedAt)
+		}
+	}
+
+	return SuggestionsDto{
+		Categories: rankSuggestions(categories, total, limit),
+		Merchants:  rankSuggestions(merchants, total, limit),
+	}
+}
+
+// relevance returns how similar the entry at index i is to the request, 0 if it is unrelated. An entry is unrelated
+// if a description or merchant was given and it shares neither a description token nor the merchant, the amount
+// alone is too weak a signal.
+func (h *SuggestionHistory) relevance(i int, queryTokens map[string]bool, input SuggestionsInput) float64 {
+	entry := h.entries[i]
+	var score, weights float64
+	related := len(queryTokens) == 0 && input.MerchantID == nil
+
+	if len(queryTokens) > 0 {
+		similarity := tokenSimilarity(queryTokens, h.tokens[i])
+		score += suggestionDescriptionWeight * similarity
+		weights += suggestionDescriptionWeight
+		related = related || similarity > 0
+	}
+	if input.MerchantID != nil {
+		if entry.MerchantID != nil && *entry.MerchantID == *input.MerchantID {
+			score += suggestionMerchantWeight
+			related = true
+		}
+		weights += suggestionMerchantWeight
+	}
+	if input.Amount != nil {
+		score += suggestionAmountWeight * amountSimilarity(*input.Amount, entry.Amount)
+		weights += suggestionAmountWeight
+	}
+
+	if !related {
+		return 0
+	}
+	if weights == 0 {
+		return 1
+	}
+	return score / weights
+}
+
+// suggestionScore accumulates the votes for a category or merchant
+type suggestionScore struct {
+	id         uuid.UUID
+	name       string
+	weight     float64
+	count      int
+	lastUsedAt time.Time
+}
+
+func addSuggestionVote(scores map[uuid.UUID]*suggestionScore, id uuid.UUID, name string, weight float64, usedAt time.Time) {
+	score, ok := scores[id]
+	if !ok {
+		score = &suggestionScore{id: id, name: name}
+		scores[id] = score
+	}
+	score.weight += weight
+	score.count++
+	if usedAt.After(score.lastUsedAt) {
+		score.lastUsedAt = usedAt
+	}
+}
+
+// rankSuggestions sorts the scores by weight, then by count and name, and returns at most limit suggestions
+func rankSuggestions(scores map[uuid.UUID]*suggestionScore, total float64, limit int) []SuggestionDto {
+	ranked := make([]*suggestionScore, 0, len(scores))
+	for _, score := range scores {
+		ranked = append(ranked, score)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.weight != b.weight {
+			return a.weight > b.weight
+		}
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		return a.name < b.name
+	})
+
+	suggestions := make([]SuggestionDto, 0, min(limit, len(ranked)))
+	for _, score := range ranked[:min(limit, len(ranked))] {
+		suggestions = append(suggestions, SuggestionDto{
+			ID:         score.id.String(),
+			Name:       score.name,
+			Score:      math.Round(score.weight/total*1000) / 1000,
+			Count:      score.count,
+			LastUsedAt: score.lastUsedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return suggestions
+}
+
+// suggestionRecency halves the weight of an entry every suggestionRecencyHalfLife, future entries count fully
+func suggestionRecency(transactedAt, now time.Time) float64 {
+	age := now.Sub(transactedAt)
+	if age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(suggestionRecencyHalfLife))
+}
+
+// suggestionTokens splits a description into lower-case words of at least two characters. Numbers are dropped, they
+// are mostly dates, card numbers and references that differ between otherwise identical descriptions.
+func suggestionTokens(description string) map[string]bool {
+	tokens := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < 2 || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		tokens[word] = true
+	}
+	return tokens
+}
+
+// tokenSimilarity returns the Jaccard similarity of two token sets, the shared tokens divided by all tokens
+func tokenSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// amountSimilarity returns 1 for equal amounts, decreasing to 0 as the smaller amount approaches 0
+func amountSimilarity(a, b int64) float64 {
+	a, b = abs64(a), abs64(b)
+	if a == b {
+		return 1
+	}
+	return float64(min(a, b)) / float64(max(a, b))
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSuggestionHistory_Suggest(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	groceries, restaurants, transport := uuid.New(), uuid.New(), uuid.New()
+	mercadona, lidl := uuid.New(), uuid.New()
+
+	history := NewSuggestionHistory([]SuggestionHistoryEntry{
+		{Description: "Card payment MERCADONA 1234", MerchantID: &mercadona, MerchantName: "Mercadona", CategoryID: &groceries, CategoryName: "Groceries", Amount: 4210, TransactedAt: now.AddDate(0, 0, -2)},
+		{Description: "Mercadona", MerchantID: &mercadona, MerchantName: "Mercadona", CategoryID: &groceries, CategoryName: "Groceries", Amount: 3800, TransactedAt: now.AddDate(0, -1, 0)},
+		{Description: "Mercadona cafeteria", MerchantID: &mercadona, MerchantName: "Mercadona", CategoryID: &restaurants, CategoryName: "Restaurants", Amount: 450, TransactedAt: now.AddDate(-1, 0, 0)},
+		{Description: "Card payment LIDL", MerchantID: &lidl, MerchantName: "Lidl", CategoryID: &groceries, CategoryName: "Groceries", Amount: 2500, TransactedAt: now.AddDate(0, 0, -5)},
+		{Description: "Metro ticket", CategoryID: &transport, CategoryName: "Transport", Amount: 240, TransactedAt: now.AddDate(0, 0, -1)},
+	})
+
+	amount := int64(4000)
+	suggestions := history.Suggest(SuggestionsInput{Description: "MERCADONA 5678", Amount: &amount}, now)
+	if len(suggestions.Categories) != 2 || suggestions.Categories[0].ID != groceries.String() {
+		t.Fatalf("Expected groceries first and restaurants second, got %+v", suggestions.Categories)
+	}
+	if suggestions.Categories[0].Count != 2 || suggestions.Categories[0].Score <= suggestions.Categories[1].Score {
+		t.Errorf("Expected groceries to outscore restaurants with 2 entries, got %+v", suggestions.Categories)
+	}
+	if len(suggestions.Merchants) != 1 || suggestions.Merchants[0].ID != mercadona.String() || suggestions.Merchants[0].Score != 1 {
+		t.Errorf("Expected only Mercadona suggested, got %+v", suggestions.Merchants)
+	}
+
+	// Without description and merchant every entry counts, by frequency and recency
+	suggestions = history.Suggest(SuggestionsInput{Limit: 1}, now)
+	if len(suggestions.Categories) != 1 || suggestions.Categories[0].Name != "Groceries" || suggestions.Categories[0].Count != 3 {
+		t.Errorf("Expected the most used category only, got %+v", suggestions.Categories)
+	}
+
+	// Unrelated descriptions don't suggest anything
+	suggestions = history.Suggest(SuggestionsInput{Description: "Netflix 2024"}, now)
+	if len(suggestions.Categories) != 0 || len(suggestions.Merchants) != 0 {
+		t.Errorf("Expected no suggestions, got %+v", suggestions)
+	}
+}
+
+func TestSuggestionTokens(t *testing.T) {
+	tokens := suggestionTokens("Card payment: MERCADONA, 2024-01-15 #4521 a")
+	for _, want := range []string{"card", "payment", "mercadona"} {
+		if !tokens[want] {
+			t.Errorf("Expected token %q in %v", want, tokens)
+		}
+	}
+	if len(tokens) != 3 {
+		t.Errorf("Expected numbers and single characters to be dropped, got %v", tokens)
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import "math"
+
+// Validate checks that the transfer moves a positive amount between two different balances
+func (t TransferInput) Validate() error {
+	if t.FromBalanceID == t.ToBalanceID {
+		return &ValidationError{Field: "toBalanceId", Reason: "must be a different balance than fromBalanceId"}
+	}
+	if t.Amount <= 0 {
+		return &ValidationError{Field: "amount", Reason: "must be positive"}
+	}
+	if t.ReceivedAmount != nil && *t.ReceivedAmount <= 0 {
+		return &ValidationError{Field: "receivedAmount", Reason: "must be positive"}
+	}
+	return nil
+}
+
+// TransferRate returns the exchange rate implied by the sent and received amounts, rounded to 6 decimals
+func TransferRate(amount, receivedAmount int64) float64 {
+	if amount == 0 {
+		return 0
+	}
+	return math.Round(float64(receivedAmount)/float64(amount)*1e6) / 1e6
+}
+
+// transferLegAmount returns the total amount of the non-deleted entries of a transfer leg
+func transferLegAmount(leg *Transaction) int64 {
+	var amount int64
+	for _, entry := range leg.TransactionEntries {
+		if entry.DeletedAt == nil {
+			amount += entry.Amount
+		}
+	}
+	return amount
+}
+
+// transferLegDescription returns the description of the first non-deleted entry of a transfer leg
+func transferLegDescription(leg *Transaction) string {
+	for _, entry := range leg.TransactionEntries {
+		if entry.DeletedAt == nil && entry.Description != nil {
+			return *entry.Description
+		}
+	}
+	return ""
+}
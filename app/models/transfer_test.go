@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransferInputValidate(t *testing.T) {
+	from := NewBalanceID()
+	to := NewBalanceID()
+	negative := int64(-5)
+
+	cases := []struct {
+		name    string
+		input   TransferInput
+		wantErr bool
+	}{
+		{"valid", TransferInput{FromBalanceID: from, ToBalanceID: to, Amount: 1000}, false},
+		{"same balance", TransferInput{FromBalanceID: from, ToBalanceID: from, Amount: 1000}, true},
+		{"zero amount", TransferInput{FromBalanceID: from, ToBalanceID: to}, true},
+		{"negative received amount", TransferInput{FromBalanceID: from, ToBalanceID: to, Amount: 1000, ReceivedAmount: &negative}, true},
+	}
+	for _, tc := range cases {
+		if err := tc.input.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestToAPITransfer(t *testing.T) {
+	description := "Savings"
+	deletedAt := time.Now()
+	transactedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	transfer := &Transfer{
+		ID: NewOperationID(),
+		MoveOut: &Transaction{
+			BalanceID:    NewBalanceID(),
+			TransactedAt: transactedAt,
+			Balance:      &Balance{Currency: "EUR"},
+			TransactionEntries: []TransactionEntry{
+				{Amount: 300, DeletedAt: &deletedAt},
+				{Amount: 10000, Description: &description},
+			},
+		},
+		MoveIn: &Transaction{
+			BalanceID:          NewBalanceID(),
+			TransactedAt:       transactedAt,
+			Balance:            &Balance{Currency: "USD"},
+			TransactionEntries: []TransactionEntry{{Amount: 10850}},
+		},
+	}
+
+	dto := ToAPITransfer(transfer)
+	if dto.Amount != 10000 || dto.ReceivedAmount == nil || *dto.ReceivedAmount != 10850 {
+		t.Errorf("Expected amounts 10000 -> 10850, got %d -> %v", dto.Amount, dto.ReceivedAmount)
+	}
+	if dto.Rate != 1.085 {
+		t.Errorf("Expected rate 1.085, got %v", dto.Rate)
+	}
+	if dto.FromCurrency != "EUR" || dto.ToCurrency != "USD" || dto.Description != description {
+		t.Errorf("Unexpected transfer: %+v", dto)
+	}
+	if dto.TransactedAt != "2024-06-01T10:00:00Z" {
+		t.Errorf("Expected transactedAt of the legs, got %s", dto.TransactedAt)
+	}
+}
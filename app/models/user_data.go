@@ -0,0 +1,103 @@
+package models
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErasedUserID replaces the user of rows kept after a user data erasure because they are shared with a group, e.g. a
+// balance other members recorded transactions on
+var ErasedUserID = uuid.Nil
+
+// erasedUserNamespace is the namespace of the name-based UUIDs that stand in for erased users in audit events
+var erasedUserNamespace = uuid.MustParse("6f1c7a52-3d0e-4b8e-9a57-2f4c8e1d0b93")
+
+// ErasedUserAuditID returns the ID that replaces an erased user in audit events. It is a SHA-1 name-based UUID of
+// the user ID, so the erase event of a known user can be found without the event storing the ID.
+func ErasedUserAuditID(userID uuid.UUID) uuid.UUID {
+	return uuid.NewSHA1(erasedUserNamespace, userID[:])
+}
+
+// UserData is all data owned by a user, including soft-deleted rows, as exported by GET /users/{userId}/export
+type UserData struct {
+	UserID                string
+	Balances              []Balance
+	Categories            []Category
+	Merchants             []Merchant
+	Transactions          []Transaction      // Without entries, see TransactionEntries
+	TransactionEntries    []TransactionEntry // With their transaction, category and amounts preloaded
+	Budgets               []Budget
+	CategorizationRules   []CategorizationRule
+	RecurringTransactions []RecurringTransaction
+	ImportProfiles        []ImportProfile
+	GroupMemberships      []GroupMember
+}
+
+// UserDataExportManifestDto describes the files of a user data export archive
+type UserDataExportManifestDto struct {
+	UserID     string         `json:"userId"`
+	ExportedAt string         `json:"exportedAt"`
+	Files      map[string]int `json:"files"` // Number of records per file
+}
+
+// WriteUserDataExport writes the user data as a ZIP archive with one JSON array file per entity type and a
+// manifest.json listing the files
+func WriteUserDataExport(w io.Writer, data *UserData, exportedAt time.Time) error {
+	files := []struct {
+		Name    string
+		Records []interface{}
+	}{
+		{"balances.json", convertAll(data.Balances, func(b *Balance) interface{} { return ToAPIBalance(b) })},
+		{"categories.json", convertAll(data.Categories, func(c *Category) interface{} { return ToAPICategory(c) })},
+		{"merchants.json", convertAll(data.Merchants, func(m *Merchant) interface{} { return ToAPIMerchant(m) })},
+		{"transactions.json", convertAll(data.Transactions, func(t *Transaction) interface{} { return ToAPICreateTransaction(t) })},
+		{"transaction_entries.json", convertAll(data.TransactionEntries, func(te *TransactionEntry) interface{} { return ToAPITransactionEntry(te) })},
+		{"budgets.json", convertAll(data.Budgets, func(b *Budget) interface{} { return ToAPIBudget(b) })},
+		{"categorization_rules.json", convertAll(data.CategorizationRules, func(r *CategorizationRule) interface{} { return ToAPICategorizationRule(r) })},
+		{"recurring_transactions.json", convertAll(data.RecurringTransactions, func(rt *RecurringTransaction) interface{} { return ToAPIRecurringTransaction(rt) })},
+		{"import_profiles.json", convertAll(data.ImportProfiles, func(p *ImportProfile) interface{} { return ToAPIImportProfile(p) })},
+		{"group_memberships.json", convertAll(data.GroupMemberships, func(m *GroupMember) interface{} { return ToAPIGroupMember(m) })},
+	}
+
+	manifest := UserDataExportManifestDto{
+		UserID:     data.UserID,
+		ExportedAt: exportedAt.UTC().Format(time.RFC3339),
+		Files:      make(map[string]int, len(files)),
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		if err := writeZipJSON(archive, file.Name, file.Records, exportedAt); err != nil {
+			return err
+		}
+		manifest.Files[file.Name] = len(file.Records)
+	}
+	if err := writeZipJSON(archive, "manifest.json", manifest, exportedAt); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// convertAll converts every item to its API model, the result is an empty (not null) JSON array for no items
+func convertAll[T any](items []T, convert func(*T) interface{}) []interface{} {
+	records := make([]interface{}, 0, len(items))
+	for i := range items {
+		records = append(records, convert(&items[i]))
+	}
+	return records
+}
+
+// writeZipJSON adds an indented JSON file to the archive
+func writeZipJSON(archive *zip.Writer, name string, value interface{}, modified time.Time) error {
+	fw, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(fw)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func readZipJSON(t *testing.T, archive *zip.Reader, name string, value interface{}) {
+	t.Helper()
+	file, err := archive.Open(name)
+	if err != nil {
+		t.Fatalf("Missing %s in archive: %v", name, err)
+	}
+	defer file.Close()
+	if err := json.NewDecoder(file).Decode(value); err != nil {
+		t.Fatalf("Failed to decode %s: %v", name, err)
+	}
+}
+
+func TestWriteUserDataExport(t *testing.T) {
+	userID := uuid.New()
+	deletedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	transaction := Transaction{ID: uuid.New(), UserID: userID, BalanceID: uuid.New(), Type: "expense"}
+	data := &UserData{
+		UserID: userID.String(),
+		Balances: []Balance{
+			{ID: uuid.New(), UserID: userID, Title: "Cash", Currency: "EUR"},
+			{ID: uuid.New(), UserID: userID, Title: "Old", Currency: "USD", DeletedAt: &deletedAt},
+		},
+		Transactions: []Transaction{transaction},
+		TransactionEntries: []TransactionEntry{
+			{ID: uuid.New(), TransactionID: transaction.ID, Amount: 1250, Transaction: &transaction,
+				TransactionEntryAmounts: []TransactionEntryAmount{{Currency: "USD", Amount: 1362}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	exportedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	if err := WriteUserDataExport(&buf, data, exportedAt); err != nil {
+		t.Fatalf("Failed to write export: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+
+	var manifest UserDataExportManifestDto
+	readZipJSON(t, archive, "manifest.json", &manifest)
+	if manifest.UserID != userID.String() || manifest.ExportedAt != "2026-10-17T12:00:00Z" {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+	if manifest.Files["balances.json"] != 2 || manifest.Files["merchants.json"] != 0 {
+		t.Errorf("Unexpected file counts %v", manifest.Files)
+	}
+
+	var balances []BalanceDto
+	readZipJSON(t, archive, "balances.json", &balances)
+	if len(balances) != 2 || balances[1].DeletedAt == "" {
+		t.Errorf("Expected both balances including the deleted one, got %+v", balances)
+	}
+
+	var merchants []MerchantDto
+	readZipJSON(t, archive, "merchants.json", &merchants)
+	if merchants == nil || len(merchants) != 0 {
+		t.Errorf("Expected an empty merchants array, got %v", merchants)
+	}
+
+	var entries []TransactionEntryDto
+	readZipJSON(t, archive, "transaction_entries.json", &entries)
+	if len(entries) != 1 || entries[0].TransactionID != transaction.ID.String() || entries[0].CurrencyAmounts["USD"] != 1362 {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+}
@@ -15,6 +15,13 @@ const (
 	PrefixMerchant         = "4e" // merchant
 	PrefixTransaction      = "7a" // transaction
 	PrefixTransactionEntry = "7e" // transaction entry
+	PrefixGroup            = "6a" // group
+	PrefixGroupInvitation  = "1e" // group invitation
+	PrefixBudget           = "b0" // budget
+	PrefixRecurring        = "5e" // recurring transaction
+	PrefixImportProfile    = "1f" // import profile
+	PrefixRule             = "c1" // categorization rule
+	PrefixOperation        = "fa" // operation (batch of transactions or transfer)
 )
 
 // GenerateUUIDWithPrefix creates a UUID with the specified 2-character hex prefix
@@ -79,6 +86,34 @@ func NewTransactionEntryID() uuid.UUID {
 	return GenerateUUIDWithPrefix(PrefixTransactionEntry)
 }
 
+func NewGroupID() uuid.UUID {
+	return GenerateUUIDWithPrefix(PrefixGroup)
+}
+
+func NewGroupInvitationID() uuid.UUID {
+	return GenerateUUIDWithPrefix(PrefixGroupInvitation)
+}
+
+func NewBudgetID() uuid.UUID {
+	return GenerateUUIDWithPrefix(PrefixBudget)
+}
+
+func NewRecurringTransactionID() uuid.UUID {
+	return GenerateUUIDWithPrefix(PrefixRecurring)
+}
+
+func NewImportProfileID() uuid.UUID {
+	return GenerateUUIDWithPrefix(PrefixImportProfile)
+}
+
+func NewCategorizationRuleID() uuid.UUID {
+	return GenerateUUIDWithPrefix(PrefixRule)
+}
+
+func NewOperationID() uuid.UUID {
+	return GenerateUUIDWithPrefix(PrefixOperation)
+}
+
 // GetEntityTypeFromUUID extracts the entity type from a UUID by examining its 2-character hex prefix
 func GetEntityTypeFromUUID(id uuid.UUID) string {
 	idStr := strings.ReplaceAll(id.String(), "-", "")
@@ -103,6 +138,20 @@ func GetEntityTypeFromUUID(id uuid.UUID) string {
 		return "Transaction"
 	case PrefixTransactionEntry:
 		return "TransactionEntry"
+	case PrefixGroup:
+		return "Group"
+	case PrefixGroupInvitation:
+		return "GroupInvitation"
+	case PrefixBudget:
+		return "Budget"
+	case PrefixRecurring:
+		return "RecurringTransaction"
+	case PrefixImportProfile:
+		return "ImportProfile"
+	case PrefixRule:
+		return "CategorizationRule"
+	case PrefixOperation:
+		return "Operation"
 	default:
 		return "Unknown"
 	}
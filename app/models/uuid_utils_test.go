@@ -19,6 +19,13 @@ func TestUUIDHexPrefixes(t *testing.T) {
 		{"Merchant", func() string { return NewMerchantID().String() }, "4e", "Merchant"},
 		{"Transaction", func() string { return NewTransactionID().String() }, "7a", "Transaction"},
 		{"TransactionEntry", func() string { return NewTransactionEntryID().String() }, "7e", "TransactionEntry"},
+		{"Group", func() string { return NewGroupID().String() }, "6a", "Group"},
+		{"GroupInvitation", func() string { return NewGroupInvitationID().String() }, "1e", "GroupInvitation"},
+		{"Budget", func() string { return NewBudgetID().String() }, "b0", "Budget"},
+		{"RecurringTransaction", func() string { return NewRecurringTransactionID().String() }, "5e", "RecurringTransaction"},
+		{"ImportProfile", func() string { return NewImportProfileID().String() }, "1f", "ImportProfile"},
+		{"CategorizationRule", func() string { return NewCategorizationRuleID().String() }, "c1", "CategorizationRule"},
+		{"Operation", func() string { return NewOperationID().String() }, "fa", "Operation"},
 	}
 
 	for _, tt := range tests {
@@ -12,10 +12,32 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// MaxExchangeRateLookbackDays limits how many days back the nearest available exchange rates are searched
+const MaxExchangeRateLookbackDays = 14
+
 type ExchangeRatesDb interface {
 	GetExchangeRates(ctx context.Context, baseCurrency string, date ...time.Time) (map[string]float64, error)
 	GetSupportedCurrencies(ctx context.Context) ([]string, error)
 	GetSupportedCurrenciesRates(ctx context.Context, baseCurrency string, date ...time.Time) (map[string]float64, error)
+	// GetSupportedCurrenciesRatesAt retrieves the rates for the given day or, if missing, the nearest earlier
+	// available day. Returns the day the rates belong to, or nil rates if none found within the lookback window.
+	GetSupportedCurrenciesRatesAt(ctx context.Context, baseCurrency string, date time.Time) (map[string]float64, time.Time, error)
+}
+
+// exchangeRateLookbackDays lists the days searched for rates of the given date, newest first. Rates are never
+// published for future days, so the search starts from today at the latest.
+func exchangeRateLookbackDays(date, now time.Time) []time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if day.After(today) {
+		day = today
+	}
+
+	days := make([]time.Time, 0, MaxExchangeRateLookbackDays+1)
+	for i := 0; i <= MaxExchangeRateLookbackDays; i++ {
+		days = append(days, day.AddDate(0, 0, -i))
+	}
+	return days
 }
 
 type ExchangeRatesDbImpl struct {
@@ -53,14 +75,7 @@ func (db *ExchangeRatesDbImpl) GetExchangeRates(ctx context.Context, baseCurrenc
 
 	input := &dynamodb.GetItemInput{
 		TableName: aws.String(db.dbName),
-		Key: map[string]types.AttributeValue{
-			"Key": &types.AttributeValueMemberS{
-				Value: targetDate,
-			},
-			"SortKey": &types.AttributeValueMemberS{
-				Value: baseCurrency,
-			},
-		},
+		Key:       exchangeRatesKey(targetDate, baseCurrency),
 	}
 
 	result, err := db.dbClient.GetItem(ctx, input)
@@ -73,8 +88,24 @@ func (db *ExchangeRatesDbImpl) GetExchangeRates(ctx context.Context, baseCurrenc
 		return nil, nil // Item not found, return nil
 	}
 
-	// Extract and decode the exchange rates from the ExchangeRates attribute
-	if ratesAttr, exists := result.Item["ExchangeRates"]; exists {
+	return decodeExchangeRates(result.Item), nil
+}
+
+// exchangeRatesKey builds the primary key of the rates item of the given day (YYYY-MM-DD) and base currency
+func exchangeRatesKey(day, baseCurrency string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"Key": &types.AttributeValueMemberS{
+			Value: day,
+		},
+		"SortKey": &types.AttributeValueMemberS{
+			Value: baseCurrency,
+		},
+	}
+}
+
+// decodeExchangeRates extracts the exchange rates from the ExchangeRates attribute of an item
+func decodeExchangeRates(item map[string]types.AttributeValue) map[string]float64 {
+	if ratesAttr, exists := item["ExchangeRates"]; exists {
 		if mapVal, ok := ratesAttr.(*types.AttributeValueMemberM); ok {
 			rates := make(map[string]float64)
 			for currency, value := range mapVal.Value {
@@ -84,11 +115,11 @@ func (db *ExchangeRatesDbImpl) GetExchangeRates(ctx context.Context, baseCurrenc
 					}
 				}
 			}
-			return rates, nil
+			return rates
 		}
 	}
 
-	return nil, nil
+	return nil
 }
 
 // GetSupportedCurrencies retrieves the list of supported currencies from DynamoDB
@@ -144,6 +175,53 @@ func (db *ExchangeRatesDbImpl) GetSupportedCurrenciesRates(ctx context.Context,
 	return db.GetExchangeRates(ctx, baseCurrency, date...)
 }
 
+// GetSupportedCurrenciesRatesAt retrieves exchange rates for the given day, falling back to the nearest earlier day.
+// The rates are keyed by day, so the whole lookback window is read with a single BatchGetItem instead of walking
+// back one GetItem at a time.
+func (db *ExchangeRatesDbImpl) GetSupportedCurrenciesRatesAt(ctx context.Context, baseCurrency string, date time.Time) (map[string]float64, time.Time, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), db.defaultTimeout)
+		defer cancel()
+	}
+
+	days := exchangeRateLookbackDays(date, time.Now().UTC())
+	keys := make([]map[string]types.AttributeValue, 0, len(days))
+	for _, day := range days {
+		keys = append(keys, exchangeRatesKey(day.Format("2006-01-02"), baseCurrency))
+	}
+
+	ratesByDay := make(map[string]map[string]float64, len(days))
+	request := map[string]types.KeysAndAttributes{
+		db.dbName: {Keys: keys},
+	}
+	// DynamoDB may return part of the keys as unprocessed when throttled, request them again until all are read
+	for len(request) > 0 {
+		result, err := db.dbClient.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		for _, item := range result.Responses[db.dbName] {
+			keyAttr, ok := item["Key"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			if rates := decodeExchangeRates(item); len(rates) > 0 {
+				ratesByDay[keyAttr.Value] = rates
+			}
+		}
+		request = result.UnprocessedKeys
+	}
+
+	for _, day := range days {
+		if rates, ok := ratesByDay[day.Format("2006-01-02")]; ok {
+			return rates, day, nil
+		}
+	}
+
+	return nil, time.Time{}, nil
+}
+
 // parseFloat64 safely parses a string to float64
 func parseFloat64(s string) (float64, error) {
 	var f float64
@@ -164,6 +242,7 @@ type CachedExchangeRatesDbImpl struct {
 
 type cacheEntry struct {
 	rates     map[string]float64
+	rateDate  time.Time // day the rates belong to, set for GetSupportedCurrenciesRatesAt lookups
 	timestamp time.Time
 }
 
@@ -292,6 +371,43 @@ func (db *CachedExchangeRatesDbImpl) GetSupportedCurrenciesRates(ctx context.Con
 	return db.GetExchangeRates(ctx, baseCurrency, date...)
 }
 
+// GetSupportedCurrenciesRatesAt retrieves exchange rates for the given day with caching, falling back to the nearest earlier day
+func (db *CachedExchangeRatesDbImpl) GetSupportedCurrenciesRatesAt(ctx context.Context, baseCurrency string, date time.Time) (map[string]float64, time.Time, error) {
+	cacheKey := "at:" + date.UTC().Format("2006-01-02") + ":" + baseCurrency
+
+	db.exchangeRatesMutex.Lock()
+	defer db.exchangeRatesMutex.Unlock()
+
+	if entry, exists := db.exchangeRatesCache[cacheKey]; exists {
+		if time.Since(entry.timestamp).Seconds() < float64(db.ttlSeconds) {
+			result := make(map[string]float64, len(entry.rates))
+			for k, v := range entry.rates {
+				result[k] = v
+			}
+			return result, entry.rateDate, nil
+		}
+	}
+
+	rates, rateDate, err := db.underlying.GetSupportedCurrenciesRatesAt(ctx, baseCurrency, date)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if rates != nil {
+		cachedRates := make(map[string]float64, len(rates))
+		for k, v := range rates {
+			cachedRates[k] = v
+		}
+		db.exchangeRatesCache[cacheKey] = cacheEntry{
+			rates:     cachedRates,
+			rateDate:  rateDate,
+			timestamp: time.Now(),
+		}
+	}
+
+	return rates, rateDate, nil
+}
+
 // ExchangeRatesStaticImpl provides hardcoded exchange rates for testing
 type ExchangeRatesStaticImpl struct{}
 
@@ -324,6 +440,15 @@ func (db *ExchangeRatesStaticImpl) GetSupportedCurrenciesRates(ctx context.Conte
 	return db.GetExchangeRates(ctx, baseCurrency, date...)
 }
 
+// GetSupportedCurrenciesRatesAt returns hardcoded exchange rates, reporting the requested day as the rate date
+func (db *ExchangeRatesStaticImpl) GetSupportedCurrenciesRatesAt(ctx context.Context, baseCurrency string, date time.Time) (map[string]float64, time.Time, error) {
+	rates, err := db.GetExchangeRates(ctx, baseCurrency, date)
+	if err != nil || rates == nil {
+		return nil, time.Time{}, err
+	}
+	return rates, time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
 // getStaticRates returns predefined exchange rates for supported currencies
 func getStaticRates(baseCurrency string) map[string]float64 {
 	allRates := map[string]map[string]float64{
@@ -403,5 +528,14 @@ func (db *ExchangeRatesDbMock) GetSupportedCurrenciesRates(ctx context.Context,
 	return args.Get(0).(map[string]float64), args.Error(1)
 }
 
+// GetSupportedCurrenciesRatesAt mocks the GetSupportedCurrenciesRatesAt method
+func (db *ExchangeRatesDbMock) GetSupportedCurrenciesRatesAt(ctx context.Context, baseCurrency string, date time.Time) (map[string]float64, time.Time, error) {
+	args := db.Called(ctx, baseCurrency, date)
+	if args.Get(0) == nil {
+		return nil, time.Time{}, args.Error(2)
+	}
+	return args.Get(0).(map[string]float64), args.Get(1).(time.Time), args.Error(2)
+}
+
 // Ensure ExchangeRatesDbMock implements ExchangeRatesDb interface
 var _ ExchangeRatesDb = (*ExchangeRatesDbMock)(nil)
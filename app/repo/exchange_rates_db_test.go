@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExchangeRateLookbackDays(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		date  time.Time
+		first string
+		last  string
+	}{
+		{"past day", time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC), "2024-03-01", "2024-02-16"},
+		{"today", now, "2024-03-10", "2024-02-25"},
+		{"future day starts from today", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), "2024-03-10", "2024-02-25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := exchangeRateLookbackDays(tt.date, now)
+			if len(days) != MaxExchangeRateLookbackDays+1 {
+				t.Fatalf("Expected %d days, got %d", MaxExchangeRateLookbackDays+1, len(days))
+			}
+			if got := days[0].Format("2006-01-02"); got != tt.first {
+				t.Errorf("Expected first day %s, got %s", tt.first, got)
+			}
+			if got := days[len(days)-1].Format("2006-01-02"); got != tt.last {
+				t.Errorf("Expected last day %s, got %s", tt.last, got)
+			}
+			for i := 1; i < len(days); i++ {
+				if !days[i].Before(days[i-1]) {
+					t.Fatalf("Expected days newest first, got %s after %s", days[i], days[i-1])
+				}
+			}
+		})
+	}
+}
+
+func TestCachedExchangeRatesDbGetSupportedCurrenciesRatesAt(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	rateDate := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	underlying := &ExchangeRatesDbMock{}
+	underlying.On("GetSupportedCurrenciesRatesAt", ctx, "EUR", date).
+		Return(map[string]float64{"USD": 1.1}, rateDate, nil).Once()
+
+	db := NewCachedExchangeRatesDb(underlying, 60)
+	for i := 0; i < 2; i++ {
+		rates, gotDate, err := db.GetSupportedCurrenciesRatesAt(ctx, "EUR", date)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rates["USD"] != 1.1 {
+			t.Errorf("Expected USD rate 1.1, got %v", rates["USD"])
+		}
+		if !gotDate.Equal(rateDate) {
+			t.Errorf("Expected rate date %s, got %s", rateDate, gotDate)
+		}
+	}
+
+	// The second lookup is served from the cache
+	underlying.AssertNumberOfCalls(t, "GetSupportedCurrenciesRatesAt", 1)
+}
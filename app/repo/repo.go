@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/savak1990/transactions-service/app/models"
 )
@@ -17,6 +18,14 @@ type Repository interface {
 	UpdateTransaction(ctx context.Context, tx models.Transaction) (*models.Transaction, error)
 	DeleteTransaction(ctx context.Context, transactionID string) error
 
+	// Duplicate detection methods
+	ListDuplicateCandidates(ctx context.Context, filter models.ListDuplicateTransactionsInput) ([]models.DuplicateCandidate, error)
+	FindPossibleDuplicate(ctx context.Context, tx models.Transaction, window time.Duration) (string, error) // Empty if there is none
+
+	// Transaction entry amount methods
+	ListTransactionEntriesAfter(ctx context.Context, afterEntryID string, limit int) ([]models.TransactionEntry, error)
+	SaveTransactionEntryAmounts(ctx context.Context, amounts []models.TransactionEntryAmount) error
+
 	// Category methods
 	CreateCategory(ctx context.Context, category models.Category) (*models.Category, error)
 	ListCategories(ctx context.Context, input models.ListCategoriesInput) ([]models.Category, error)
@@ -39,6 +48,7 @@ type Repository interface {
 	UpdateBalance(ctx context.Context, balance models.Balance) (*models.Balance, error)
 	DeleteBalance(ctx context.Context, balanceId string) error
 	DeleteBalancesByUserId(ctx context.Context, userId string) error
+	GetBalanceAmounts(ctx context.Context, balanceIDs []string, asOf *time.Time) (map[string]models.BalanceAmounts, error)
 
 	// Merchant methods
 	CreateMerchant(ctx context.Context, merchant models.Merchant) (*models.Merchant, error)
@@ -49,6 +59,100 @@ type Repository interface {
 	DeleteMerchant(ctx context.Context, merchantId string) error
 	DeleteMerchantsByUserId(ctx context.Context, userId string) error
 
+	// Budget methods
+	CreateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error)
+	GetBudget(ctx context.Context, budgetID string) (*models.Budget, error)
+	ListBudgets(ctx context.Context, filter models.ListBudgetsInput) ([]models.Budget, error)
+	UpdateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error)
+	DeleteBudget(ctx context.Context, budgetID string) error
+
+	// Categorization rule methods
+	CreateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error)
+	GetCategorizationRule(ctx context.Context, ruleID string) (*models.CategorizationRule, error)
+	ListCategorizationRules(ctx context.Context, filter models.ListCategorizationRulesInput) ([]models.CategorizationRule, error)
+	UpdateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error)
+	DeleteCategorizationRule(ctx context.Context, ruleID string) error
+	ApplyCategorizationChanges(ctx context.Context, changes []models.CategorizationChange) error
+
+	// Suggestion methods
+	ListSuggestionHistory(ctx context.Context, userID string, limit int) ([]models.SuggestionHistoryEntry, error)
+
+	// Recurring transaction methods
+	CreateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error)
+	GetRecurringTransaction(ctx context.Context, recurringTransactionID string) (*models.RecurringTransaction, error)
+	ListRecurringTransactions(ctx context.Context, filter models.ListRecurringTransactionsInput) ([]models.RecurringTransaction, error)
+	UpdateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error)
+	DeleteRecurringTransaction(ctx context.Context, recurringTransactionID string) error
+	ListDueRecurringTransactions(ctx context.Context, now time.Time, recurringTransactionID string) ([]models.RecurringTransaction, error)
+	GetRecurringOccurrenceTransactionID(ctx context.Context, recurringTransactionID string, occurrence time.Time) (string, error)
+	UpdateRecurringTransactionProgress(ctx context.Context, recurring models.RecurringTransaction) error
+
+	// Import methods
+	CreateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error)
+	GetImportProfile(ctx context.Context, importProfileID string) (*models.ImportProfile, error)
+	ListImportProfiles(ctx context.Context, filter models.ListImportProfilesInput) ([]models.ImportProfile, error)
+	UpdateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error)
+	DeleteImportProfile(ctx context.Context, importProfileID string) error
+	ListTransactionExternalIDs(ctx context.Context, balanceID string, externalIDs []string) ([]string, error) // External IDs already imported into the balance
+
+	// Idempotency key methods
+	ClaimIdempotencyKey(ctx context.Context, record models.IdempotencyKey) (bool, error)               // False if the caller already has the key
+	FindIdempotencyKey(ctx context.Context, userID string, key string) (*models.IdempotencyKey, error) // Returns nil if the key doesn't exist
+	CompleteIdempotencyKey(ctx context.Context, record models.IdempotencyKey) error
+	TakeOverIdempotencyKey(ctx context.Context, record models.IdempotencyKey, now time.Time, staleBefore time.Time) (bool, error) // False if the key isn't expired or abandoned anymore
+	RenewIdempotencyKey(ctx context.Context, userID string, key string) error
+	DeleteIdempotencyKey(ctx context.Context, userID string, key string, requestHash string) error // Only while in progress for the request
+
+	// Restore methods, reverse the soft delete of entities
+	GetTransactionIncludingDeleted(ctx context.Context, transactionID string) (*models.Transaction, error)
+	ListTransactionsDeletedWith(ctx context.Context, tx models.Transaction) ([]models.Transaction, error) // The transaction and the transactions of its operation deleted with it
+	RestoreTransactions(ctx context.Context, transactions []models.Transaction) error
+	GetCategoryIncludingDeleted(ctx context.Context, categoryID string) (*models.Category, error)
+	GetCategoryGroupIncludingDeleted(ctx context.Context, categoryGroupID string) (*models.CategoryGroup, error)
+	GetMerchantIncludingDeleted(ctx context.Context, merchantID string) (*models.Merchant, error)
+	RestoreBalance(ctx context.Context, balanceID string) error
+	RestoreCategory(ctx context.Context, categoryID string) error
+	RestoreCategoryGroup(ctx context.Context, categoryGroupID string) error
+	RestoreMerchant(ctx context.Context, merchantID string) error
+
+	// Purge methods, permanently delete soft-deleted data
+	PurgeDeletedData(ctx context.Context, cutoff time.Time, dryRun bool) ([]models.PurgedTableRowsDto, error) // Rows removed per table
+
+	// User data methods, export and erasure of everything a user owns
+	GetUserData(ctx context.Context, userID string) (*models.UserData, error)              // Includes soft-deleted rows
+	EraseUserData(ctx context.Context, userID string) ([]models.PurgedTableRowsDto, error) // Rows removed per table
+
+	// Audit methods, events are recorded by the mutations themselves
+	ListAuditEvents(ctx context.Context, filter models.ListAuditEventsInput) ([]models.AuditEvent, error)
+
+	// Operation methods, transactions created together in a batch share an operation ID
+	GetOperationTransactions(ctx context.Context, operationID string) ([]models.Transaction, error)
+	ReplaceOperationTransactions(ctx context.Context, operationID string, transactions []models.Transaction) ([]models.Transaction, error)
+	DeleteOperation(ctx context.Context, operationID string) error
+
+	// Transfer methods
+	CreateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction) (*models.Transfer, error)
+	GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error)
+	FindTransferByTransactionID(ctx context.Context, transactionID string) (*models.Transfer, error) // Returns nil if the transaction isn't a transfer leg
+	UpdateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction) (*models.Transfer, error)
+	DeleteTransfer(ctx context.Context, transferID string) error
+
+	// Group methods
+	CreateGroup(ctx context.Context, group models.Group) (*models.Group, error)
+	FindGroup(ctx context.Context, groupID string) (*models.Group, error) // Returns nil if the group doesn't exist
+	ListGroupsByMember(ctx context.Context, userID string) ([]models.Group, error)
+	UpdateGroup(ctx context.Context, group models.Group) (*models.Group, error)
+	DeleteGroup(ctx context.Context, groupID string) error
+	SaveGroupMember(ctx context.Context, member models.GroupMember) (*models.GroupMember, error)
+	DeleteGroupMember(ctx context.Context, groupID string, userID string) error
+
+	// Group invitation methods
+	CreateGroupInvitation(ctx context.Context, invitation models.GroupInvitation) (*models.GroupInvitation, error)
+	GetGroupInvitation(ctx context.Context, invitationID string) (*models.GroupInvitation, error)
+	ListGroupInvitations(ctx context.Context, filter models.ListGroupInvitationsInput) ([]models.GroupInvitation, error)
+	UpdateGroupInvitationStatus(ctx context.Context, invitationID string, status string) error
+	AcceptGroupInvitation(ctx context.Context, invitationID string, member models.GroupMember) (*models.GroupMember, error)
+
 	// Transaction statistics methods
 	GetTransactionStats(ctx context.Context, filter models.TransactionStatsInput) ([]models.TransactionStatsItemDto, error)
 }
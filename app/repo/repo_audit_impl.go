@@ -0,0 +1,181 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// recordAudit writes the audit event of a change within the database transaction of the change. The actor and the
+// request ID are taken from the context of tx. Before is nil for creates and after is nil for deletes.
+func recordAudit(tx *gorm.DB, action string, before, after interface{}) error {
+	ctx := tx.Statement.Context
+	event, err := models.NewAuditEvent(action, before, after, auditActor(ctx), auth.RequestIDFromContext(ctx), time.Now())
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// recordPurgeAudits records a purge of each entity in batches. The snapshots of earlier events of the entities hold
+// the data that was purged and are removed, the events only keep which entity changed when.
+func recordPurgeAudits(tx *gorm.DB, entities []interface{}) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	ctx := tx.Statement.Context
+	actor, requestID, now := auditActor(ctx), auth.RequestIDFromContext(ctx), time.Now()
+	events := make([]*models.AuditEvent, 0, len(entities))
+	idsByType := make(map[string][]uuid.UUID)
+	for _, entity := range entities {
+		event, err := models.NewPurgeAuditEvent(entity, actor, requestID, now)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+		idsByType[event.EntityType] = append(idsByType[event.EntityType], event.EntityID)
+	}
+
+	for entityType, ids := range idsByType {
+		for start := 0; start < len(ids); start += auditBatchSize {
+			end := min(start+auditBatchSize, len(ids))
+			if err := tx.Exec(`UPDATE audit_event SET before = NULL, after = NULL WHERE entity_type = ? AND entity_id IN ?`,
+				entityType, ids[start:end]).Error; err != nil {
+				return fmt.Errorf("failed to remove audit snapshots of purged %s rows: %w", entityType, err)
+			}
+		}
+	}
+	if err := tx.CreateInBatches(events, auditBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to record audit events: %w", err)
+	}
+	return nil
+}
+
+// auditBatchSize is the number of audit events inserted or redacted per statement by recordPurgeAudits
+const auditBatchSize = 500
+
+// auditActor returns the JWT subject of the caller or models.AuditActorSystem for internal callers
+func auditActor(ctx context.Context) string {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.UserID == uuid.Nil {
+		return models.AuditActorSystem
+	}
+	return principal.UserID.String()
+}
+
+// auditedCreate creates the entity and records its creation
+func auditedCreate[T any](tx *gorm.DB, entity *T) error {
+	if err := tx.Create(entity).Error; err != nil {
+		return err
+	}
+	return recordAudit(tx, models.AuditActionCreate, nil, entity)
+}
+
+// auditedSave saves all fields of the entity with the given ID and records the update with the previous state
+func auditedSave[T any](tx *gorm.DB, id interface{}, entity *T) error {
+	var before T
+	if err := tx.Where("id = ?", id).First(&before).Error; err != nil {
+		return err
+	}
+	if err := tx.Save(entity).Error; err != nil {
+		return err
+	}
+	return recordAudit(tx, models.AuditActionUpdate, &before, entity)
+}
+
+// auditedUpdate updates the columns of the row with the given ID that also matches the optional conditions and
+// records the update with the previous state, returns false if no row matches
+func auditedUpdate[T any](tx *gorm.DB, id interface{}, values interface{}, columns []string, conds ...interface{}) (bool, error) {
+	scope := func() *gorm.DB {
+		query := tx.Where("id = ?", id)
+		if len(conds) > 0 {
+			query = query.Where(conds[0], conds[1:]...)
+		}
+		return query
+	}
+
+	var before []T
+	if err := scope().Limit(1).Find(&before).Error; err != nil {
+		return false, err
+	}
+	if len(before) == 0 {
+		return false, nil
+	}
+
+	update := scope().Model(new(T))
+	if len(columns) > 0 {
+		update = update.Select(columns)
+	}
+	if err := update.Updates(values).Error; err != nil {
+		return false, err
+	}
+
+	var after T
+	if err := tx.Where("id = ?", id).First(&after).Error; err != nil {
+		return false, err
+	}
+	return true, recordAudit(tx, models.AuditActionUpdate, &before[0], &after)
+}
+
+// auditedSoftDelete sets deleted_at of the rows matching the condition and records a delete of each row, returns
+// the number of deleted rows
+func auditedSoftDelete[T any](tx *gorm.DB, deletedAt time.Time, query string, args ...interface{}) (int64, error) {
+	var rows []T
+	if err := tx.Where(query, args...).Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	result := tx.Model(new(T)).Where(query, args...).Update("deleted_at", deletedAt)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	for i := range rows {
+		if err := recordAudit(tx, models.AuditActionDelete, &rows[i], nil); err != nil {
+			return 0, err
+		}
+	}
+	return result.RowsAffected, nil
+}
+
+// auditTransaction loads the transaction with its active entries as the snapshot of an audit event
+func auditTransaction(tx *gorm.DB, transactionID interface{}) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := tx.Preload("TransactionEntries", "deleted_at IS NULL").Where("id = ?", transactionID).First(&transaction).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "transaction", ID: fmt.Sprint(transactionID)}
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &transaction, nil
+}
+
+// ListAuditEvents retrieves the audit events of an entity in the order they were recorded
+func (r *PostgreSQLRepository) ListAuditEvents(ctx context.Context, filter models.ListAuditEventsInput) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	query := db.WithContext(ctx).Where("entity_id = ?", filter.EntityID)
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Order("created_at, id").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	return events, nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/savak1990/transactions-service/app/models"
 	"gorm.io/gorm"
@@ -10,9 +11,14 @@ import (
 
 // CreateBalance creates a new balance in the database
 func (r *PostgreSQLRepository) CreateBalance(ctx context.Context, balance models.Balance) (*models.Balance, error) {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	// Create the balance in the database
-	if err := db.WithContext(ctx).Create(&balance).Error; err != nil {
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &balance)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to create balance: %w", err)
 	}
 	return &balance, nil
@@ -21,7 +27,10 @@ func (r *PostgreSQLRepository) CreateBalance(ctx context.Context, balance models
 // ListBalances retrieves balances with optional inclusion of soft-deleted ones
 func (r *PostgreSQLRepository) ListBalances(ctx context.Context, filter models.ListBalancesInput) ([]models.Balance, error) {
 	var balances []models.Balance
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	query := db.WithContext(ctx)
 
 	// Filter out deleted balances unless explicitly requested
@@ -38,16 +47,13 @@ func (r *PostgreSQLRepository) ListBalances(ctx context.Context, filter models.L
 	}
 
 	// Apply ordering
-	orderBy := "created_at ASC"
+	orderBy := "created_at"
+	sortKind := sortValueTime
+	direction := "ASC"
 	if filter.SortBy != "" {
-		direction := "ASC"
-		if filter.Order == "desc" || filter.Order == "DESC" {
-			direction = "DESC"
-		}
-
 		// Validate sortBy field
 		validSortFields := map[string]string{
-			"rank":      "rank",
+			"rank":      "COALESCE(rank, 0)", // rank is optional, a missing rank is 0 like in the cursor of the balance
 			"createdAt": "created_at",
 			"updatedAt": "updated_at",
 			"title":     "title",
@@ -55,12 +61,24 @@ func (r *PostgreSQLRepository) ListBalances(ctx context.Context, filter models.L
 		}
 
 		if dbField, valid := validSortFields[filter.SortBy]; valid {
-			orderBy = fmt.Sprintf("%s %s", dbField, direction)
-		} else {
-			orderBy = "created_at ASC"
+			orderBy = dbField
+			if filter.Order == "desc" {
+				direction = "DESC"
+			}
+			switch dbField {
+			case "COALESCE(rank, 0)":
+				sortKind = sortValueInt
+			case "title":
+				sortKind = sortValueString
+			}
 		}
 	}
-	query = query.Order(orderBy)
+
+	// Order by the sort key with the balance ID as tie-breaker and continue after the cursor, if any
+	query, err = applyKeysetPagination(query, filter.Cursor, orderBy, sortKind, "id", direction)
+	if err != nil {
+		return nil, err
+	}
 
 	// Apply limit
 	if filter.Limit > 0 {
@@ -77,10 +95,13 @@ func (r *PostgreSQLRepository) ListBalances(ctx context.Context, filter models.L
 // GetBalance retrieves a balance by ID including soft-deleted ones
 func (r *PostgreSQLRepository) GetBalance(ctx context.Context, balanceID string) (*models.Balance, error) {
 	var balance models.Balance
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	if err := db.WithContext(ctx).Where("id = ?", balanceID).First(&balance).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("balance not found: %s", balanceID)
+			return nil, &models.NotFoundError{Entity: "balance", ID: balanceID}
 		}
 		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
@@ -89,8 +110,13 @@ func (r *PostgreSQLRepository) GetBalance(ctx context.Context, balanceID string)
 
 // UpdateBalance updates an existing balance
 func (r *PostgreSQLRepository) UpdateBalance(ctx context.Context, balance models.Balance) (*models.Balance, error) {
-	db := r.getDB()
-	if err := db.WithContext(ctx).Save(&balance).Error; err != nil {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedSave(tx, balance.ID, &balance)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to update balance: %w", err)
 	}
 	return &balance, nil
@@ -98,23 +124,114 @@ func (r *PostgreSQLRepository) UpdateBalance(ctx context.Context, balance models
 
 // DeleteBalance soft deletes a balance by ID
 func (r *PostgreSQLRepository) DeleteBalance(ctx context.Context, balanceID string) error {
-	db := r.getDB()
-	result := db.WithContext(ctx).Model(&models.Balance{}).Where("id = ?", balanceID).Update("deleted_at", "NOW()")
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete balance: %w", result.Error)
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	var deleted int64
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = auditedSoftDelete[models.Balance](tx, time.Now().UTC(), "id = ? AND deleted_at IS NULL", balanceID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to delete balance: %w", err)
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("balance not found: %s", balanceID)
+	if deleted == 0 {
+		return &models.NotFoundError{Entity: "balance", ID: balanceID}
 	}
 	return nil
 }
 
-// DeleteBalancesByUserId soft deletes all balances for a user ID
+// DeleteBalancesByUserId soft deletes all active balances for a user ID, deleted ones keep their deletion time
 func (r *PostgreSQLRepository) DeleteBalancesByUserId(ctx context.Context, userId string) error {
-	db := r.getDB()
-	result := db.WithContext(ctx).Model(&models.Balance{}).Where("user_id = ?", userId).Update("deleted_at", "NOW()")
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete balances for user %s: %w", userId, result.Error)
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := auditedSoftDelete[models.Balance](tx, time.Now().UTC(), "user_id = ? AND deleted_at IS NULL", userId)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to delete balances for user %s: %w", userId, err)
 	}
 	return nil
 }
+
+// balanceAmountRaw represents a raw aggregated amount row for a balance
+type balanceAmountRaw struct {
+	BalanceID   string `gorm:"column:balance_id"`
+	Currency    string `gorm:"column:currency"`
+	TotalAmount int64  `gorm:"column:total_amount"` // Amount in cents
+}
+
+// signedEntryAmountSQL returns a SQL expression that signs the given amount column by transaction type:
+// init, income and move_in add to the balance, expense and move_out subtract from it
+func signedEntryAmountSQL(amountColumn string) string {
+	return fmt.Sprintf("CASE WHEN t.type IN ('expense', 'move_out') THEN -%s ELSE %s END", amountColumn, amountColumn)
+}
+
+// GetBalanceAmounts computes the running amount of each balance from its non-deleted transaction entries.
+// If asOf is provided, only transactions transacted at or before that time are taken into account.
+func (r *PostgreSQLRepository) GetBalanceAmounts(ctx context.Context, balanceIDs []string, asOf *time.Time) (map[string]models.BalanceAmounts, error) {
+	amounts := make(map[string]models.BalanceAmounts, len(balanceIDs))
+	if len(balanceIDs) == 0 {
+		return amounts, nil
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	baseQuery := func() *gorm.DB {
+		query := db.WithContext(ctx).Table("transaction_entry te").
+			Joins("JOIN transaction t ON te.transaction_id = t.id").
+			Where("te.deleted_at IS NULL AND t.deleted_at IS NULL").
+			Where("t.balance_id IN ?", balanceIDs)
+		if asOf != nil {
+			query = query.Where("t.transacted_at <= ?", *asOf)
+		}
+		return query
+	}
+
+	// Amounts in the balance currency come straight from transaction_entry
+	var baseRows []balanceAmountRaw
+	if err := baseQuery().
+		Select("t.balance_id::text as balance_id, COALESCE(SUM(" + signedEntryAmountSQL("te.amount") + "), 0) as total_amount").
+		Group("t.balance_id").
+		Find(&baseRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute balance amounts: %w", err)
+	}
+
+	// Amounts in other currencies are derived from transaction_entry_amount
+	var currencyRows []balanceAmountRaw
+	if err := baseQuery().
+		Joins("JOIN transaction_entry_amount tea ON tea.transaction_entry_id = te.id").
+		Select("t.balance_id::text as balance_id, tea.currency as currency, COALESCE(SUM(" + signedEntryAmountSQL("tea.amount") + "), 0) as total_amount").
+		Group("t.balance_id, tea.currency").
+		Find(&currencyRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute balance currency amounts: %w", err)
+	}
+
+	// Balances without any entries still get an explicit zero amount
+	for _, balanceID := range balanceIDs {
+		amounts[balanceID] = models.BalanceAmounts{}
+	}
+
+	for _, row := range baseRows {
+		balanceAmounts := amounts[row.BalanceID]
+		balanceAmounts.Amount = row.TotalAmount
+		amounts[row.BalanceID] = balanceAmounts
+	}
+
+	for _, row := range currencyRows {
+		balanceAmounts := amounts[row.BalanceID]
+		if balanceAmounts.CurrencyAmounts == nil {
+			balanceAmounts.CurrencyAmounts = make(map[string]int64)
+		}
+		balanceAmounts.CurrencyAmounts[row.Currency] = row.TotalAmount
+		amounts[row.BalanceID] = balanceAmounts
+	}
+
+	return amounts, nil
+}
@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestGetBalanceAmountsQueries(t *testing.T) {
+	balanceIDs := []string{uuid.New().String(), uuid.New().String()}
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		asOf *time.Time
+	}{
+		{"now", nil},
+		{"as of", &asOf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+			var statements []string
+			var vars [][]interface{}
+			if err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+				statements = append(statements, tx.Statement.SQL.String())
+				vars = append(vars, tx.Statement.Vars)
+			}); err != nil {
+				t.Fatalf("Failed to register callback: %v", err)
+			}
+
+			amounts, err := NewPostgreSQLRepository(db).GetBalanceAmounts(context.Background(), balanceIDs, tt.asOf)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(statements) != 2 {
+				t.Fatalf("Expected 2 queries, got %d", len(statements))
+			}
+
+			for i, statement := range statements {
+				// Expenses and outgoing movements subtract from the balance, every other type adds to it
+				if !strings.Contains(statement, "CASE WHEN t.type IN ('expense', 'move_out') THEN -") {
+					t.Errorf("Expected amounts to be signed by transaction type, got\n%s", statement)
+				}
+				if !strings.Contains(statement, "te.deleted_at IS NULL AND t.deleted_at IS NULL") {
+					t.Errorf("Expected soft-deleted entries and transactions to be excluded, got\n%s", statement)
+				}
+				hasCutoff := strings.Contains(statement, "t.transacted_at <=")
+				if hasCutoff != (tt.asOf != nil) {
+					t.Errorf("Expected the asOf cutoff only when asOf is given, got\n%s", statement)
+				}
+				if tt.asOf != nil && vars[i][len(vars[i])-1] != asOf {
+					t.Errorf("Expected the cutoff to be %s, got %v", asOf, vars[i])
+				}
+			}
+
+			// Without rows every balance still reports an explicit zero
+			for _, balanceID := range balanceIDs {
+				balanceAmounts, ok := amounts[balanceID]
+				if !ok || balanceAmounts.Amount != 0 || len(balanceAmounts.CurrencyAmounts) != 0 {
+					t.Errorf("Expected a zero amount for balance %s, got %+v (present %t)", balanceID, balanceAmounts, ok)
+				}
+			}
+		})
+	}
+}
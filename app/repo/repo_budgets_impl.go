@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// CreateBudget creates a new budget
+func (r *PostgreSQLRepository) CreateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &budget)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create budget: %w", err)
+	}
+	return &budget, nil
+}
+
+// GetBudget retrieves a non-deleted budget by ID
+func (r *PostgreSQLRepository) GetBudget(ctx context.Context, budgetID string) (*models.Budget, error) {
+	var budget models.Budget
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", budgetID).First(&budget).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "budget", ID: budgetID}
+		}
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+	return &budget, nil
+}
+
+// ListBudgets retrieves non-deleted budgets filtered by group, user and scope
+func (r *PostgreSQLRepository) ListBudgets(ctx context.Context, filter models.ListBudgetsInput) ([]models.Budget, error) {
+	var budgets []models.Budget
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	query := db.WithContext(ctx).Where("deleted_at IS NULL")
+
+	if filter.GroupID != "" {
+		query = query.Where("group_id = ?", filter.GroupID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ScopeType != "" {
+		query = query.Where("scope_type = ?", filter.ScopeType)
+	}
+	if filter.ScopeID != "" {
+		query = query.Where("scope_id = ?", filter.ScopeID)
+	}
+
+	if err := query.Order("name ASC, id ASC").Find(&budgets).Error; err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+	return budgets, nil
+}
+
+// UpdateBudget updates an existing budget
+func (r *PostgreSQLRepository) UpdateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	var updated bool
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = auditedUpdate[models.Budget](tx, budget.ID, &budget,
+			[]string{"group_id", "user_id", "name", "scope_type", "scope_id", "period", "start_date", "end_date", "amount", "currency", "updated_at"},
+			"deleted_at IS NULL")
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update budget: %w", err)
+	}
+	if !updated {
+		return nil, &models.NotFoundError{Entity: "budget", ID: budget.ID.String()}
+	}
+	return r.GetBudget(ctx, budget.ID.String())
+}
+
+// DeleteBudget soft deletes a budget by ID
+func (r *PostgreSQLRepository) DeleteBudget(ctx context.Context, budgetID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	var deleted int64
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = auditedSoftDelete[models.Budget](tx, time.Now().UTC(), "id = ? AND deleted_at IS NULL", budgetID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	if deleted == 0 {
+		return &models.NotFoundError{Entity: "budget", ID: budgetID}
+	}
+	return nil
+}
@@ -11,9 +11,14 @@ import (
 
 // CreateCategory creates a new category in the database
 func (r *PostgreSQLRepository) CreateCategory(ctx context.Context, category models.Category) (*models.Category, error) {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	// Create the category in the database
-	if err := db.WithContext(ctx).Create(&category).Error; err != nil {
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &category)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to create category: %w", err)
 	}
 	return &category, nil
@@ -22,7 +27,10 @@ func (r *PostgreSQLRepository) CreateCategory(ctx context.Context, category mode
 // ListCategories retrieves all categories
 func (r *PostgreSQLRepository) ListCategories(ctx context.Context, input models.ListCategoriesInput) ([]models.Category, error) {
 	var categories []models.Category
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	query := db.WithContext(ctx)
 
 	// Exclude soft-deleted categories by default
@@ -30,32 +38,40 @@ func (r *PostgreSQLRepository) ListCategories(ctx context.Context, input models.
 
 	// Apply ordering
 	orderBy := "name"
+	sortKind := sortValueString
 	if input.SortBy != "" {
 		switch input.SortBy {
-		case "name", "rank", "created_at", "updated_at":
-			orderBy = input.SortBy
+		case "name":
+			orderBy = "name"
+		case "rank":
+			orderBy = "COALESCE(rank, 0)" // rank is optional, treat missing rank as 0 to keep keyset comparison valid
+			sortKind = sortValueInt
 		case "createdAt":
 			orderBy = "created_at"
+			sortKind = sortValueTime
 		case "updatedAt":
 			orderBy = "updated_at"
+			sortKind = sortValueTime
 		default:
 			orderBy = "name" // fallback to default if invalid sort field
 		}
 	}
 	order := "ASC"
-	if input.Order != "" && (input.Order == "DESC" || input.Order == "desc") {
+	if input.Order == "desc" {
 		order = "DESC"
 	}
-	query = query.Order(fmt.Sprintf("%s %s", orderBy, order))
+
+	// Order by the sort key with the category ID as tie-breaker and continue after the cursor, if any
+	query, err = applyKeysetPagination(query, input.Cursor, orderBy, sortKind, "id", order)
+	if err != nil {
+		return nil, err
+	}
 
 	// Apply limit
 	if input.Limit > 0 {
 		query = query.Limit(input.Limit)
 	}
 
-	// For now, we'll ignore pagination (StartKey) since it's more complex
-	// You can implement pagination later if needed
-
 	if err := query.Preload("CategoryGroup").Find(&categories).Error; err != nil {
 		return nil, fmt.Errorf("failed to list categories: %w", err)
 	}
@@ -66,10 +82,13 @@ func (r *PostgreSQLRepository) ListCategories(ctx context.Context, input models.
 // GetCategory retrieves a category by ID
 func (r *PostgreSQLRepository) GetCategory(ctx context.Context, categoryID string) (*models.Category, error) {
 	var category models.Category
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	if err := db.WithContext(ctx).Preload("CategoryGroup").Where("id = ? AND deleted_at IS NULL", categoryID).First(&category).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("category not found: %s", categoryID)
+			return nil, &models.NotFoundError{Entity: "category", ID: categoryID}
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -78,8 +97,13 @@ func (r *PostgreSQLRepository) GetCategory(ctx context.Context, categoryID strin
 
 // UpdateCategory updates an existing category
 func (r *PostgreSQLRepository) UpdateCategory(ctx context.Context, category models.Category) (*models.Category, error) {
-	db := r.getDB()
-	if err := db.WithContext(ctx).Save(&category).Error; err != nil {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedSave(tx, category.ID, &category)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to update category: %w", err)
 	}
 
@@ -94,20 +118,23 @@ func (r *PostgreSQLRepository) UpdateCategory(ctx context.Context, category mode
 
 // DeleteCategory deletes a category by ID using soft deletion
 func (r *PostgreSQLRepository) DeleteCategory(ctx context.Context, categoryID string) error {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
 
 	// Simply set deleted_at to current time - no foreign key constraints involved
-	now := time.Now()
-	result := db.WithContext(ctx).Model(&models.Category{}).
-		Where("id = ? AND deleted_at IS NULL", categoryID).
-		Update("deleted_at", now)
-
-	if result.Error != nil {
-		return fmt.Errorf("failed to soft delete category: %w", result.Error)
+	var deleted int64
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = auditedSoftDelete[models.Category](tx, time.Now(), "id = ? AND deleted_at IS NULL", categoryID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to soft delete category: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("category not found or already deleted: %s", categoryID)
+	if deleted == 0 {
+		return &models.NotFoundError{Entity: "category", ID: categoryID}
 	}
 
 	return nil
@@ -115,8 +142,25 @@ func (r *PostgreSQLRepository) DeleteCategory(ctx context.Context, categoryID st
 
 // DeleteCategoriesByUserId deletes all categories for a user ID
 func (r *PostgreSQLRepository) DeleteCategoriesByUserId(ctx context.Context, userId string) error {
-	db := r.getDB()
-	if err := db.WithContext(ctx).Where("user_id = ?", userId).Delete(&models.Category{}).Error; err != nil {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var categories []models.Category
+		if err := tx.Where("user_id = ?", userId).Find(&categories).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&models.Category{}).Error; err != nil {
+			return err
+		}
+		for i := range categories {
+			if err := recordAudit(tx, models.AuditActionDelete, &categories[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
 		return fmt.Errorf("failed to delete categories for user %s: %w", userId, err)
 	}
 	return nil
@@ -0,0 +1,190 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// CreateCategorizationRule creates a new categorization rule
+func (r *PostgreSQLRepository) CreateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &rule)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create categorization rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// GetCategorizationRule retrieves a non-deleted categorization rule by ID
+func (r *PostgreSQLRepository) GetCategorizationRule(ctx context.Context, ruleID string) (*models.CategorizationRule, error) {
+	var rule models.CategorizationRule
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", ruleID).First(&rule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "categorization rule", ID: ruleID}
+		}
+		return nil, fmt.Errorf("failed to get categorization rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// ListCategorizationRules retrieves non-deleted categorization rules filtered by group and user in evaluation order,
+// disabled rules are only included on request
+func (r *PostgreSQLRepository) ListCategorizationRules(ctx context.Context, filter models.ListCategorizationRulesInput) ([]models.CategorizationRule, error) {
+	var rules []models.CategorizationRule
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	query := db.WithContext(ctx).Where("deleted_at IS NULL")
+
+	if filter.GroupID != "" {
+		query = query.Where("group_id = ?", filter.GroupID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if !filter.IncludeDisabled {
+		query = query.Where("disabled = ?", false)
+	}
+
+	if err := query.Order("priority ASC, created_at ASC, id ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list categorization rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateCategorizationRule updates an existing categorization rule
+func (r *PostgreSQLRepository) UpdateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	var updated bool
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = auditedUpdate[models.CategorizationRule](tx, rule.ID, &rule,
+			[]string{"group_id", "user_id", "name", "priority", "disabled", "merchant_id", "balance_id",
+				"description_contains", "description_pattern", "min_amount", "max_amount", "set_category_id",
+				"set_merchant_id", "append_description", "updated_at"},
+			"deleted_at IS NULL")
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update categorization rule: %w", err)
+	}
+	if !updated {
+		return nil, &models.NotFoundError{Entity: "categorization rule", ID: rule.ID.String()}
+	}
+	return r.GetCategorizationRule(ctx, rule.ID.String())
+}
+
+// DeleteCategorizationRule soft deletes a categorization rule by ID
+func (r *PostgreSQLRepository) DeleteCategorizationRule(ctx context.Context, ruleID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	var deleted int64
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = auditedSoftDelete[models.CategorizationRule](tx, time.Now().UTC(), "id = ? AND deleted_at IS NULL", ruleID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to delete categorization rule: %w", err)
+	}
+	if deleted == 0 {
+		return &models.NotFoundError{Entity: "categorization rule", ID: ruleID}
+	}
+	return nil
+}
+
+// ApplyCategorizationChanges stores the changes of categorization rules on existing transactions. The changes of a
+// transaction are applied and audited in one database transaction, the fingerprint is recomputed if the merchant
+// changes.
+func (r *PostgreSQLRepository) ApplyCategorizationChanges(ctx context.Context, changes []models.CategorizationChange) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	var transactionIDs []uuid.UUID
+	changesByTransaction := make(map[uuid.UUID][]models.CategorizationChange)
+	for _, change := range changes {
+		if _, ok := changesByTransaction[change.TransactionID]; !ok {
+			transactionIDs = append(transactionIDs, change.TransactionID)
+		}
+		changesByTransaction[change.TransactionID] = append(changesByTransaction[change.TransactionID], change)
+	}
+
+	for _, transactionID := range transactionIDs {
+		if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return applyCategorizationChanges(tx, transactionID, changesByTransaction[transactionID])
+		}); err != nil {
+			return fmt.Errorf("failed to apply categorization rules to transaction %s: %w", transactionID, err)
+		}
+	}
+	return nil
+}
+
+// applyCategorizationChanges updates the entries and the merchant of one transaction within the given database
+// transaction
+func applyCategorizationChanges(tx *gorm.DB, transactionID uuid.UUID, changes []models.CategorizationChange) error {
+	before, err := auditTransaction(tx, transactionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+
+	var merchantID *uuid.UUID
+	for _, change := range changes {
+		updates := map[string]interface{}{}
+		if change.CategoryID != nil {
+			updates["category_id"] = *change.CategoryID
+		}
+		if change.Description != nil {
+			updates["description"] = *change.Description
+		}
+		if change.MerchantID != nil {
+			merchantID = change.MerchantID
+		}
+		if len(updates) == 0 {
+			continue
+		}
+		updates["updated_at"] = now
+		if err := tx.Model(&models.TransactionEntry{}).
+			Where("id = ? AND transaction_id = ? AND deleted_at IS NULL", change.EntryID, transactionID).
+			UpdateColumns(updates).Error; err != nil {
+			return fmt.Errorf("failed to update transaction entry %s: %w", change.EntryID, err)
+		}
+	}
+
+	if merchantID != nil {
+		if err := tx.Model(&models.Transaction{}).Where("id = ?", transactionID).
+			UpdateColumns(map[string]interface{}{"merchant_id": *merchantID, "updated_at": now}).Error; err != nil {
+			return fmt.Errorf("failed to update transaction merchant: %w", err)
+		}
+		updated := *before
+		updated.MerchantID = merchantID
+		if err := updateTransactionFingerprint(tx, updated); err != nil {
+			return err
+		}
+	}
+
+	after, err := auditTransaction(tx, transactionID)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, models.AuditActionUpdate, before, after)
+}
@@ -13,8 +13,13 @@ import (
 
 // CreateCategoryGroup creates a new category group in the database
 func (r *PostgreSQLRepository) CreateCategoryGroup(ctx context.Context, categoryGroup models.CategoryGroup) (*models.CategoryGroup, error) {
-	db := r.getDB()
-	if err := db.WithContext(ctx).Create(&categoryGroup).Error; err != nil {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &categoryGroup)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to create category group: %w", err)
 	}
 	return &categoryGroup, nil
@@ -23,14 +28,17 @@ func (r *PostgreSQLRepository) CreateCategoryGroup(ctx context.Context, category
 // ListCategoryGroups retrieves category groups based on the filter
 func (r *PostgreSQLRepository) ListCategoryGroups(ctx context.Context, filter models.ListCategoryGroupsInput) ([]models.CategoryGroup, error) {
 	var categoryGroups []models.CategoryGroup
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	query := db.WithContext(ctx).Where("deleted_at IS NULL")
 
 	// Apply ordering
 	orderBy := "rank"
 	if filter.SortBy != "" {
 		switch filter.SortBy {
-		case "rank", "name", "created_at", "updated_at":
+		case "rank", "name":
 			orderBy = filter.SortBy
 		case "createdAt":
 			orderBy = "created_at"
@@ -41,7 +49,7 @@ func (r *PostgreSQLRepository) ListCategoryGroups(ctx context.Context, filter mo
 		}
 	}
 	order := "DESC"
-	if filter.Order != "" && (filter.Order == "ASC" || filter.Order == "asc") {
+	if filter.Order == "asc" {
 		order = "ASC"
 	}
 	query = query.Order(fmt.Sprintf("%s %s", orderBy, order))
@@ -61,7 +69,10 @@ func (r *PostgreSQLRepository) ListCategoryGroups(ctx context.Context, filter mo
 // GetCategoryGroup retrieves a category group by ID
 func (r *PostgreSQLRepository) GetCategoryGroup(ctx context.Context, categoryGroupID string) (*models.CategoryGroup, error) {
 	var categoryGroup models.CategoryGroup
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 
 	if err := db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", categoryGroupID).First(&categoryGroup).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -75,8 +86,13 @@ func (r *PostgreSQLRepository) GetCategoryGroup(ctx context.Context, categoryGro
 
 // UpdateCategoryGroup updates an existing category group
 func (r *PostgreSQLRepository) UpdateCategoryGroup(ctx context.Context, categoryGroup models.CategoryGroup) (*models.CategoryGroup, error) {
-	db := r.getDB()
-	if err := db.WithContext(ctx).Save(&categoryGroup).Error; err != nil {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedSave(tx, categoryGroup.ID, &categoryGroup)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to update category group: %w", err)
 	}
 	return &categoryGroup, nil
@@ -84,20 +100,23 @@ func (r *PostgreSQLRepository) UpdateCategoryGroup(ctx context.Context, category
 
 // DeleteCategoryGroup deletes a category group by ID using soft deletion
 func (r *PostgreSQLRepository) DeleteCategoryGroup(ctx context.Context, categoryGroupID string) error {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
 
 	// Simply set deleted_at to current time - no foreign key constraints involved
-	now := time.Now()
-	result := db.WithContext(ctx).Model(&models.CategoryGroup{}).
-		Where("id = ? AND deleted_at IS NULL", categoryGroupID).
-		Update("deleted_at", now)
-
-	if result.Error != nil {
-		return fmt.Errorf("failed to soft delete category group: %w", result.Error)
+	var deleted int64
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = auditedSoftDelete[models.CategoryGroup](tx, time.Now(), "id = ? AND deleted_at IS NULL", categoryGroupID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to soft delete category group: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("category group not found or already deleted: %s", categoryGroupID)
+	if deleted == 0 {
+		return &models.NotFoundError{Entity: "category group", ID: categoryGroupID}
 	}
 
 	return nil
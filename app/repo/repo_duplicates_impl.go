@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// maxDuplicateCandidates bounds the transactions loaded to find duplicate clusters
+const maxDuplicateCandidates = 5000
+
+// ListDuplicateCandidates retrieves non-deleted transactions sharing their fingerprint with at least one other
+// transaction in the filter scope, ordered by fingerprint and transaction date. Clustering by time is left to the caller.
+func (r *PostgreSQLRepository) ListDuplicateCandidates(ctx context.Context, filter models.ListDuplicateTransactionsInput) ([]models.DuplicateCandidate, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	db = db.WithContext(ctx)
+
+	fingerprints := applyDuplicateFilter(db.Model(&models.Transaction{}), filter, "transaction").
+		Select("fingerprint").
+		Where("fingerprint IS NOT NULL").
+		Group("fingerprint").
+		Having("COUNT(*) > 1")
+
+	var candidates []models.DuplicateCandidate
+	err = applyDuplicateFilter(db.Table("transaction t"), filter, "t").
+		Select(`t.id AS transaction_id, t.group_id, t.user_id, t.balance_id, t.merchant_id, t.type, t.external_id,
+			t.fingerprint, t.transacted_at, t.created_at,
+			(SELECT COALESCE(SUM(e.amount), 0) FROM transaction_entry e WHERE e.transaction_id = t.id AND e.deleted_at IS NULL) AS amount,
+			COALESCE((SELECT e.description FROM transaction_entry e WHERE e.transaction_id = t.id AND e.deleted_at IS NULL
+				ORDER BY e.created_at, e.id LIMIT 1), '') AS description`).
+		Where("t.fingerprint IN (?)", fingerprints).
+		Order("t.fingerprint ASC, t.transacted_at ASC, t.id ASC").
+		Limit(maxDuplicateCandidates).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// FindPossibleDuplicate returns the ID of the other non-deleted transaction with the same fingerprint whose
+// transaction date is closest to the given transaction within the window, empty if there is none
+func (r *PostgreSQLRepository) FindPossibleDuplicate(ctx context.Context, tx models.Transaction, window time.Duration) (string, error) {
+	if tx.Fingerprint == nil {
+		return "", nil
+	}
+
+	var transactionIDs []string
+	db, err := r.getDB()
+	if err != nil {
+		return "", err
+	}
+	if err := db.WithContext(ctx).Model(&models.Transaction{}).
+		Where("fingerprint = ? AND id <> ? AND deleted_at IS NULL", *tx.Fingerprint, tx.ID).
+		Where("transacted_at BETWEEN ? AND ?", tx.TransactedAt.Add(-window), tx.TransactedAt.Add(window)).
+		Order(gorm.Expr("ABS(EXTRACT(EPOCH FROM (transacted_at - ?))) ASC, created_at ASC", tx.TransactedAt)).
+		Limit(1).
+		Pluck("id", &transactionIDs).Error; err != nil {
+		return "", fmt.Errorf("failed to find possible duplicate: %w", err)
+	}
+	if len(transactionIDs) == 0 {
+		return "", nil
+	}
+	return transactionIDs[0], nil
+}
+
+// applyDuplicateFilter restricts a query on the transaction table (with the given alias) to the filter scope
+func applyDuplicateFilter(query *gorm.DB, filter models.ListDuplicateTransactionsInput, alias string) *gorm.DB {
+	query = query.Where(alias + ".deleted_at IS NULL")
+	if filter.GroupID != "" {
+		query = query.Where(alias+".group_id = ?", filter.GroupID)
+	}
+	if filter.UserID != "" {
+		query = query.Where(alias+".user_id = ?", filter.UserID)
+	}
+	if len(filter.BalanceIds) > 0 {
+		query = query.Where(alias+".balance_id IN ?", filter.BalanceIds)
+	}
+	if !filter.StartTime.IsZero() {
+		query = query.Where(alias+".transacted_at >= ?", filter.StartTime)
+	}
+	if !filter.EndTime.IsZero() {
+		query = query.Where(alias+".transacted_at <= ?", filter.EndTime)
+	}
+	return query
+}
@@ -0,0 +1,291 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateGroup creates a new group together with its initial members
+func (r *PostgreSQLRepository) CreateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := auditedCreate(tx, &group); err != nil {
+			return err
+		}
+		for i := range group.Members {
+			if err := recordAudit(tx, models.AuditActionCreate, nil, &group.Members[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+	return r.FindGroup(ctx, group.ID.String())
+}
+
+// FindGroup retrieves a non-deleted group with its members, returns nil if the group doesn't exist
+func (r *PostgreSQLRepository) FindGroup(ctx context.Context, groupID string) (*models.Group, error) {
+	var group models.Group
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	err = db.WithContext(ctx).
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Where("id = ? AND deleted_at IS NULL", groupID).
+		First(&group).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil // Data may reference a group ID that has no group record (yet)
+		}
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	return &group, nil
+}
+
+// ListGroupsByMember retrieves all non-deleted groups the user is a member of
+func (r *PostgreSQLRepository) ListGroupsByMember(ctx context.Context, userID string) ([]models.Group, error) {
+	var groups []models.Group
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	err = db.WithContext(ctx).
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Where("deleted_at IS NULL").
+		Where("id IN (?)", db.Model(&models.GroupMember{}).Select("group_id").Where("user_id = ?", userID)).
+		Order("name ASC, id ASC").
+		Find(&groups).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	return groups, nil
+}
+
+// UpdateGroup updates the name and description of a group
+func (r *PostgreSQLRepository) UpdateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	var updated bool
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = auditedUpdate[models.Group](tx, group.ID, map[string]interface{}{
+			"name":        group.Name,
+			"description": group.Description,
+			"updated_at":  time.Now().UTC(),
+		}, nil, "deleted_at IS NULL")
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update group: %w", err)
+	}
+	if !updated {
+		return nil, &models.NotFoundError{Entity: "group", ID: group.ID.String()}
+	}
+	return r.FindGroup(ctx, group.ID.String())
+}
+
+// DeleteGroup soft deletes a group and revokes its pending invitations
+func (r *PostgreSQLRepository) DeleteGroup(ctx context.Context, groupID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleted, err := auditedSoftDelete[models.Group](tx, time.Now().UTC(), "id = ? AND deleted_at IS NULL", groupID)
+		if err != nil {
+			return fmt.Errorf("failed to delete group: %w", err)
+		}
+		if deleted == 0 {
+			return &models.NotFoundError{Entity: "group", ID: groupID}
+		}
+
+		var invitationIDs []string
+		if err := tx.Model(&models.GroupInvitation{}).
+			Where("group_id = ? AND status = ?", groupID, models.InvitationStatusPending).
+			Pluck("id", &invitationIDs).Error; err != nil {
+			return fmt.Errorf("failed to revoke group invitations: %w", err)
+		}
+		for _, invitationID := range invitationIDs {
+			if _, err := auditedUpdate[models.GroupInvitation](tx, invitationID, map[string]interface{}{
+				"status":       models.InvitationStatusRevoked,
+				"responded_at": time.Now().UTC(),
+			}, nil); err != nil {
+				return fmt.Errorf("failed to revoke group invitations: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// SaveGroupMember adds a member to a group or updates the role of an existing member
+func (r *PostgreSQLRepository) SaveGroupMember(ctx context.Context, member models.GroupMember) (*models.GroupMember, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveGroupMember(tx, &member)
+	}); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// saveGroupMember upserts the member and records its creation or the update of its role
+func saveGroupMember(tx *gorm.DB, member *models.GroupMember) error {
+	var existing []models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ?", member.GroupID, member.UserID).Limit(1).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to get group member: %w", err)
+	}
+
+	member.UpdatedAt = time.Now().UTC()
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(member).Error
+	if err != nil {
+		return fmt.Errorf("failed to save group member: %w", err)
+	}
+
+	if len(existing) == 0 {
+		return recordAudit(tx, models.AuditActionCreate, nil, member)
+	}
+	return recordAudit(tx, models.AuditActionUpdate, &existing[0], member)
+}
+
+// DeleteGroupMember removes a user from a group
+func (r *PostgreSQLRepository) DeleteGroupMember(ctx context.Context, groupID string, userID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var members []models.GroupMember
+		if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).Limit(1).Find(&members).Error; err != nil {
+			return fmt.Errorf("failed to delete group member: %w", err)
+		}
+		if len(members) == 0 {
+			return &models.NotFoundError{Entity: "group member", ID: userID}
+		}
+
+		if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupMember{}).Error; err != nil {
+			return fmt.Errorf("failed to delete group member: %w", err)
+		}
+		return recordAudit(tx, models.AuditActionDelete, &members[0], nil)
+	})
+}
+
+// CreateGroupInvitation creates a new group invitation
+func (r *PostgreSQLRepository) CreateGroupInvitation(ctx context.Context, invitation models.GroupInvitation) (*models.GroupInvitation, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &invitation)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create group invitation: %w", err)
+	}
+	return r.GetGroupInvitation(ctx, invitation.ID.String())
+}
+
+// GetGroupInvitation retrieves a group invitation by ID with its group
+func (r *PostgreSQLRepository) GetGroupInvitation(ctx context.Context, invitationID string) (*models.GroupInvitation, error) {
+	var invitation models.GroupInvitation
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Preload("Group").Where("id = ?", invitationID).First(&invitation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "group invitation", ID: invitationID}
+		}
+		return nil, fmt.Errorf("failed to get group invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+// ListGroupInvitations retrieves invitations of non-deleted groups filtered by group, invited user and status
+func (r *PostgreSQLRepository) ListGroupInvitations(ctx context.Context, filter models.ListGroupInvitationsInput) ([]models.GroupInvitation, error) {
+	var invitations []models.GroupInvitation
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	query := db.WithContext(ctx).
+		Preload("Group").
+		Joins("JOIN user_group ON user_group.id = group_invitation.group_id AND user_group.deleted_at IS NULL")
+
+	if filter.GroupID != "" {
+		query = query.Where("group_invitation.group_id = ?", filter.GroupID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("group_invitation.user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("group_invitation.status = ?", filter.Status)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("group_invitation.expires_at > ?", time.Now().UTC())
+	}
+
+	if err := query.Order("group_invitation.created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list group invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// UpdateGroupInvitationStatus moves a pending invitation to the given final status
+func (r *PostgreSQLRepository) UpdateGroupInvitationStatus(ctx context.Context, invitationID string, status string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateGroupInvitationStatus(tx, invitationID, status)
+	})
+}
+
+func updateGroupInvitationStatus(tx *gorm.DB, invitationID string, status string) error {
+	updated, err := auditedUpdate[models.GroupInvitation](tx, invitationID, map[string]interface{}{
+		"status":       status,
+		"responded_at": time.Now().UTC(),
+		"updated_at":   time.Now().UTC(),
+	}, nil, "status = ?", models.InvitationStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to update group invitation: %w", err)
+	}
+	if !updated {
+		return &models.ConflictError{Reason: fmt.Sprintf("group invitation is no longer pending: %s", invitationID)}
+	}
+	return nil
+}
+
+// AcceptGroupInvitation marks the invitation as accepted and adds the member in one database transaction
+func (r *PostgreSQLRepository) AcceptGroupInvitation(ctx context.Context, invitationID string, member models.GroupMember) (*models.GroupMember, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateGroupInvitationStatus(tx, invitationID, models.InvitationStatusAccepted); err != nil {
+			return err
+		}
+		return saveGroupMember(tx, &member)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
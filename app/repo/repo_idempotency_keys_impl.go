@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClaimIdempotencyKey stores a new in-progress idempotency key, returns false if the caller already has the key
+func (r *PostgreSQLRepository) ClaimIdempotencyKey(ctx context.Context, record models.IdempotencyKey) (bool, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return false, err
+	}
+	result := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindIdempotencyKey retrieves an idempotency key of a caller, returns nil if the key doesn't exist
+func (r *PostgreSQLRepository) FindIdempotencyKey(ctx context.Context, userID string, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &record, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request an in-progress idempotency key was claimed for
+func (r *PostgreSQLRepository) CompleteIdempotencyKey(ctx context.Context, record models.IdempotencyKey) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	result := db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND key = ? AND request_hash = ? AND status_code = 0", record.UserID, record.Key, record.RequestHash).
+		Select("status_code", "response_body", "updated_at").
+		Updates(&record)
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return &models.NotFoundError{Entity: "idempotency key", ID: record.Key}
+	}
+	return nil
+}
+
+// TakeOverIdempotencyKey claims an expired or abandoned idempotency key for a new request in place. The key is only
+// taken over while it is still expired at now or in progress without renewal since staleBefore, so of concurrent
+// retries taking over the same key exactly one succeeds. Returns false if the key was taken over or renewed meanwhile.
+func (r *PostgreSQLRepository) TakeOverIdempotencyKey(ctx context.Context, record models.IdempotencyKey, now time.Time, staleBefore time.Time) (bool, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return false, err
+	}
+	result := db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", record.UserID, record.Key).
+		Where("expires_at < ? OR (status_code = 0 AND updated_at < ?)", now, staleBefore).
+		Updates(map[string]interface{}{
+			"request_hash":  record.RequestHash,
+			"status_code":   0,
+			"response_body": "",
+			"created_at":    gorm.Expr("now()"),
+			"updated_at":    gorm.Expr("now()"),
+			"expires_at":    record.ExpiresAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to take over idempotency key: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RenewIdempotencyKey refreshes the claim of an in-progress idempotency key so that it isn't taken over as abandoned
+func (r *PostgreSQLRepository) RenewIdempotencyKey(ctx context.Context, userID string, key string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	result := db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND key = ? AND status_code = 0", userID, key).
+		UpdateColumn("updated_at", gorm.Expr("now()"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to renew idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return &models.NotFoundError{Entity: "idempotency key", ID: key}
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey removes an in-progress idempotency key of a caller so that the key can be used again. Keys
+// completed or claimed for another request meanwhile are kept.
+func (r *PostgreSQLRepository) DeleteIdempotencyKey(ctx context.Context, userID string, key string, requestHash string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	if err := db.WithContext(ctx).Where("user_id = ? AND key = ? AND status_code = 0 AND request_hash = ?", userID, key, requestHash).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+func TestTakeOverIdempotencyKeyIsConditional(t *testing.T) {
+	db := newDryRunDB(t)
+	var statement string
+	if err := db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		statement = tx.Statement.SQL.String()
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	now := time.Now().UTC()
+	record := models.IdempotencyKey{UserID: uuid.New(), Key: "key", RequestHash: "hash", ExpiresAt: now.Add(time.Hour)}
+	repo := NewPostgreSQLRepository(db.Session(&gorm.Session{SkipDefaultTransaction: true}))
+	if _, err := repo.TakeOverIdempotencyKey(context.Background(), record, now, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The stale conditions are part of the UPDATE, so a key claimed by a concurrent takeover isn't taken again
+	want := `WHERE (user_id = $5 AND key = $6) AND (expires_at < $7 OR (status_code = 0 AND updated_at < $8))`
+	if !strings.HasPrefix(statement, `UPDATE "idempotency_key" SET `) || !strings.HasSuffix(statement, want) {
+		t.Errorf("Expected a conditional update ending with\n%s\ngot\n%s", want, statement)
+	}
+}
+
+func TestDeleteIdempotencyKeyOnlyReleasesTheOwnClaim(t *testing.T) {
+	db := newDryRunDB(t)
+	var statement string
+	var vars []interface{}
+	if err := db.Callback().Delete().After("gorm:delete").Register("test:capture", func(tx *gorm.DB) {
+		statement, vars = tx.Statement.SQL.String(), tx.Statement.Vars
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	userID := uuid.New().String()
+	repo := NewPostgreSQLRepository(db.Session(&gorm.Session{SkipDefaultTransaction: true}))
+	if err := repo.DeleteIdempotencyKey(context.Background(), userID, "key", "hash"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A stale release must not delete a key completed or taken over by another request
+	want := `DELETE FROM "idempotency_key" WHERE user_id = $1 AND key = $2 AND status_code = 0 AND request_hash = $3`
+	if statement != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, statement)
+	}
+	if len(vars) != 3 || vars[0] != userID || vars[1] != "key" || vars[2] != "hash" {
+		t.Errorf("Unexpected arguments %v", vars)
+	}
+}
@@ -4,14 +4,13 @@ import (
 	"fmt"
 
 	"github.com/savak1990/transactions-service/app/aws"
-	"github.com/savak1990/transactions-service/app/config"
 	"gorm.io/gorm"
 )
 
 // PostgreSQLRepository implements Repository interface using PostgreSQL
 type PostgreSQLRepository struct {
-	db     *gorm.DB
-	config *config.AppConfig // Store config for lazy initialization
+	db          *gorm.DB
+	connections *aws.ConnectionManager // Opens the database on first use
 }
 
 // NewPostgreSQLRepository creates a new PostgreSQL repository
@@ -21,27 +20,25 @@ func NewPostgreSQLRepository(db *gorm.DB) *PostgreSQLRepository {
 	}
 }
 
-// NewPostgreSQLRepositoryWithConfig creates a new PostgreSQL repository with lazy DB initialization
-func NewPostgreSQLRepositoryWithConfig(cfg config.AppConfig) *PostgreSQLRepository {
+// NewPostgreSQLRepositoryWithConnectionManager creates a new PostgreSQL repository with lazy DB initialization
+func NewPostgreSQLRepositoryWithConnectionManager(connections *aws.ConnectionManager) *PostgreSQLRepository {
 	return &PostgreSQLRepository{
-		config: &cfg,
+		connections: connections,
 	}
 }
 
-// getDB returns the database connection, initializing it if necessary
-func (r *PostgreSQLRepository) getDB() *gorm.DB {
+// getDB returns the database connection, initializing it if necessary. While the database is unreachable it fails
+// with aws.ErrDatabaseUnavailable or aws.ErrDatabaseMaintenance.
+func (r *PostgreSQLRepository) getDB() (*gorm.DB, error) {
 	if r.db != nil {
-		return r.db
+		return r.db, nil
 	}
 
-	if r.config != nil {
-		// Lazy initialization - this will trigger connection and panic if failed
-		// We want this panic to bubble up to the maintenance middleware
-		r.db = aws.GetGormDB(*r.config)
-		return r.db
+	if r.connections != nil {
+		return r.connections.DB()
 	}
 
-	panic(fmt.Errorf("PostgreSQL repository not properly initialized"))
+	return nil, fmt.Errorf("PostgreSQL repository not properly initialized")
 }
 
 // Ensure PostgreSQLRepository implements Repository interface
@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// CreateImportProfile creates a new import profile
+func (r *PostgreSQLRepository) CreateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &profile)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create import profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// GetImportProfile retrieves a non-deleted import profile by ID
+func (r *PostgreSQLRepository) GetImportProfile(ctx context.Context, importProfileID string) (*models.ImportProfile, error) {
+	var profile models.ImportProfile
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", importProfileID).First(&profile).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "import profile", ID: importProfileID}
+		}
+		return nil, fmt.Errorf("failed to get import profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// ListImportProfiles retrieves non-deleted import profiles filtered by group and user
+func (r *PostgreSQLRepository) ListImportProfiles(ctx context.Context, filter models.ListImportProfilesInput) ([]models.ImportProfile, error) {
+	var profiles []models.ImportProfile
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	query := db.WithContext(ctx).Where("deleted_at IS NULL")
+
+	if filter.GroupID != "" {
+		query = query.Where("group_id = ?", filter.GroupID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if err := query.Order("name ASC, id ASC").Find(&profiles).Error; err != nil {
+		return nil, fmt.Errorf("failed to list import profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// UpdateImportProfile updates the column mapping of an existing import profile
+func (r *PostgreSQLRepository) UpdateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	var updated bool
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = auditedUpdate[models.ImportProfile](tx, profile.ID, &profile,
+			[]string{"group_id", "user_id", "name", "delimiter", "has_header", "skip_rows", "date_column", "date_format",
+				"amount_column", "debit_column", "credit_column", "decimal_separator", "invert_sign",
+				"description_column", "merchant_column", "id_column", "updated_at"},
+			"deleted_at IS NULL")
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update import profile: %w", err)
+	}
+	if !updated {
+		return nil, &models.NotFoundError{Entity: "import profile", ID: profile.ID.String()}
+	}
+	return r.GetImportProfile(ctx, profile.ID.String())
+}
+
+// DeleteImportProfile soft deletes an import profile by ID
+func (r *PostgreSQLRepository) DeleteImportProfile(ctx context.Context, importProfileID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	var deleted int64
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = auditedSoftDelete[models.ImportProfile](tx, time.Now().UTC(), "id = ? AND deleted_at IS NULL", importProfileID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to delete import profile: %w", err)
+	}
+	if deleted == 0 {
+		return &models.NotFoundError{Entity: "import profile", ID: importProfileID}
+	}
+	return nil
+}
+
+// ListTransactionExternalIDs returns which of the given external IDs were already imported into the balance,
+// transactions deleted after the import count as imported so that re-importing a file doesn't bring them back
+func (r *PostgreSQLRepository) ListTransactionExternalIDs(ctx context.Context, balanceID string, externalIDs []string) ([]string, error) {
+	if len(externalIDs) == 0 {
+		return []string{}, nil
+	}
+
+	var existing []string
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Model(&models.Transaction{}).
+		Where("balance_id = ? AND external_id IN ? AND deleted_at IS NULL", balanceID, externalIDs).
+		Distinct().
+		Pluck("external_id", &existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to list imported external IDs: %w", err)
+	}
+	return existing, nil
+}
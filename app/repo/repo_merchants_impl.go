@@ -11,8 +11,13 @@ import (
 
 // CreateMerchant creates a new merchant in the database
 func (r *PostgreSQLRepository) CreateMerchant(ctx context.Context, merchant models.Merchant) (*models.Merchant, error) {
-	db := r.getDB()
-	if err := db.WithContext(ctx).Create(&merchant).Error; err != nil {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &merchant)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to create merchant: %w", err)
 	}
 	return &merchant, nil
@@ -21,7 +26,10 @@ func (r *PostgreSQLRepository) CreateMerchant(ctx context.Context, merchant mode
 // ListMerchants retrieves merchants based on the filter
 func (r *PostgreSQLRepository) ListMerchants(ctx context.Context, filter models.ListMerchantsInput) ([]models.Merchant, error) {
 	var merchants []models.Merchant
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	query := db.WithContext(ctx)
 
 	// Filter out soft-deleted merchants
@@ -40,19 +48,33 @@ func (r *PostgreSQLRepository) ListMerchants(ctx context.Context, filter models.
 
 	// Apply ordering
 	orderBy := "created_at"
+	sortKind := sortValueTime
 	if filter.SortBy != "" {
 		switch filter.SortBy {
-		case "rank", "name", "created_at", "updated_at":
+		case "rank":
+			orderBy = "COALESCE(rank, 0)" // rank is optional, a missing rank is 0 like in the cursor of the merchant
+			sortKind = sortValueInt
+		case "name":
 			orderBy = filter.SortBy
+			sortKind = sortValueString
+		case "createdAt":
+			orderBy = "created_at"
+		case "updatedAt":
+			orderBy = "updated_at"
 		default:
 			orderBy = "created_at" // fallback to default if invalid sort field
 		}
 	}
 	order := "ASC"
-	if filter.Order != "" && (filter.Order == "DESC" || filter.Order == "desc") {
+	if filter.Order == "desc" {
 		order = "DESC"
 	}
-	query = query.Order(fmt.Sprintf("%s %s", orderBy, order))
+
+	// Order by the sort key with the merchant ID as tie-breaker and continue after the cursor, if any
+	query, err = applyKeysetPagination(query, filter.Cursor, orderBy, sortKind, "id", order)
+	if err != nil {
+		return nil, err
+	}
 
 	// Apply limit
 	limit := 50 // default limit
@@ -71,10 +93,13 @@ func (r *PostgreSQLRepository) ListMerchants(ctx context.Context, filter models.
 // GetMerchant retrieves a merchant by ID
 func (r *PostgreSQLRepository) GetMerchant(ctx context.Context, merchantId string) (*models.Merchant, error) {
 	var merchant models.Merchant
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 	if err := db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", merchantId).First(&merchant).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("merchant not found: %s", merchantId)
+			return nil, &models.NotFoundError{Entity: "merchant", ID: merchantId}
 		}
 		return nil, fmt.Errorf("failed to get merchant: %w", err)
 	}
@@ -83,8 +108,13 @@ func (r *PostgreSQLRepository) GetMerchant(ctx context.Context, merchantId strin
 
 // UpdateMerchant updates an existing merchant
 func (r *PostgreSQLRepository) UpdateMerchant(ctx context.Context, merchant models.Merchant) (*models.Merchant, error) {
-	db := r.getDB()
-	if err := db.WithContext(ctx).Save(&merchant).Error; err != nil {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedSave(tx, merchant.ID, &merchant)
+	}); err != nil {
 		return nil, fmt.Errorf("failed to update merchant: %w", err)
 	}
 	return &merchant, nil
@@ -92,7 +122,10 @@ func (r *PostgreSQLRepository) UpdateMerchant(ctx context.Context, merchant mode
 
 // DeleteMerchant soft deletes a merchant by ID and nullifies merchant references in transactions
 func (r *PostgreSQLRepository) DeleteMerchant(ctx context.Context, merchantId string) error {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
 
 	// Start a transaction to ensure both operations succeed or fail together
 	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -105,9 +138,7 @@ func (r *PostgreSQLRepository) DeleteMerchant(ctx context.Context, merchantId st
 
 		// Then manually soft delete the merchant by setting deleted_at timestamp
 		// This avoids triggering foreign key constraints that GORM's Delete() might cause
-		if err := tx.Model(&models.Merchant{}).
-			Where("id = ? AND deleted_at IS NULL", merchantId).
-			Update("deleted_at", time.Now()).Error; err != nil {
+		if _, err := auditedSoftDelete[models.Merchant](tx, time.Now(), "id = ? AND deleted_at IS NULL", merchantId); err != nil {
 			return fmt.Errorf("failed to soft delete merchant: %w", err)
 		}
 
@@ -117,7 +148,10 @@ func (r *PostgreSQLRepository) DeleteMerchant(ctx context.Context, merchantId st
 
 // DeleteMerchantsByUserId deletes all merchants for a user ID and nullifies merchant references in transactions
 func (r *PostgreSQLRepository) DeleteMerchantsByUserId(ctx context.Context, userId string) error {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
 
 	// Start a transaction to ensure both operations succeed or fail together
 	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -129,9 +163,7 @@ func (r *PostgreSQLRepository) DeleteMerchantsByUserId(ctx context.Context, user
 		}
 
 		// Then manually soft delete all merchants for the user by setting deleted_at timestamp
-		if err := tx.Model(&models.Merchant{}).
-			Where("user_id = ? AND deleted_at IS NULL", userId).
-			Update("deleted_at", time.Now()).Error; err != nil {
+		if _, err := auditedSoftDelete[models.Merchant](tx, time.Now(), "user_id = ? AND deleted_at IS NULL", userId); err != nil {
 			return fmt.Errorf("failed to soft delete merchants for user %s: %w", userId, err)
 		}
 
@@ -142,8 +174,11 @@ func (r *PostgreSQLRepository) DeleteMerchantsByUserId(ctx context.Context, user
 // GetMerchantByNameAndUserId checks if a merchant with the given name already exists for a user
 func (r *PostgreSQLRepository) GetMerchantByNameAndUserId(ctx context.Context, name string, userId string) (*models.Merchant, error) {
 	var merchant models.Merchant
-	db := r.getDB()
-	err := db.WithContext(ctx).Where("name = ? AND user_id = ? AND deleted_at IS NULL", name, userId).First(&merchant).Error
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	err = db.WithContext(ctx).Where("name = ? AND user_id = ? AND deleted_at IS NULL", name, userId).First(&merchant).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil // No existing merchant found - this is expected when name is available
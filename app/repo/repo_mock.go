@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/savak1990/transactions-service/app/models"
 	"github.com/stretchr/testify/mock"
@@ -56,6 +57,20 @@ func (m *MockRepository) DeleteTransaction(ctx context.Context, transactionID st
 	return args.Error(0)
 }
 
+func (m *MockRepository) ListTransactionEntriesAfter(ctx context.Context, afterEntryID string, limit int) ([]models.TransactionEntry, error) {
+	args := m.Called(ctx, afterEntryID, limit)
+	var entries []models.TransactionEntry
+	if v := args.Get(0); v != nil {
+		entries = v.([]models.TransactionEntry)
+	}
+	return entries, args.Error(1)
+}
+
+func (m *MockRepository) SaveTransactionEntryAmounts(ctx context.Context, amounts []models.TransactionEntryAmount) error {
+	args := m.Called(ctx, amounts)
+	return args.Error(0)
+}
+
 func (m *MockRepository) ListTransactions(ctx context.Context, filter models.ListTransactionsInput) ([]models.Transaction, error) {
 	args := m.Called(ctx, filter)
 	var transactions []models.Transaction
@@ -164,6 +179,14 @@ func (m *MockRepository) DeleteBalancesByUserId(ctx context.Context, userId stri
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetBalanceAmounts(ctx context.Context, balanceIDs []string, asOf *time.Time) (map[string]models.BalanceAmounts, error) {
+	args := m.Called(ctx, balanceIDs, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]models.BalanceAmounts), args.Error(1)
+}
+
 // Merchant methods
 
 func (m *MockRepository) CreateMerchant(ctx context.Context, merchant models.Merchant) (*models.Merchant, error) {
@@ -257,6 +280,511 @@ func (m *MockRepository) DeleteCategoryGroup(ctx context.Context, categoryGroupI
 	return args.Error(0)
 }
 
+// Group methods
+
+func (m *MockRepository) CreateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	args := m.Called(ctx, group)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *MockRepository) FindGroup(ctx context.Context, groupID string) (*models.Group, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *MockRepository) ListGroupsByMember(ctx context.Context, userID string) ([]models.Group, error) {
+	args := m.Called(ctx, userID)
+	var result []models.Group
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Group)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) UpdateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	args := m.Called(ctx, group)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *MockRepository) DeleteGroup(ctx context.Context, groupID string) error {
+	args := m.Called(ctx, groupID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveGroupMember(ctx context.Context, member models.GroupMember) (*models.GroupMember, error) {
+	args := m.Called(ctx, member)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupMember), args.Error(1)
+}
+
+func (m *MockRepository) DeleteGroupMember(ctx context.Context, groupID string, userID string) error {
+	args := m.Called(ctx, groupID, userID)
+	return args.Error(0)
+}
+
+// Group invitation methods
+
+func (m *MockRepository) CreateGroupInvitation(ctx context.Context, invitation models.GroupInvitation) (*models.GroupInvitation, error) {
+	args := m.Called(ctx, invitation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupInvitation), args.Error(1)
+}
+
+func (m *MockRepository) GetGroupInvitation(ctx context.Context, invitationID string) (*models.GroupInvitation, error) {
+	args := m.Called(ctx, invitationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupInvitation), args.Error(1)
+}
+
+func (m *MockRepository) ListGroupInvitations(ctx context.Context, filter models.ListGroupInvitationsInput) ([]models.GroupInvitation, error) {
+	args := m.Called(ctx, filter)
+	var result []models.GroupInvitation
+	if v := args.Get(0); v != nil {
+		result = v.([]models.GroupInvitation)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) UpdateGroupInvitationStatus(ctx context.Context, invitationID string, status string) error {
+	args := m.Called(ctx, invitationID, status)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AcceptGroupInvitation(ctx context.Context, invitationID string, member models.GroupMember) (*models.GroupMember, error) {
+	args := m.Called(ctx, invitationID, member)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupMember), args.Error(1)
+}
+
+// Budget methods
+
+func (m *MockRepository) CreateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	args := m.Called(ctx, budget)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (m *MockRepository) GetBudget(ctx context.Context, budgetID string) (*models.Budget, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (m *MockRepository) ListBudgets(ctx context.Context, filter models.ListBudgetsInput) ([]models.Budget, error) {
+	args := m.Called(ctx, filter)
+	var result []models.Budget
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Budget)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) UpdateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	args := m.Called(ctx, budget)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (m *MockRepository) DeleteBudget(ctx context.Context, budgetID string) error {
+	args := m.Called(ctx, budgetID)
+	return args.Error(0)
+}
+
+// Recurring transaction methods
+
+func (m *MockRepository) CreateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	args := m.Called(ctx, recurring)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTransaction), args.Error(1)
+}
+
+func (m *MockRepository) GetRecurringTransaction(ctx context.Context, recurringTransactionID string) (*models.RecurringTransaction, error) {
+	args := m.Called(ctx, recurringTransactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTransaction), args.Error(1)
+}
+
+func (m *MockRepository) ListRecurringTransactions(ctx context.Context, filter models.ListRecurringTransactionsInput) ([]models.RecurringTransaction, error) {
+	args := m.Called(ctx, filter)
+	var result []models.RecurringTransaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.RecurringTransaction)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) UpdateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	args := m.Called(ctx, recurring)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTransaction), args.Error(1)
+}
+
+func (m *MockRepository) DeleteRecurringTransaction(ctx context.Context, recurringTransactionID string) error {
+	args := m.Called(ctx, recurringTransactionID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListDueRecurringTransactions(ctx context.Context, now time.Time, recurringTransactionID string) ([]models.RecurringTransaction, error) {
+	args := m.Called(ctx, now, recurringTransactionID)
+	var result []models.RecurringTransaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.RecurringTransaction)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) GetRecurringOccurrenceTransactionID(ctx context.Context, recurringTransactionID string, occurrence time.Time) (string, error) {
+	args := m.Called(ctx, recurringTransactionID, occurrence)
+	return args.Get(0).(string), args.Error(1)
+}
+
+func (m *MockRepository) UpdateRecurringTransactionProgress(ctx context.Context, recurring models.RecurringTransaction) error {
+	args := m.Called(ctx, recurring)
+	return args.Error(0)
+}
+
+// Import methods
+
+func (m *MockRepository) CreateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	args := m.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportProfile), args.Error(1)
+}
+
+func (m *MockRepository) GetImportProfile(ctx context.Context, importProfileID string) (*models.ImportProfile, error) {
+	args := m.Called(ctx, importProfileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportProfile), args.Error(1)
+}
+
+func (m *MockRepository) ListImportProfiles(ctx context.Context, filter models.ListImportProfilesInput) ([]models.ImportProfile, error) {
+	args := m.Called(ctx, filter)
+	var result []models.ImportProfile
+	if v := args.Get(0); v != nil {
+		result = v.([]models.ImportProfile)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) UpdateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	args := m.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportProfile), args.Error(1)
+}
+
+func (m *MockRepository) DeleteImportProfile(ctx context.Context, importProfileID string) error {
+	args := m.Called(ctx, importProfileID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListTransactionExternalIDs(ctx context.Context, balanceID string, externalIDs []string) ([]string, error) {
+	args := m.Called(ctx, balanceID, externalIDs)
+	var result []string
+	if v := args.Get(0); v != nil {
+		result = v.([]string)
+	}
+	return result, args.Error(1)
+}
+
+// Duplicate detection methods
+
+func (m *MockRepository) ListDuplicateCandidates(ctx context.Context, filter models.ListDuplicateTransactionsInput) ([]models.DuplicateCandidate, error) {
+	args := m.Called(ctx, filter)
+	var result []models.DuplicateCandidate
+	if v := args.Get(0); v != nil {
+		result = v.([]models.DuplicateCandidate)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) FindPossibleDuplicate(ctx context.Context, tx models.Transaction, window time.Duration) (string, error) {
+	args := m.Called(ctx, tx, window)
+	return args.Get(0).(string), args.Error(1)
+}
+
+// Idempotency key methods
+
+func (m *MockRepository) ClaimIdempotencyKey(ctx context.Context, record models.IdempotencyKey) (bool, error) {
+	args := m.Called(ctx, record)
+	return args.Get(0).(bool), args.Error(1)
+}
+
+func (m *MockRepository) FindIdempotencyKey(ctx context.Context, userID string, key string) (*models.IdempotencyKey, error) {
+	args := m.Called(ctx, userID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockRepository) CompleteIdempotencyKey(ctx context.Context, record models.IdempotencyKey) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TakeOverIdempotencyKey(ctx context.Context, record models.IdempotencyKey, now time.Time, staleBefore time.Time) (bool, error) {
+	args := m.Called(ctx, record, now, staleBefore)
+	return args.Get(0).(bool), args.Error(1)
+}
+
+func (m *MockRepository) RenewIdempotencyKey(ctx context.Context, userID string, key string) error {
+	args := m.Called(ctx, userID, key)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteIdempotencyKey(ctx context.Context, userID string, key string, requestHash string) error {
+	args := m.Called(ctx, userID, key, requestHash)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction) (*models.Transfer, error) {
+	args := m.Called(ctx, transfer, legs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockRepository) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockRepository) FindTransferByTransactionID(ctx context.Context, transactionID string) (*models.Transfer, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockRepository) UpdateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction) (*models.Transfer, error) {
+	args := m.Called(ctx, transfer, legs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (m *MockRepository) DeleteTransfer(ctx context.Context, transferID string) error {
+	args := m.Called(ctx, transferID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetOperationTransactions(ctx context.Context, operationID string) ([]models.Transaction, error) {
+	args := m.Called(ctx, operationID)
+	var result []models.Transaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Transaction)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) ReplaceOperationTransactions(ctx context.Context, operationID string, transactions []models.Transaction) ([]models.Transaction, error) {
+	args := m.Called(ctx, operationID, transactions)
+	var result []models.Transaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Transaction)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) DeleteOperation(ctx context.Context, operationID string) error {
+	args := m.Called(ctx, operationID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetTransactionIncludingDeleted(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockRepository) ListTransactionsDeletedWith(ctx context.Context, tx models.Transaction) ([]models.Transaction, error) {
+	args := m.Called(ctx, tx)
+	var result []models.Transaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Transaction)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) RestoreTransactions(ctx context.Context, transactions []models.Transaction) error {
+	args := m.Called(ctx, transactions)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetCategoryIncludingDeleted(ctx context.Context, categoryID string) (*models.Category, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockRepository) GetCategoryGroupIncludingDeleted(ctx context.Context, categoryGroupID string) (*models.CategoryGroup, error) {
+	args := m.Called(ctx, categoryGroupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategoryGroup), args.Error(1)
+}
+
+func (m *MockRepository) GetMerchantIncludingDeleted(ctx context.Context, merchantID string) (*models.Merchant, error) {
+	args := m.Called(ctx, merchantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Merchant), args.Error(1)
+}
+
+func (m *MockRepository) RestoreBalance(ctx context.Context, balanceID string) error {
+	args := m.Called(ctx, balanceID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RestoreCategory(ctx context.Context, categoryID string) error {
+	args := m.Called(ctx, categoryID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RestoreCategoryGroup(ctx context.Context, categoryGroupID string) error {
+	args := m.Called(ctx, categoryGroupID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RestoreMerchant(ctx context.Context, merchantID string) error {
+	args := m.Called(ctx, merchantID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) PurgeDeletedData(ctx context.Context, cutoff time.Time, dryRun bool) ([]models.PurgedTableRowsDto, error) {
+	args := m.Called(ctx, cutoff, dryRun)
+	var result []models.PurgedTableRowsDto
+	if v := args.Get(0); v != nil {
+		result = v.([]models.PurgedTableRowsDto)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) GetUserData(ctx context.Context, userID string) (*models.UserData, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserData), args.Error(1)
+}
+
+func (m *MockRepository) EraseUserData(ctx context.Context, userID string) ([]models.PurgedTableRowsDto, error) {
+	args := m.Called(ctx, userID)
+	var result []models.PurgedTableRowsDto
+	if v := args.Get(0); v != nil {
+		result = v.([]models.PurgedTableRowsDto)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) ListAuditEvents(ctx context.Context, filter models.ListAuditEventsInput) ([]models.AuditEvent, error) {
+	args := m.Called(ctx, filter)
+	var result []models.AuditEvent
+	if v := args.Get(0); v != nil {
+		result = v.([]models.AuditEvent)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) CreateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	args := m.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRepository) GetCategorizationRule(ctx context.Context, ruleID string) (*models.CategorizationRule, error) {
+	args := m.Called(ctx, ruleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRepository) ListCategorizationRules(ctx context.Context, filter models.ListCategorizationRulesInput) ([]models.CategorizationRule, error) {
+	args := m.Called(ctx, filter)
+	var result []models.CategorizationRule
+	if v := args.Get(0); v != nil {
+		result = v.([]models.CategorizationRule)
+	}
+	return result, args.Error(1)
+}
+
+func (m *MockRepository) UpdateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	args := m.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRepository) DeleteCategorizationRule(ctx context.Context, ruleID string) error {
+	args := m.Called(ctx, ruleID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ApplyCategorizationChanges(ctx context.Context, changes []models.CategorizationChange) error {
+	args := m.Called(ctx, changes)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListSuggestionHistory(ctx context.Context, userID string, limit int) ([]models.SuggestionHistoryEntry, error) {
+	args := m.Called(ctx, userID, limit)
+	var result []models.SuggestionHistoryEntry
+	if v := args.Get(0); v != nil {
+		result = v.([]models.SuggestionHistoryEntry)
+	}
+	return result, args.Error(1)
+}
+
 // Helper methods for testing
 
 // ExpectCreateTransaction sets up an expectation for CreateTransaction method
@@ -289,6 +817,16 @@ func (m *MockRepository) ExpectDeleteTransaction(ctx context.Context, transactio
 	return m.On("DeleteTransaction", ctx, transactionID).Return(err)
 }
 
+// ExpectListTransactionEntriesAfter sets up an expectation for ListTransactionEntriesAfter method
+func (m *MockRepository) ExpectListTransactionEntriesAfter(ctx context.Context, afterEntryID string, limit int, result []models.TransactionEntry, err error) *mock.Call {
+	return m.On("ListTransactionEntriesAfter", ctx, afterEntryID, limit).Return(result, err)
+}
+
+// ExpectSaveTransactionEntryAmounts sets up an expectation for SaveTransactionEntryAmounts method
+func (m *MockRepository) ExpectSaveTransactionEntryAmounts(ctx context.Context, amounts []models.TransactionEntryAmount, err error) *mock.Call {
+	return m.On("SaveTransactionEntryAmounts", ctx, amounts).Return(err)
+}
+
 // ExpectCreateCategory sets up an expectation for CreateCategory method
 func (m *MockRepository) ExpectCreateCategory(ctx context.Context, category models.Category, result *models.Category, err error) *mock.Call {
 	return m.On("CreateCategory", ctx, category).Return(result, err)
@@ -334,6 +872,11 @@ func (m *MockRepository) ExpectDeleteBalance(ctx context.Context, balanceId stri
 	return m.On("DeleteBalance", ctx, balanceId).Return(err)
 }
 
+// ExpectGetBalanceAmounts sets up an expectation for GetBalanceAmounts method
+func (m *MockRepository) ExpectGetBalanceAmounts(ctx context.Context, balanceIDs []string, asOf *time.Time, result map[string]models.BalanceAmounts, err error) *mock.Call {
+	return m.On("GetBalanceAmounts", ctx, balanceIDs, asOf).Return(result, err)
+}
+
 // ExpectCreateMerchant sets up an expectation for CreateMerchant method
 func (m *MockRepository) ExpectCreateMerchant(ctx context.Context, merchant models.Merchant, result *models.Merchant, err error) *mock.Call {
 	return m.On("CreateMerchant", ctx, merchant).Return(result, err)
@@ -409,5 +952,345 @@ func (m *MockRepository) ExpectGetTransactionStats(ctx context.Context, input mo
 	return m.On("GetTransactionStats", ctx, input).Return(result, err)
 }
 
+// ExpectCreateGroup sets up an expectation for CreateGroup method
+func (m *MockRepository) ExpectCreateGroup(ctx context.Context, group models.Group, result *models.Group, err error) *mock.Call {
+	return m.On("CreateGroup", ctx, group).Return(result, err)
+}
+
+// ExpectFindGroup sets up an expectation for FindGroup method
+func (m *MockRepository) ExpectFindGroup(ctx context.Context, groupID string, result *models.Group, err error) *mock.Call {
+	return m.On("FindGroup", ctx, groupID).Return(result, err)
+}
+
+// ExpectListGroupsByMember sets up an expectation for ListGroupsByMember method
+func (m *MockRepository) ExpectListGroupsByMember(ctx context.Context, userID string, result []models.Group, err error) *mock.Call {
+	return m.On("ListGroupsByMember", ctx, userID).Return(result, err)
+}
+
+// ExpectUpdateGroup sets up an expectation for UpdateGroup method
+func (m *MockRepository) ExpectUpdateGroup(ctx context.Context, group models.Group, result *models.Group, err error) *mock.Call {
+	return m.On("UpdateGroup", ctx, group).Return(result, err)
+}
+
+// ExpectDeleteGroup sets up an expectation for DeleteGroup method
+func (m *MockRepository) ExpectDeleteGroup(ctx context.Context, groupID string, err error) *mock.Call {
+	return m.On("DeleteGroup", ctx, groupID).Return(err)
+}
+
+// ExpectSaveGroupMember sets up an expectation for SaveGroupMember method
+func (m *MockRepository) ExpectSaveGroupMember(ctx context.Context, member models.GroupMember, result *models.GroupMember, err error) *mock.Call {
+	return m.On("SaveGroupMember", ctx, member).Return(result, err)
+}
+
+// ExpectDeleteGroupMember sets up an expectation for DeleteGroupMember method
+func (m *MockRepository) ExpectDeleteGroupMember(ctx context.Context, groupID string, userID string, err error) *mock.Call {
+	return m.On("DeleteGroupMember", ctx, groupID, userID).Return(err)
+}
+
+// ExpectCreateGroupInvitation sets up an expectation for CreateGroupInvitation method
+func (m *MockRepository) ExpectCreateGroupInvitation(ctx context.Context, invitation models.GroupInvitation, result *models.GroupInvitation, err error) *mock.Call {
+	return m.On("CreateGroupInvitation", ctx, invitation).Return(result, err)
+}
+
+// ExpectGetGroupInvitation sets up an expectation for GetGroupInvitation method
+func (m *MockRepository) ExpectGetGroupInvitation(ctx context.Context, invitationID string, result *models.GroupInvitation, err error) *mock.Call {
+	return m.On("GetGroupInvitation", ctx, invitationID).Return(result, err)
+}
+
+// ExpectListGroupInvitations sets up an expectation for ListGroupInvitations method
+func (m *MockRepository) ExpectListGroupInvitations(ctx context.Context, filter models.ListGroupInvitationsInput, result []models.GroupInvitation, err error) *mock.Call {
+	return m.On("ListGroupInvitations", ctx, filter).Return(result, err)
+}
+
+// ExpectUpdateGroupInvitationStatus sets up an expectation for UpdateGroupInvitationStatus method
+func (m *MockRepository) ExpectUpdateGroupInvitationStatus(ctx context.Context, invitationID string, status string, err error) *mock.Call {
+	return m.On("UpdateGroupInvitationStatus", ctx, invitationID, status).Return(err)
+}
+
+// ExpectAcceptGroupInvitation sets up an expectation for AcceptGroupInvitation method
+func (m *MockRepository) ExpectAcceptGroupInvitation(ctx context.Context, invitationID string, member models.GroupMember, result *models.GroupMember, err error) *mock.Call {
+	return m.On("AcceptGroupInvitation", ctx, invitationID, member).Return(result, err)
+}
+
+// ExpectCreateBudget sets up an expectation for CreateBudget method
+func (m *MockRepository) ExpectCreateBudget(ctx context.Context, budget models.Budget, result *models.Budget, err error) *mock.Call {
+	return m.On("CreateBudget", ctx, budget).Return(result, err)
+}
+
+// ExpectGetBudget sets up an expectation for GetBudget method
+func (m *MockRepository) ExpectGetBudget(ctx context.Context, budgetID string, result *models.Budget, err error) *mock.Call {
+	return m.On("GetBudget", ctx, budgetID).Return(result, err)
+}
+
+// ExpectListBudgets sets up an expectation for ListBudgets method
+func (m *MockRepository) ExpectListBudgets(ctx context.Context, filter models.ListBudgetsInput, result []models.Budget, err error) *mock.Call {
+	return m.On("ListBudgets", ctx, filter).Return(result, err)
+}
+
+// ExpectUpdateBudget sets up an expectation for UpdateBudget method
+func (m *MockRepository) ExpectUpdateBudget(ctx context.Context, budget models.Budget, result *models.Budget, err error) *mock.Call {
+	return m.On("UpdateBudget", ctx, budget).Return(result, err)
+}
+
+// ExpectDeleteBudget sets up an expectation for DeleteBudget method
+func (m *MockRepository) ExpectDeleteBudget(ctx context.Context, budgetID string, err error) *mock.Call {
+	return m.On("DeleteBudget", ctx, budgetID).Return(err)
+}
+
+// ExpectCreateRecurringTransaction sets up an expectation for CreateRecurringTransaction method
+func (m *MockRepository) ExpectCreateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction, result *models.RecurringTransaction, err error) *mock.Call {
+	return m.On("CreateRecurringTransaction", ctx, recurring).Return(result, err)
+}
+
+// ExpectGetRecurringTransaction sets up an expectation for GetRecurringTransaction method
+func (m *MockRepository) ExpectGetRecurringTransaction(ctx context.Context, recurringTransactionID string, result *models.RecurringTransaction, err error) *mock.Call {
+	return m.On("GetRecurringTransaction", ctx, recurringTransactionID).Return(result, err)
+}
+
+// ExpectListRecurringTransactions sets up an expectation for ListRecurringTransactions method
+func (m *MockRepository) ExpectListRecurringTransactions(ctx context.Context, filter models.ListRecurringTransactionsInput, result []models.RecurringTransaction, err error) *mock.Call {
+	return m.On("ListRecurringTransactions", ctx, filter).Return(result, err)
+}
+
+// ExpectUpdateRecurringTransaction sets up an expectation for UpdateRecurringTransaction method
+func (m *MockRepository) ExpectUpdateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction, result *models.RecurringTransaction, err error) *mock.Call {
+	return m.On("UpdateRecurringTransaction", ctx, recurring).Return(result, err)
+}
+
+// ExpectDeleteRecurringTransaction sets up an expectation for DeleteRecurringTransaction method
+func (m *MockRepository) ExpectDeleteRecurringTransaction(ctx context.Context, recurringTransactionID string, err error) *mock.Call {
+	return m.On("DeleteRecurringTransaction", ctx, recurringTransactionID).Return(err)
+}
+
+// ExpectListDueRecurringTransactions sets up an expectation for ListDueRecurringTransactions method
+func (m *MockRepository) ExpectListDueRecurringTransactions(ctx context.Context, now time.Time, recurringTransactionID string, result []models.RecurringTransaction, err error) *mock.Call {
+	return m.On("ListDueRecurringTransactions", ctx, now, recurringTransactionID).Return(result, err)
+}
+
+// ExpectGetRecurringOccurrenceTransactionID sets up an expectation for GetRecurringOccurrenceTransactionID method
+func (m *MockRepository) ExpectGetRecurringOccurrenceTransactionID(ctx context.Context, recurringTransactionID string, occurrence time.Time, result string, err error) *mock.Call {
+	return m.On("GetRecurringOccurrenceTransactionID", ctx, recurringTransactionID, occurrence).Return(result, err)
+}
+
+// ExpectUpdateRecurringTransactionProgress sets up an expectation for UpdateRecurringTransactionProgress method
+func (m *MockRepository) ExpectUpdateRecurringTransactionProgress(ctx context.Context, recurring models.RecurringTransaction, err error) *mock.Call {
+	return m.On("UpdateRecurringTransactionProgress", ctx, recurring).Return(err)
+}
+
+// ExpectCreateImportProfile sets up an expectation for CreateImportProfile method
+func (m *MockRepository) ExpectCreateImportProfile(ctx context.Context, profile models.ImportProfile, result *models.ImportProfile, err error) *mock.Call {
+	return m.On("CreateImportProfile", ctx, profile).Return(result, err)
+}
+
+// ExpectGetImportProfile sets up an expectation for GetImportProfile method
+func (m *MockRepository) ExpectGetImportProfile(ctx context.Context, importProfileID string, result *models.ImportProfile, err error) *mock.Call {
+	return m.On("GetImportProfile", ctx, importProfileID).Return(result, err)
+}
+
+// ExpectListImportProfiles sets up an expectation for ListImportProfiles method
+func (m *MockRepository) ExpectListImportProfiles(ctx context.Context, filter models.ListImportProfilesInput, result []models.ImportProfile, err error) *mock.Call {
+	return m.On("ListImportProfiles", ctx, filter).Return(result, err)
+}
+
+// ExpectUpdateImportProfile sets up an expectation for UpdateImportProfile method
+func (m *MockRepository) ExpectUpdateImportProfile(ctx context.Context, profile models.ImportProfile, result *models.ImportProfile, err error) *mock.Call {
+	return m.On("UpdateImportProfile", ctx, profile).Return(result, err)
+}
+
+// ExpectDeleteImportProfile sets up an expectation for DeleteImportProfile method
+func (m *MockRepository) ExpectDeleteImportProfile(ctx context.Context, importProfileID string, err error) *mock.Call {
+	return m.On("DeleteImportProfile", ctx, importProfileID).Return(err)
+}
+
+// ExpectListTransactionExternalIDs sets up an expectation for ListTransactionExternalIDs method
+func (m *MockRepository) ExpectListTransactionExternalIDs(ctx context.Context, balanceID string, externalIDs []string, result []string, err error) *mock.Call {
+	return m.On("ListTransactionExternalIDs", ctx, balanceID, externalIDs).Return(result, err)
+}
+
+// ExpectListDuplicateCandidates sets up an expectation for ListDuplicateCandidates method
+func (m *MockRepository) ExpectListDuplicateCandidates(ctx context.Context, filter models.ListDuplicateTransactionsInput, result []models.DuplicateCandidate, err error) *mock.Call {
+	return m.On("ListDuplicateCandidates", ctx, filter).Return(result, err)
+}
+
+// ExpectFindPossibleDuplicate sets up an expectation for FindPossibleDuplicate method
+func (m *MockRepository) ExpectFindPossibleDuplicate(ctx context.Context, tx models.Transaction, window time.Duration, result string, err error) *mock.Call {
+	return m.On("FindPossibleDuplicate", ctx, tx, window).Return(result, err)
+}
+
+// ExpectClaimIdempotencyKey sets up an expectation for ClaimIdempotencyKey method
+func (m *MockRepository) ExpectClaimIdempotencyKey(ctx context.Context, record models.IdempotencyKey, result bool, err error) *mock.Call {
+	return m.On("ClaimIdempotencyKey", ctx, record).Return(result, err)
+}
+
+// ExpectFindIdempotencyKey sets up an expectation for FindIdempotencyKey method
+func (m *MockRepository) ExpectFindIdempotencyKey(ctx context.Context, userID string, key string, result *models.IdempotencyKey, err error) *mock.Call {
+	return m.On("FindIdempotencyKey", ctx, userID, key).Return(result, err)
+}
+
+// ExpectCompleteIdempotencyKey sets up an expectation for CompleteIdempotencyKey method
+func (m *MockRepository) ExpectCompleteIdempotencyKey(ctx context.Context, record models.IdempotencyKey, err error) *mock.Call {
+	return m.On("CompleteIdempotencyKey", ctx, record).Return(err)
+}
+
+// ExpectTakeOverIdempotencyKey sets up an expectation for TakeOverIdempotencyKey method
+func (m *MockRepository) ExpectTakeOverIdempotencyKey(ctx context.Context, record models.IdempotencyKey, now time.Time, staleBefore time.Time, result bool, err error) *mock.Call {
+	return m.On("TakeOverIdempotencyKey", ctx, record, now, staleBefore).Return(result, err)
+}
+
+// ExpectRenewIdempotencyKey sets up an expectation for RenewIdempotencyKey method
+func (m *MockRepository) ExpectRenewIdempotencyKey(ctx context.Context, userID string, key string, err error) *mock.Call {
+	return m.On("RenewIdempotencyKey", ctx, userID, key).Return(err)
+}
+
+// ExpectDeleteIdempotencyKey sets up an expectation for DeleteIdempotencyKey method
+func (m *MockRepository) ExpectDeleteIdempotencyKey(ctx context.Context, userID string, key string, requestHash string, err error) *mock.Call {
+	return m.On("DeleteIdempotencyKey", ctx, userID, key, requestHash).Return(err)
+}
+
+// ExpectCreateTransfer sets up an expectation for CreateTransfer method
+func (m *MockRepository) ExpectCreateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction, result *models.Transfer, err error) *mock.Call {
+	return m.On("CreateTransfer", ctx, transfer, legs).Return(result, err)
+}
+
+// ExpectGetTransfer sets up an expectation for GetTransfer method
+func (m *MockRepository) ExpectGetTransfer(ctx context.Context, transferID string, result *models.Transfer, err error) *mock.Call {
+	return m.On("GetTransfer", ctx, transferID).Return(result, err)
+}
+
+// ExpectFindTransferByTransactionID sets up an expectation for FindTransferByTransactionID method
+func (m *MockRepository) ExpectFindTransferByTransactionID(ctx context.Context, transactionID string, result *models.Transfer, err error) *mock.Call {
+	return m.On("FindTransferByTransactionID", ctx, transactionID).Return(result, err)
+}
+
+// ExpectUpdateTransfer sets up an expectation for UpdateTransfer method
+func (m *MockRepository) ExpectUpdateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction, result *models.Transfer, err error) *mock.Call {
+	return m.On("UpdateTransfer", ctx, transfer, legs).Return(result, err)
+}
+
+// ExpectDeleteTransfer sets up an expectation for DeleteTransfer method
+func (m *MockRepository) ExpectDeleteTransfer(ctx context.Context, transferID string, err error) *mock.Call {
+	return m.On("DeleteTransfer", ctx, transferID).Return(err)
+}
+
+// ExpectGetOperationTransactions sets up an expectation for GetOperationTransactions method
+func (m *MockRepository) ExpectGetOperationTransactions(ctx context.Context, operationID string, result []models.Transaction, err error) *mock.Call {
+	return m.On("GetOperationTransactions", ctx, operationID).Return(result, err)
+}
+
+// ExpectReplaceOperationTransactions sets up an expectation for ReplaceOperationTransactions method
+func (m *MockRepository) ExpectReplaceOperationTransactions(ctx context.Context, operationID string, transactions []models.Transaction, result []models.Transaction, err error) *mock.Call {
+	return m.On("ReplaceOperationTransactions", ctx, operationID, transactions).Return(result, err)
+}
+
+// ExpectDeleteOperation sets up an expectation for DeleteOperation method
+func (m *MockRepository) ExpectDeleteOperation(ctx context.Context, operationID string, err error) *mock.Call {
+	return m.On("DeleteOperation", ctx, operationID).Return(err)
+}
+
+// ExpectGetTransactionIncludingDeleted sets up an expectation for GetTransactionIncludingDeleted method
+func (m *MockRepository) ExpectGetTransactionIncludingDeleted(ctx context.Context, transactionID string, result *models.Transaction, err error) *mock.Call {
+	return m.On("GetTransactionIncludingDeleted", ctx, transactionID).Return(result, err)
+}
+
+// ExpectListTransactionsDeletedWith sets up an expectation for ListTransactionsDeletedWith method
+func (m *MockRepository) ExpectListTransactionsDeletedWith(ctx context.Context, tx models.Transaction, result []models.Transaction, err error) *mock.Call {
+	return m.On("ListTransactionsDeletedWith", ctx, tx).Return(result, err)
+}
+
+// ExpectRestoreTransactions sets up an expectation for RestoreTransactions method
+func (m *MockRepository) ExpectRestoreTransactions(ctx context.Context, transactions []models.Transaction, err error) *mock.Call {
+	return m.On("RestoreTransactions", ctx, transactions).Return(err)
+}
+
+// ExpectGetCategoryIncludingDeleted sets up an expectation for GetCategoryIncludingDeleted method
+func (m *MockRepository) ExpectGetCategoryIncludingDeleted(ctx context.Context, categoryID string, result *models.Category, err error) *mock.Call {
+	return m.On("GetCategoryIncludingDeleted", ctx, categoryID).Return(result, err)
+}
+
+// ExpectGetCategoryGroupIncludingDeleted sets up an expectation for GetCategoryGroupIncludingDeleted method
+func (m *MockRepository) ExpectGetCategoryGroupIncludingDeleted(ctx context.Context, categoryGroupID string, result *models.CategoryGroup, err error) *mock.Call {
+	return m.On("GetCategoryGroupIncludingDeleted", ctx, categoryGroupID).Return(result, err)
+}
+
+// ExpectGetMerchantIncludingDeleted sets up an expectation for GetMerchantIncludingDeleted method
+func (m *MockRepository) ExpectGetMerchantIncludingDeleted(ctx context.Context, merchantID string, result *models.Merchant, err error) *mock.Call {
+	return m.On("GetMerchantIncludingDeleted", ctx, merchantID).Return(result, err)
+}
+
+// ExpectRestoreBalance sets up an expectation for RestoreBalance method
+func (m *MockRepository) ExpectRestoreBalance(ctx context.Context, balanceID string, err error) *mock.Call {
+	return m.On("RestoreBalance", ctx, balanceID).Return(err)
+}
+
+// ExpectRestoreCategory sets up an expectation for RestoreCategory method
+func (m *MockRepository) ExpectRestoreCategory(ctx context.Context, categoryID string, err error) *mock.Call {
+	return m.On("RestoreCategory", ctx, categoryID).Return(err)
+}
+
+// ExpectRestoreCategoryGroup sets up an expectation for RestoreCategoryGroup method
+func (m *MockRepository) ExpectRestoreCategoryGroup(ctx context.Context, categoryGroupID string, err error) *mock.Call {
+	return m.On("RestoreCategoryGroup", ctx, categoryGroupID).Return(err)
+}
+
+// ExpectRestoreMerchant sets up an expectation for RestoreMerchant method
+func (m *MockRepository) ExpectRestoreMerchant(ctx context.Context, merchantID string, err error) *mock.Call {
+	return m.On("RestoreMerchant", ctx, merchantID).Return(err)
+}
+
+// ExpectPurgeDeletedData sets up an expectation for PurgeDeletedData method
+func (m *MockRepository) ExpectPurgeDeletedData(ctx context.Context, cutoff time.Time, dryRun bool, result []models.PurgedTableRowsDto, err error) *mock.Call {
+	return m.On("PurgeDeletedData", ctx, cutoff, dryRun).Return(result, err)
+}
+
+// ExpectGetUserData sets up an expectation for GetUserData method
+func (m *MockRepository) ExpectGetUserData(ctx context.Context, userID string, result *models.UserData, err error) *mock.Call {
+	return m.On("GetUserData", ctx, userID).Return(result, err)
+}
+
+// ExpectEraseUserData sets up an expectation for EraseUserData method
+func (m *MockRepository) ExpectEraseUserData(ctx context.Context, userID string, result []models.PurgedTableRowsDto, err error) *mock.Call {
+	return m.On("EraseUserData", ctx, userID).Return(result, err)
+}
+
+// ExpectListAuditEvents sets up an expectation for ListAuditEvents method
+func (m *MockRepository) ExpectListAuditEvents(ctx context.Context, filter models.ListAuditEventsInput, result []models.AuditEvent, err error) *mock.Call {
+	return m.On("ListAuditEvents", ctx, filter).Return(result, err)
+}
+
+// ExpectCreateCategorizationRule sets up an expectation for CreateCategorizationRule method
+func (m *MockRepository) ExpectCreateCategorizationRule(ctx context.Context, rule models.CategorizationRule, result *models.CategorizationRule, err error) *mock.Call {
+	return m.On("CreateCategorizationRule", ctx, rule).Return(result, err)
+}
+
+// ExpectGetCategorizationRule sets up an expectation for GetCategorizationRule method
+func (m *MockRepository) ExpectGetCategorizationRule(ctx context.Context, ruleID string, result *models.CategorizationRule, err error) *mock.Call {
+	return m.On("GetCategorizationRule", ctx, ruleID).Return(result, err)
+}
+
+// ExpectListCategorizationRules sets up an expectation for ListCategorizationRules method
+func (m *MockRepository) ExpectListCategorizationRules(ctx context.Context, filter models.ListCategorizationRulesInput, result []models.CategorizationRule, err error) *mock.Call {
+	return m.On("ListCategorizationRules", ctx, filter).Return(result, err)
+}
+
+// ExpectUpdateCategorizationRule sets up an expectation for UpdateCategorizationRule method
+func (m *MockRepository) ExpectUpdateCategorizationRule(ctx context.Context, rule models.CategorizationRule, result *models.CategorizationRule, err error) *mock.Call {
+	return m.On("UpdateCategorizationRule", ctx, rule).Return(result, err)
+}
+
+// ExpectDeleteCategorizationRule sets up an expectation for DeleteCategorizationRule method
+func (m *MockRepository) ExpectDeleteCategorizationRule(ctx context.Context, ruleID string, err error) *mock.Call {
+	return m.On("DeleteCategorizationRule", ctx, ruleID).Return(err)
+}
+
+// ExpectApplyCategorizationChanges sets up an expectation for ApplyCategorizationChanges method
+func (m *MockRepository) ExpectApplyCategorizationChanges(ctx context.Context, changes []models.CategorizationChange, err error) *mock.Call {
+	return m.On("ApplyCategorizationChanges", ctx, changes).Return(err)
+}
+
+// ExpectListSuggestionHistory sets up an expectation for ListSuggestionHistory method
+func (m *MockRepository) ExpectListSuggestionHistory(ctx context.Context, userID string, limit int, result []models.SuggestionHistoryEntry, err error) *mock.Call {
+	return m.On("ListSuggestionHistory", ctx, userID, limit).Return(result, err)
+}
+
 // Ensure MockRepository implements Repository interface
 var _ Repository = (*MockRepository)(nil)
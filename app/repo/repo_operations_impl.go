@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetOperationTransactions retrieves all transactions sharing the operation ID, oldest first
+func (r *PostgreSQLRepository) GetOperationTransactions(ctx context.Context, operationID string) ([]models.Transaction, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []models.Transaction
+	if err := db.WithContext(ctx).
+		Preload("Balance").
+		Preload("Merchant", "deleted_at IS NULL").
+		Preload("TransactionEntries", "deleted_at IS NULL").
+		Preload("TransactionEntries.TransactionEntryAmounts").
+		Preload("TransactionEntries.Category").
+		Preload("TransactionEntries.Category.CategoryGroup").
+		Where("operation_id = ? AND deleted_at IS NULL", operationID).
+		Order("created_at ASC, id ASC").
+		Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get operation transactions: %w", err)
+	}
+	if len(transactions) == 0 {
+		return nil, &models.NotFoundError{Entity: "operation", ID: operationID}
+	}
+
+	return transactions, nil
+}
+
+// ReplaceOperationTransactions soft deletes the active transactions of an operation and creates the given ones in
+// their place in a single database transaction. Transactions of the operation that were already deleted are left
+// alone. The replacements are created at the deletion time of the replaced transactions, which is how restores
+// recognise replaced transactions.
+func (r *PostgreSQLRepository) ReplaceOperationTransactions(ctx context.Context, operationID string, transactions []models.Transaction) ([]models.Transaction, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var replaced []models.Transaction
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deletedAt, err := deleteOperationTransactions(tx, operationID)
+		if err != nil {
+			return err
+		}
+		for i := range transactions {
+			transactions[i].CreatedAt = deletedAt
+		}
+		replaced, err = createTransactions(tx, transactions)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
+// DeleteOperation deletes every transaction of an operation in a single database transaction
+func (r *PostgreSQLRepository) DeleteOperation(ctx context.Context, operationID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := deleteOperationTransactions(tx, operationID)
+		return err
+	})
+}
+
+// lockOperationTransactions locks the active transactions of an operation and returns their IDs, so concurrent edits
+// of the same operation are applied one after another
+func lockOperationTransactions(tx *gorm.DB, operationID string) ([]string, error) {
+	var transactionIDs []string
+	if err := tx.Model(&models.Transaction{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("operation_id = ? AND deleted_at IS NULL", operationID).
+		Pluck("id", &transactionIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock operation transactions: %w", err)
+	}
+	if len(transactionIDs) == 0 {
+		return nil, &models.NotFoundError{Entity: "operation", ID: operationID}
+	}
+	return transactionIDs, nil
+}
+
+// deleteOperationTransactions locks and soft deletes the active transactions of an operation and returns their
+// deletion time
+func deleteOperationTransactions(tx *gorm.DB, operationID string) (time.Time, error) {
+	transactionIDs, err := lockOperationTransactions(tx, operationID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// All transactions of the operation share deletedAt so they are restored together
+	deletedAt := time.Now().UTC()
+	for _, transactionID := range transactionIDs {
+		if err := deleteTransaction(tx, transactionID, deletedAt); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return deletedAt, nil
+}
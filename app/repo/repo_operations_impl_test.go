@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+func TestDeleteOperationTransactionsOnlyTouchesActiveRows(t *testing.T) {
+	db := newDryRunDB(t).Session(&gorm.Session{SkipDefaultTransaction: true, Context: context.Background()})
+	var statements, deletes []string
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("test:capture", func(tx *gorm.DB) {
+		deletes = append(deletes, tx.Statement.SQL.String())
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	// Nothing is found in dry run, the lock is the only statement issued
+	operationID := "fa001111-1111-1111-1111-111111111111"
+	_, err := deleteOperationTransactions(db, operationID)
+	var notFound *models.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Expected NotFoundError, got %v", err)
+	}
+
+	// Transactions of the operation that were already deleted are neither locked nor deleted again
+	if len(statements) != 1 {
+		t.Fatalf("Expected 1 statement, got %v", statements)
+	}
+	lock := statements[0]
+	if !strings.Contains(lock, "operation_id = $1 AND deleted_at IS NULL") || !strings.HasSuffix(lock, "FOR UPDATE") {
+		t.Errorf("Expected the active transactions of the operation to be locked, got %s", lock)
+	}
+	if len(deletes) != 0 {
+		t.Errorf("Expected no rows to be hard deleted, got %v", deletes)
+	}
+}
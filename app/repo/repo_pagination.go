@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// sortValueKind defines how the sort key value stored in a pagination cursor is typed in the database
+type sortValueKind int
+
+const (
+	sortValueTime sortValueKind = iota
+	sortValueInt
+	sortValueString
+	sortValueFloat
+)
+
+// applyKeysetPagination orders the query by the sort column with the ID column as a tie-breaker
+// and, when a cursor is given, continues strictly after the cursor position.
+// Ordering by (sort column, id) makes paging deterministic even for duplicate sort values.
+func applyKeysetPagination(query *gorm.DB, cursor *models.PageCursor, sortColumn string, kind sortValueKind, idColumn string, direction string) (*gorm.DB, error) {
+	if cursor != nil {
+		var value interface{}
+		switch kind {
+		case sortValueTime:
+			t, err := cursor.TimeValue()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", models.ErrInvalidCursor, err)
+			}
+			value = t
+		case sortValueInt:
+			n, err := cursor.IntValue()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", models.ErrInvalidCursor, err)
+			}
+			value = n
+		case sortValueFloat:
+			f, err := cursor.FloatValue()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", models.ErrInvalidCursor, err)
+			}
+			value = f
+		default:
+			value = cursor.Value
+		}
+
+		operator := ">"
+		if direction == "DESC" {
+			operator = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", sortColumn, idColumn, operator), value, cursor.ID)
+	}
+
+	return query.Order(fmt.Sprintf("%s %s, %s %s", sortColumn, direction, idColumn, direction)), nil
+}
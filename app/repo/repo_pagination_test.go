@@ -0,0 +1,161 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// captureQueries records the SQL of the queries run on the dry run database
+func captureQueries(t *testing.T, db *gorm.DB) *[]string {
+	t.Helper()
+	var statements []string
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	return &statements
+}
+
+func TestRankPagingTreatsMissingRankAsZero(t *testing.T) {
+	// A balance or merchant without rank is encoded with rank 0 in the cursor, the next page has to compare the same
+	// value or rows with a NULL rank are skipped or repeated
+	cursor := models.NewPageCursor("rank", "asc", 0, uuid.New())
+	wantWhere := `(COALESCE(rank, 0), id) > ($`
+	wantOrder := `ORDER BY COALESCE(rank, 0) ASC, id ASC`
+
+	tests := []struct {
+		name string
+		list func(r *PostgreSQLRepository) error
+	}{
+		{"balances", func(r *PostgreSQLRepository) error {
+			_, err := r.ListBalances(context.Background(), models.ListBalancesInput{SortBy: "rank", Order: "asc", Cursor: &cursor})
+			return err
+		}},
+		{"merchants", func(r *PostgreSQLRepository) error {
+			_, err := r.ListMerchants(context.Background(), models.ListMerchantsInput{SortBy: "rank", Order: "asc", Cursor: &cursor})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+			statements := captureQueries(t, db)
+
+			if err := tt.list(NewPostgreSQLRepository(db)); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(*statements) != 1 {
+				t.Fatalf("Expected 1 query, got %d", len(*statements))
+			}
+			statement := (*statements)[0]
+			if !strings.Contains(statement, wantWhere) || !strings.Contains(statement, wantOrder) {
+				t.Errorf("Expected keyset predicate %q and order %q, got\n%s", wantWhere, wantOrder, statement)
+			}
+		})
+	}
+}
+
+func TestSearchRankCursorContinuesAfterTiedRanks(t *testing.T) {
+	secret := []byte("test-secret")
+	rank := 0.0607927106320858 // Typical ts_rank value, not exactly representable in decimal
+	entry := &models.TransactionEntry{ID: uuid.New(), SearchMatch: &models.SearchMatch{Rank: rank}}
+	token, err := models.EncodePageCursor(models.TransactionEntryCursor(entry, models.SortByRelevance, "DESC"), secret)
+	if err != nil {
+		t.Fatalf("Expected cursor to be encoded, got error: %v", err)
+	}
+	cursor, err := models.DecodePageCursor(token, secret)
+	if err != nil {
+		t.Fatalf("Expected cursor to be decoded, got error: %v", err)
+	}
+
+	db := newDryRunDB(t)
+	var statement string
+	var vars []interface{}
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statement, vars = tx.Statement.SQL.String(), tx.Statement.Vars
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	query, err := applyKeysetPagination(db.Table("search_result"), cursor, "search_result.rank", sortValueFloat, "search_result.id", "DESC")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := query.Find(&[]map[string]interface{}{}).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Rows tied on rank continue with lower IDs and the cursor row itself is not repeated, which holds only when
+	// the rank is compared as the same float8 value the page was read with
+	wantWhere := "(search_result.rank, search_result.id) < ($1, $2)"
+	wantOrder := "ORDER BY search_result.rank DESC, search_result.id DESC"
+	if !strings.Contains(statement, wantWhere) || !strings.Contains(statement, wantOrder) {
+		t.Errorf("Expected keyset predicate %q and order %q, got\n%s", wantWhere, wantOrder, statement)
+	}
+	if len(vars) != 2 || vars[0] != rank || vars[1] != entry.ID.String() {
+		t.Errorf("Expected the exact rank %v and ID %s as arguments, got %#v", rank, entry.ID, vars)
+	}
+}
+
+func TestUpdatedAtPagingUsesTheCursorOfTheSameKey(t *testing.T) {
+	// The cursor of the last item holds its update time for the API sort key updatedAt, the next page has to
+	// continue after that time in the same direction
+	updatedAt := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	merchant := &models.Merchant{ID: uuid.New(), CreatedAt: updatedAt.Add(-time.Hour), UpdatedAt: updatedAt}
+	category := &models.Category{ID: uuid.New(), CreatedAt: updatedAt.Add(-time.Hour), UpdatedAt: updatedAt}
+	merchantCursor := models.MerchantCursor(merchant, "updatedAt", "desc")
+	categoryCursor := models.CategoryCursor(category, "updatedAt", "desc")
+
+	tests := []struct {
+		name string
+		list func(r *PostgreSQLRepository) error
+	}{
+		{"merchants", func(r *PostgreSQLRepository) error {
+			_, err := r.ListMerchants(context.Background(), models.ListMerchantsInput{SortBy: "updatedAt", Order: "desc", Cursor: &merchantCursor})
+			return err
+		}},
+		{"categories", func(r *PostgreSQLRepository) error {
+			_, err := r.ListCategories(context.Background(), models.ListCategoriesInput{SortBy: "updatedAt", Order: "desc", Cursor: &categoryCursor})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+			var statement string
+			var vars []interface{}
+			if err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+				if statement == "" {
+					statement, vars = tx.Statement.SQL.String(), tx.Statement.Vars
+				}
+			}); err != nil {
+				t.Fatalf("Failed to register callback: %v", err)
+			}
+
+			if err := tt.list(NewPostgreSQLRepository(db)); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !strings.Contains(statement, "(updated_at, id) < ($") || !strings.Contains(statement, "ORDER BY updated_at DESC, id DESC") {
+				t.Errorf("Expected descending updated_at paging, got\n%s", statement)
+			}
+			found := false
+			for _, v := range vars {
+				if value, ok := v.(time.Time); ok && value.Equal(updatedAt) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected the update time %s as keyset argument, got %#v", updatedAt, vars)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// Rows of the purge, soft-deleted before the cutoff. Transactions of a purged balance are purged with it even if
+// they weren't deleted themselves, entries and amounts go with their transaction.
+const (
+	purgeBalancesSQL     = `SELECT id FROM balance WHERE deleted_at < @cutoff`
+	purgeTransactionsSQL = `SELECT id FROM transaction WHERE deleted_at < @cutoff OR balance_id IN (` + purgeBalancesSQL + `)`
+	purgeEntriesSQL      = `SELECT id FROM transaction_entry WHERE deleted_at < @cutoff OR transaction_id IN (` + purgeTransactionsSQL + `)`
+)
+
+// purgeSteps permanently delete soft-deleted rows in dependency order, rows referencing a table are removed before
+// the rows of the table. Categories still used by entries and category groups still used by categories are kept.
+// Expired idempotency keys are removed as well.
+var purgeSteps = []purgeStep{
+	{"transaction_entry_amount", `DELETE FROM transaction_entry_amount WHERE transaction_entry_id IN (` + purgeEntriesSQL + `)`},
+	{"transaction_entry", `DELETE FROM transaction_entry WHERE id IN (` + purgeEntriesSQL + `)`},
+	{"transfer", `DELETE FROM transfer WHERE move_out_transaction_id IN (` + purgeTransactionsSQL + `)
+		OR move_in_transaction_id IN (` + purgeTransactionsSQL + `)`},
+	{"transaction", `DELETE FROM transaction WHERE id IN (` + purgeTransactionsSQL + `)`},
+	{"balance", `DELETE FROM balance WHERE deleted_at < @cutoff`},
+	{"merchant", `DELETE FROM merchant WHERE deleted_at < @cutoff`},
+	{"category", `DELETE FROM category WHERE deleted_at < @cutoff
+		AND NOT EXISTS (SELECT 1 FROM transaction_entry WHERE transaction_entry.category_id = category.id)`},
+	{"category_group", `DELETE FROM category_group WHERE deleted_at < @cutoff
+		AND NOT EXISTS (SELECT 1 FROM category WHERE category.category_group_id = category_group.id)`},
+	{"idempotency_key", `DELETE FROM idempotency_key WHERE expires_at < now()`}, // Can't be replayed anymore
+}
+
+// purgeStep permanently deletes rows of one table
+type purgeStep struct {
+	Table string
+	SQL   string // DELETE statement with named arguments
+}
+
+// runPurgeSteps executes the steps in order and returns the number of rows removed per table
+func runPurgeSteps(tx *gorm.DB, steps []purgeStep, args map[string]interface{}) ([]models.PurgedTableRowsDto, error) {
+	removed := make([]models.PurgedTableRowsDto, 0, len(steps))
+	for _, step := range steps {
+		result := tx.Exec(step.SQL, args)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to delete from %s: %w", step.Table, result.Error)
+		}
+		removed = append(removed, models.PurgedTableRowsDto{Table: step.Table, Rows: result.RowsAffected})
+	}
+	return removed, nil
+}
+
+// purgeAuditedTables delete the rows of the audited entity types of the purge and return them, so a purge audit event
+// is recorded per row. Entries and amounts are part of the audit snapshots of their transaction.
+var purgeAuditedTables = map[string]func(tx *gorm.DB, step purgeStep, args map[string]interface{}) ([]interface{}, error){
+	"transfer":       purgeReturning[models.Transfer],
+	"transaction":    purgeReturning[models.Transaction],
+	"balance":        purgeReturning[models.Balance],
+	"merchant":       purgeReturning[models.Merchant],
+	"category":       purgeReturning[models.Category],
+	"category_group": purgeReturning[models.CategoryGroup],
+}
+
+// purgeReturning executes the step and returns the deleted rows
+func purgeReturning[T any](tx *gorm.DB, step purgeStep, args map[string]interface{}) ([]interface{}, error) {
+	var rows []T
+	if err := tx.Raw(step.SQL+" RETURNING *", args).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete from %s: %w", step.Table, err)
+	}
+	entities := make([]interface{}, len(rows))
+	for i := range rows {
+		entities[i] = &rows[i]
+	}
+	return entities, nil
+}
+
+// runAuditedPurgeSteps executes the steps in order like runPurgeSteps and records a purge audit event for each
+// deleted row of an audited entity type, see recordPurgeAudits
+func runAuditedPurgeSteps(tx *gorm.DB, steps []purgeStep, args map[string]interface{}) ([]models.PurgedTableRowsDto, error) {
+	purged := make([]models.PurgedTableRowsDto, 0, len(steps))
+	for _, step := range steps {
+		purge, audited := purgeAuditedTables[step.Table]
+		if !audited {
+			removed, err := runPurgeSteps(tx, []purgeStep{step}, args)
+			if err != nil {
+				return nil, err
+			}
+			purged = append(purged, removed...)
+			continue
+		}
+
+		entities, err := purge(tx, step, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordPurgeAudits(tx, entities); err != nil {
+			return nil, err
+		}
+		purged = append(purged, models.PurgedTableRowsDto{Table: step.Table, Rows: int64(len(entities))})
+	}
+	return purged, nil
+}
+
+// errPurgeDryRun rolls back the purge transaction of a dry run after the rows have been counted
+var errPurgeDryRun = errors.New("purge dry run")
+
+// PurgeDeletedData permanently deletes the rows soft-deleted before the cutoff in a single database transaction,
+// records a purge audit event per deleted entity and returns the number of rows removed per table. A dry run deletes the same rows and rolls back, so the counts are exact.
+func (r *PostgreSQLRepository) PurgeDeletedData(ctx context.Context, cutoff time.Time, dryRun bool) ([]models.PurgedTableRowsDto, error) {
+	var purged []models.PurgedTableRowsDto
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if purged, err = runAuditedPurgeSteps(tx, purgeSteps, map[string]interface{}{"cutoff": cutoff}); err != nil {
+			return err
+		}
+		if dryRun {
+			return errPurgeDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPurgeDryRun) {
+		return nil, fmt.Errorf("failed to purge deleted data: %w", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+func TestPurgeStepsDeleteInDependencyOrder(t *testing.T) {
+	db := newDryRunDB(t)
+	var statements []string
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	order := map[string]int{}
+	for i, step := range purgeSteps {
+		if err := db.Exec(step.SQL, map[string]interface{}{"cutoff": time.Now()}).Error; err != nil {
+			t.Fatalf("Expected step %s to run, got %v", step.Table, err)
+		}
+		order[step.Table] = i
+	}
+
+	// Rows referencing a table are removed before the rows of the table
+	references := map[string][]string{
+		"transaction_entry_amount": {"transaction_entry"},
+		"transaction_entry":        {"transaction", "category"},
+		"transfer":                 {"transaction"},
+		"transaction":              {"balance", "merchant"},
+		"category":                 {"category_group"},
+	}
+	for table, referenced := range references {
+		for _, parent := range referenced {
+			if order[table] > order[parent] {
+				t.Errorf("Expected %s to be purged before %s", table, parent)
+			}
+		}
+	}
+
+	if len(statements) != len(purgeSteps) {
+		t.Fatalf("Expected %d statements, got %d", len(purgeSteps), len(statements))
+	}
+	for i, statement := range statements {
+		if strings.Contains(statement, "@cutoff") {
+			t.Errorf("Expected the cutoff of step %s to be bound, got %s", purgeSteps[i].Table, statement)
+		}
+	}
+	// Transactions of purged balances go with them, categories and category groups still in use are kept
+	if !strings.Contains(purgeSteps[order["transaction"]].SQL, "balance_id IN (") {
+		t.Error("Expected the transactions of purged balances to be purged")
+	}
+	for _, table := range []string{"category", "category_group"} {
+		if !strings.Contains(purgeSteps[order[table]].SQL, "NOT EXISTS") {
+			t.Errorf("Expected %s rows still in use to be kept", table)
+		}
+	}
+}
+
+func TestPurgeStepsOfAuditedEntitiesReturnTheRows(t *testing.T) {
+	db := newDryRunDB(t)
+	var statements []string
+	capture := func(tx *gorm.DB) { statements = append(statements, tx.Statement.SQL.String()) }
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:capture", capture); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", capture); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	purged, err := runAuditedPurgeSteps(db, purgeSteps, map[string]interface{}{"cutoff": time.Now()})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(purged) != len(purgeSteps) || len(statements) != len(purgeSteps) {
+		t.Fatalf("Expected %d steps, got %d with %d statements", len(purgeSteps), len(purged), len(statements))
+	}
+	for i, step := range purgeSteps {
+		if purged[i].Table != step.Table {
+			t.Errorf("Expected step %d to purge %s, got %s", i, step.Table, purged[i].Table)
+		}
+		// Rows of audited entities are returned by the delete itself, so each purged row gets its audit event
+		_, audited := purgeAuditedTables[step.Table]
+		if returning := strings.HasSuffix(statements[i], " RETURNING *"); returning != audited {
+			t.Errorf("Expected step %s to return its rows: %v, got %s", step.Table, audited, statements[i])
+		}
+	}
+	for _, table := range []string{"transaction", "transfer", "balance", "merchant", "category", "category_group"} {
+		if _, audited := purgeAuditedTables[table]; !audited {
+			t.Errorf("Expected purged %s rows to be audited", table)
+		}
+	}
+}
+
+func TestRecordPurgeAuditsKeepsNoData(t *testing.T) {
+	db := newDryRunDB(t).Session(&gorm.Session{SkipDefaultTransaction: true, Context: context.Background()})
+	var inserted []*models.AuditEvent
+	if err := db.Callback().Create().After("gorm:create").Register("test:capture", func(tx *gorm.DB) {
+		events, _ := tx.Statement.Dest.([]*models.AuditEvent)
+		inserted = append(inserted, events...)
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	var redactions []string
+	var redactionVars [][]interface{}
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		redactions = append(redactions, tx.Statement.SQL.String())
+		redactionVars = append(redactionVars, tx.Statement.Vars)
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	userID, groupID := uuid.New(), uuid.New()
+	balance := &models.Balance{ID: uuid.New(), UserID: userID, GroupID: groupID, Currency: "EUR"}
+	merchant := &models.Merchant{ID: uuid.New(), UserID: userID, GroupID: groupID, Name: "Shop"}
+	if err := recordPurgeAudits(db, []interface{}{balance, merchant}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(inserted) != 2 {
+		t.Fatalf("Expected 2 audit events, got %d", len(inserted))
+	}
+	for i, want := range []struct {
+		entityType string
+		entityID   uuid.UUID
+	}{{"balance", balance.ID}, {"merchant", merchant.ID}} {
+		event := inserted[i]
+		if event.Action != models.AuditActionPurge || event.EntityType != want.entityType || event.EntityID != want.entityID {
+			t.Errorf("Expected purge of %s %s, got %s of %s %s", want.entityType, want.entityID, event.Action, event.EntityType, event.EntityID)
+		}
+		if event.UserID != userID || event.GroupID != groupID || event.Actor != models.AuditActorSystem {
+			t.Errorf("Expected event of the owner recorded by the system, got %+v", event)
+		}
+		if event.Before != nil || event.After != nil {
+			t.Errorf("Expected no snapshot of the purged entity, got %+v", event)
+		}
+	}
+
+	// Earlier snapshots of the purged entities are removed too
+	if len(redactions) != 2 {
+		t.Fatalf("Expected the snapshots of both entity types to be removed, got %v", redactions)
+	}
+	redacted := map[string][]interface{}{}
+	for i, statement := range redactions {
+		if !strings.HasPrefix(statement, "UPDATE audit_event SET before = NULL, after = NULL WHERE entity_type = ") {
+			t.Errorf("Expected audit snapshots to be removed, got %s", statement)
+		}
+		redacted[redactionVars[i][0].(string)] = redactionVars[i][1:]
+	}
+	if ids := redacted["balance"]; len(ids) != 1 || ids[0] != balance.ID {
+		t.Errorf("Expected the snapshots of balance %s to be removed, got %v", balance.ID, redacted["balance"])
+	}
+	if ids := redacted["merchant"]; len(ids) != 1 || ids[0] != merchant.ID {
+		t.Errorf("Expected the snapshots of merchant %s to be removed, got %v", merchant.ID, redacted["merchant"])
+	}
+
+	if err := recordPurgeAudits(db, nil); err != nil || len(inserted) != 2 {
+		t.Errorf("Expected no events without purged rows, got %v with %d events", err, len(inserted))
+	}
+}
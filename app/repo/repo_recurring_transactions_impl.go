@@ -0,0 +1,170 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// CreateRecurringTransaction creates a new recurring transaction
+func (r *PostgreSQLRepository) CreateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return auditedCreate(tx, &recurring)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create recurring transaction: %w", err)
+	}
+	return &recurring, nil
+}
+
+// GetRecurringTransaction retrieves a non-deleted recurring transaction by ID
+func (r *PostgreSQLRepository) GetRecurringTransaction(ctx context.Context, recurringTransactionID string) (*models.RecurringTransaction, error) {
+	var recurring models.RecurringTransaction
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", recurringTransactionID).First(&recurring).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "recurring transaction", ID: recurringTransactionID}
+		}
+		return nil, fmt.Errorf("failed to get recurring transaction: %w", err)
+	}
+	return &recurring, nil
+}
+
+// ListRecurringTransactions retrieves non-deleted recurring transactions filtered by group, user and balance
+func (r *PostgreSQLRepository) ListRecurringTransactions(ctx context.Context, filter models.ListRecurringTransactionsInput) ([]models.RecurringTransaction, error) {
+	var recurring []models.RecurringTransaction
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	query := db.WithContext(ctx).Where("deleted_at IS NULL")
+
+	if filter.GroupID != "" {
+		query = query.Where("group_id = ?", filter.GroupID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.BalanceID != "" {
+		query = query.Where("balance_id = ?", filter.BalanceID)
+	}
+
+	if err := query.Order("name ASC, id ASC").Find(&recurring).Error; err != nil {
+		return nil, fmt.Errorf("failed to list recurring transactions: %w", err)
+	}
+	return recurring, nil
+}
+
+// UpdateRecurringTransaction updates the template and schedule of an existing recurring transaction
+func (r *PostgreSQLRepository) UpdateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	var updated bool
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = auditedUpdate[models.RecurringTransaction](tx, recurring.ID, &recurring,
+			[]string{"group_id", "user_id", "balance_id", "name", "template", "schedule", "start_at", "paused", "next_occurrence_at", "updated_at"},
+			"deleted_at IS NULL")
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update recurring transaction: %w", err)
+	}
+	if !updated {
+		return nil, &models.NotFoundError{Entity: "recurring transaction", ID: recurring.ID.String()}
+	}
+	return r.GetRecurringTransaction(ctx, recurring.ID.String())
+}
+
+// DeleteRecurringTransaction soft deletes a recurring transaction by ID, transactions it generated are kept
+func (r *PostgreSQLRepository) DeleteRecurringTransaction(ctx context.Context, recurringTransactionID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	var deleted int64
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		deleted, err = auditedSoftDelete[models.RecurringTransaction](tx, time.Now().UTC(), "id = ? AND deleted_at IS NULL", recurringTransactionID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to delete recurring transaction: %w", err)
+	}
+	if deleted == 0 {
+		return &models.NotFoundError{Entity: "recurring transaction", ID: recurringTransactionID}
+	}
+	return nil
+}
+
+// ListDueRecurringTransactions retrieves active recurring transactions with an occurrence at or before now that
+// hasn't been materialized yet, optionally only the one with the given ID
+func (r *PostgreSQLRepository) ListDueRecurringTransactions(ctx context.Context, now time.Time, recurringTransactionID string) ([]models.RecurringTransaction, error) {
+	var recurring []models.RecurringTransaction
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	query := db.WithContext(ctx).
+		Where("deleted_at IS NULL AND paused = FALSE").
+		Where("next_occurrence_at IS NOT NULL AND next_occurrence_at <= ?", now)
+
+	if recurringTransactionID != "" {
+		query = query.Where("id = ?", recurringTransactionID)
+	}
+
+	if err := query.Order("next_occurrence_at ASC, id ASC").Find(&recurring).Error; err != nil {
+		return nil, fmt.Errorf("failed to list due recurring transactions: %w", err)
+	}
+	return recurring, nil
+}
+
+// GetRecurringOccurrenceTransactionID returns the ID of the transaction generated for an occurrence of a recurring
+// transaction, empty if the occurrence hasn't been materialized
+func (r *PostgreSQLRepository) GetRecurringOccurrenceTransactionID(ctx context.Context, recurringTransactionID string, occurrence time.Time) (string, error) {
+	var transactionIDs []string
+	db, err := r.getDB()
+	if err != nil {
+		return "", err
+	}
+	if err := db.WithContext(ctx).Model(&models.Transaction{}).
+		Where("recurring_transaction_id = ? AND recurring_occurrence_at = ?", recurringTransactionID, occurrence).
+		Limit(1).
+		Pluck("id", &transactionIDs).Error; err != nil {
+		return "", fmt.Errorf("failed to get recurring occurrence transaction: %w", err)
+	}
+	if len(transactionIDs) == 0 {
+		return "", nil
+	}
+	return transactionIDs[0], nil
+}
+
+// UpdateRecurringTransactionProgress stores the next, last and count of materialized occurrences
+func (r *PostgreSQLRepository) UpdateRecurringTransactionProgress(ctx context.Context, recurring models.RecurringTransaction) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	var updated bool
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = auditedUpdate[models.RecurringTransaction](tx, recurring.ID, &recurring,
+			[]string{"next_occurrence_at", "last_occurrence_at", "occurrences_count", "updated_at"})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to update recurring transaction progress: %w", err)
+	}
+	if !updated {
+		return &models.NotFoundError{Entity: "recurring transaction", ID: recurring.ID.String()}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// GetTransactionIncludingDeleted retrieves a transaction by ID whether or not it is soft deleted
+func (r *PostgreSQLRepository) GetTransactionIncludingDeleted(ctx context.Context, transactionID string) (*models.Transaction, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var tx models.Transaction
+	if err := db.WithContext(ctx).Where("id = ?", transactionID).First(&tx).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "transaction", ID: transactionID}
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &tx, nil
+}
+
+// ListTransactionsDeletedWith retrieves a soft-deleted transaction together with the transactions of its operation
+// deleted at the same time, each with its balance and the entries deleted with it
+func (r *PostgreSQLRepository) ListTransactionsDeletedWith(ctx context.Context, tx models.Transaction) ([]models.Transaction, error) {
+	if tx.DeletedAt == nil {
+		return nil, fmt.Errorf("transaction is not deleted: %s", tx.ID.String())
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.WithContext(ctx).
+		Preload("Balance").
+		Preload("TransactionEntries", "deleted_at = ?", *tx.DeletedAt)
+	if tx.OperationID != nil {
+		query = query.Where("id = ? OR (operation_id = ? AND deleted_at = ?)", tx.ID, *tx.OperationID, *tx.DeletedAt)
+	} else {
+		query = query.Where("id = ?", tx.ID)
+	}
+
+	var transactions []models.Transaction
+	if err := query.Order("created_at ASC, id ASC").Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transactions deleted with %s: %w", tx.ID.String(), err)
+	}
+	return transactions, nil
+}
+
+// RestoreTransactions reverses the soft delete of the transactions and of their loaded entries, and saves the
+// regenerated entry amounts, in a single database transaction. The transfer linking restored legs is restored too.
+func (r *PostgreSQLRepository) RestoreTransactions(ctx context.Context, transactions []models.Transaction) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		now := time.Now().UTC()
+		for _, tx := range transactions {
+			before, err := auditTransaction(dbTx, tx.ID)
+			if err != nil {
+				return err
+			}
+
+			result := dbTx.Model(&models.Transaction{}).
+				Where("id = ? AND deleted_at IS NOT NULL", tx.ID).
+				Updates(map[string]interface{}{"deleted_at": nil, "updated_at": now})
+			if result.Error != nil {
+				return fmt.Errorf("failed to restore transaction %s: %w", tx.ID.String(), result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return &models.NotFoundError{Entity: "transaction", ID: tx.ID.String()}
+			}
+
+			for _, entry := range tx.TransactionEntries {
+				if err := dbTx.Model(&models.TransactionEntry{}).
+					Where("id = ?", entry.ID).
+					Updates(map[string]interface{}{"deleted_at": nil, "updated_at": now}).Error; err != nil {
+					return fmt.Errorf("failed to restore transaction entry %s: %w", entry.ID.String(), err)
+				}
+
+				// Amounts are removed on delete, clear any leftovers before saving the regenerated ones
+				if err := dbTx.Where("transaction_entry_id = ?", entry.ID).Delete(&models.TransactionEntryAmount{}).Error; err != nil {
+					return fmt.Errorf("failed to delete transaction entry amounts for entry %s: %w", entry.ID.String(), err)
+				}
+				if len(entry.TransactionEntryAmounts) > 0 {
+					if err := dbTx.Create(&entry.TransactionEntryAmounts).Error; err != nil {
+						return fmt.Errorf("failed to create transaction entry amounts for entry %s: %w", entry.ID.String(), err)
+					}
+				}
+			}
+
+			after, err := auditTransaction(dbTx, tx.ID)
+			if err != nil {
+				return err
+			}
+			if err := recordAudit(dbTx, models.AuditActionRestore, before, after); err != nil {
+				return err
+			}
+		}
+
+		transactionIDs := make([]interface{}, len(transactions))
+		for i, tx := range transactions {
+			transactionIDs[i] = tx.ID
+		}
+		return restoreTransfers(dbTx, transactionIDs, now)
+	})
+}
+
+// GetCategoryIncludingDeleted retrieves a category by ID whether or not it is soft deleted
+func (r *PostgreSQLRepository) GetCategoryIncludingDeleted(ctx context.Context, categoryID string) (*models.Category, error) {
+	var category models.Category
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("id = ?", categoryID).First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "category", ID: categoryID}
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return &category, nil
+}
+
+// GetCategoryGroupIncludingDeleted retrieves a category group by ID whether or not it is soft deleted
+func (r *PostgreSQLRepository) GetCategoryGroupIncludingDeleted(ctx context.Context, categoryGroupID string) (*models.CategoryGroup, error) {
+	var categoryGroup models.CategoryGroup
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("id = ?", categoryGroupID).First(&categoryGroup).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "category group", ID: categoryGroupID}
+		}
+		return nil, fmt.Errorf("failed to get category group: %w", err)
+	}
+	return &categoryGroup, nil
+}
+
+// GetMerchantIncludingDeleted retrieves a merchant by ID whether or not it is soft deleted
+func (r *PostgreSQLRepository) GetMerchantIncludingDeleted(ctx context.Context, merchantID string) (*models.Merchant, error) {
+	var merchant models.Merchant
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Where("id = ?", merchantID).First(&merchant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "merchant", ID: merchantID}
+		}
+		return nil, fmt.Errorf("failed to get merchant: %w", err)
+	}
+	return &merchant, nil
+}
+
+// RestoreBalance reverses the soft delete of a balance
+func (r *PostgreSQLRepository) RestoreBalance(ctx context.Context, balanceID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	return restoreSoftDeleted[models.Balance](ctx, db, "balance", balanceID)
+}
+
+// RestoreCategory reverses the soft delete of a category
+func (r *PostgreSQLRepository) RestoreCategory(ctx context.Context, categoryID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	return restoreSoftDeleted[models.Category](ctx, db, "category", categoryID)
+}
+
+// RestoreCategoryGroup reverses the soft delete of a category group
+func (r *PostgreSQLRepository) RestoreCategoryGroup(ctx context.Context, categoryGroupID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	return restoreSoftDeleted[models.CategoryGroup](ctx, db, "category group", categoryGroupID)
+}
+
+// RestoreMerchant reverses the soft delete of a merchant. Transactions lost their reference to the merchant when it
+// was deleted and aren't linked again.
+func (r *PostgreSQLRepository) RestoreMerchant(ctx context.Context, merchantID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+	return restoreSoftDeleted[models.Merchant](ctx, db, "merchant", merchantID)
+}
+
+// restoreSoftDeleted clears deleted_at of a soft-deleted row of the model's table and records the restore
+func restoreSoftDeleted[T any](ctx context.Context, db *gorm.DB, entity, id string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before T
+		if err := tx.Where("id = ? AND deleted_at IS NOT NULL", id).First(&before).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &models.NotFoundError{Entity: entity, ID: id}
+			}
+			return fmt.Errorf("failed to get %s: %w", entity, err)
+		}
+
+		if err := tx.Model(new(T)).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now().UTC()}).Error; err != nil {
+			return fmt.Errorf("failed to restore %s: %w", entity, err)
+		}
+
+		var after T
+		if err := tx.Where("id = ?", id).First(&after).Error; err != nil {
+			return fmt.Errorf("failed to reload restored %s: %w", entity, err)
+		}
+		return recordAudit(tx, models.AuditActionRestore, &before, &after)
+	})
+}
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// ListSuggestionHistory retrieves the latest non-deleted income and expense entries of a user with their category
+// and merchant, newest first. Deleted categories and merchants are left out of the entries.
+func (r *PostgreSQLRepository) ListSuggestionHistory(ctx context.Context, userID string, limit int) ([]models.SuggestionHistoryEntry, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.SuggestionHistoryEntry
+	err = db.WithContext(ctx).Table("transaction_entry").
+		Select(`COALESCE(transaction_entry.description, '') AS description,
+			merchant.id AS merchant_id, COALESCE(merchant.name, '') AS merchant_name,
+			category.id AS category_id, COALESCE(category.name, '') AS category_name,
+			transaction_entry.amount, transaction.transacted_at`).
+		Joins("JOIN transaction ON transaction_entry.transaction_id = transaction.id").
+		Joins("LEFT JOIN merchant ON transaction.merchant_id = merchant.id AND merchant.deleted_at IS NULL").
+		Joins("LEFT JOIN category ON transaction_entry.category_id = category.id AND category.deleted_at IS NULL").
+		Where("transaction.user_id = ? AND transaction.type IN ?", userID, models.CategorizedTransactionTypes).
+		Where("transaction.deleted_at IS NULL AND transaction_entry.deleted_at IS NULL").
+		Order("transaction.transacted_at DESC, transaction_entry.id DESC").
+		Limit(limit).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suggestion history: %w", err)
+	}
+	return entries, nil
+}
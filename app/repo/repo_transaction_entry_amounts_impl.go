@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm/clause"
+)
+
+// ListTransactionEntriesAfter retrieves a batch of non-deleted transaction entries ordered by ID, starting after the given entry ID.
+// Entries are loaded with their transaction, balance and currency amounts.
+func (r *PostgreSQLRepository) ListTransactionEntriesAfter(ctx context.Context, afterEntryID string, limit int) ([]models.TransactionEntry, error) {
+	var entries []models.TransactionEntry
+
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.WithContext(ctx).
+		Preload("Transaction").
+		Preload("Transaction.Balance"). // Load balance regardless of deletion status for its currency
+		Preload("TransactionEntryAmounts").
+		Joins("JOIN transaction ON transaction_entry.transaction_id = transaction.id").
+		Where("transaction_entry.deleted_at IS NULL AND transaction.deleted_at IS NULL")
+
+	if afterEntryID != "" {
+		query = query.Where("transaction_entry.id > ?", afterEntryID)
+	}
+
+	if err := query.Order("transaction_entry.id ASC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transaction entries after %s: %w", afterEntryID, err)
+	}
+
+	return entries, nil
+}
+
+// SaveTransactionEntryAmounts inserts the given amounts or updates them if an amount for the same entry and currency already exists
+func (r *PostgreSQLRepository) SaveTransactionEntryAmounts(ctx context.Context, amounts []models.TransactionEntryAmount) error {
+	if len(amounts) == 0 {
+		return nil
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for i := range amounts {
+		amounts[i].UpdatedAt = now
+		if amounts[i].CreatedAt.IsZero() {
+			amounts[i].CreatedAt = now
+		}
+	}
+
+	if err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "transaction_entry_id"}, {Name: "currency"}},
+			DoUpdates: clause.AssignmentColumns([]string{"amount", "exchange_rate", "exchange_rate_date", "updated_at"}),
+		}).
+		Create(&amounts).Error; err != nil {
+		return fmt.Errorf("failed to save transaction entry amounts: %w", err)
+	}
+
+	return nil
+}
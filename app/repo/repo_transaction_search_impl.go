@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// transactionSearchHeadlineOptions configures the snippets of matched fields
+var transactionSearchHeadlineOptions = fmt.Sprintf(`StartSel=%s, StopSel=%s, MinWords=5, MaxWords=20, MaxFragments=2, FragmentDelimiter=" ... "`,
+	models.SearchHighlightStart, models.SearchHighlightStop)
+
+// transactionSearchRow is a ranked search result before its entry is loaded
+type transactionSearchRow struct {
+	ID                 uuid.UUID
+	Rank               float64
+	DescriptionSnippet *string
+	MerchantSnippet    *string
+	CategorySnippet    *string
+}
+
+// searchTransactionEntries lists the entries matching the filter whose description, merchant name or category name
+// match the full-text search. Ranks weigh matches in the description above the merchant and the category name.
+// Matching entries are ranked and paginated first and only the entries of the page are loaded with their relations.
+func (r *PostgreSQLRepository) searchTransactionEntries(ctx context.Context, filter models.ListTransactionsInput) ([]models.TransactionEntry, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	search := filter.Search
+
+	matches := applyTransactionEntryFilters(db.WithContext(ctx).Model(&models.TransactionEntry{}), filter).
+		Joins("LEFT JOIN merchant AS search_merchant ON transaction.merchant_id = search_merchant.id AND search_merchant.deleted_at IS NULL").
+		Joins("LEFT JOIN category AS search_category ON transaction_entry.category_id = search_category.id AND search_category.deleted_at IS NULL").
+		Where(`(transaction_entry.search_vector @@ to_tsquery('simple', ?)
+			OR search_merchant.search_vector @@ to_tsquery('simple', ?)
+			OR search_category.search_vector @@ to_tsquery('simple', ?))`, search, search, search).
+		Select(`transaction_entry.id, transaction_entry.created_at, transaction_entry.amount, transaction.transacted_at,
+			transaction_entry.description, search_merchant.name AS merchant_name, search_category.name AS category_name,
+			transaction_entry.search_vector @@ to_tsquery('simple', ?) AS description_matched,
+			COALESCE(search_merchant.search_vector @@ to_tsquery('simple', ?), FALSE) AS merchant_matched,
+			COALESCE(search_category.search_vector @@ to_tsquery('simple', ?), FALSE) AS category_matched,
+			ts_rank(
+				setweight(transaction_entry.search_vector, 'A') ||
+				setweight(COALESCE(search_merchant.search_vector, ''::tsvector), 'B') ||
+				setweight(COALESCE(search_category.search_vector, ''::tsvector), 'C'),
+				to_tsquery('simple', ?))::float8 AS rank`, search, search, search, search)
+
+	// Snippets are computed in the outer query so that only the rows of the page get highlighted
+	query := db.WithContext(ctx).Table("(?) AS search_result", matches).
+		Select(`search_result.id, search_result.rank,
+			CASE WHEN search_result.description_matched THEN ts_headline('simple', search_result.description, to_tsquery('simple', ?), ?) END AS description_snippet,
+			CASE WHEN search_result.merchant_matched THEN ts_headline('simple', search_result.merchant_name, to_tsquery('simple', ?), ?) END AS merchant_snippet,
+			CASE WHEN search_result.category_matched THEN ts_headline('simple', search_result.category_name, to_tsquery('simple', ?), ?) END AS category_snippet`,
+			search, transactionSearchHeadlineOptions, search, transactionSearchHeadlineOptions, search, transactionSearchHeadlineOptions)
+
+	// Apply sorting, by rank unless requested otherwise. Ranks are ordered and compared with the cursor through the
+	// same float8 search_result.rank column the page is read from, so that cursor values match tied ranks exactly.
+	orderBy := "search_result.created_at"
+	sortKind := sortValueTime
+	switch filter.SortBy {
+	case models.SortByRelevance:
+		orderBy = "search_result.rank"
+		sortKind = sortValueFloat
+	case "transactedAt":
+		orderBy = "search_result.transacted_at"
+	case "amount":
+		orderBy = "search_result.amount"
+		sortKind = sortValueInt
+	}
+
+	order := "DESC"
+	if filter.Order == "asc" {
+		order = "ASC"
+	}
+
+	query, err = applyKeysetPagination(query, filter.Cursor, orderBy, sortKind, "search_result.id", order)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 50 // default limit
+	if filter.Limit > 0 && filter.Limit <= 100 {
+		limit = filter.Limit
+	}
+
+	var rows []transactionSearchRow
+	if err := query.Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to search transaction entries: %w", err)
+	}
+	if len(rows) == 0 {
+		return []models.TransactionEntry{}, nil
+	}
+
+	entryIDs := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		entryIDs[i] = row.ID
+	}
+
+	var entries []models.TransactionEntry
+	if err := preloadTransactionEntryRelations(db.WithContext(ctx)).Where("id IN ?", entryIDs).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transaction entries: %w", err)
+	}
+
+	// Return the entries in the order of the ranked rows with their search match
+	entriesByID := make(map[uuid.UUID]models.TransactionEntry, len(entries))
+	for _, entry := range entries {
+		entriesByID[entry.ID] = entry
+	}
+	results := make([]models.TransactionEntry, 0, len(rows))
+	for _, row := range rows {
+		entry, ok := entriesByID[row.ID]
+		if !ok {
+			continue // Deleted in between
+		}
+		entry.SearchMatch = &models.SearchMatch{
+			Rank:               row.Rank,
+			DescriptionSnippet: row.DescriptionSnippet,
+			MerchantSnippet:    row.MerchantSnippet,
+			CategorySnippet:    row.CategorySnippet,
+		}
+		results = append(results, entry)
+	}
+
+	return results, nil
+}
@@ -20,7 +20,10 @@ type TransactionStatsRawGrouped struct {
 // GetTransactionStats retrieves aggregated transaction statistics based on grouping
 func (r *PostgreSQLRepository) GetTransactionStats(ctx context.Context, filter models.TransactionStatsInput) ([]models.TransactionStatsItemDto, error) {
 	var results []TransactionStatsRawGrouped
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 
 	// Set default display currency if not provided
 	displayCurrency := filter.DisplayCurrency
@@ -102,6 +105,9 @@ func (r *PostgreSQLRepository) GetTransactionStats(ctx context.Context, filter m
 		query = query.Where("t.transacted_at <= ?", *filter.EndTime)
 	}
 
+	// Apply amount and attribute filters in the same way as the transactions list
+	query = applyTransactionEntryAttributeFilter(query, filter.TransactionEntryFilter, "te", "t")
+
 	// Add grouping-specific SELECT and GROUP BY clauses
 	selectClause, groupByClause, joinClause := r.buildGroupingQuery(filter.Grouping, displayCurrency)
 
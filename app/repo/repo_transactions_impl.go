@@ -2,7 +2,9 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/savak1990/transactions-service/app/models"
@@ -12,11 +14,19 @@ import (
 // CreateTransaction creates a new transaction in the database
 func (r *PostgreSQLRepository) CreateTransaction(ctx context.Context, tx models.Transaction) (*models.Transaction, error) {
 
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 
-	// Create the transaction in the database with its transaction entries
-	if err := db.WithContext(ctx).Create(&tx).Error; err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	// Create the transaction in the database with its transaction entries and record the creation
+	if err := db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		if err := dbTx.Create(&tx).Error; err != nil {
+			return createTransactionError(tx, err)
+		}
+		return recordAudit(dbTx, models.AuditActionCreate, nil, &tx)
+	}); err != nil {
+		return nil, err
 	}
 
 	// Reload the transaction with all relationships
@@ -37,34 +47,18 @@ func (r *PostgreSQLRepository) CreateTransaction(ctx context.Context, tx models.
 
 // CreateTransactions creates multiple transactions atomically in a single database transaction
 func (r *PostgreSQLRepository) CreateTransactions(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 
 	var createdTransactions []models.Transaction
 
 	// Use a database transaction to ensure atomicity
-	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Create all transactions in the same database transaction
-		for _, transaction := range transactions {
-			if err := tx.Create(&transaction).Error; err != nil {
-				return fmt.Errorf("failed to create transaction %s: %w", transaction.ID.String(), err)
-			}
-
-			// Reload the transaction with all relationships
-			var createdTx models.Transaction
-			if err := tx.
-				Preload("Merchant").
-				Preload("TransactionEntries").
-				Preload("TransactionEntries.TransactionEntryAmounts"). // Load multi-currency amounts
-				Preload("TransactionEntries.Category").
-				Preload("TransactionEntries.Category.CategoryGroup").
-				Where("id = ?", transaction.ID).
-				First(&createdTx).Error; err != nil {
-				return fmt.Errorf("failed to reload created transaction %s: %w", transaction.ID.String(), err)
-			}
-
-			createdTransactions = append(createdTransactions, createdTx)
-		}
-		return nil
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		createdTransactions, err = createTransactions(tx, transactions)
+		return err
 	})
 
 	if err != nil {
@@ -74,10 +68,58 @@ func (r *PostgreSQLRepository) CreateTransactions(ctx context.Context, transacti
 	return createdTransactions, nil
 }
 
+// createTransactions creates the transactions within the given database transaction and reloads them with all
+// relationships
+func createTransactions(tx *gorm.DB, transactions []models.Transaction) ([]models.Transaction, error) {
+	var createdTransactions []models.Transaction
+
+	for _, transaction := range transactions {
+		if err := tx.Create(&transaction).Error; err != nil {
+			return nil, createTransactionError(transaction, err)
+		}
+		if err := recordAudit(tx, models.AuditActionCreate, nil, &transaction); err != nil {
+			return nil, err
+		}
+
+		// Reload the transaction with all relationships
+		var createdTx models.Transaction
+		if err := tx.
+			Preload("Merchant").
+			Preload("TransactionEntries").
+			Preload("TransactionEntries.TransactionEntryAmounts"). // Load multi-currency amounts
+			Preload("TransactionEntries.Category").
+			Preload("TransactionEntries.Category.CategoryGroup").
+			Where("id = ?", transaction.ID).
+			First(&createdTx).Error; err != nil {
+			return nil, fmt.Errorf("failed to reload created transaction %s: %w", transaction.ID.String(), err)
+		}
+
+		createdTransactions = append(createdTransactions, createdTx)
+	}
+
+	return createdTransactions, nil
+}
+
+// createTransactionError reports a transaction violating a unique index as a ConflictError, e.g. a second transaction
+// for the same occurrence of a recurring transaction
+func createTransactionError(transaction models.Transaction, err error) error {
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("failed to create transaction %s: %w", transaction.ID.String(), err)
+	}
+	if transaction.RecurringTransactionID != nil && transaction.RecurringOccurrenceAt != nil {
+		return &models.ConflictError{Reason: fmt.Sprintf("occurrence %s of recurring transaction %s already has a transaction",
+			transaction.RecurringOccurrenceAt.Format(time.RFC3339), transaction.RecurringTransactionID.String())}
+	}
+	return &models.ConflictError{Reason: fmt.Sprintf("transaction %s already exists", transaction.ID.String())}
+}
+
 // GetTransaction retrieves a single transaction by ID
 func (r *PostgreSQLRepository) GetTransaction(ctx context.Context, transactionID string) (*models.Transaction, error) {
 
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 
 	var tx models.Transaction
 	if err := db.WithContext(ctx).
@@ -87,10 +129,10 @@ func (r *PostgreSQLRepository) GetTransaction(ctx context.Context, transactionID
 		Preload("TransactionEntries.TransactionEntryAmounts"). // Load multi-currency amounts
 		Preload("TransactionEntries.Category").
 		Preload("TransactionEntries.Category.CategoryGroup"). // Load CategoryGroup without filtering to detect soft-deleted groups
-		Where("transaction.id = ?", transactionID).
+		Where("transaction.id = ? AND transaction.deleted_at IS NULL", transactionID).
 		First(&tx).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("transaction not found: %s", transactionID)
+			return nil, &models.NotFoundError{Entity: "transaction", ID: transactionID}
 		}
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
@@ -101,173 +143,184 @@ func (r *PostgreSQLRepository) GetTransaction(ctx context.Context, transactionID
 // UpdateTransaction updates an existing transaction
 func (r *PostgreSQLRepository) UpdateTransaction(ctx context.Context, tx models.Transaction) (*models.Transaction, error) {
 
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
 
 	// Use a database transaction to ensure atomicity
-	err := db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
-		// First, get the existing transaction to compare changes
-		var existingTx models.Transaction
-		if err := dbTx.Where("id = ?", tx.ID).First(&existingTx).Error; err != nil {
-			return fmt.Errorf("failed to get existing transaction: %w", err)
-		}
+	err = db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		return updateTransaction(dbTx, tx)
+	})
 
-		// Check if any main transaction fields have changed
-		needsMainUpdate := false
-		updatedTx := existingTx // Copy existing transaction
+	if err != nil {
+		return nil, err
+	}
 
-		// Check balance ID
-		if tx.BalanceID != existingTx.BalanceID {
-			updatedTx.BalanceID = tx.BalanceID
-			needsMainUpdate = true
-		}
+	// Reload the transaction with all relationships (including soft-deleted entries)
+	var updatedTx models.Transaction
+	if err := db.WithContext(ctx).
+		Preload("Merchant", "deleted_at IS NULL").
+		Preload("TransactionEntries").                         // Include all entries (deleted and non-deleted)
+		Preload("TransactionEntries.TransactionEntryAmounts"). // Load multi-currency amounts
+		Preload("TransactionEntries.Category").
+		Preload("TransactionEntries.Category.CategoryGroup").
+		Where("id = ?", tx.ID).
+		First(&updatedTx).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload updated transaction: %w", err)
+	}
 
-		// Check merchant ID
-		if (tx.MerchantID == nil && existingTx.MerchantID != nil) ||
-			(tx.MerchantID != nil && existingTx.MerchantID == nil) ||
-			(tx.MerchantID != nil && existingTx.MerchantID != nil && *tx.MerchantID != *existingTx.MerchantID) {
-			updatedTx.MerchantID = tx.MerchantID
-			needsMainUpdate = true
-		}
+	return &updatedTx, nil
+}
 
-		// Check operation ID
-		if (tx.OperationID == nil && existingTx.OperationID != nil) ||
-			(tx.OperationID != nil && existingTx.OperationID == nil) ||
-			(tx.OperationID != nil && existingTx.OperationID != nil && *tx.OperationID != *existingTx.OperationID) {
-			updatedTx.OperationID = tx.OperationID
-			needsMainUpdate = true
-		}
+// updateTransaction applies the changes of a transaction and its entries within the given database transaction
+func updateTransaction(dbTx *gorm.DB, tx models.Transaction) error {
+	// First, get the existing transaction to compare changes
+	var existingTx models.Transaction
+	if err := dbTx.Where("id = ?", tx.ID).First(&existingTx).Error; err != nil {
+		return fmt.Errorf("failed to get existing transaction: %w", err)
+	}
+	before, err := auditTransaction(dbTx, tx.ID)
+	if err != nil {
+		return err
+	}
 
-		// Check type
-		if tx.Type != existingTx.Type {
-			updatedTx.Type = tx.Type
-			needsMainUpdate = true
-		}
+	// Check if any main transaction fields have changed
+	needsMainUpdate := false
+	updatedTx := existingTx // Copy existing transaction
 
-		// Check approved at
-		if !tx.ApprovedAt.Equal(existingTx.ApprovedAt) {
-			updatedTx.ApprovedAt = tx.ApprovedAt
-			needsMainUpdate = true
-		}
+	// Check balance ID
+	if tx.BalanceID != existingTx.BalanceID {
+		updatedTx.BalanceID = tx.BalanceID
+		needsMainUpdate = true
+	}
 
-		// Check transacted at
-		if !tx.TransactedAt.Equal(existingTx.TransactedAt) {
-			updatedTx.TransactedAt = tx.TransactedAt
-			needsMainUpdate = true
-		}
+	// Check merchant ID
+	if (tx.MerchantID == nil && existingTx.MerchantID != nil) ||
+		(tx.MerchantID != nil && existingTx.MerchantID == nil) ||
+		(tx.MerchantID != nil && existingTx.MerchantID != nil && *tx.MerchantID != *existingTx.MerchantID) {
+		updatedTx.MerchantID = tx.MerchantID
+		needsMainUpdate = true
+	}
 
-		// Only update the main transaction if something actually changed
-		if needsMainUpdate {
-			updatedTx.UpdatedAt = time.Now().UTC()
-			// Create a copy without transaction entries to avoid GORM auto-management
-			txWithoutEntries := updatedTx
-			txWithoutEntries.TransactionEntries = nil
+	// Check operation ID
+	if (tx.OperationID == nil && existingTx.OperationID != nil) ||
+		(tx.OperationID != nil && existingTx.OperationID == nil) ||
+		(tx.OperationID != nil && existingTx.OperationID != nil && *tx.OperationID != *existingTx.OperationID) {
+		updatedTx.OperationID = tx.OperationID
+		needsMainUpdate = true
+	}
 
-			if err := dbTx.Save(&txWithoutEntries).Error; err != nil {
-				return fmt.Errorf("failed to update transaction: %w", err)
-			}
-		}
+	// Check type
+	if tx.Type != existingTx.Type {
+		updatedTx.Type = tx.Type
+		needsMainUpdate = true
+	}
 
-		// Handle transaction entries separately if provided
-		// If TransactionEntries is nil or empty, we skip entry updates entirely
-		if len(tx.TransactionEntries) > 0 {
-			// Get all existing transaction entries (including soft-deleted ones for potential undelete)
-			var allExistingEntries []models.TransactionEntry
-			if err := dbTx.Where("transaction_id = ?", tx.ID).Find(&allExistingEntries).Error; err != nil {
-				return fmt.Errorf("failed to get existing transaction entries: %w", err)
-			}
+	// Check approved at
+	if !tx.ApprovedAt.Equal(existingTx.ApprovedAt) {
+		updatedTx.ApprovedAt = tx.ApprovedAt
+		needsMainUpdate = true
+	}
 
-			// Get only non-deleted entries for the soft-delete logic
-			var activeExistingEntries []models.TransactionEntry
-			if err := dbTx.Where("transaction_id = ? AND deleted_at IS NULL", tx.ID).Find(&activeExistingEntries).Error; err != nil {
-				return fmt.Errorf("failed to get active transaction entries: %w", err)
-			}
+	// Check transacted at
+	if !tx.TransactedAt.Equal(existingTx.TransactedAt) {
+		updatedTx.TransactedAt = tx.TransactedAt
+		needsMainUpdate = true
+	}
 
-			// Create maps for quick lookup
-			allExistingEntriesMap := make(map[string]models.TransactionEntry)
-			for _, entry := range allExistingEntries {
-				allExistingEntriesMap[entry.ID.String()] = entry
-			}
+	// Entry amounts in other currencies depend on the balance currency and the transaction date
+	needsAmountsUpdate := updatedTx.BalanceID != existingTx.BalanceID || !updatedTx.TransactedAt.Equal(existingTx.TransactedAt)
 
-			// Process each entry in the update request
-			updatedEntryIDs := make(map[string]bool)
-			for _, entry := range tx.TransactionEntries {
-				updatedEntryIDs[entry.ID.String()] = true
-
-				// Check if this entry already exists (including soft-deleted ones)
-				if existingEntry, exists := allExistingEntriesMap[entry.ID.String()]; exists {
-					// Check if any fields have actually changed to avoid unnecessary updates
-					needsUpdate := false
-					updatedEntry := existingEntry // Copy existing entry
-
-					// Check description
-					if (entry.Description == nil && existingEntry.Description != nil) ||
-						(entry.Description != nil && existingEntry.Description == nil) ||
-						(entry.Description != nil && existingEntry.Description != nil && *entry.Description != *existingEntry.Description) {
-						updatedEntry.Description = entry.Description
-						needsUpdate = true
-					}
+	// Only update the main transaction if something actually changed
+	if needsMainUpdate {
+		updatedTx.UpdatedAt = time.Now().UTC()
+		// Create a copy without transaction entries to avoid GORM auto-management
+		txWithoutEntries := updatedTx
+		txWithoutEntries.TransactionEntries = nil
 
-					// Check amount
-					if entry.Amount != existingEntry.Amount {
-						updatedEntry.Amount = entry.Amount
-						needsUpdate = true
-					}
+		if err := dbTx.Save(&txWithoutEntries).Error; err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+	}
 
-					// Check category ID
-					if (entry.CategoryID == nil && existingEntry.CategoryID != nil) ||
-						(entry.CategoryID != nil && existingEntry.CategoryID == nil) ||
-						(entry.CategoryID != nil && existingEntry.CategoryID != nil && *entry.CategoryID != *existingEntry.CategoryID) {
-						updatedEntry.CategoryID = entry.CategoryID
-						needsUpdate = true
-					}
+	// Handle transaction entries separately if provided
+	// If TransactionEntries is nil or empty, we skip entry updates entirely
+	if len(tx.TransactionEntries) > 0 {
+		// Get all existing transaction entries (including soft-deleted ones for potential undelete)
+		var allExistingEntries []models.TransactionEntry
+		if err := dbTx.Where("transaction_id = ?", tx.ID).Find(&allExistingEntries).Error; err != nil {
+			return fmt.Errorf("failed to get existing transaction entries: %w", err)
+		}
 
-					// Check if it was soft-deleted and needs to be undeleted
-					if existingEntry.DeletedAt != nil {
-						updatedEntry.DeletedAt = nil // Undelete
-						needsUpdate = true
-					}
+		// Get only non-deleted entries for the soft-delete logic
+		var activeExistingEntries []models.TransactionEntry
+		if err := dbTx.Where("transaction_id = ? AND deleted_at IS NULL", tx.ID).Find(&activeExistingEntries).Error; err != nil {
+			return fmt.Errorf("failed to get active transaction entries: %w", err)
+		}
 
-					// Only update if something actually changed
-					if needsUpdate {
-						updatedEntry.UpdatedAt = time.Now().UTC()
+		// Create maps for quick lookup
+		allExistingEntriesMap := make(map[string]models.TransactionEntry)
+		for _, entry := range allExistingEntries {
+			allExistingEntriesMap[entry.ID.String()] = entry
+		}
 
-						// First delete existing TransactionEntryAmounts for this entry
-						if err := dbTx.Where("transaction_entry_id = ?", entry.ID).Delete(&models.TransactionEntryAmount{}).Error; err != nil {
-							return fmt.Errorf("failed to delete existing transaction entry amounts for entry %s: %w", entry.ID.String(), err)
-						}
+		// Process each entry in the update request
+		updatedEntryIDs := make(map[string]bool)
+		for _, entry := range tx.TransactionEntries {
+			updatedEntryIDs[entry.ID.String()] = true
+
+			// Check if this entry already exists (including soft-deleted ones)
+			if existingEntry, exists := allExistingEntriesMap[entry.ID.String()]; exists {
+				// Check if any fields have actually changed to avoid unnecessary updates
+				needsUpdate := false
+				updatedEntry := existingEntry // Copy existing entry
+
+				// Check description
+				if (entry.Description == nil && existingEntry.Description != nil) ||
+					(entry.Description != nil && existingEntry.Description == nil) ||
+					(entry.Description != nil && existingEntry.Description != nil && *entry.Description != *existingEntry.Description) {
+					updatedEntry.Description = entry.Description
+					needsUpdate = true
+				}
 
-						// Create the updated entry without TransactionEntryAmounts to avoid GORM conflicts
-						entryWithoutAmounts := updatedEntry
-						entryWithoutAmounts.TransactionEntryAmounts = nil
-						if err := dbTx.Save(&entryWithoutAmounts).Error; err != nil {
-							return fmt.Errorf("failed to update transaction entry %s: %w", entry.ID.String(), err)
-						}
+				// Check amount
+				if entry.Amount != existingEntry.Amount {
+					updatedEntry.Amount = entry.Amount
+					needsUpdate = true
+				}
 
-						// Create new TransactionEntryAmounts if provided
-						if len(entry.TransactionEntryAmounts) > 0 {
-							for _, amount := range entry.TransactionEntryAmounts {
-								amount.CreatedAt = time.Now().UTC()
-								amount.UpdatedAt = time.Now().UTC()
-								if err := dbTx.Create(&amount).Error; err != nil {
-									return fmt.Errorf("failed to create transaction entry amount for entry %s, currency %s: %w", entry.ID.String(), amount.Currency, err)
-								}
-							}
-						}
+				// Check category ID
+				if (entry.CategoryID == nil && existingEntry.CategoryID != nil) ||
+					(entry.CategoryID != nil && existingEntry.CategoryID == nil) ||
+					(entry.CategoryID != nil && existingEntry.CategoryID != nil && *entry.CategoryID != *existingEntry.CategoryID) {
+					updatedEntry.CategoryID = entry.CategoryID
+					needsUpdate = true
+				}
+
+				// Check if it was soft-deleted and needs to be undeleted
+				if existingEntry.DeletedAt != nil {
+					updatedEntry.DeletedAt = nil // Undelete
+					needsUpdate = true
+				}
+
+				// Only update if something actually changed (or amounts must be re-priced)
+				if needsUpdate || needsAmountsUpdate {
+					updatedEntry.UpdatedAt = time.Now().UTC()
+
+					// First delete existing TransactionEntryAmounts for this entry
+					if err := dbTx.Where("transaction_entry_id = ?", entry.ID).Delete(&models.TransactionEntryAmount{}).Error; err != nil {
+						return fmt.Errorf("failed to delete existing transaction entry amounts for entry %s: %w", entry.ID.String(), err)
 					}
-				} else {
-					// Create new entry
-					entry.TransactionID = tx.ID
-					entry.CreatedAt = time.Now().UTC()
-					entry.UpdatedAt = time.Now().UTC()
-
-					// Create the entry without TransactionEntryAmounts to avoid GORM conflicts
-					entryWithoutAmounts := entry
+
+					// Create the updated entry without TransactionEntryAmounts to avoid GORM conflicts
+					entryWithoutAmounts := updatedEntry
 					entryWithoutAmounts.TransactionEntryAmounts = nil
-					if err := dbTx.Create(&entryWithoutAmounts).Error; err != nil {
-						return fmt.Errorf("failed to create transaction entry %s: %w", entry.ID.String(), err)
+					if err := dbTx.Save(&entryWithoutAmounts).Error; err != nil {
+						return fmt.Errorf("failed to update transaction entry %s: %w", entry.ID.String(), err)
 					}
 
-					// Create TransactionEntryAmounts if provided
+					// Create new TransactionEntryAmounts if provided
 					if len(entry.TransactionEntryAmounts) > 0 {
 						for _, amount := range entry.TransactionEntryAmounts {
 							amount.CreatedAt = time.Now().UTC()
@@ -278,82 +331,136 @@ func (r *PostgreSQLRepository) UpdateTransaction(ctx context.Context, tx models.
 						}
 					}
 				}
-			}
+			} else {
+				// Create new entry
+				entry.TransactionID = tx.ID
+				entry.CreatedAt = time.Now().UTC()
+				entry.UpdatedAt = time.Now().UTC()
+
+				// Create the entry without TransactionEntryAmounts to avoid GORM conflicts
+				entryWithoutAmounts := entry
+				entryWithoutAmounts.TransactionEntryAmounts = nil
+				if err := dbTx.Create(&entryWithoutAmounts).Error; err != nil {
+					return fmt.Errorf("failed to create transaction entry %s: %w", entry.ID.String(), err)
+				}
 
-			// Soft delete active entries that are not in the update request
-			for _, existingEntry := range activeExistingEntries {
-				if !updatedEntryIDs[existingEntry.ID.String()] {
-					// First, soft delete all TransactionEntryAmounts for this entry
-					if err := dbTx.Where("transaction_entry_id = ?", existingEntry.ID).Delete(&models.TransactionEntryAmount{}).Error; err != nil {
-						return fmt.Errorf("failed to delete transaction entry amounts for entry %s: %w", existingEntry.ID.String(), err)
+				// Create TransactionEntryAmounts if provided
+				if len(entry.TransactionEntryAmounts) > 0 {
+					for _, amount := range entry.TransactionEntryAmounts {
+						amount.CreatedAt = time.Now().UTC()
+						amount.UpdatedAt = time.Now().UTC()
+						if err := dbTx.Create(&amount).Error; err != nil {
+							return fmt.Errorf("failed to create transaction entry amount for entry %s, currency %s: %w", entry.ID.String(), amount.Currency, err)
+						}
 					}
+				}
+			}
+		}
 
-					// Soft delete by setting deleted_at timestamp
-					now := time.Now().UTC()
-					existingEntry.DeletedAt = &now
-					existingEntry.UpdatedAt = now
+		// Soft delete active entries that are not in the update request
+		for _, existingEntry := range activeExistingEntries {
+			if !updatedEntryIDs[existingEntry.ID.String()] {
+				// First, soft delete all TransactionEntryAmounts for this entry
+				if err := dbTx.Where("transaction_entry_id = ?", existingEntry.ID).Delete(&models.TransactionEntryAmount{}).Error; err != nil {
+					return fmt.Errorf("failed to delete transaction entry amounts for entry %s: %w", existingEntry.ID.String(), err)
+				}
 
-					if err := dbTx.Save(&existingEntry).Error; err != nil {
-						return fmt.Errorf("failed to soft delete transaction entry %s: %w", existingEntry.ID.String(), err)
-					}
+				// Soft delete by setting deleted_at timestamp
+				now := time.Now().UTC()
+				existingEntry.DeletedAt = &now
+				existingEntry.UpdatedAt = now
+
+				if err := dbTx.Save(&existingEntry).Error; err != nil {
+					return fmt.Errorf("failed to soft delete transaction entry %s: %w", existingEntry.ID.String(), err)
 				}
 			}
 		}
+	}
 
-		return nil
-	})
+	if err := updateTransactionFingerprint(dbTx, updatedTx); err != nil {
+		return err
+	}
 
+	after, err := auditTransaction(dbTx, tx.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return recordAudit(dbTx, models.AuditActionUpdate, before, after)
+}
 
-	// Reload the transaction with all relationships (including soft-deleted entries)
-	var updatedTx models.Transaction
-	if err := db.WithContext(ctx).
-		Preload("Merchant", "deleted_at IS NULL").
-		Preload("TransactionEntries").                         // Include all entries (deleted and non-deleted)
-		Preload("TransactionEntries.TransactionEntryAmounts"). // Load multi-currency amounts
-		Preload("TransactionEntries.Category").
-		Preload("TransactionEntries.Category.CategoryGroup").
-		Where("id = ?", tx.ID).
-		First(&updatedTx).Error; err != nil {
-		return nil, fmt.Errorf("failed to reload updated transaction: %w", err)
+// updateTransactionFingerprint recomputes the fingerprint of an updated transaction from its balance, type, merchant
+// and the stored entry amounts, the stored fingerprint is only written if it changed
+func updateTransactionFingerprint(dbTx *gorm.DB, tx models.Transaction) error {
+	var amount int64
+	if err := dbTx.Model(&models.TransactionEntry{}).
+		Where("transaction_id = ? AND deleted_at IS NULL", tx.ID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&amount).Error; err != nil {
+		return fmt.Errorf("failed to sum transaction entry amounts: %w", err)
 	}
-
-	return &updatedTx, nil
+	fingerprint := models.TransactionFingerprint(tx.BalanceID, tx.Type, amount, tx.MerchantID)
+	if tx.Fingerprint == nil || *tx.Fingerprint != fingerprint {
+		if err := dbTx.Model(&models.Transaction{}).Where("id = ?", tx.ID).UpdateColumn("fingerprint", fingerprint).Error; err != nil {
+			return fmt.Errorf("failed to update transaction fingerprint: %w", err)
+		}
+	}
+	return nil
 }
 
 // DeleteTransaction soft deletes a transaction and all related data
 func (r *PostgreSQLRepository) DeleteTransaction(ctx context.Context, transactionID string) error {
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
 
 	// Use a database transaction to ensure atomicity
 	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// First, get all transaction entries for this transaction
-		var transactionEntries []models.TransactionEntry
-		if err := tx.Where("transaction_id = ?", transactionID).Find(&transactionEntries).Error; err != nil {
-			return fmt.Errorf("failed to get transaction entries for deletion: %w", err)
-		}
+		return deleteTransaction(tx, transactionID, time.Now().UTC())
+	})
+}
 
-		// Delete all TransactionEntryAmounts for each transaction entry
-		for _, entry := range transactionEntries {
-			if err := tx.Where("transaction_entry_id = ?", entry.ID).Delete(&models.TransactionEntryAmount{}).Error; err != nil {
-				return fmt.Errorf("failed to delete transaction entry amounts for entry %s: %w", entry.ID.String(), err)
-			}
-		}
+// deleteTransaction soft deletes a transaction with its active entries within the given database transaction. The
+// transaction and its entries get the same deletedAt, which tells a restore which entries were deleted with it.
+// Entry amounts are removed and regenerated on restore.
+func deleteTransaction(tx *gorm.DB, transactionID string, deletedAt time.Time) error {
+	before, err := auditTransaction(tx, transactionID)
+	if err != nil {
+		return err
+	}
 
-		// Soft delete all transaction entries
-		if err := tx.Where("transaction_id = ?", transactionID).Delete(&models.TransactionEntry{}).Error; err != nil {
-			return fmt.Errorf("failed to delete transaction entries: %w", err)
-		}
+	// First, get the active transaction entries of this transaction
+	var transactionEntries []models.TransactionEntry
+	if err := tx.Where("transaction_id = ? AND deleted_at IS NULL", transactionID).Find(&transactionEntries).Error; err != nil {
+		return fmt.Errorf("failed to get transaction entries for deletion: %w", err)
+	}
 
-		// Finally, soft delete the transaction
-		if err := tx.Where("id = ?", transactionID).Delete(&models.Transaction{}).Error; err != nil {
-			return fmt.Errorf("failed to delete transaction: %w", err)
+	// Delete all TransactionEntryAmounts for each transaction entry
+	for _, entry := range transactionEntries {
+		if err := tx.Where("transaction_entry_id = ?", entry.ID).Delete(&models.TransactionEntryAmount{}).Error; err != nil {
+			return fmt.Errorf("failed to delete transaction entry amounts for entry %s: %w", entry.ID.String(), err)
 		}
+	}
 
-		return nil
-	})
+	// Soft delete the active transaction entries
+	if err := tx.Model(&models.TransactionEntry{}).
+		Where("transaction_id = ? AND deleted_at IS NULL", transactionID).
+		Updates(map[string]interface{}{"deleted_at": deletedAt, "updated_at": deletedAt}).Error; err != nil {
+		return fmt.Errorf("failed to delete transaction entries: %w", err)
+	}
+
+	// Finally, soft delete the transaction
+	result := tx.Model(&models.Transaction{}).
+		Where("id = ? AND deleted_at IS NULL", transactionID).
+		Updates(map[string]interface{}{"deleted_at": deletedAt, "updated_at": deletedAt})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete transaction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return &models.NotFoundError{Entity: "transaction", ID: transactionID}
+	}
+
+	return recordAudit(tx, models.AuditActionDelete, before, nil)
 }
 
 // ListTransactions retrieves transaction entries based on the filter
@@ -365,18 +472,74 @@ func (r *PostgreSQLRepository) ListTransactions(ctx context.Context, filter mode
 
 // ListTransactionEntries retrieves transaction entries with all related data based on the filter
 func (r *PostgreSQLRepository) ListTransactionEntries(ctx context.Context, filter models.ListTransactionsInput) ([]models.TransactionEntry, error) {
+	if filter.Search != "" {
+		return r.searchTransactionEntries(ctx, filter)
+	}
+
 	var entries []models.TransactionEntry
 
-	db := r.getDB()
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := preloadTransactionEntryRelations(db.WithContext(ctx))
+
+	query = applyTransactionEntryFilters(query, filter)
+
+	// Apply sorting
+	orderBy := "transaction_entry.created_at"
+	sortKind := sortValueTime
+	if filter.SortBy != "" {
+		switch filter.SortBy {
+		case "transactedAt": // API uses camelCase, map to database field
+			orderBy = "transaction.transacted_at"
+		case "amount":
+			orderBy = "transaction_entry.amount"
+			sortKind = sortValueInt
+		case "createdAt": // API uses camelCase, map to database field
+			orderBy = "transaction_entry.created_at"
+		}
+	}
+
+	order := "DESC"
+	if filter.Order == "asc" {
+		order = "ASC"
+	}
+
+	// Order by the sort key with the entry ID as tie-breaker and continue after the cursor, if any
+	query, err = applyKeysetPagination(query, filter.Cursor, orderBy, sortKind, "transaction_entry.id", order)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply limit
+	limit := 50 // default limit
+	if filter.Limit > 0 && filter.Limit <= 100 {
+		limit = filter.Limit
+	}
+	query = query.Limit(limit)
 
-	query := db.WithContext(ctx).
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transaction entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// preloadTransactionEntryRelations loads the transaction, balance, merchant, category and amounts of listed entries
+func preloadTransactionEntryRelations(query *gorm.DB) *gorm.DB {
+	return query.
 		Preload("Transaction").
 		Preload("Transaction.Merchant", "deleted_at IS NULL"). // Only load non-deleted merchants
 		Preload("Transaction.Balance").
 		Preload("TransactionEntryAmounts"). // Load multi-currency amounts
 		Preload("Category").
 		Preload("Category.CategoryGroup") // Load CategoryGroup without filtering to detect soft-deleted groups
+}
 
+// applyTransactionEntryFilters joins the transaction and balance of the entries and applies the filters of the list
+func applyTransactionEntryFilters(query *gorm.DB, filter models.ListTransactionsInput) *gorm.DB {
 	// Always join with balance table (include both deleted and non-deleted balances)
 	query = query.Joins("JOIN transaction ON transaction_entry.transaction_id = transaction.id").
 		Joins("JOIN balance ON transaction.balance_id = balance.id")
@@ -449,36 +612,64 @@ func (r *PostgreSQLRepository) ListTransactionEntries(ctx context.Context, filte
 		query = query.Where("transaction_entry.deleted_at IS NULL")
 	}
 
-	// Apply sorting
-	orderBy := "transaction_entry.created_at"
-	if filter.SortBy != "" {
-		switch filter.SortBy {
-		case "transactedAt": // API uses camelCase, map to database field
-			orderBy = "transaction.transacted_at"
-		case "amount":
-			orderBy = "transaction_entry.amount"
-		case "createdAt": // API uses camelCase, map to database field
-			orderBy = "transaction_entry.created_at"
+	return applyTransactionEntryAttributeFilter(query, filter.TransactionEntryFilter, "transaction_entry", "transaction")
+}
+
+// applyTransactionEntryAttributeFilter applies the amount and attribute filters to a query of entries joined with
+// their transactions. Takes the names the query uses for both tables so that the transactions list and the stats
+// filter in the same way.
+func applyTransactionEntryAttributeFilter(query *gorm.DB, filter models.TransactionEntryFilter, entryTable, transactionTable string) *gorm.DB {
+	if filter.MinAmount != nil || filter.MaxAmount != nil {
+		if filter.AmountCurrency != "" {
+			// Compare the amount converted to the currency, entries without a conversion don't match
+			condition := fmt.Sprintf("EXISTS (SELECT 1 FROM transaction_entry_amount amount_filter WHERE amount_filter.transaction_entry_id = %s.id AND amount_filter.currency = ?", entryTable)
+			args := []interface{}{filter.AmountCurrency}
+			if filter.MinAmount != nil {
+				condition += " AND amount_filter.amount >= ?"
+				args = append(args, *filter.MinAmount)
+			}
+			if filter.MaxAmount != nil {
+				condition += " AND amount_filter.amount <= ?"
+				args = append(args, *filter.MaxAmount)
+			}
+			query = query.Where(condition+")", args...)
+		} else {
+			if filter.MinAmount != nil {
+				query = query.Where(entryTable+".amount >= ?", *filter.MinAmount)
+			}
+			if filter.MaxAmount != nil {
+				query = query.Where(entryTable+".amount <= ?", *filter.MaxAmount)
+			}
 		}
 	}
 
-	order := "DESC"
-	if filter.Order != "" && (filter.Order == "ASC" || filter.Order == "asc") {
-		order = "ASC"
+	if filter.HasMerchant != nil {
+		if *filter.HasMerchant {
+			query = query.Where(transactionTable + ".merchant_id IS NOT NULL")
+		} else {
+			query = query.Where(transactionTable + ".merchant_id IS NULL")
+		}
 	}
 
-	query = query.Order(fmt.Sprintf("%s %s", orderBy, order))
-
-	// Apply limit
-	limit := 50 // default limit
-	if filter.Limit > 0 && filter.Limit <= 100 {
-		limit = filter.Limit
+	if filter.HasCategory != nil {
+		if *filter.HasCategory {
+			query = query.Where(entryTable + ".category_id IS NOT NULL")
+		} else {
+			query = query.Where(entryTable + ".category_id IS NULL")
+		}
 	}
-	query = query.Limit(limit)
 
-	if err := query.Find(&entries).Error; err != nil {
-		return nil, fmt.Errorf("failed to list transaction entries: %w", err)
+	if filter.DescriptionContains != "" {
+		query = query.Where(entryTable+".description ILIKE ? ESCAPE '\\'", "%"+escapeLikePattern(filter.DescriptionContains)+"%")
 	}
 
-	return entries, nil
+	return query
+}
+
+// likePatternEscaper escapes the wildcards of LIKE patterns with backslash
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLikePattern escapes user input to be matched literally in a LIKE pattern
+func escapeLikePattern(value string) string {
+	return likePatternEscaper.Replace(value)
 }
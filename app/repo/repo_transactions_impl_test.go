@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"coffee", "coffee"},
+		{"50%", `50\%`},
+		{"snake_case", `snake\_case`},
+		{`C:\path`, `C:\\path`},
+		{`%_\`, `\%\_\\`},
+		{`\%`, `\\\%`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLikePattern(tt.value); got != tt.want {
+			t.Errorf("escapeLikePattern(%q) = %q, expected %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestApplyTransactionEntryAttributeFilter(t *testing.T) {
+	minAmount, maxAmount := int64(1000), int64(2500)
+	hasMerchant, hasCategory := true, false
+
+	tests := []struct {
+		name     string
+		filter   models.TransactionEntryFilter
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:    "no filter",
+			wantSQL: `SELECT * FROM "transaction_entry"`,
+		},
+		{
+			name:     "amount range",
+			filter:   models.TransactionEntryFilter{MinAmount: &minAmount, MaxAmount: &maxAmount},
+			wantSQL:  `SELECT * FROM "transaction_entry" WHERE transaction_entry.amount >= $1 AND transaction_entry.amount <= $2`,
+			wantVars: []interface{}{minAmount, maxAmount},
+		},
+		{
+			name:   "amount in currency",
+			filter: models.TransactionEntryFilter{MinAmount: &minAmount, AmountCurrency: "USD"},
+			wantSQL: `SELECT * FROM "transaction_entry" WHERE EXISTS (SELECT 1 FROM transaction_entry_amount amount_filter ` +
+				`WHERE amount_filter.transaction_entry_id = transaction_entry.id AND amount_filter.currency = $1 AND amount_filter.amount >= $2)`,
+			wantVars: []interface{}{"USD", minAmount},
+		},
+		{
+			name:    "merchant and category presence",
+			filter:  models.TransactionEntryFilter{HasMerchant: &hasMerchant, HasCategory: &hasCategory},
+			wantSQL: `SELECT * FROM "transaction_entry" WHERE transaction.merchant_id IS NOT NULL AND transaction_entry.category_id IS NULL`,
+		},
+		{
+			name:     "description with wildcards",
+			filter:   models.TransactionEntryFilter{DescriptionContains: `50%_off\`},
+			wantSQL:  `SELECT * FROM "transaction_entry" WHERE transaction_entry.description ILIKE $1 ESCAPE '\'`,
+			wantVars: []interface{}{`%50\%\_off\\%`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newDryRunDB(t)
+			stmt := applyTransactionEntryAttributeFilter(db.Model(&models.TransactionEntry{}), tt.filter, "transaction_entry", "transaction").
+				Find(&[]models.TransactionEntry{}).Statement
+
+			if got := stmt.SQL.String(); got != tt.wantSQL {
+				t.Errorf("Expected SQL\n%s\ngot\n%s", tt.wantSQL, got)
+			}
+			if len(stmt.Vars) != len(tt.wantVars) || (len(tt.wantVars) > 0 && !reflect.DeepEqual(stmt.Vars, tt.wantVars)) {
+				t.Errorf("Expected vars %v, got %v", tt.wantVars, stmt.Vars)
+			}
+		})
+	}
+}
+
+func TestCreateTransactionErrorReportsDuplicatesAsConflicts(t *testing.T) {
+	recurringID := uuid.New()
+	occurrence := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	occurrenceTx := models.Transaction{ID: models.NewTransactionID(), RecurringTransactionID: &recurringID, RecurringOccurrenceAt: &occurrence}
+
+	var conflict *models.ConflictError
+	if err := createTransactionError(occurrenceTx, fmt.Errorf("insert: %w", gorm.ErrDuplicatedKey)); !errors.As(err, &conflict) ||
+		!strings.Contains(conflict.Reason, recurringID.String()) {
+		t.Errorf("Expected a conflict on the occurrence of %s, got %v", recurringID, err)
+	}
+	if err := createTransactionError(models.Transaction{ID: models.NewTransactionID()}, gorm.ErrDuplicatedKey); !errors.As(err, &conflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	if err := createTransactionError(occurrenceTx, gorm.ErrForeignKeyViolated); errors.As(err, &conflict) || !errors.Is(err, gorm.ErrForeignKeyViolated) {
+		t.Errorf("Expected other errors to be wrapped unchanged, got %v", err)
+	}
+}
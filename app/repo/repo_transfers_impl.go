@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// CreateTransfer creates the legs of a transfer and the transfer linking them in a single database transaction
+func (r *PostgreSQLRepository) CreateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction) (*models.Transfer, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, leg := range legs {
+			if err := tx.Create(&leg).Error; err != nil {
+				return createTransactionError(leg, err)
+			}
+			if err := recordAudit(tx, models.AuditActionCreate, nil, &leg); err != nil {
+				return err
+			}
+		}
+		if err := auditedCreate(tx, &transfer); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return &models.ConflictError{Reason: "a leg of the transfer is already a leg of another transfer"}
+			}
+			return fmt.Errorf("failed to create transfer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetTransfer(ctx, transfer.ID.String())
+}
+
+// GetTransfer retrieves a transfer by ID with both legs, their balances and entries
+func (r *PostgreSQLRepository) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var transfer models.Transfer
+	if err := preloadTransferLegs(db.WithContext(ctx)).Where("id = ? AND deleted_at IS NULL", transferID).First(&transfer).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &models.NotFoundError{Entity: "transfer", ID: transferID}
+		}
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+	return &transfer, nil
+}
+
+// FindTransferByTransactionID retrieves the transfer the transaction is a leg of, nil if it isn't a transfer leg.
+// Deleted transfers are found too, so a leg never becomes a standalone transaction.
+func (r *PostgreSQLRepository) FindTransferByTransactionID(ctx context.Context, transactionID string) (*models.Transfer, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var transfers []models.Transfer
+	if err := db.WithContext(ctx).
+		Where("move_out_transaction_id = ? OR move_in_transaction_id = ?", transactionID, transactionID).
+		Limit(1).
+		Find(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("failed to find transfer of transaction: %w", err)
+	}
+	if len(transfers) == 0 {
+		return nil, nil
+	}
+	return &transfers[0], nil
+}
+
+// UpdateTransfer applies the changes of both legs of a transfer in a single database transaction
+func (r *PostgreSQLRepository) UpdateTransfer(ctx context.Context, transfer models.Transfer, legs []models.Transaction) (*models.Transfer, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, leg := range legs {
+			if err := updateTransaction(tx, leg); err != nil {
+				return fmt.Errorf("failed to update transfer leg %s: %w", leg.Type, err)
+			}
+		}
+		if _, err := auditedUpdate[models.Transfer](tx, transfer.ID, map[string]interface{}{"updated_at": gorm.Expr("NOW()")}, nil); err != nil {
+			return fmt.Errorf("failed to update transfer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetTransfer(ctx, transfer.ID.String())
+}
+
+// DeleteTransfer deletes a transfer and both of its legs in a single database transaction. All three share deletedAt,
+// so restoring either leg restores the transfer with both legs.
+func (r *PostgreSQLRepository) DeleteTransfer(ctx context.Context, transferID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var transfer models.Transfer
+		if err := tx.Where("id = ? AND deleted_at IS NULL", transferID).First(&transfer).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &models.NotFoundError{Entity: "transfer", ID: transferID}
+			}
+			return fmt.Errorf("failed to get transfer: %w", err)
+		}
+
+		deletedAt := time.Now().UTC()
+		if _, err := auditedSoftDelete[models.Transfer](tx, deletedAt, "id = ? AND deleted_at IS NULL", transferID); err != nil {
+			return fmt.Errorf("failed to delete transfer: %w", err)
+		}
+		for _, legID := range []string{transfer.MoveOutTransactionID.String(), transfer.MoveInTransactionID.String()} {
+			if err := deleteTransaction(tx, legID, deletedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// restoreTransfers reverses the soft delete of the transfers whose legs are among the restored transactions and
+// records each restore
+func restoreTransfers(tx *gorm.DB, transactionIDs []interface{}, restoredAt time.Time) error {
+	var transfers []models.Transfer
+	if err := tx.Where("(move_out_transaction_id IN ? OR move_in_transaction_id IN ?) AND deleted_at IS NOT NULL", transactionIDs, transactionIDs).
+		Find(&transfers).Error; err != nil {
+		return fmt.Errorf("failed to get deleted transfers: %w", err)
+	}
+
+	for i := range transfers {
+		before := transfers[i]
+		if err := tx.Model(&models.Transfer{}).
+			Where("id = ?", before.ID).
+			Updates(map[string]interface{}{"deleted_at": nil, "updated_at": restoredAt}).Error; err != nil {
+			return fmt.Errorf("failed to restore transfer %s: %w", before.ID.String(), err)
+		}
+
+		var after models.Transfer
+		if err := tx.Where("id = ?", before.ID).First(&after).Error; err != nil {
+			return fmt.Errorf("failed to reload restored transfer %s: %w", before.ID.String(), err)
+		}
+		if err := recordAudit(tx, models.AuditActionRestore, &before, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preloadTransferLegs loads both legs of transfers with their balances and entries
+func preloadTransferLegs(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("MoveOut").
+		Preload("MoveOut.Balance").
+		Preload("MoveOut.TransactionEntries", "deleted_at IS NULL").
+		Preload("MoveIn").
+		Preload("MoveIn.Balance").
+		Preload("MoveIn.TransactionEntries", "deleted_at IS NULL")
+}
@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"gorm.io/gorm"
+)
+
+// GetUserData retrieves all rows owned by the user, including soft-deleted ones
+func (r *PostgreSQLRepository) GetUserData(ctx context.Context, userID string) (*models.UserData, error) {
+	data := &models.UserData{UserID: userID}
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	db = db.WithContext(ctx)
+
+	queries := []struct {
+		Name  string
+		Query *gorm.DB
+		Dest  interface{}
+	}{
+		{"balances", db.Where("user_id = ?", userID).Order("created_at, id"), &data.Balances},
+		{"categories", db.Where("user_id = ?", userID).Order("created_at, id"), &data.Categories},
+		{"merchants", db.Where("user_id = ?", userID).Order("created_at, id"), &data.Merchants},
+		{"transactions", db.Where("user_id = ?", userID).Order("transacted_at, id"), &data.Transactions},
+		{"transaction entries", db.Joins("Transaction").Preload("Transaction.Balance").Preload("Transaction.Merchant").
+			Preload("Category").Preload("TransactionEntryAmounts").
+			Where(`"Transaction".user_id = ?`, userID).Order(`"Transaction".transacted_at, transaction_entry.id`), &data.TransactionEntries},
+		{"budgets", db.Where("user_id = ?", userID).Order("created_at, id"), &data.Budgets},
+		{"categorization rules", db.Where("user_id = ?", userID).Order("created_at, id"), &data.CategorizationRules},
+		{"recurring transactions", db.Where("user_id = ?", userID).Order("created_at, id"), &data.RecurringTransactions},
+		{"import profiles", db.Where("user_id = ?", userID).Order("created_at, id"), &data.ImportProfiles},
+		{"group memberships", db.Where("user_id = ?", userID).Order("created_at, group_id"), &data.GroupMemberships},
+	}
+	for _, q := range queries {
+		if err := q.Query.Find(q.Dest).Error; err != nil {
+			return nil, fmt.Errorf("failed to get %s of user %s: %w", q.Name, userID, err)
+		}
+	}
+	return data, nil
+}
+
+// Rows removed by a user data erasure: everything the user owns and groups created by the user without other
+// members. Balances of the user that other group members recorded transactions or recurring transactions on are
+// shared household data, they are kept with an anonymised owner together with the transactions of the other members.
+const (
+	eraseBalancesSQL = `SELECT id FROM balance WHERE user_id = @user
+		AND NOT EXISTS (SELECT 1 FROM transaction WHERE transaction.balance_id = balance.id AND transaction.user_id <> @user)
+		AND NOT EXISTS (SELECT 1 FROM recurring_transaction WHERE recurring_transaction.balance_id = balance.id AND recurring_transaction.user_id <> @user)`
+	eraseTransactionsSQL = `SELECT id FROM transaction WHERE user_id = @user`
+	eraseEntriesSQL      = `SELECT id FROM transaction_entry WHERE transaction_id IN (` + eraseTransactionsSQL + `)`
+	eraseGroupsSQL       = `SELECT id FROM user_group WHERE created_by = @user AND NOT EXISTS
+		(SELECT 1 FROM group_member WHERE group_member.group_id = user_group.id AND group_member.user_id <> @user)`
+)
+
+// eraseAnonymiseSteps replace the user in rows that are kept because they belong to a group: shared balances and the
+// audit history of group entities. The snapshots of those events hold the user's data and are removed, the events
+// only keep which entity changed when. They run before eraseUserSteps, which removes the user's remaining rows.
+var eraseAnonymiseSteps = []purgeStep{
+	{"balance", `UPDATE balance SET user_id = @erased WHERE user_id = @user AND id NOT IN (` + eraseBalancesSQL + `)`},
+	{"audit_event", `UPDATE audit_event SET user_id = @erased, before = NULL, after = NULL
+		WHERE user_id = @user AND group_id <> @noGroup`},
+	{"audit_event", `UPDATE audit_event SET entity_id = @erasedEntity WHERE entity_type = 'group_member' AND entity_id = @user`},
+	{"audit_event", `UPDATE audit_event SET actor = @erasedActor WHERE actor = @user`},
+}
+
+// eraseUserSteps delete the rows of a user in dependency order, including soft-deleted rows. Merchant references of
+// other users' transactions and category references of their entries are cleared before the steps.
+var eraseUserSteps = []purgeStep{
+	{"transaction_entry_amount", `DELETE FROM transaction_entry_amount WHERE transaction_entry_id IN (` + eraseEntriesSQL + `)`},
+	{"transaction_entry", `DELETE FROM transaction_entry WHERE id IN (` + eraseEntriesSQL + `)`},
+	{"transfer", `DELETE FROM transfer WHERE user_id = @user OR move_out_transaction_id IN (` + eraseTransactionsSQL + `)
+		OR move_in_transaction_id IN (` + eraseTransactionsSQL + `)`},
+	{"transaction", `DELETE FROM transaction WHERE id IN (` + eraseTransactionsSQL + `)`},
+	{"recurring_transaction", `DELETE FROM recurring_transaction WHERE user_id = @user`},
+	{"balance", `DELETE FROM balance WHERE id IN (` + eraseBalancesSQL + `)`},
+	{"merchant", `DELETE FROM merchant WHERE user_id = @user`},
+	{"category", `DELETE FROM category WHERE user_id = @user`},
+	{"budget", `DELETE FROM budget WHERE user_id = @user`},
+	{"categorization_rule", `DELETE FROM categorization_rule WHERE user_id = @user`},
+	{"import_profile", `DELETE FROM import_profile WHERE user_id = @user`},
+	{"idempotency_key", `DELETE FROM idempotency_key WHERE user_id = @user`},
+	{"group_invitation", `DELETE FROM group_invitation WHERE user_id = @user OR invited_by = @user
+		OR group_id IN (` + eraseGroupsSQL + `)`},
+	{"group_member", `DELETE FROM group_member WHERE user_id = @user OR group_id IN (` + eraseGroupsSQL + `)`},
+	{"user_group", `DELETE FROM user_group WHERE id IN (` + eraseGroupsSQL + `)`},
+	{"audit_event", `DELETE FROM audit_event WHERE user_id = @user`}, // Events of group entities were anonymised
+}
+
+// EraseUserData permanently deletes all data of the user in a single database transaction and returns the number
+// of rows removed per table. The audit trail of the user is replaced by a single erase event without personal data.
+func (r *PostgreSQLRepository) EraseUserData(ctx context.Context, userID string) ([]models.PurgedTableRowsDto, error) {
+	var erased []models.PurgedTableRowsDto
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, &models.ValidationError{Field: "userId", Reason: fmt.Sprintf("'%s' is not a valid UUID", userID)}
+	}
+	args := map[string]interface{}{
+		"user":         userID,
+		"noGroup":      uuid.Nil.String(),
+		"erased":       models.ErasedUserID.String(),
+		"erasedEntity": models.ErasedUserAuditID(id).String(),
+		"erasedActor":  models.AuditActorErased,
+	}
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Transactions and entries of other users are kept, only the references to erased merchants and categories are removed
+		if err := tx.Exec(`UPDATE transaction_entry SET category_id = NULL
+			WHERE category_id IN (SELECT id FROM category WHERE user_id = @user)`, args).Error; err != nil {
+			return fmt.Errorf("failed to unlink categories: %w", err)
+		}
+		if err := tx.Exec(`UPDATE transaction SET merchant_id = NULL
+			WHERE merchant_id IN (SELECT id FROM merchant WHERE user_id = @user)`, args).Error; err != nil {
+			return fmt.Errorf("failed to unlink merchants: %w", err)
+		}
+
+		if _, err := runPurgeSteps(tx, eraseAnonymiseSteps, args); err != nil {
+			return err
+		}
+
+		var err error
+		if erased, err = runPurgeSteps(tx, eraseUserSteps, args); err != nil {
+			return err
+		}
+		return recordAudit(tx, models.AuditActionErase, nil, &models.UserErasureReportDto{UserID: userID, Tables: erased})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase data of user %s: %w", userID, err)
+	}
+	return erased, nil
+}
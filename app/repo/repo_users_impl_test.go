@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// findStep returns the step of the table whose SQL starts with the given statement kind
+func findStep(t *testing.T, steps []purgeStep, table, kind string) purgeStep {
+	t.Helper()
+	for _, step := range steps {
+		if step.Table == table && strings.HasPrefix(step.SQL, kind) {
+			return step
+		}
+	}
+	t.Fatalf("Expected a %s step for %s", kind, table)
+	return purgeStep{}
+}
+
+func TestEraseUserKeepsSharedHouseholdData(t *testing.T) {
+	// Transactions of other members on the user's balances aren't erased, only the user's own ones
+	transactions := findStep(t, eraseUserSteps, "transaction", "DELETE")
+	if strings.Contains(transactions.SQL, "balance_id") {
+		t.Errorf("Expected transactions to be erased by user only, got %s", transactions.SQL)
+	}
+
+	// Balances with transactions of other members are anonymised instead of deleted
+	anonymised := findStep(t, eraseAnonymiseSteps, "balance", "UPDATE")
+	deleted := findStep(t, eraseUserSteps, "balance", "DELETE")
+	if !strings.Contains(anonymised.SQL, "NOT IN ("+eraseBalancesSQL+")") || !strings.Contains(deleted.SQL, "IN ("+eraseBalancesSQL+")") {
+		t.Errorf("Expected balances to be split by eraseBalancesSQL, got\n%s\n%s", anonymised.SQL, deleted.SQL)
+	}
+	if !strings.Contains(eraseBalancesSQL, "transaction.user_id <> @user") {
+		t.Errorf("Expected balances with transactions of other users to be kept, got %s", eraseBalancesSQL)
+	}
+
+	// Audit events of group entities are anonymised, only those without group are deleted
+	audit := findStep(t, eraseAnonymiseSteps, "audit_event", "UPDATE audit_event SET user_id")
+	if !strings.Contains(audit.SQL, "group_id <> @noGroup") {
+		t.Errorf("Expected audit events of group entities to be anonymised, got %s", audit.SQL)
+	}
+	// Their snapshots hold the data of the user and are removed
+	if !strings.Contains(audit.SQL, "before = NULL, after = NULL") {
+		t.Errorf("Expected the snapshots of anonymised audit events to be removed, got %s", audit.SQL)
+	}
+}
+
+func TestEraseUserStepsBindTheUser(t *testing.T) {
+	db := newDryRunDB(t)
+	var statements []string
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+
+	args := map[string]interface{}{"user": "user-id", "noGroup": "nil-id", "erased": "erased-id", "erasedEntity": "entity-id", "erasedActor": "erased"}
+	for _, steps := range [][]purgeStep{eraseAnonymiseSteps, eraseUserSteps} {
+		if _, err := runPurgeSteps(db, steps, args); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	for _, statement := range statements {
+		if strings.Contains(statement, "@") {
+			t.Errorf("Expected all named arguments to be bound, got %s", statement)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/awslabs/aws-lambda-go-api-proxy/gorillamux"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/service"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// purgeDeletedDataRuleSuffix identifies the EventBridge rule of the scheduled purge of soft-deleted data, all other
+// scheduled events run the recurring transactions job
+const purgeDeletedDataRuleSuffix = "-purge-deleted-data"
+
+// lambdaEventHandler routes EventBridge scheduled events (the recurring transactions and purge crons) to their jobs
+// and all other events to the API Gateway handler
+func lambdaEventHandler(adapter *gorillamux.GorillaMuxAdapter, svc service.Service, retentionDays int) func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	apiHandler := lambdaHandler(adapter)
+	return func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+		var scheduled events.CloudWatchEvent
+		if err := json.Unmarshal(payload, &scheduled); err == nil && scheduled.Source == "aws.events" && scheduled.DetailType == "Scheduled Event" {
+			// Scheduled events aren't authenticated requests, the job runs with the permissions of the system
+			ctx = auth.WithPrincipal(ctx, auth.SystemPrincipal())
+			for _, resource := range scheduled.Resources {
+				if strings.HasSuffix(resource, purgeDeletedDataRuleSuffix) {
+					return runPurgeDeletedDataJob(ctx, svc, models.PurgeDeletedDataInput{RetentionDays: retentionDays, Now: scheduled.Time})
+				}
+			}
+			return runRecurringTransactionsJob(ctx, svc, models.RunRecurringTransactionsInput{Now: scheduled.Time})
+		}
+
+		var event events.APIGatewayV2HTTPRequest
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode API Gateway event: %w", err)
+		}
+		return apiHandler(ctx, event)
+	}
+}
+
+// runRecurringTransactionsJob materializes due recurring transaction occurrences and logs the report
+func runRecurringTransactionsJob(ctx context.Context, svc service.Service, input models.RunRecurringTransactionsInput) (*models.RecurringTransactionsRunDto, error) {
+	report, err := svc.RunRecurringTransactions(ctx, input)
+	if err != nil {
+		log.WithError(err).Error("Failed to run recurring transactions")
+		return report, err
+	}
+
+	log.WithFields(log.Fields{
+		"run_at":               report.RunAt,
+		"dry_run":              report.DryRun,
+		"templates_processed":  report.TemplatesProcessed,
+		"transactions_created": report.TransactionsCreated,
+		"occurrences_skipped":  report.OccurrencesSkipped,
+		"templates_failed":     report.TemplatesFailed,
+	}).Info("Recurring transactions run finished")
+	return report, nil
+}
+
+// runPurgeDeletedDataJob permanently deletes soft-deleted data past the retention period, the service logs the report
+func runPurgeDeletedDataJob(ctx context.Context, svc service.Service, input models.PurgeDeletedDataInput) (*models.PurgeDeletedDataReportDto, error) {
+	report, err := svc.PurgeDeletedData(ctx, input)
+	if err != nil {
+		log.WithError(err).Error("Failed to purge deleted data")
+		return nil, err
+	}
+	return report, nil
+}
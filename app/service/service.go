@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	m "github.com/savak1990/transactions-service/app/models"
 )
@@ -15,9 +16,36 @@ type Service interface {
 	DeleteTransaction(ctx context.Context, transactionID string) error
 	ListTransactions(ctx context.Context, filter m.ListTransactionsInput) ([]m.Transaction, error)
 	ListTransactionEntries(ctx context.Context, filter m.ListTransactionsInput) ([]m.TransactionEntry, error)
+	ListDuplicateTransactions(ctx context.Context, filter m.ListDuplicateTransactionsInput) ([]m.DuplicateClusterDto, error)
+	ExportTransactionEntries(ctx context.Context, filter m.ListTransactionsInput, handle func([]m.TransactionEntry) error) error // Streams all matching entries page by page
+
+	// Restore soft-deleted entities
+	RestoreTransaction(ctx context.Context, transactionID string) (*m.SingleTransactionDto, error)
+	RestoreBalance(ctx context.Context, balanceID string) (*m.Balance, error)
+	RestoreCategory(ctx context.Context, categoryID string) (*m.Category, error)
+	RestoreCategoryGroup(ctx context.Context, categoryGroupID string) (*m.CategoryGroup, error)
+	RestoreMerchant(ctx context.Context, merchantID string) (*m.Merchant, error)
+
+	// Operations, transactions created together in a batch, all transactions are changed atomically
+	GetOperation(ctx context.Context, operationID string) ([]m.Transaction, error)
+	UpdateOperation(ctx context.Context, operationID string, transactions []m.Transaction) ([]m.Transaction, error)
+	DeleteOperation(ctx context.Context, operationID string) error
+
+	// Transfers between balances, both legs are changed atomically
+	CreateTransfer(ctx context.Context, input m.TransferInput) (*m.Transfer, error)
+	GetTransfer(ctx context.Context, transferID string) (*m.Transfer, error)
+	UpdateTransfer(ctx context.Context, transferID string, input m.TransferInput) (*m.Transfer, error)
+	DeleteTransfer(ctx context.Context, transferID string) error
+
+	// Idempotency keys of retried requests
+	BeginIdempotentRequest(ctx context.Context, key string, requestHash string) (*m.IdempotencyKey, error) // Returns the completed key to replay, nil to process the request
+	CompleteIdempotentRequest(ctx context.Context, key string, requestHash string, statusCode int, responseBody string) error
+	RenewIdempotentRequest(ctx context.Context, key string) error
+	ReleaseIdempotentRequest(ctx context.Context, key string, requestHash string) error
 
 	CreateBalance(ctx context.Context, balance m.Balance) (*m.Balance, error)
 	GetBalance(ctx context.Context, balanceID string) (*m.Balance, error)
+	GetBalanceWithAmounts(ctx context.Context, balanceID string, asOf *time.Time) (*m.Balance, error)
 	UpdateBalance(ctx context.Context, balance m.Balance) (*m.Balance, error)
 	DeleteBalance(ctx context.Context, balanceID string) error
 	DeleteBalancesByUserId(ctx context.Context, userId string) error
@@ -43,6 +71,70 @@ type Service interface {
 	DeleteMerchantsByUserId(ctx context.Context, userId string) error
 	ListMerchants(ctx context.Context, filter m.ListMerchantsInput) ([]m.Merchant, error)
 
+	// Budgets and spent-vs-limit reporting
+	CreateBudget(ctx context.Context, budget m.Budget) (*m.Budget, error)
+	GetBudget(ctx context.Context, budgetID string) (*m.Budget, error)
+	ListBudgets(ctx context.Context, filter m.ListBudgetsInput) ([]m.Budget, error)
+	UpdateBudget(ctx context.Context, budget m.Budget) (*m.Budget, error)
+	DeleteBudget(ctx context.Context, budgetID string) error
+	GetBudgetStatus(ctx context.Context, budgetID string, asOf *time.Time) (*m.BudgetStatusDto, error)
+
+	// Categorization rules applied to new and existing transaction entries
+	CreateCategorizationRule(ctx context.Context, rule m.CategorizationRule) (*m.CategorizationRule, error)
+	GetCategorizationRule(ctx context.Context, ruleID string) (*m.CategorizationRule, error)
+	ListCategorizationRules(ctx context.Context, filter m.ListCategorizationRulesInput) ([]m.CategorizationRule, error)
+	UpdateCategorizationRule(ctx context.Context, rule m.CategorizationRule) (*m.CategorizationRule, error)
+	DeleteCategorizationRule(ctx context.Context, ruleID string) error
+	ApplyCategorizationRules(ctx context.Context, input m.ApplyCategorizationRulesInput) (*m.CategorizationRulesReportDto, error)
+
+	// Category and merchant suggestions learned from past entries
+	GetSuggestions(ctx context.Context, input m.SuggestionsInput) (*m.SuggestionsDto, error)
+
+	// Recurring transactions and scheduled materialization of their occurrences
+	CreateRecurringTransaction(ctx context.Context, recurring m.RecurringTransaction) (*m.RecurringTransaction, error)
+	GetRecurringTransaction(ctx context.Context, recurringTransactionID string) (*m.RecurringTransaction, error)
+	ListRecurringTransactions(ctx context.Context, filter m.ListRecurringTransactionsInput) ([]m.RecurringTransaction, error)
+	UpdateRecurringTransaction(ctx context.Context, recurring m.RecurringTransaction) (*m.RecurringTransaction, error)
+	DeleteRecurringTransaction(ctx context.Context, recurringTransactionID string) error
+	RunRecurringTransactions(ctx context.Context, input m.RunRecurringTransactionsInput) (*m.RecurringTransactionsRunDto, error)
+
+	// Bank statement imports and their CSV column mappings
+	CreateImportProfile(ctx context.Context, profile m.ImportProfile) (*m.ImportProfile, error)
+	GetImportProfile(ctx context.Context, importProfileID string) (*m.ImportProfile, error)
+	ListImportProfiles(ctx context.Context, filter m.ListImportProfilesInput) ([]m.ImportProfile, error)
+	UpdateImportProfile(ctx context.Context, profile m.ImportProfile) (*m.ImportProfile, error)
+	DeleteImportProfile(ctx context.Context, importProfileID string) error
+	ImportTransactions(ctx context.Context, input m.ImportTransactionsInput) (*m.ImportReportDto, error)
+
+	// Groups, members and invitations
+	CreateGroup(ctx context.Context, group m.Group) (*m.Group, error)
+	ListGroups(ctx context.Context) ([]m.Group, error)
+	GetGroup(ctx context.Context, groupID string) (*m.Group, error)
+	UpdateGroup(ctx context.Context, group m.Group) (*m.Group, error)
+	DeleteGroup(ctx context.Context, groupID string) error
+	UpdateGroupMember(ctx context.Context, groupID string, userID string, role string) (*m.GroupMember, error)
+	RemoveGroupMember(ctx context.Context, groupID string, userID string) error
+	CreateGroupInvitation(ctx context.Context, invitation m.GroupInvitation) (*m.GroupInvitation, error)
+	ListGroupInvitations(ctx context.Context, groupID string) ([]m.GroupInvitation, error)
+	RevokeGroupInvitation(ctx context.Context, groupID string, invitationID string) error
+	ListMyGroupInvitations(ctx context.Context) ([]m.GroupInvitation, error)
+	AcceptGroupInvitation(ctx context.Context, invitationID string) (*m.GroupMember, error)
+	DeclineGroupInvitation(ctx context.Context, invitationID string) error
+
+	// Exchange-rate re-conversion of existing entry amounts
+	ReconvertEntryAmounts(ctx context.Context, input m.ReconvertAmountsInput, onProgress func(m.ReconvertAmountsReportDto)) (*m.ReconvertAmountsReportDto, error)
+
+	// Retention job permanently deleting soft-deleted data
+	PurgeDeletedData(ctx context.Context, input m.PurgeDeletedDataInput) (*m.PurgeDeletedDataReportDto, error)
+
+	// Export and erasure of all data of a user
+	ExportUserData(ctx context.Context, userID string) (*m.UserData, error)
+	EraseUserData(ctx context.Context, userID string) (*m.UserErasureReportDto, error)
+
+	// Audit log of entity mutations
+	ListAuditEvents(ctx context.Context, filter m.ListAuditEventsInput) ([]m.AuditEvent, error)
+	GetTransactionHistory(ctx context.Context, transactionID string) ([]m.AuditEvent, error)
+
 	// Transaction statistics
 	GetTransactionStats(ctx context.Context, filter m.TransactionStatsInput) ([]m.TransactionStatsItemDto, error)
 }
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultReconvertBatchSize = 500
+	maxReconvertBatchSize     = 1000
+)
+
+// exchangeRatesSnapshot holds the exchange rates effective for a base currency on a given day
+type exchangeRatesSnapshot struct {
+	supportedCurrencies []string
+	exchangeRates       map[string]float64
+	exchangeRateDate    *time.Time
+}
+
+// ReconvertEntryAmounts walks all non-deleted transaction entries in batches and fills in missing
+// transaction_entry_amount rows for supported currencies. With RepriceExisting set, existing amounts
+// are recomputed with the exchange rates effective at the transaction date as well.
+// The onProgress callback (optional) is invoked after every batch with the report so far. Only admins may run the job.
+func (s *ServiceImpl) ReconvertEntryAmounts(ctx context.Context, input models.ReconvertAmountsInput, onProgress func(models.ReconvertAmountsReportDto)) (*models.ReconvertAmountsReportDto, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	batchSize := defaultReconvertBatchSize
+	if input.BatchSize > 0 && input.BatchSize <= maxReconvertBatchSize {
+		batchSize = input.BatchSize
+	}
+
+	report := models.ReconvertAmountsReportDto{
+		DryRun:      input.DryRun,
+		LastEntryID: input.AfterEntryID,
+	}
+
+	// Rates are looked up once per base currency and transaction day
+	ratesCache := make(map[string]exchangeRatesSnapshot)
+
+	for input.MaxBatches <= 0 || report.Batches < input.MaxBatches {
+		if err := ctx.Err(); err != nil {
+			return &report, err
+		}
+
+		entries, err := s.repo.ListTransactionEntriesAfter(ctx, report.LastEntryID, batchSize)
+		if err != nil {
+			return &report, err
+		}
+		if len(entries) == 0 {
+			report.Completed = true
+			break
+		}
+
+		var amountsToSave []models.TransactionEntryAmount
+		for _, entry := range entries {
+			report.EntriesScanned++
+
+			if entry.Transaction == nil || entry.Transaction.Balance == nil {
+				logrus.WithField("transaction_entry_id", entry.ID.String()).Warn("Skipping entry without transaction or balance")
+				continue
+			}
+			baseCurrency := entry.Transaction.Balance.Currency
+			transactedAt := entry.Transaction.TransactedAt
+
+			cacheKey := baseCurrency + ":" + transactedAt.Format("2006-01-02")
+			snapshot, cached := ratesCache[cacheKey]
+			if !cached {
+				supportedCurrencies, exchangeRates, exchangeRateDate, err := s.getExchangeRatesAt(ctx, baseCurrency, transactedAt)
+				if err != nil {
+					return &report, err
+				}
+				snapshot = exchangeRatesSnapshot{supportedCurrencies, exchangeRates, exchangeRateDate}
+				ratesCache[cacheKey] = snapshot
+			}
+			if snapshot.exchangeRates == nil {
+				report.EntriesWithoutRates++
+			}
+
+			changes := s.diffEntryAmounts(entry, baseCurrency, snapshot, input.RepriceExisting, &report)
+			if len(changes) > 0 {
+				report.EntriesChanged++
+				amountsToSave = append(amountsToSave, changes...)
+			}
+		}
+
+		if !input.DryRun {
+			if err := s.repo.SaveTransactionEntryAmounts(ctx, amountsToSave); err != nil {
+				return &report, err
+			}
+		}
+
+		report.Batches++
+		report.LastEntryID = entries[len(entries)-1].ID.String()
+
+		logrus.WithFields(logrus.Fields{
+			"batch":           report.Batches,
+			"entries_scanned": report.EntriesScanned,
+			"entries_changed": report.EntriesChanged,
+			"amounts_created": report.AmountsCreated,
+			"amounts_updated": report.AmountsUpdated,
+			"last_entry_id":   report.LastEntryID,
+			"dry_run":         input.DryRun,
+		}).Info("Reconverted transaction entry amounts batch")
+
+		if onProgress != nil {
+			onProgress(report)
+		}
+
+		if len(entries) < batchSize {
+			report.Completed = true
+			break
+		}
+	}
+
+	return &report, nil
+}
+
+// diffEntryAmounts returns the amounts of the entry that are missing or, when repricing, differ from the expected ones
+func (s *ServiceImpl) diffEntryAmounts(entry models.TransactionEntry, baseCurrency string, snapshot exchangeRatesSnapshot, repriceExisting bool, report *models.ReconvertAmountsReportDto) []models.TransactionEntryAmount {
+	existingAmounts := make(map[string]models.TransactionEntryAmount, len(entry.TransactionEntryAmounts))
+	for _, amount := range entry.TransactionEntryAmounts {
+		existingAmounts[amount.Currency] = amount
+	}
+
+	expectedAmounts := s.createTransactionEntryAmounts(
+		entry.ID,
+		entry.Amount,
+		baseCurrency,
+		snapshot.supportedCurrencies,
+		snapshot.exchangeRates,
+		snapshot.exchangeRateDate)
+
+	var changes []models.TransactionEntryAmount
+	for _, expected := range expectedAmounts {
+		existing, exists := existingAmounts[expected.Currency]
+		if !exists {
+			report.AmountsCreated++
+			changes = append(changes, expected)
+			continue
+		}
+
+		// Stored rates have 6 decimal places, ignore differences below that precision
+		if repriceExisting && (existing.Amount != expected.Amount || math.Abs(existing.ExchangeRate-expected.ExchangeRate) >= 1e-6) {
+			expected.CreatedAt = existing.CreatedAt
+			report.AmountsUpdated++
+			changes = append(changes, expected)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/repo"
+	"github.com/stretchr/testify/mock"
+)
+
+// reconversionEntries builds entries of EUR balances without converted amounts
+func reconversionEntries(count int) []models.TransactionEntry {
+	entries := make([]models.TransactionEntry, count)
+	for i := range entries {
+		entries[i] = models.TransactionEntry{
+			ID:     models.NewTransactionEntryID(),
+			Amount: int64(1000 * (i + 1)),
+			Transaction: &models.Transaction{
+				TransactedAt: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+				Balance:      &models.Balance{Currency: "EUR"},
+			},
+		}
+	}
+	return entries
+}
+
+// runReconversion runs the job over a single batch of entries and returns the report and the saved amounts
+func runReconversion(t *testing.T, entries []models.TransactionEntry, input models.ReconvertAmountsInput) (*models.ReconvertAmountsReportDto, []models.TransactionEntryAmount, *repo.MockRepository) {
+	t.Helper()
+	svc, mockRepo := newTestService()
+	mockRepo.On("ListTransactionEntriesAfter", mock.Anything, input.AfterEntryID, defaultReconvertBatchSize).Return(entries, nil)
+	var saved []models.TransactionEntryAmount
+	mockRepo.On("SaveTransactionEntryAmounts", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { saved = append(saved, args.Get(1).([]models.TransactionEntryAmount)...) }).
+		Return(nil)
+
+	admin := auth.WithPrincipal(context.Background(), auth.SystemPrincipal())
+	report, err := svc.ReconvertEntryAmounts(admin, input, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return report, saved, mockRepo
+}
+
+func TestReconvertEntryAmountsIsIdempotent(t *testing.T) {
+	entries := reconversionEntries(2)
+
+	first, saved, _ := runReconversion(t, entries, models.ReconvertAmountsInput{})
+	if !first.Completed || first.EntriesChanged != 2 || first.AmountsCreated == 0 || int(first.AmountsCreated) != len(saved) {
+		t.Fatalf("Expected missing amounts of both entries to be created, got %+v with %d saved", first, len(saved))
+	}
+
+	// A second run over the converted entries, also when repricing with the same rates, has nothing left to do
+	for i := range entries {
+		for _, amount := range saved {
+			if amount.TransactionEntryID == entries[i].ID {
+				entries[i].TransactionEntryAmounts = append(entries[i].TransactionEntryAmounts, amount)
+			}
+		}
+	}
+	for _, input := range []models.ReconvertAmountsInput{{}, {RepriceExisting: true}} {
+		again, savedAgain, _ := runReconversion(t, entries, input)
+		if again.EntriesChanged != 0 || again.AmountsCreated != 0 || again.AmountsUpdated != 0 || len(savedAgain) != 0 {
+			t.Errorf("Expected no changes on a second run with %+v, got %+v with %d saved", input, again, len(savedAgain))
+		}
+		if again.EntriesScanned != 2 || !again.Completed {
+			t.Errorf("Expected all entries to be scanned again, got %+v", again)
+		}
+	}
+}
+
+func TestReconvertEntryAmountsRepricesChangedAmounts(t *testing.T) {
+	entries := reconversionEntries(1)
+	_, saved, _ := runReconversion(t, entries, models.ReconvertAmountsInput{})
+	stale := saved[0]
+	stale.Amount++
+	entries[0].TransactionEntryAmounts = append([]models.TransactionEntryAmount{stale}, saved[1:]...)
+
+	report, repriced, _ := runReconversion(t, entries, models.ReconvertAmountsInput{RepriceExisting: true})
+	if report.AmountsUpdated != 1 || report.AmountsCreated != 0 || len(repriced) != 1 || repriced[0].Amount != saved[0].Amount {
+		t.Errorf("Expected only the stale amount to be repriced, got %+v with %v", report, repriced)
+	}
+}
+
+func TestReconvertEntryAmountsDryRun(t *testing.T) {
+	afterEntryID := uuid.New().String()
+	report, _, mockRepo := runReconversion(t, reconversionEntries(2), models.ReconvertAmountsInput{AfterEntryID: afterEntryID, DryRun: true})
+
+	mockRepo.AssertCalled(t, "ListTransactionEntriesAfter", mock.Anything, afterEntryID, defaultReconvertBatchSize)
+	mockRepo.AssertNotCalled(t, "SaveTransactionEntryAmounts", mock.Anything, mock.Anything)
+	if !report.DryRun || report.EntriesChanged != 2 || report.AmountsCreated == 0 {
+		t.Errorf("Expected the changes to be reported without saving, got %+v", report)
+	}
+}
+
+func TestReconvertEntryAmountsRequiresAdmin(t *testing.T) {
+	svc, mockRepo := newTestService()
+
+	_, err := svc.ReconvertEntryAmounts(principalContext(uuid.New()), models.ReconvertAmountsInput{}, nil)
+	assertForbidden(t, err)
+	mockRepo.AssertNotCalled(t, "ListTransactionEntriesAfter", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// ListAuditEvents retrieves the audit events of an entity. The caller needs read access to the owner of every
+// event; events of shared data without owner, such as category groups, are readable by every caller.
+func (s *ServiceImpl) ListAuditEvents(ctx context.Context, filter models.ListAuditEventsInput) ([]models.AuditEvent, error) {
+	if _, err := auth.RequirePrincipal(ctx); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.ListAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAuditEvents(ctx, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetTransactionHistory retrieves the audit events of a transaction, including those of a deleted transaction
+func (s *ServiceImpl) GetTransactionHistory(ctx context.Context, transactionID string) ([]models.AuditEvent, error) {
+	events, err := s.ListAuditEvents(ctx, models.ListAuditEventsInput{EntityID: transactionID, EntityType: "transaction"})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	// Transactions created before the audit log have no history, an unknown transaction is reported as not found
+	tx, err := s.repo.GetTransactionIncludingDeleted(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "transaction", tx.UserID, tx.GroupID, accessRead); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// authorizeAuditEvents checks read access to the owners of the events, each distinct owner is checked once
+func (s *ServiceImpl) authorizeAuditEvents(ctx context.Context, events []models.AuditEvent) error {
+	type owner struct{ userID, groupID uuid.UUID }
+	checked := make(map[owner]bool)
+	for _, event := range events {
+		o := owner{event.UserID, event.GroupID}
+		if checked[o] || (o.userID == uuid.Nil && o.groupID == uuid.Nil) {
+			continue
+		}
+		if err := s.authorizeAccess(ctx, event.EntityType, o.userID, o.groupID, accessRead); err != nil {
+			return err
+		}
+		checked[o] = true
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// accessLevel is the kind of access an operation needs on group data
+type accessLevel int
+
+const (
+	accessRead   accessLevel = iota // Listing and reading data, allowed to every group role
+	accessWrite                     // Creating, updating and deleting data, allowed to owners and editors
+	accessManage                    // Managing the group itself, its members and invitations, allowed to owners only
+)
+
+// authorizeAccess checks that the caller may access data owned by the given user within the given group.
+// For registered groups the membership role decides: viewers may only read, editors and owners may also write.
+// Data of groups without a group record predates group management and stays shared with every member listed in
+// the token, writing it requires the group in the token so that no data is created in groups of others. Owners of
+// data keep read access to it after leaving the group.
+func (s *ServiceImpl) authorizeAccess(ctx context.Context, entity string, userID, groupID uuid.UUID, level accessLevel) error {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.Unrestricted() {
+		return nil
+	}
+
+	group, err := s.findGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		if level != accessRead && groupID != uuid.Nil && !principal.HasGroup(groupID) {
+			return &models.ForbiddenError{Reason: fmt.Sprintf("%s belongs to a group the caller is not a member of", entity)}
+		}
+		if !principal.CanAccess(userID, groupID) {
+			return &models.ForbiddenError{Reason: fmt.Sprintf("%s belongs to another user or group", entity)}
+		}
+		return nil
+	}
+
+	if level == accessRead && principal.UserID == userID {
+		return nil
+	}
+	return authorizeGroupRole(principal, group, entity, level)
+}
+
+// authorizeGroupRole checks that the caller's role in the group grants the given access
+func authorizeGroupRole(principal *auth.Principal, group *models.Group, entity string, level accessLevel) error {
+	if principal.Unrestricted() {
+		return nil
+	}
+
+	role := memberRole(group, principal.UserID)
+	switch {
+	case role == "":
+		return &models.ForbiddenError{Reason: fmt.Sprintf("%s belongs to another user or group", entity)}
+	case level == accessWrite && role == models.GroupRoleViewer:
+		return &models.ForbiddenError{Reason: fmt.Sprintf("viewers have read-only access to %s data", entity)}
+	case level == accessManage && role != models.GroupRoleOwner:
+		return &models.ForbiddenError{Reason: "only group owners can manage the group"}
+	}
+	return nil
+}
+
+// findGroup retrieves the registered group with the given ID or nil if there is no group record
+func (s *ServiceImpl) findGroup(ctx context.Context, groupID uuid.UUID) (*models.Group, error) {
+	if groupID == uuid.Nil {
+		return nil, nil
+	}
+	return s.repo.FindGroup(ctx, groupID.String())
+}
+
+// memberRole returns the role of the user in the group or an empty string if the user is not a member
+func memberRole(group *models.Group, userID uuid.UUID) string {
+	for _, member := range group.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// authorizeAdmin checks that the caller may run admin jobs
+func (s *ServiceImpl) authorizeAdmin(ctx context.Context) error {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if !principal.Unrestricted() {
+		return &models.ForbiddenError{Reason: "admin role is required"}
+	}
+	return nil
+}
+
+// authorizeUser checks that the caller acts on behalf of the given user, used by bulk operations per user
+func (s *ServiceImpl) authorizeUser(ctx context.Context, userID string) error {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.Unrestricted() || principal.UserID.String() == userID {
+		return nil
+	}
+	return &models.ForbiddenError{Reason: fmt.Sprintf("cannot act on behalf of user %s", userID)}
+}
+
+// scopeListFilter validates the userId/groupId filter of a list request against the caller and returns the
+// effective filter. Without any filter the listing is scoped to the caller's own data. A filter is allowed when
+// it names the caller's user or a group the caller is a member of; the repository combines both conditions with AND.
+func (s *ServiceImpl) scopeListFilter(ctx context.Context, userID, groupID string) (string, string, error) {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if principal.Unrestricted() {
+		return userID, groupID, nil
+	}
+
+	if userID == "" && groupID == "" {
+		return principal.UserID.String(), "", nil
+	}
+	if userID != "" && userID == principal.UserID.String() {
+		return userID, groupID, nil
+	}
+	if groupID != "" {
+		if id, err := uuid.Parse(groupID); err == nil {
+			group, err := s.findGroup(ctx, id)
+			if err != nil {
+				return "", "", err
+			}
+			if (group != nil && memberRole(group, principal.UserID) != "") || (group == nil && principal.HasGroup(id)) {
+				return userID, groupID, nil
+			}
+		}
+	}
+
+	return "", "", &models.ForbiddenError{Reason: "listing data of another user or group is not allowed"}
+}
+
+// getAccessibleBalance retrieves a balance and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleBalance(ctx context.Context, balanceID string, level accessLevel) (*models.Balance, error) {
+	balance, err := s.repo.GetBalance(ctx, balanceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "balance", balance.UserID, balance.GroupID, level); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// getAccessibleCategory retrieves a category and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleCategory(ctx context.Context, categoryID string, level accessLevel) (*models.Category, error) {
+	category, err := s.repo.GetCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "category", category.UserId, category.GroupId, level); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// getAccessibleMerchant retrieves a merchant and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleMerchant(ctx context.Context, merchantID string, level accessLevel) (*models.Merchant, error) {
+	merchant, err := s.repo.GetMerchant(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "merchant", merchant.UserID, merchant.GroupID, level); err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// getAccessibleTransaction retrieves a transaction and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleTransaction(ctx context.Context, transactionID string, level accessLevel) (*models.Transaction, error) {
+	tx, err := s.repo.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "transaction", tx.UserID, tx.GroupID, level); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorizeAccess(t *testing.T) {
+	callerID := uuid.New()
+	otherUserID := uuid.New()
+	legacyGroupID := uuid.New()  // Group listed in the token without a group record
+	foreignGroupID := uuid.New() // Group the caller doesn't belong to, without a group record
+	viewerGroupID := uuid.New()
+	editorGroupID := uuid.New()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		userID  uuid.UUID
+		groupID uuid.UUID
+		level   accessLevel
+		wantErr bool
+	}{
+		{"own data without group", principalContext(callerID), callerID, uuid.Nil, accessWrite, false},
+		{"other user's data without group", principalContext(callerID), otherUserID, uuid.Nil, accessRead, true},
+		{"legacy token group", principalContext(callerID, legacyGroupID), otherUserID, legacyGroupID, accessWrite, false},
+		{"foreign group", principalContext(callerID, legacyGroupID), otherUserID, foreignGroupID, accessRead, true},
+		{"own data in foreign group", principalContext(callerID, legacyGroupID), callerID, foreignGroupID, accessRead, false},
+		{"writes own data in foreign group", principalContext(callerID, legacyGroupID), callerID, foreignGroupID, accessWrite, true},
+		{"viewer reads group data", principalContext(callerID), otherUserID, viewerGroupID, accessRead, false},
+		{"viewer writes group data", principalContext(callerID), otherUserID, viewerGroupID, accessWrite, true},
+		{"viewer writes own data in group", principalContext(callerID), callerID, viewerGroupID, accessWrite, true},
+		{"editor writes group data", principalContext(callerID), otherUserID, editorGroupID, accessWrite, false},
+		{"former member reads own data", principalContext(otherUserID), otherUserID, editorGroupID, accessRead, false},
+		{"former member writes own data", principalContext(otherUserID), otherUserID, editorGroupID, accessWrite, true},
+		{"admin", auth.WithPrincipal(context.Background(), &auth.Principal{UserID: callerID, Admin: true}), otherUserID, foreignGroupID, accessWrite, false},
+		{"system", auth.WithPrincipal(context.Background(), auth.SystemPrincipal()), otherUserID, foreignGroupID, accessWrite, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo := newTestService()
+			mockRepo.On("FindGroup", mock.Anything, legacyGroupID.String()).Return(nil, nil)
+			mockRepo.On("FindGroup", mock.Anything, foreignGroupID.String()).Return(nil, nil)
+			mockRepo.On("FindGroup", mock.Anything, viewerGroupID.String()).
+				Return(testGroup(viewerGroupID, map[uuid.UUID]string{callerID: models.GroupRoleViewer}), nil)
+			mockRepo.On("FindGroup", mock.Anything, editorGroupID.String()).
+				Return(testGroup(editorGroupID, map[uuid.UUID]string{callerID: models.GroupRoleEditor}), nil)
+
+			err := svc.authorizeAccess(tt.ctx, "balance", tt.userID, tt.groupID, tt.level)
+			if tt.wantErr {
+				assertForbidden(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected access, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateInForeignGroupIsForbidden(t *testing.T) {
+	callerID := uuid.New()
+	foreignGroupID := uuid.New() // Unregistered group that isn't in the caller's token
+
+	svc, mockRepo := newTestService()
+	mockRepo.On("FindGroup", mock.Anything, foreignGroupID.String()).Return(nil, nil)
+
+	_, err := svc.CreateBalance(principalContext(callerID), models.Balance{UserID: callerID, GroupID: foreignGroupID, Currency: "EUR"})
+	assertForbidden(t, err)
+	mockRepo.AssertNotCalled(t, "CreateBalance", mock.Anything, mock.Anything)
+}
+
+func TestAuthorizeAccessWithoutPrincipal(t *testing.T) {
+	svc, _ := newTestService()
+
+	err := svc.authorizeAccess(context.Background(), "balance", uuid.New(), uuid.Nil, accessRead)
+	if !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("Expected ErrUnauthenticated, got %v", err)
+	}
+}
+
+func TestScopeListFilter(t *testing.T) {
+	callerID := uuid.New()
+	otherUserID := uuid.New()
+	memberGroupID := uuid.New()
+	legacyGroupID := uuid.New()
+	foreignGroupID := uuid.New()
+	foreignLegacyGroupID := uuid.New()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userID    string
+		groupID   string
+		wantUser  string
+		wantGroup string
+		wantErr   bool
+	}{
+		{"no filter is scoped to the caller", principalContext(callerID), "", "", callerID.String(), "", false},
+		{"own user", principalContext(callerID), callerID.String(), "", callerID.String(), "", false},
+		{"own user within a foreign group", principalContext(callerID), callerID.String(), foreignGroupID.String(), callerID.String(), foreignGroupID.String(), false},
+		{"member group", principalContext(callerID), "", memberGroupID.String(), "", memberGroupID.String(), false},
+		{"member group and another user", principalContext(callerID), otherUserID.String(), memberGroupID.String(), otherUserID.String(), memberGroupID.String(), false},
+		{"legacy token group", principalContext(callerID, legacyGroupID), "", legacyGroupID.String(), "", legacyGroupID.String(), false},
+		{"foreign group", principalContext(callerID), "", foreignGroupID.String(), "", "", true},
+		{"foreign legacy group", principalContext(callerID, legacyGroupID), "", foreignLegacyGroupID.String(), "", "", true},
+		{"another user", principalContext(callerID), otherUserID.String(), "", "", "", true},
+		{"invalid group ID", principalContext(callerID), "", "not-a-uuid", "", "", true},
+		{"admin keeps the filter", auth.WithPrincipal(context.Background(), &auth.Principal{UserID: callerID, Admin: true}), "", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo := newTestService()
+			mockRepo.On("FindGroup", mock.Anything, memberGroupID.String()).
+				Return(testGroup(memberGroupID, map[uuid.UUID]string{callerID: models.GroupRoleViewer}), nil)
+			mockRepo.On("FindGroup", mock.Anything, foreignGroupID.String()).
+				Return(testGroup(foreignGroupID, map[uuid.UUID]string{otherUserID: models.GroupRoleOwner}), nil)
+			mockRepo.On("FindGroup", mock.Anything, legacyGroupID.String()).Return(nil, nil)
+			mockRepo.On("FindGroup", mock.Anything, foreignLegacyGroupID.String()).Return(nil, nil)
+
+			userID, groupID, err := svc.scopeListFilter(tt.ctx, tt.userID, tt.groupID)
+			if tt.wantErr {
+				assertForbidden(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if userID != tt.wantUser || groupID != tt.wantGroup {
+				t.Errorf("Expected filter (%s, %s), got (%s, %s)", tt.wantUser, tt.wantGroup, userID, groupID)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// ErrInvalidBudgetScope is returned when the category, category group or merchant of a budget doesn't exist
+var ErrInvalidBudgetScope error = &models.ValidationError{Reason: "invalid budget scope"}
+
+func (s *ServiceImpl) CreateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	if err := s.authorizeAccess(ctx, "budget", budget.UserID, budget.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.checkBudgetScope(ctx, budget); err != nil {
+		return nil, err
+	}
+	budget.ID = models.NewBudgetID()
+	return s.repo.CreateBudget(ctx, budget)
+}
+
+func (s *ServiceImpl) GetBudget(ctx context.Context, budgetID string) (*models.Budget, error) {
+	return s.getAccessibleBudget(ctx, budgetID, accessRead)
+}
+
+func (s *ServiceImpl) ListBudgets(ctx context.Context, filter models.ListBudgetsInput) ([]models.Budget, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListBudgets(ctx, filter)
+}
+
+func (s *ServiceImpl) UpdateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	if _, err := s.getAccessibleBudget(ctx, budget.ID.String(), accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "budget", budget.UserID, budget.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.checkBudgetScope(ctx, budget); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateBudget(ctx, budget)
+}
+
+func (s *ServiceImpl) DeleteBudget(ctx context.Context, budgetID string) error {
+	if _, err := s.getAccessibleBudget(ctx, budgetID, accessWrite); err != nil {
+		return err
+	}
+	return s.repo.DeleteBudget(ctx, budgetID)
+}
+
+// GetBudgetStatus reports the expenses of the budget period containing asOf (nil means now) in the budget currency
+func (s *ServiceImpl) GetBudgetStatus(ctx context.Context, budgetID string, asOf *time.Time) (*models.BudgetStatusDto, error) {
+	budget, err := s.getAccessibleBudget(ctx, budgetID, accessRead)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if asOf != nil {
+		now = asOf.UTC()
+	}
+	periodStart, periodEnd := budget.PeriodAt(now)
+
+	// Only expenses transacted up to asOf count, the stats end time is inclusive
+	endTime := now
+	if !now.Before(periodEnd) {
+		endTime = periodEnd.Add(-time.Microsecond)
+	}
+
+	filter := models.TransactionStatsInput{
+		Type:            "expense",
+		GroupID:         budget.GroupID.String(),
+		Grouping:        models.GroupingCurrency,
+		DisplayCurrency: budget.Currency,
+		StartTime:       periodStart,
+		EndTime:         &endTime,
+	}
+	switch budget.ScopeType {
+	case models.BudgetScopeCategory:
+		filter.CategoryId = []string{budget.ScopeID.String()}
+	case models.BudgetScopeCategoryGroup:
+		filter.CategoryGroupId = []string{budget.ScopeID.String()}
+	case models.BudgetScopeMerchant:
+		filter.MerchantId = []string{budget.ScopeID.String()}
+	}
+
+	stats, err := s.repo.GetTransactionStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget spending: %w", err)
+	}
+
+	var spent int64
+	transactionsCount := 0
+	for _, item := range stats {
+		spent += int64(item.Amount)
+		transactionsCount += item.Count
+	}
+
+	status := models.NewBudgetStatus(budget, periodStart, periodEnd, now, spent, transactionsCount)
+	return &status, nil
+}
+
+// checkBudgetScope checks that the category, category group or merchant of the budget exists and is accessible
+func (s *ServiceImpl) checkBudgetScope(ctx context.Context, budget models.Budget) error {
+	if budget.ScopeID == nil {
+		return nil
+	}
+
+	var err error
+	switch budget.ScopeType {
+	case models.BudgetScopeCategory:
+		_, err = s.getAccessibleCategory(ctx, budget.ScopeID.String(), accessRead)
+	case models.BudgetScopeCategoryGroup:
+		var categoryGroup *models.CategoryGroup
+		if categoryGroup, err = s.repo.GetCategoryGroup(ctx, budget.ScopeID.String()); err == nil && categoryGroup == nil {
+			err = &models.NotFoundError{Entity: "category group", ID: budget.ScopeID.String()}
+		}
+	case models.BudgetScopeMerchant:
+		_, err = s.getAccessibleMerchant(ctx, budget.ScopeID.String(), accessRead)
+	}
+	if models.IsNotFound(err) {
+		return fmt.Errorf("%w: %v", ErrInvalidBudgetScope, err)
+	}
+	return err
+}
+
+// getAccessibleBudget retrieves a budget and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleBudget(ctx context.Context, budgetID string, level accessLevel) (*models.Budget, error) {
+	budget, err := s.repo.GetBudget(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "budget", budget.UserID, budget.GroupID, level); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+const (
+	// maxCategorizationEntries bounds the entries scanned by a single application of categorization rules, larger
+	// sets have to be split by time range or balance
+	maxCategorizationEntries = 5000
+
+	// categorizationPageSize is the number of entries loaded per page while applying categorization rules
+	categorizationPageSize = 100
+)
+
+// categorizationOwner is the user and group owning a transaction changed by categorization rules
+type categorizationOwner struct {
+	userID  uuid.UUID
+	groupID uuid.UUID
+}
+
+func (s *ServiceImpl) CreateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	if err := s.authorizeAccess(ctx, "categorization rule", rule.UserID, rule.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategorizationRuleReferences(ctx, rule); err != nil {
+		return nil, err
+	}
+	rule.ID = models.NewCategorizationRuleID()
+	return s.repo.CreateCategorizationRule(ctx, rule)
+}
+
+func (s *ServiceImpl) GetCategorizationRule(ctx context.Context, ruleID string) (*models.CategorizationRule, error) {
+	return s.getAccessibleCategorizationRule(ctx, ruleID, accessRead)
+}
+
+func (s *ServiceImpl) ListCategorizationRules(ctx context.Context, filter models.ListCategorizationRulesInput) ([]models.CategorizationRule, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListCategorizationRules(ctx, filter)
+}
+
+func (s *ServiceImpl) UpdateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	if _, err := s.getAccessibleCategorizationRule(ctx, rule.ID.String(), accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "categorization rule", rule.UserID, rule.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategorizationRuleReferences(ctx, rule); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateCategorizationRule(ctx, rule)
+}
+
+func (s *ServiceImpl) DeleteCategorizationRule(ctx context.Context, ruleID string) error {
+	if _, err := s.getAccessibleCategorizationRule(ctx, ruleID, accessWrite); err != nil {
+		return err
+	}
+	return s.repo.DeleteCategorizationRule(ctx, ruleID)
+}
+
+// ApplyCategorizationRules applies the enabled rules of a group to its existing income and expense entries. Entries
+// with a category are skipped unless IncludeCategorized is set, in which case rules also replace categories and
+// merchants. Preview reports the changes without storing them. At most maxCategorizationEntries entries are scanned,
+// the report is marked truncated if there are more.
+func (s *ServiceImpl) ApplyCategorizationRules(ctx context.Context, input models.ApplyCategorizationRulesInput) (*models.CategorizationRulesReportDto, error) {
+	var err error
+	if input.UserID, input.GroupID, err = s.scopeListFilter(ctx, input.UserID, input.GroupID); err != nil {
+		return nil, err
+	}
+	if input.GroupID == "" {
+		return nil, &models.ValidationError{Field: "groupId", Reason: "is required"}
+	}
+
+	rules, err := s.repo.ListCategorizationRules(ctx, models.ListCategorizationRulesInput{GroupID: input.GroupID})
+	if err != nil {
+		return nil, err
+	}
+	if len(input.RuleIDs) > 0 {
+		rules, err = selectCategorizationRules(rules, input.RuleIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ruleSet, err := models.NewCategorizationRuleSet(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CategorizationRulesReportDto{
+		Preview: input.Preview,
+		Changes: []models.CategorizationChangeDto{},
+	}
+	if ruleSet.Empty() {
+		return report, nil
+	}
+
+	filter := models.ListTransactionsInput{
+		GroupID:    input.GroupID,
+		UserID:     input.UserID,
+		BalanceIds: input.BalanceIDs,
+		Types:      models.CategorizedTransactionTypes,
+		StartTime:  input.StartTime,
+		EndTime:    input.EndTime,
+		SortBy:     "createdAt",
+		Order:      "asc",
+		Limit:      categorizationPageSize,
+	}
+	if !input.IncludeCategorized {
+		hasCategory := false
+		filter.HasCategory = &hasCategory
+	}
+
+	var changes []models.CategorizationChange
+	merchants := map[uuid.UUID]*uuid.UUID{}  // Merchants set by earlier changes, by transaction ID
+	owners := map[categorizationOwner]bool{} // Owners of the changed transactions
+	for {
+		entries, err := s.repo.ListTransactionEntries(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			if report.EntriesScanned == maxCategorizationEntries {
+				report.Truncated = true
+				break
+			}
+			report.EntriesScanned++
+
+			entry := &entries[i]
+			tx := entry.Transaction
+			if tx == nil {
+				continue
+			}
+			if merchantID, ok := merchants[tx.ID]; ok {
+				tx.MerchantID = merchantID
+			}
+			rule := ruleSet.Match(tx, entry)
+			if rule == nil {
+				continue
+			}
+			change, ok := rule.Change(tx, entry, input.IncludeCategorized)
+			if !ok {
+				continue
+			}
+			if change.MerchantID != nil {
+				merchants[tx.ID] = change.MerchantID
+			}
+			owners[categorizationOwner{tx.UserID, tx.GroupID}] = true
+			changes = append(changes, change)
+		}
+		if len(entries) < filter.Limit || report.Truncated {
+			break
+		}
+		last := entries[len(entries)-1]
+		cursor := models.NewPageCursor(filter.SortBy, filter.Order, last.CreatedAt, last.ID)
+		filter.Cursor = &cursor
+	}
+
+	report.EntriesChanged = len(changes)
+	for _, change := range changes {
+		report.Changes = append(report.Changes, models.ToAPICategorizationChange(change))
+	}
+	if input.Preview || len(changes) == 0 {
+		return report, nil
+	}
+
+	// A group reader can preview the changes of the rules but not apply them
+	for owner := range owners {
+		if err := s.authorizeAccess(ctx, "transaction", owner.userID, owner.groupID, accessWrite); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.ApplyCategorizationChanges(ctx, changes); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// categorizationRules loads the enabled categorization rules of a group for new transactions
+func (s *ServiceImpl) categorizationRules(ctx context.Context, groupID uuid.UUID) (*models.CategorizationRuleSet, error) {
+	rules, err := s.repo.ListCategorizationRules(ctx, models.ListCategorizationRulesInput{GroupID: groupID.String()})
+	if err != nil {
+		return nil, err
+	}
+	return models.NewCategorizationRuleSet(rules)
+}
+
+// selectCategorizationRules returns the rules with the given IDs, every ID must be an enabled rule of the group
+func selectCategorizationRules(rules []models.CategorizationRule, ruleIDs []string) ([]models.CategorizationRule, error) {
+	var selected []models.CategorizationRule
+	for i, ruleID := range ruleIDs {
+		index := slices.IndexFunc(rules, func(rule models.CategorizationRule) bool { return rule.ID.String() == ruleID })
+		if index < 0 {
+			return nil, &models.ValidationError{
+				Field:  fmt.Sprintf("ruleIds[%d]", i),
+				Reason: fmt.Sprintf("references categorization rule %s that doesn't exist or is disabled", ruleID),
+			}
+		}
+		selected = append(selected, rules[index])
+	}
+	return selected, nil
+}
+
+// checkCategorizationRuleReferences checks that the merchants, balance and category of the rule exist and are
+// accessible
+func (s *ServiceImpl) checkCategorizationRuleReferences(ctx context.Context, rule models.CategorizationRule) error {
+	if rule.MerchantID != nil {
+		if _, err := s.getAccessibleMerchant(ctx, rule.MerchantID.String(), accessRead); err != nil {
+			return referenceError(err, "conditions.merchantId")
+		}
+	}
+	if rule.BalanceID != nil {
+		if _, err := s.getAccessibleBalance(ctx, rule.BalanceID.String(), accessRead); err != nil {
+			return referenceError(err, "conditions.balanceId")
+		}
+	}
+	if rule.SetCategoryID != nil {
+		if _, err := s.getAccessibleCategory(ctx, rule.SetCategoryID.String(), accessRead); err != nil {
+			return referenceError(err, "actions.categoryId")
+		}
+	}
+	if rule.SetMerchantID != nil {
+		if _, err := s.getAccessibleMerchant(ctx, rule.SetMerchantID.String(), accessRead); err != nil {
+			return referenceError(err, "actions.merchantId")
+		}
+	}
+	return nil
+}
+
+// getAccessibleCategorizationRule retrieves a categorization rule and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleCategorizationRule(ctx context.Context, ruleID string, level accessLevel) (*models.CategorizationRule, error) {
+	rule, err := s.repo.GetCategorizationRule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "categorization rule", rule.UserID, rule.GroupID, level); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxDuplicateWindow bounds the window of the duplicates listing, larger windows cluster regular payments
+const maxDuplicateWindow = 31 * 24 * time.Hour
+
+// ListDuplicateTransactions lists clusters of transactions with the same balance, type, amount and merchant whose
+// transaction dates are within the window of each other, oldest cluster first
+func (s *ServiceImpl) ListDuplicateTransactions(ctx context.Context, filter models.ListDuplicateTransactionsInput) ([]models.DuplicateClusterDto, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
+	if filter.Window <= 0 {
+		filter.Window = models.DefaultDuplicateWindow
+	}
+	filter.Window = min(filter.Window, maxDuplicateWindow)
+
+	candidates, err := s.repo.ListDuplicateCandidates(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := models.ClusterDuplicates(candidates, filter.Window)
+	clusterDtos := make([]models.DuplicateClusterDto, len(clusters))
+	for i, cluster := range clusters {
+		clusterDtos[i] = models.ToAPIDuplicateCluster(cluster)
+	}
+	return clusterDtos, nil
+}
+
+// setPossibleDuplicate sets the duplicate hint of a created transaction. The hint is best effort, failing to
+// compute it doesn't fail the creation.
+func (s *ServiceImpl) setPossibleDuplicate(ctx context.Context, created *models.Transaction) {
+	duplicateID, err := s.repo.FindPossibleDuplicate(ctx, *created, models.DefaultDuplicateWindow)
+	if err != nil {
+		log.WithError(err).WithField("transaction_id", created.ID.String()).Warn("Failed to check for duplicate transactions")
+		return
+	}
+	if duplicateID == "" {
+		return
+	}
+	if id, err := uuid.Parse(duplicateID); err == nil {
+		created.PossibleDuplicateOf = &id
+	}
+}
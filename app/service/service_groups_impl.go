@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// groupInvitationTTL is how long an invitation can be accepted or declined
+const groupInvitationTTL = 14 * 24 * time.Hour
+
+// Conflicts of group operations, the handler maps them to 409 Conflict
+var (
+	ErrGroupExists             error = &models.ConflictError{Reason: "group already exists"}
+	ErrLastGroupOwner          error = &models.ConflictError{Reason: "group must keep at least one owner"}
+	ErrAlreadyGroupMember      error = &models.ConflictError{Reason: "user is already a member of the group"}
+	ErrPendingInvitationExists error = &models.ConflictError{Reason: "user already has a pending invitation to the group"}
+	ErrInvitationNotPending    error = &models.ConflictError{Reason: "invitation is no longer pending"}
+)
+
+// CreateGroup creates a group with the caller as its owner. A group ID may be given to register a group that
+// existing data already references, the caller must then be a member of that group in the token.
+func (s *ServiceImpl) CreateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if principal.UserID == uuid.Nil {
+		return nil, &models.ForbiddenError{Reason: "groups can only be created on behalf of a user"}
+	}
+
+	if group.ID != uuid.Nil {
+		if !principal.Unrestricted() && !principal.HasGroup(group.ID) {
+			return nil, &models.ForbiddenError{Reason: fmt.Sprintf("cannot register group %s", group.ID)}
+		}
+		existing, err := s.repo.FindGroup(ctx, group.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("%w: %s", ErrGroupExists, group.ID)
+		}
+	} else {
+		group.ID = models.NewGroupID()
+	}
+
+	group.CreatedBy = principal.UserID
+	group.Members = []models.GroupMember{{
+		GroupID: group.ID,
+		UserID:  principal.UserID,
+		Role:    models.GroupRoleOwner,
+	}}
+	return s.repo.CreateGroup(ctx, group)
+}
+
+// ListGroups lists the groups the caller is a member of
+func (s *ServiceImpl) ListGroups(ctx context.Context) ([]models.Group, error) {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListGroupsByMember(ctx, principal.UserID.String())
+}
+
+func (s *ServiceImpl) GetGroup(ctx context.Context, groupID string) (*models.Group, error) {
+	return s.getAccessibleGroup(ctx, groupID, accessRead)
+}
+
+func (s *ServiceImpl) UpdateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	if _, err := s.getAccessibleGroup(ctx, group.ID.String(), accessManage); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateGroup(ctx, group)
+}
+
+func (s *ServiceImpl) DeleteGroup(ctx context.Context, groupID string) error {
+	if _, err := s.getAccessibleGroup(ctx, groupID, accessManage); err != nil {
+		return err
+	}
+	return s.repo.DeleteGroup(ctx, groupID)
+}
+
+// UpdateGroupMember changes the role of an existing member, the group must keep at least one owner
+func (s *ServiceImpl) UpdateGroupMember(ctx context.Context, groupID string, userID string, role string) (*models.GroupMember, error) {
+	if !models.IsValidGroupRole(role) {
+		return nil, &models.ValidationError{Field: "role", Reason: fmt.Sprintf("'%s' is invalid, must be one of: owner, editor, viewer", role)}
+	}
+
+	group, err := s.getAccessibleGroup(ctx, groupID, accessManage)
+	if err != nil {
+		return nil, err
+	}
+
+	member := findGroupMember(group, userID)
+	if member == nil {
+		return nil, &models.NotFoundError{Entity: "group member", ID: userID}
+	}
+	if member.Role == models.GroupRoleOwner && role != models.GroupRoleOwner && countGroupOwners(group) == 1 {
+		return nil, ErrLastGroupOwner
+	}
+
+	member.Role = role
+	return s.repo.SaveGroupMember(ctx, *member)
+}
+
+// RemoveGroupMember removes a member from a group. Owners can remove anyone, other members can only leave.
+func (s *ServiceImpl) RemoveGroupMember(ctx context.Context, groupID string, userID string) error {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	level := accessManage
+	if principal.UserID.String() == userID {
+		level = accessRead
+	}
+	group, err := s.getAccessibleGroup(ctx, groupID, level)
+	if err != nil {
+		return err
+	}
+
+	member := findGroupMember(group, userID)
+	if member == nil {
+		return &models.NotFoundError{Entity: "group member", ID: userID}
+	}
+	if member.Role == models.GroupRoleOwner && countGroupOwners(group) == 1 {
+		return ErrLastGroupOwner
+	}
+
+	return s.repo.DeleteGroupMember(ctx, groupID, userID)
+}
+
+// CreateGroupInvitation invites a user to join a group with the given role
+func (s *ServiceImpl) CreateGroupInvitation(ctx context.Context, invitation models.GroupInvitation) (*models.GroupInvitation, error) {
+	if !models.IsValidGroupRole(invitation.Role) {
+		return nil, &models.ValidationError{Field: "role", Reason: fmt.Sprintf("'%s' is invalid, must be one of: owner, editor, viewer", invitation.Role)}
+	}
+
+	group, err := s.getAccessibleGroup(ctx, invitation.GroupID.String(), accessManage)
+	if err != nil {
+		return nil, err
+	}
+	if findGroupMember(group, invitation.UserID.String()) != nil {
+		return nil, ErrAlreadyGroupMember
+	}
+
+	pending, err := s.repo.ListGroupInvitations(ctx, models.ListGroupInvitationsInput{
+		GroupID:    invitation.GroupID.String(),
+		UserID:     invitation.UserID.String(),
+		Status:     models.InvitationStatusPending,
+		ActiveOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		return nil, ErrPendingInvitationExists
+	}
+
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation.ID = models.NewGroupInvitationID()
+	invitation.Status = models.InvitationStatusPending
+	invitation.InvitedBy = principal.UserID
+	invitation.ExpiresAt = time.Now().UTC().Add(groupInvitationTTL)
+	return s.repo.CreateGroupInvitation(ctx, invitation)
+}
+
+// ListGroupInvitations lists all invitations of a group
+func (s *ServiceImpl) ListGroupInvitations(ctx context.Context, groupID string) ([]models.GroupInvitation, error) {
+	if _, err := s.getAccessibleGroup(ctx, groupID, accessManage); err != nil {
+		return nil, err
+	}
+	return s.repo.ListGroupInvitations(ctx, models.ListGroupInvitationsInput{GroupID: groupID})
+}
+
+// RevokeGroupInvitation revokes a pending invitation of a group
+func (s *ServiceImpl) RevokeGroupInvitation(ctx context.Context, groupID string, invitationID string) error {
+	if _, err := s.getAccessibleGroup(ctx, groupID, accessManage); err != nil {
+		return err
+	}
+
+	invitation, err := s.repo.GetGroupInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.GroupID.String() != groupID {
+		return &models.NotFoundError{Entity: "group invitation", ID: invitationID}
+	}
+	if invitation.Status != models.InvitationStatusPending {
+		return ErrInvitationNotPending
+	}
+
+	return s.repo.UpdateGroupInvitationStatus(ctx, invitationID, models.InvitationStatusRevoked)
+}
+
+// ListMyGroupInvitations lists the pending invitations of the caller
+func (s *ServiceImpl) ListMyGroupInvitations(ctx context.Context) ([]models.GroupInvitation, error) {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListGroupInvitations(ctx, models.ListGroupInvitationsInput{
+		UserID:     principal.UserID.String(),
+		Status:     models.InvitationStatusPending,
+		ActiveOnly: true,
+	})
+}
+
+// AcceptGroupInvitation accepts an invitation of the caller and adds the caller to the group with the invited role
+func (s *ServiceImpl) AcceptGroupInvitation(ctx context.Context, invitationID string) (*models.GroupMember, error) {
+	invitation, err := s.getOwnPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.AcceptGroupInvitation(ctx, invitationID, models.GroupMember{
+		GroupID: invitation.GroupID,
+		UserID:  invitation.UserID,
+		Role:    invitation.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// DeclineGroupInvitation declines an invitation of the caller
+func (s *ServiceImpl) DeclineGroupInvitation(ctx context.Context, invitationID string) error {
+	if _, err := s.getOwnPendingInvitation(ctx, invitationID); err != nil {
+		return err
+	}
+	return s.repo.UpdateGroupInvitationStatus(ctx, invitationID, models.InvitationStatusDeclined)
+}
+
+// getOwnPendingInvitation retrieves an invitation addressed to the caller that can still be answered
+func (s *ServiceImpl) getOwnPendingInvitation(ctx context.Context, invitationID string) (*models.GroupInvitation, error) {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.repo.GetGroupInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.UserID != principal.UserID {
+		// Invitations of other users are not disclosed
+		return nil, &models.NotFoundError{Entity: "group invitation", ID: invitationID}
+	}
+	if !invitation.IsPending(time.Now().UTC()) || invitation.Group == nil || invitation.Group.DeletedAt != nil {
+		return nil, ErrInvitationNotPending
+	}
+	return invitation, nil
+}
+
+// getAccessibleGroup retrieves a group and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleGroup(ctx context.Context, groupID string, level accessLevel) (*models.Group, error) {
+	group, err := s.repo.FindGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, &models.NotFoundError{Entity: "group", ID: groupID}
+	}
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeGroupRole(principal, group, "group", level); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func findGroupMember(group *models.Group, userID string) *models.GroupMember {
+	for i := range group.Members {
+		if group.Members[i].UserID.String() == userID {
+			return &group.Members[i]
+		}
+	}
+	return nil
+}
+
+func countGroupOwners(group *models.Group) int {
+	owners := 0
+	for _, member := range group.Members {
+		if member.Role == models.GroupRoleOwner {
+			owners++
+		}
+	}
+	return owners
+}
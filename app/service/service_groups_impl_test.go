@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorizeGroupRole(t *testing.T) {
+	groupID := uuid.New()
+	ownerID := uuid.New()
+	editorID := uuid.New()
+	viewerID := uuid.New()
+	outsiderID := uuid.New()
+	group := testGroup(groupID, map[uuid.UUID]string{
+		ownerID:  models.GroupRoleOwner,
+		editorID: models.GroupRoleEditor,
+		viewerID: models.GroupRoleViewer,
+	})
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		allowed map[accessLevel]bool
+	}{
+		{"owner", ownerID, map[accessLevel]bool{accessRead: true, accessWrite: true, accessManage: true}},
+		{"editor", editorID, map[accessLevel]bool{accessRead: true, accessWrite: true, accessManage: false}},
+		{"viewer", viewerID, map[accessLevel]bool{accessRead: true, accessWrite: false, accessManage: false}},
+		{"non-member", outsiderID, map[accessLevel]bool{accessRead: false, accessWrite: false, accessManage: false}},
+	}
+
+	levelNames := map[accessLevel]string{accessRead: "read", accessWrite: "write", accessManage: "manage"}
+	for _, tt := range tests {
+		for level, allowed := range tt.allowed {
+			t.Run(tt.name+" "+levelNames[level], func(t *testing.T) {
+				err := authorizeGroupRole(&auth.Principal{UserID: tt.userID}, group, "balance", level)
+				if allowed {
+					if err != nil {
+						t.Fatalf("Expected access, got %v", err)
+					}
+					return
+				}
+				assertForbidden(t, err)
+			})
+		}
+	}
+}
+
+func TestViewerCannotWriteGroupData(t *testing.T) {
+	svc, mockRepo := newTestService()
+	groupID := uuid.New()
+	viewerID := uuid.New()
+	ctx := principalContext(viewerID, groupID)
+	mockRepo.On("FindGroup", mock.Anything, groupID.String()).
+		Return(testGroup(groupID, map[uuid.UUID]string{viewerID: models.GroupRoleViewer}), nil)
+
+	// The token claim doesn't grant write access once the group is registered
+	_, err := svc.CreateBalance(ctx, models.Balance{UserID: viewerID, GroupID: groupID, Title: "Savings", Currency: "EUR"})
+	assertForbidden(t, err)
+	mockRepo.AssertNotCalled(t, "CreateBalance", mock.Anything, mock.Anything)
+}
+
+func TestGroupManagementRequiresOwner(t *testing.T) {
+	groupID := uuid.New()
+	ownerID := uuid.New()
+	editorID := uuid.New()
+	group := testGroup(groupID, map[uuid.UUID]string{ownerID: models.GroupRoleOwner, editorID: models.GroupRoleEditor})
+
+	svc, mockRepo := newTestService()
+	mockRepo.On("FindGroup", mock.Anything, groupID.String()).Return(group, nil)
+	ctx := principalContext(editorID)
+
+	_, err := svc.UpdateGroup(ctx, models.Group{ID: groupID, Name: "Renamed"})
+	assertForbidden(t, err)
+	assertForbidden(t, svc.DeleteGroup(ctx, groupID.String()))
+	_, err = svc.UpdateGroupMember(ctx, groupID.String(), ownerID.String(), models.GroupRoleViewer)
+	assertForbidden(t, err)
+	assertForbidden(t, svc.RemoveGroupMember(ctx, groupID.String(), ownerID.String()))
+	_, err = svc.CreateGroupInvitation(ctx, models.GroupInvitation{GroupID: groupID, UserID: uuid.New(), Role: models.GroupRoleViewer})
+	assertForbidden(t, err)
+	_, err = svc.ListGroupInvitations(ctx, groupID.String())
+	assertForbidden(t, err)
+
+	// Members can still read the group and leave it
+	if _, err := svc.GetGroup(ctx, groupID.String()); err != nil {
+		t.Fatalf("Expected editor to read the group, got %v", err)
+	}
+	mockRepo.On("DeleteGroupMember", mock.Anything, groupID.String(), editorID.String()).Return(nil)
+	if err := svc.RemoveGroupMember(ctx, groupID.String(), editorID.String()); err != nil {
+		t.Fatalf("Expected editor to leave the group, got %v", err)
+	}
+
+	// Owners manage the group
+	mockRepo.On("SaveGroupMember", mock.Anything, mock.AnythingOfType("models.GroupMember")).
+		Return(&models.GroupMember{GroupID: groupID, UserID: editorID, Role: models.GroupRoleViewer}, nil)
+	member, err := svc.UpdateGroupMember(principalContext(ownerID), groupID.String(), editorID.String(), models.GroupRoleViewer)
+	if err != nil {
+		t.Fatalf("Expected owner to change roles, got %v", err)
+	}
+	if member.Role != models.GroupRoleViewer {
+		t.Errorf("Expected role %s, got %s", models.GroupRoleViewer, member.Role)
+	}
+}
+
+func TestInvalidGroupRoleIsRejected(t *testing.T) {
+	svc, mockRepo := newTestService()
+	groupID := uuid.New()
+	ctx := principalContext(uuid.New())
+
+	_, err := svc.UpdateGroupMember(ctx, groupID.String(), uuid.New().String(), "admin")
+	assertValidationError(t, err)
+	_, err = svc.CreateGroupInvitation(ctx, models.GroupInvitation{GroupID: groupID, UserID: uuid.New(), Role: "admin"})
+	assertValidationError(t, err)
+	mockRepo.AssertNotCalled(t, "FindGroup", mock.Anything, mock.Anything)
+}
+
+func TestLastGroupOwnerIsProtected(t *testing.T) {
+	groupID := uuid.New()
+	ownerID := uuid.New()
+	editorID := uuid.New()
+
+	t.Run("demotion", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("FindGroup", mock.Anything, groupID.String()).
+			Return(testGroup(groupID, map[uuid.UUID]string{ownerID: models.GroupRoleOwner, editorID: models.GroupRoleEditor}), nil)
+
+		_, err := svc.UpdateGroupMember(principalContext(ownerID), groupID.String(), ownerID.String(), models.GroupRoleEditor)
+		if !errors.Is(err, ErrLastGroupOwner) {
+			t.Fatalf("Expected ErrLastGroupOwner, got %v", err)
+		}
+		mockRepo.AssertNotCalled(t, "SaveGroupMember", mock.Anything, mock.Anything)
+	})
+
+	t.Run("removal", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("FindGroup", mock.Anything, groupID.String()).
+			Return(testGroup(groupID, map[uuid.UUID]string{ownerID: models.GroupRoleOwner, editorID: models.GroupRoleEditor}), nil)
+
+		err := svc.RemoveGroupMember(principalContext(ownerID), groupID.String(), ownerID.String())
+		if !errors.Is(err, ErrLastGroupOwner) {
+			t.Fatalf("Expected ErrLastGroupOwner, got %v", err)
+		}
+		mockRepo.AssertNotCalled(t, "DeleteGroupMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("another owner remains", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("FindGroup", mock.Anything, groupID.String()).
+			Return(testGroup(groupID, map[uuid.UUID]string{ownerID: models.GroupRoleOwner, editorID: models.GroupRoleOwner}), nil)
+		mockRepo.On("DeleteGroupMember", mock.Anything, groupID.String(), ownerID.String()).Return(nil)
+
+		if err := svc.RemoveGroupMember(principalContext(ownerID), groupID.String(), ownerID.String()); err != nil {
+			t.Fatalf("Expected owner to leave while another owner remains, got %v", err)
+		}
+	})
+}
+
+func TestAnswerGroupInvitation(t *testing.T) {
+	groupID := uuid.New()
+	inviteeID := uuid.New()
+	invitationID := uuid.New()
+
+	invitation := func(status string, expiresAt time.Time) *models.GroupInvitation {
+		return &models.GroupInvitation{
+			ID:        invitationID,
+			GroupID:   groupID,
+			UserID:    inviteeID,
+			Role:      models.GroupRoleEditor,
+			Status:    status,
+			ExpiresAt: expiresAt,
+			Group:     &models.Group{ID: groupID},
+		}
+	}
+	future := time.Now().UTC().Add(time.Hour)
+
+	t.Run("accept", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetGroupInvitation", mock.Anything, invitationID.String()).
+			Return(invitation(models.InvitationStatusPending, future), nil)
+		expected := models.GroupMember{GroupID: groupID, UserID: inviteeID, Role: models.GroupRoleEditor}
+		mockRepo.On("AcceptGroupInvitation", mock.Anything, invitationID.String(), expected).Return(&expected, nil)
+
+		member, err := svc.AcceptGroupInvitation(principalContext(inviteeID), invitationID.String())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if member.Role != models.GroupRoleEditor {
+			t.Errorf("Expected invited role %s, got %s", models.GroupRoleEditor, member.Role)
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("decline", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetGroupInvitation", mock.Anything, invitationID.String()).
+			Return(invitation(models.InvitationStatusPending, future), nil)
+		mockRepo.On("UpdateGroupInvitationStatus", mock.Anything, invitationID.String(), models.InvitationStatusDeclined).Return(nil)
+
+		if err := svc.DeclineGroupInvitation(principalContext(inviteeID), invitationID.String()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invitation of another user", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetGroupInvitation", mock.Anything, invitationID.String()).
+			Return(invitation(models.InvitationStatusPending, future), nil)
+
+		_, err := svc.AcceptGroupInvitation(principalContext(uuid.New()), invitationID.String())
+		if !models.IsNotFound(err) {
+			t.Fatalf("Expected NotFoundError, got %v", err)
+		}
+	})
+
+	for name, answered := range map[string]*models.GroupInvitation{
+		"already declined": invitation(models.InvitationStatusDeclined, future),
+		"expired":          invitation(models.InvitationStatusPending, time.Now().UTC().Add(-time.Hour)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, mockRepo := newTestService()
+			mockRepo.On("GetGroupInvitation", mock.Anything, invitationID.String()).Return(answered, nil)
+
+			if _, err := svc.AcceptGroupInvitation(principalContext(inviteeID), invitationID.String()); !errors.Is(err, ErrInvitationNotPending) {
+				t.Fatalf("Expected ErrInvitationNotPending on accept, got %v", err)
+			}
+			if err := svc.DeclineGroupInvitation(principalContext(inviteeID), invitationID.String()); !errors.Is(err, ErrInvitationNotPending) {
+				t.Fatalf("Expected ErrInvitationNotPending on decline, got %v", err)
+			}
+			mockRepo.AssertNotCalled(t, "AcceptGroupInvitation", mock.Anything, mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "UpdateGroupInvitationStatus", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+const (
+	// idempotencyKeyTTL is how long the response of a request is replayed for retries with the same key
+	idempotencyKeyTTL = 24 * time.Hour
+
+	// idempotencyLockTimeout is how long a key stays claimed by a request that stopped renewing it without completing
+	// (e.g. the Lambda timed out), a retry after that processes the request again
+	idempotencyLockTimeout = time.Minute
+
+	// IdempotencyLockRenewInterval is how often a request still being processed renews the claim of its key, well
+	// within idempotencyLockTimeout so that slow requests are never taken over by a retry
+	IdempotencyLockRenewInterval = idempotencyLockTimeout / 3
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request
+	ErrIdempotencyKeyReused error = &models.ConflictError{Reason: "idempotency key was already used for a different request"}
+	// ErrIdempotencyKeyInProgress is returned when a request with the same idempotency key is still being processed
+	ErrIdempotencyKeyInProgress error = &models.ConflictError{Reason: "a request with this idempotency key is still in progress"}
+)
+
+// BeginIdempotentRequest claims an idempotency key of the caller for a request. It returns the stored key when the
+// same request was already completed, its response has to be replayed, and nil when the request has to be processed
+// and then completed with CompleteIdempotentRequest or released with ReleaseIdempotentRequest.
+func (s *ServiceImpl) BeginIdempotentRequest(ctx context.Context, key string, requestHash string) (*models.IdempotencyKey, error) {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	record := models.IdempotencyKey{
+		UserID:      principal.UserID,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(idempotencyKeyTTL),
+	}
+
+	// A second attempt is needed when the key is released or taken over by another retry in the meantime
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := s.repo.ClaimIdempotencyKey(ctx, record)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		existing, err := s.repo.FindIdempotencyKey(ctx, principal.UserID.String(), key)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case existing == nil:
+			// Released by the request holding it in the meantime
+			continue
+		case existing.ExpiresAt.Before(now), existing.StatusCode == 0 && existing.UpdatedAt.Before(now.Add(-idempotencyLockTimeout)):
+			// The takeover re-checks the same conditions atomically, so only one of concurrent retries claims the key
+			taken, err := s.repo.TakeOverIdempotencyKey(ctx, record, now, now.Add(-idempotencyLockTimeout))
+			if err != nil {
+				return nil, err
+			}
+			if taken {
+				return nil, nil
+			}
+			continue
+		case existing.RequestHash != requestHash:
+			return nil, ErrIdempotencyKeyReused
+		case existing.StatusCode == 0:
+			return nil, ErrIdempotencyKeyInProgress
+		default:
+			return existing, nil
+		}
+	}
+	return nil, ErrIdempotencyKeyInProgress
+}
+
+// CompleteIdempotentRequest stores the response of a request whose idempotency key was claimed by BeginIdempotentRequest
+func (s *ServiceImpl) CompleteIdempotentRequest(ctx context.Context, key string, requestHash string, statusCode int, responseBody string) error {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	return s.repo.CompleteIdempotencyKey(ctx, models.IdempotencyKey{
+		UserID:       principal.UserID,
+		Key:          key,
+		RequestHash:  requestHash,
+		StatusCode:   statusCode,
+		ResponseBody: responseBody,
+	})
+}
+
+// RenewIdempotentRequest keeps the idempotency key of a request that is still being processed claimed
+func (s *ServiceImpl) RenewIdempotentRequest(ctx context.Context, key string) error {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	return s.repo.RenewIdempotencyKey(ctx, principal.UserID.String(), key)
+}
+
+// ReleaseIdempotentRequest frees an idempotency key claimed by a request that failed, so that it can be retried.
+// A key completed or taken over by another request meanwhile is left alone.
+func (s *ServiceImpl) ReleaseIdempotentRequest(ctx context.Context, key string, requestHash string) error {
+	principal, err := auth.RequirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteIdempotencyKey(ctx, principal.UserID.String(), key, requestHash)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBeginIdempotentRequest(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now().UTC()
+	longAgo := now.Add(-2 * idempotencyLockTimeout)
+
+	tests := []struct {
+		name       string
+		existing   models.IdempotencyKey
+		wantTaken  bool // The stored key is taken over for the request
+		wantReplay bool
+		wantErr    error
+	}{
+		{
+			name:      "abandoned in-progress key is taken over",
+			existing:  models.IdempotencyKey{RequestHash: "hash", CreatedAt: longAgo, UpdatedAt: longAgo, ExpiresAt: now.Add(time.Hour)},
+			wantTaken: true,
+		},
+		{
+			name:      "expired completed key is taken over",
+			existing:  models.IdempotencyKey{RequestHash: "other", StatusCode: 201, CreatedAt: longAgo, UpdatedAt: longAgo, ExpiresAt: now.Add(-time.Minute)},
+			wantTaken: true,
+		},
+		{
+			name:     "renewed slow request keeps the key",
+			existing: models.IdempotencyKey{RequestHash: "hash", CreatedAt: longAgo, UpdatedAt: now, ExpiresAt: now.Add(time.Hour)},
+			wantErr:  ErrIdempotencyKeyInProgress,
+		},
+		{
+			name:       "completed request is replayed",
+			existing:   models.IdempotencyKey{RequestHash: "hash", StatusCode: 201, ResponseBody: "{}", CreatedAt: now, UpdatedAt: now, ExpiresAt: now.Add(time.Hour)},
+			wantReplay: true,
+		},
+		{
+			name:     "key reused for another request",
+			existing: models.IdempotencyKey{RequestHash: "other", StatusCode: 201, CreatedAt: now, UpdatedAt: now, ExpiresAt: now.Add(time.Hour)},
+			wantErr:  ErrIdempotencyKeyReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo := newTestService()
+			ctx := principalContext(userID)
+			existing := tt.existing
+			existing.UserID = userID
+			existing.Key = "key"
+
+			mockRepo.On("ClaimIdempotencyKey", mock.Anything, mock.AnythingOfType("models.IdempotencyKey")).Return(false, nil).Once()
+			mockRepo.On("FindIdempotencyKey", mock.Anything, userID.String(), "key").Return(&existing, nil).Once()
+			if tt.wantTaken {
+				mockRepo.On("TakeOverIdempotencyKey", mock.Anything, mock.AnythingOfType("models.IdempotencyKey"), mock.Anything, mock.Anything).
+					Return(true, nil).Once()
+			}
+
+			stored, err := svc.BeginIdempotentRequest(ctx, "key", "hash")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.wantReplay != (stored != nil) {
+				t.Errorf("Expected replay %v, got stored key %v", tt.wantReplay, stored)
+			}
+			if !tt.wantTaken {
+				mockRepo.AssertNotCalled(t, "TakeOverIdempotencyKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertNotCalled(t, "DeleteIdempotencyKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBeginIdempotentRequestConcurrentTakeover(t *testing.T) {
+	svc, mockRepo := newTestService()
+	userID := uuid.New()
+	ctx := principalContext(userID)
+	longAgo := time.Now().UTC().Add(-2 * idempotencyLockTimeout)
+	abandoned := &models.IdempotencyKey{UserID: userID, Key: "key", RequestHash: "hash", CreatedAt: longAgo, UpdatedAt: longAgo, ExpiresAt: time.Now().Add(time.Hour)}
+
+	// Both retries read the abandoned key, the conditional takeover lets only the first one claim it
+	mockRepo.On("ClaimIdempotencyKey", mock.Anything, mock.AnythingOfType("models.IdempotencyKey")).Return(false, nil)
+	mockRepo.On("FindIdempotencyKey", mock.Anything, userID.String(), "key").Return(abandoned, nil)
+	mockRepo.On("TakeOverIdempotencyKey", mock.Anything, mock.AnythingOfType("models.IdempotencyKey"), mock.Anything, mock.Anything).
+		Return(true, nil).Once()
+	mockRepo.On("TakeOverIdempotencyKey", mock.Anything, mock.AnythingOfType("models.IdempotencyKey"), mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.BeginIdempotentRequest(ctx, "key", "hash")
+		}(i)
+	}
+	wg.Wait()
+
+	claimed := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			claimed++
+		case !errors.Is(err, ErrIdempotencyKeyInProgress):
+			t.Errorf("Expected ErrIdempotencyKeyInProgress for the losing retry, got %v", err)
+		}
+	}
+	if claimed != 1 {
+		t.Errorf("Expected exactly one retry to claim the key, got %d", claimed)
+	}
+	mockRepo.AssertNotCalled(t, "DeleteIdempotencyKey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRenewIdempotentRequest(t *testing.T) {
+	svc, mockRepo := newTestService()
+	userID := uuid.New()
+	mockRepo.On("RenewIdempotencyKey", mock.Anything, userID.String(), "key").Return(nil).Once()
+
+	if err := svc.RenewIdempotentRequest(principalContext(userID), "key"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	mockRepo.AssertExpectations(t)
+}
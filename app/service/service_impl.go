@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,75 +15,179 @@ import (
 type ServiceImpl struct {
 	repo            repo.Repository
 	exchangeRatesDb repo.ExchangeRatesDb
+	suggestions     *suggestionCache
 }
 
 func NewServiceImpl(repo repo.Repository, exchangeRatesDb repo.ExchangeRatesDb) *ServiceImpl {
 	return &ServiceImpl{
 		repo:            repo,
 		exchangeRatesDb: exchangeRatesDb,
+		suggestions:     newSuggestionCache(),
 	}
 }
 
+// referenceError reports a referenced entity that doesn't exist as an invalid field of the request, other errors such
+// as a forbidden reference are returned unchanged
+func referenceError(err error, field string) error {
+	var notFound *models.NotFoundError
+	if errors.As(err, &notFound) {
+		return &models.ValidationError{Field: field, Reason: fmt.Sprintf("references %s %s that doesn't exist", notFound.Entity, notFound.ID)}
+	}
+	return err
+}
+
 func (s *ServiceImpl) CreateBalance(ctx context.Context, balance models.Balance) (*models.Balance, error) {
+	if err := s.authorizeAccess(ctx, "balance", balance.UserID, balance.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
 	balance.ID = models.NewBalanceID()
 	return s.repo.CreateBalance(ctx, balance)
 }
 
 func (s *ServiceImpl) GetBalance(ctx context.Context, balanceID string) (*models.Balance, error) {
-	return s.repo.GetBalance(ctx, balanceID)
+	return s.getAccessibleBalance(ctx, balanceID, accessRead)
+}
+
+// GetBalanceWithAmounts retrieves a balance together with its computed running amounts as of the given time
+func (s *ServiceImpl) GetBalanceWithAmounts(ctx context.Context, balanceID string, asOf *time.Time) (*models.Balance, error) {
+	balance, err := s.getAccessibleBalance(ctx, balanceID, accessRead)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := []models.Balance{*balance}
+	if err := s.attachBalanceAmounts(ctx, balances, asOf); err != nil {
+		return nil, err
+	}
+	return &balances[0], nil
 }
 
 func (s *ServiceImpl) ListBalances(ctx context.Context, filter models.ListBalancesInput) ([]models.Balance, error) {
-	return s.repo.ListBalances(ctx, filter)
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
+
+	balances, err := s.repo.ListBalances(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachBalanceAmounts(ctx, balances, filter.AsOf); err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// attachBalanceAmounts computes running amounts for the given balances and sets them in place
+func (s *ServiceImpl) attachBalanceAmounts(ctx context.Context, balances []models.Balance, asOf *time.Time) error {
+	if len(balances) == 0 {
+		return nil
+	}
+
+	balanceIDs := make([]string, len(balances))
+	for i, balance := range balances {
+		balanceIDs[i] = balance.ID.String()
+	}
+
+	amounts, err := s.repo.GetBalanceAmounts(ctx, balanceIDs, asOf)
+	if err != nil {
+		return fmt.Errorf("failed to compute balance amounts: %w", err)
+	}
+
+	for i := range balances {
+		balanceAmounts := amounts[balances[i].ID.String()]
+		amount := balanceAmounts.Amount
+		balances[i].Amount = &amount
+		balances[i].CurrencyAmounts = balanceAmounts.CurrencyAmounts
+	}
+	return nil
 }
 
 func (s *ServiceImpl) UpdateBalance(ctx context.Context, balance models.Balance) (*models.Balance, error) {
+	// Both the current and the new owner of the balance must be accessible to the caller
+	if _, err := s.getAccessibleBalance(ctx, balance.ID.String(), accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "balance", balance.UserID, balance.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
 	balance.UpdatedAt = time.Now().UTC()
 	return s.repo.UpdateBalance(ctx, balance)
 }
 
 func (s *ServiceImpl) DeleteBalance(ctx context.Context, balanceID string) error {
+	if _, err := s.getAccessibleBalance(ctx, balanceID, accessWrite); err != nil {
+		return err
+	}
 	return s.repo.DeleteBalance(ctx, balanceID)
 }
 
 func (s *ServiceImpl) DeleteBalancesByUserId(ctx context.Context, userId string) error {
+	if err := s.authorizeUser(ctx, userId); err != nil {
+		return err
+	}
 	return s.repo.DeleteBalancesByUserId(ctx, userId)
 }
 
 func (s *ServiceImpl) CreateCategory(ctx context.Context, category models.Category) (*models.Category, error) {
+	if err := s.authorizeAccess(ctx, "category", category.UserId, category.GroupId, accessWrite); err != nil {
+		return nil, err
+	}
 	category.ID = models.NewCategoryID()
 	return s.repo.CreateCategory(ctx, category)
 }
 
 func (s *ServiceImpl) ListCategories(ctx context.Context, filter models.ListCategoriesInput) ([]models.Category, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
 	return s.repo.ListCategories(ctx, filter)
 }
 
 func (s *ServiceImpl) GetCategory(ctx context.Context, categoryID string) (*models.Category, error) {
-	return s.repo.GetCategory(ctx, categoryID)
+	return s.getAccessibleCategory(ctx, categoryID, accessRead)
 }
 
 func (s *ServiceImpl) UpdateCategory(ctx context.Context, category models.Category) (*models.Category, error) {
+	// Both the current and the new owner of the category must be accessible to the caller
+	if _, err := s.getAccessibleCategory(ctx, category.ID.String(), accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "category", category.UserId, category.GroupId, accessWrite); err != nil {
+		return nil, err
+	}
 	category.UpdatedAt = time.Now().UTC()
 	return s.repo.UpdateCategory(ctx, category)
 }
 
 func (s *ServiceImpl) DeleteCategory(ctx context.Context, categoryID string) error {
+	if _, err := s.getAccessibleCategory(ctx, categoryID, accessWrite); err != nil {
+		return err
+	}
 	return s.repo.DeleteCategory(ctx, categoryID)
 }
 
 func (s *ServiceImpl) DeleteCategoriesByUserId(ctx context.Context, userId string) error {
+	if err := s.authorizeUser(ctx, userId); err != nil {
+		return err
+	}
 	return s.repo.DeleteCategoriesByUserId(ctx, userId)
 }
 
 func (s *ServiceImpl) CreateMerchant(ctx context.Context, merchant models.Merchant) (*models.Merchant, error) {
+	if err := s.authorizeAccess(ctx, "merchant", merchant.UserID, merchant.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+
 	// Check if a merchant with the same name already exists for this user
 	existingMerchant, err := s.repo.GetMerchantByNameAndUserId(ctx, merchant.Name, merchant.UserID.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for existing merchant: %w", err)
 	}
 	if existingMerchant != nil {
-		return nil, fmt.Errorf("merchant with name '%s' already exists for this user", merchant.Name)
+		return nil, &models.ConflictError{Reason: fmt.Sprintf("merchant with name '%s' already exists for this user", merchant.Name)}
 	}
 
 	merchant.ID = models.NewMerchantID()
@@ -90,33 +195,58 @@ func (s *ServiceImpl) CreateMerchant(ctx context.Context, merchant models.Mercha
 }
 
 func (s *ServiceImpl) GetMerchant(ctx context.Context, merchantID string) (*models.Merchant, error) {
-	return s.repo.GetMerchant(ctx, merchantID)
+	return s.getAccessibleMerchant(ctx, merchantID, accessRead)
 }
 
 func (s *ServiceImpl) ListMerchants(ctx context.Context, filter models.ListMerchantsInput) ([]models.Merchant, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
 	return s.repo.ListMerchants(ctx, filter)
 }
 
 func (s *ServiceImpl) UpdateMerchant(ctx context.Context, merchant models.Merchant) (*models.Merchant, error) {
+	// Both the current and the new owner of the merchant must be accessible to the caller
+	if _, err := s.getAccessibleMerchant(ctx, merchant.ID.String(), accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "merchant", merchant.UserID, merchant.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
 	merchant.UpdatedAt = time.Now().UTC()
 	return s.repo.UpdateMerchant(ctx, merchant)
 }
 
 func (s *ServiceImpl) DeleteMerchant(ctx context.Context, merchantID string) error {
+	if _, err := s.getAccessibleMerchant(ctx, merchantID, accessWrite); err != nil {
+		return err
+	}
 	return s.repo.DeleteMerchant(ctx, merchantID)
 }
 
 func (s *ServiceImpl) DeleteMerchantsByUserId(ctx context.Context, userId string) error {
+	if err := s.authorizeUser(ctx, userId); err != nil {
+		return err
+	}
 	return s.repo.DeleteMerchantsByUserId(ctx, userId)
 }
 
 func (s *ServiceImpl) ListTransactionEntries(ctx context.Context, filter models.ListTransactionsInput) ([]models.TransactionEntry, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
 	return s.repo.ListTransactionEntries(ctx, filter)
 }
 
 // CategoryGroup service methods
+// Category groups are shared by every user, reading them is open to any caller while changes require the admin role
 
 func (s *ServiceImpl) CreateCategoryGroup(ctx context.Context, categoryGroup models.CategoryGroup) (*models.CategoryGroup, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
 	categoryGroup.ID = models.NewCategoryGroupID()
 	return s.repo.CreateCategoryGroup(ctx, categoryGroup)
 }
@@ -130,24 +260,24 @@ func (s *ServiceImpl) GetCategoryGroup(ctx context.Context, categoryGroupID stri
 }
 
 func (s *ServiceImpl) UpdateCategoryGroup(ctx context.Context, categoryGroup models.CategoryGroup) (*models.CategoryGroup, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
 	categoryGroup.UpdatedAt = time.Now().UTC()
 	return s.repo.UpdateCategoryGroup(ctx, categoryGroup)
 }
 
 func (s *ServiceImpl) DeleteCategoryGroup(ctx context.Context, categoryGroupID string) error {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return err
+	}
 	return s.repo.DeleteCategoryGroup(ctx, categoryGroupID)
 }
 
 // CreateTransactions creates multiple transactions atomically and generates operation ID if needed
 func (s *ServiceImpl) CreateTransactions(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, *string, error) {
-	// Validate maximum number of transactions
-	if len(transactions) > 5 {
-		return nil, nil, fmt.Errorf("too many transactions: maximum 5 allowed, got %d", len(transactions))
-	}
-
-	// Validate minimum number of transactions
-	if len(transactions) == 0 {
-		return nil, nil, fmt.Errorf("at least one transaction is required")
+	if err := validateTransactionBatchSize(transactions); err != nil {
+		return nil, nil, err
 	}
 
 	var operationID *string
@@ -165,45 +295,82 @@ func (s *ServiceImpl) CreateTransactions(ctx context.Context, transactions []mod
 	}
 
 	// Generate transaction IDs and validate move operations
+	if err := s.prepareTransactions(ctx, transactions); err != nil {
+		return nil, nil, err
+	}
+
+	// Validate move operations come in pairs
+	if err := validateMovementOperations(transactions); err != nil {
+		return nil, nil, err
+	}
+
+	created, err := s.repo.CreateTransactions(ctx, transactions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return created, operationID, nil
+}
+
+// prepareTransactions generates the IDs of a batch of transactions, checks access to them and to the balances,
+// merchants and categories they reference, and converts the entry amounts to all supported currencies
+func (s *ServiceImpl) prepareTransactions(ctx context.Context, transactions []models.Transaction) error {
 	for i := range transactions {
 		transactions[i].ID = models.NewTransactionID()
 
+		if err := s.authorizeAccess(ctx, "transaction", transactions[i].UserID, transactions[i].GroupID, accessWrite); err != nil {
+			return err
+		}
+
 		// Validate balance exists (required)
-		_, err := s.repo.GetBalance(ctx, transactions[i].BalanceID.String())
+		balance, err := s.getAccessibleBalance(ctx, transactions[i].BalanceID.String(), accessWrite)
 		if err != nil {
-			return nil, nil, fmt.Errorf("balance with ID %s not found for transaction %d: %w", transactions[i].BalanceID.String(), i, err)
+			return referenceError(err, fmt.Sprintf("transactions[%d].balanceId", i))
 		}
 
 		// Validate merchant exists if merchantID is provided
 		if transactions[i].MerchantID != nil {
-			_, err := s.repo.GetMerchant(ctx, transactions[i].MerchantID.String())
+			_, err := s.getAccessibleMerchant(ctx, transactions[i].MerchantID.String(), accessRead)
 			if err != nil {
-				return nil, nil, fmt.Errorf("merchant with ID %s not found for transaction %d: %w", transactions[i].MerchantID.String(), i, err)
+				return referenceError(err, fmt.Sprintf("transactions[%d].merchantId", i))
 			}
 		}
 
 		// Validate categories exist for all transaction entries
 		for j, entry := range transactions[i].TransactionEntries {
 			if entry.CategoryID != nil {
-				_, err := s.repo.GetCategory(ctx, entry.CategoryID.String())
+				_, err := s.getAccessibleCategory(ctx, entry.CategoryID.String(), accessRead)
 				if err != nil {
-					return nil, nil, fmt.Errorf("category with ID %s not found for transaction %d entry %d: %w", entry.CategoryID.String(), i, j, err)
+					return referenceError(err, fmt.Sprintf("transactions[%d].transactionEntries[%d].categoryId", i, j))
 				}
 			}
 		}
-	}
 
-	// Validate move operations come in pairs
-	if err := validateMovementOperations(transactions); err != nil {
-		return nil, nil, err
-	}
+		// Entries without category are categorized by the rules of the group
+		rules, err := s.categorizationRules(ctx, transactions[i].GroupID)
+		if err != nil {
+			return err
+		}
+		rules.Categorize(&transactions[i])
 
-	created, err := s.repo.CreateTransactions(ctx, transactions)
-	if err != nil {
-		return nil, nil, err
+		// Convert entry amounts with the exchange rates effective at the transaction date
+		supportedCurrencies, exchangeRates, exchangeRateDate, err := s.getExchangeRatesAt(ctx, balance.Currency, transactions[i].TransactedAt)
+		if err != nil {
+			return err
+		}
+		for j := range transactions[i].TransactionEntries {
+			entry := &transactions[i].TransactionEntries[j]
+			entry.TransactionID = transactions[i].ID
+			entry.TransactionEntryAmounts = s.createTransactionEntryAmounts(
+				entry.ID,
+				entry.Amount,
+				balance.Currency,
+				supportedCurrencies,
+				exchangeRates,
+				exchangeRateDate)
+		}
 	}
-
-	return created, operationID, nil
+	return nil
 }
 
 // hasMovementTransactions checks if any transaction is of move_in or move_out type
@@ -218,8 +385,7 @@ func hasMovementTransactions(transactions []models.Transaction) bool {
 
 // generateOperationID generates a new operation ID with 'fa' prefix
 func generateOperationID() string {
-	id := uuid.New().String()
-	return "fa" + id[2:] // Replace first 2 characters with 'fa'
+	return models.NewOperationID().String()
 }
 
 // validateMovementOperations ensures move operations are properly paired
@@ -237,11 +403,22 @@ func validateMovementOperations(transactions []models.Transaction) error {
 
 	// If there are move operations, we need at least one move_in and one move_out
 	if (moveInCount > 0 || moveOutCount > 0) && (moveInCount == 0 || moveOutCount == 0) {
-		return fmt.Errorf("movement operations require both move_in and move_out transactions")
+		return &models.ValidationError{Field: "transactions", Reason: "must contain both move_in and move_out transactions for a movement"}
 	}
 
 	return nil
 }
 
+// validateTransactionBatchSize checks that a batch of transactions created or replaced together has 1 to 5 transactions
+func validateTransactionBatchSize(transactions []models.Transaction) error {
+	if len(transactions) == 0 {
+		return &models.ValidationError{Field: "transactions", Reason: "must contain at least one transaction"}
+	}
+	if len(transactions) > 5 {
+		return &models.ValidationError{Field: "transactions", Reason: fmt.Sprintf("must contain at most 5 transactions, got %d", len(transactions))}
+	}
+	return nil
+}
+
 // Ensure ServiceImpl implements Service
 var _ Service = (*ServiceImpl)(nil)
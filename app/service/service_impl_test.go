@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryGroupWritesRequireAdmin(t *testing.T) {
+	svc, mockRepo := newTestService()
+	ctx := principalContext(uuid.New())
+	categoryGroup := models.CategoryGroup{ID: uuid.New(), Name: "Food"}
+
+	_, err := svc.CreateCategoryGroup(ctx, categoryGroup)
+	assertForbidden(t, err)
+	_, err = svc.UpdateCategoryGroup(ctx, categoryGroup)
+	assertForbidden(t, err)
+	assertForbidden(t, svc.DeleteCategoryGroup(ctx, categoryGroup.ID.String()))
+
+	// Reads stay open to every authenticated user
+	mockRepo.On("GetCategoryGroup", ctx, categoryGroup.ID.String()).Return(&categoryGroup, nil)
+	if _, err := svc.GetCategoryGroup(ctx, categoryGroup.ID.String()); err != nil {
+		t.Fatalf("Expected category group read to be allowed, got %v", err)
+	}
+	mockRepo.AssertNotCalled(t, "CreateCategoryGroup", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateCategoryGroup", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "DeleteCategoryGroup", mock.Anything, mock.Anything)
+}
+
+func TestCategoryGroupWritesAllowedForAdmin(t *testing.T) {
+	svc, mockRepo := newTestService()
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: uuid.New(), Admin: true})
+	categoryGroupID := uuid.New()
+
+	mockRepo.On("CreateCategoryGroup", ctx, mock.AnythingOfType("models.CategoryGroup")).
+		Return(&models.CategoryGroup{ID: categoryGroupID, Name: "Food"}, nil)
+	mockRepo.On("DeleteCategoryGroup", ctx, categoryGroupID.String()).Return(nil)
+
+	if _, err := svc.CreateCategoryGroup(ctx, models.CategoryGroup{Name: "Food"}); err != nil {
+		t.Fatalf("Expected admin to create category groups, got %v", err)
+	}
+	if err := svc.DeleteCategoryGroup(ctx, categoryGroupID.String()); err != nil {
+		t.Fatalf("Expected admin to delete category groups, got %v", err)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListBalancesAttachesAmounts(t *testing.T) {
+	userID := uuid.New()
+	withEntries := models.Balance{ID: uuid.New(), UserID: userID, Currency: "EUR"}
+	withoutEntries := models.Balance{ID: uuid.New(), UserID: userID, Currency: "USD"}
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	svc, mockRepo := newTestService()
+	mockRepo.On("ListBalances", mock.Anything, mock.Anything).Return([]models.Balance{withEntries, withoutEntries}, nil)
+	mockRepo.On("GetBalanceAmounts", mock.Anything, []string{withEntries.ID.String(), withoutEntries.ID.String()}, &asOf).
+		Return(map[string]models.BalanceAmounts{
+			withEntries.ID.String(): {Amount: -2500, CurrencyAmounts: map[string]int64{"EUR": -2500, "USD": -2710}},
+		}, nil)
+
+	balances, err := svc.ListBalances(principalContext(userID), models.ListBalancesInput{UserID: userID.String(), AsOf: &asOf})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("Expected 2 balances, got %d", len(balances))
+	}
+	if balances[0].Amount == nil || *balances[0].Amount != -2500 || balances[0].CurrencyAmounts["USD"] != -2710 {
+		t.Errorf("Expected the amounts of the balance with entries, got %v %v", balances[0].Amount, balances[0].CurrencyAmounts)
+	}
+	// Balances without entries report an explicit zero rather than no amount
+	if balances[1].Amount == nil || *balances[1].Amount != 0 || len(balances[1].CurrencyAmounts) != 0 {
+		t.Errorf("Expected a zero amount for the balance without entries, got %v %v", balances[1].Amount, balances[1].CurrencyAmounts)
+	}
+}
+
+func TestGetBalanceWithAmountsAsOf(t *testing.T) {
+	userID := uuid.New()
+	balance := &models.Balance{ID: uuid.New(), UserID: userID, Currency: "EUR"}
+	asOf := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		asOf *time.Time
+	}{
+		{"now", nil},
+		{"cutoff", &asOf},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo := newTestService()
+			mockRepo.On("GetBalance", mock.Anything, balance.ID.String()).Return(balance, nil)
+			mockRepo.On("GetBalanceAmounts", mock.Anything, []string{balance.ID.String()}, tt.asOf).
+				Return(map[string]models.BalanceAmounts{balance.ID.String(): {Amount: 1000}}, nil)
+
+			got, err := svc.GetBalanceWithAmounts(principalContext(userID), balance.ID.String(), tt.asOf)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got.Amount == nil || *got.Amount != 1000 {
+				t.Errorf("Expected amount 1000, got %v", got.Amount)
+			}
+			mockRepo.AssertCalled(t, "GetBalanceAmounts", mock.Anything, []string{balance.ID.String()}, tt.asOf)
+		})
+	}
+
+	t.Run("another user", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetBalance", mock.Anything, balance.ID.String()).Return(balance, nil)
+
+		_, err := svc.GetBalanceWithAmounts(principalContext(uuid.New()), balance.ID.String(), &asOf)
+		assertForbidden(t, err)
+		mockRepo.AssertNotCalled(t, "GetBalanceAmounts", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
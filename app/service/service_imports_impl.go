@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxImportRows bounds the rows of a single imported file, larger statements have to be split
+	maxImportRows = 5000
+
+	// importChunkSize is the number of transactions created per database transaction during an import
+	importChunkSize = 100
+)
+
+// ErrInvalidImport is returned when an imported file can't be parsed or references a balance, category or import
+// profile that doesn't exist
+var ErrInvalidImport error = &models.ValidationError{Reason: "invalid import"}
+
+func (s *ServiceImpl) CreateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	if err := s.authorizeAccess(ctx, "import profile", profile.UserID, profile.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	profile.ID = models.NewImportProfileID()
+	return s.repo.CreateImportProfile(ctx, profile)
+}
+
+func (s *ServiceImpl) GetImportProfile(ctx context.Context, importProfileID string) (*models.ImportProfile, error) {
+	return s.getAccessibleImportProfile(ctx, importProfileID, accessRead)
+}
+
+func (s *ServiceImpl) ListImportProfiles(ctx context.Context, filter models.ListImportProfilesInput) ([]models.ImportProfile, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListImportProfiles(ctx, filter)
+}
+
+func (s *ServiceImpl) UpdateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	// Both the current and the new owner of the profile must be accessible to the caller
+	if _, err := s.getAccessibleImportProfile(ctx, profile.ID.String(), accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "import profile", profile.UserID, profile.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateImportProfile(ctx, profile)
+}
+
+func (s *ServiceImpl) DeleteImportProfile(ctx context.Context, importProfileID string) error {
+	if _, err := s.getAccessibleImportProfile(ctx, importProfileID, accessWrite); err != nil {
+		return err
+	}
+	return s.repo.DeleteImportProfile(ctx, importProfileID)
+}
+
+// ImportTransactions parses a CSV or OFX/QFX bank statement and creates an income or expense transaction with a
+// single entry for every row. Rows with an external ID that was already imported into the balance are skipped, so
+// the same statement (or overlapping ones) can be imported repeatedly. Transactions are created in chunks; when a
+// chunk fails its rows are retried one by one so that only the broken rows are reported as failed.
+func (s *ServiceImpl) ImportTransactions(ctx context.Context, input models.ImportTransactionsInput) (*models.ImportReportDto, error) {
+	if err := s.authorizeAccess(ctx, "transaction", input.UserID, input.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.getAccessibleBalance(ctx, input.BalanceID.String(), accessWrite)
+	if err != nil {
+		return nil, importReferenceError(err)
+	}
+	if input.CategoryID != nil {
+		if _, err := s.getAccessibleCategory(ctx, input.CategoryID.String(), accessRead); err != nil {
+			return nil, importReferenceError(err)
+		}
+	}
+
+	rows, err := s.parseImport(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: file has %d rows, at most %d are supported per import", ErrInvalidImport, len(rows), maxImportRows)
+	}
+
+	externalIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Err == nil && row.ExternalID != "" {
+			externalIDs = append(externalIDs, row.ExternalID)
+		}
+	}
+	imported, err := s.repo.ListTransactionExternalIDs(ctx, balance.ID.String(), externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	alreadyImported := make(map[string]bool, len(imported))
+	for _, externalID := range imported {
+		alreadyImported[externalID] = true
+	}
+
+	report := &models.ImportReportDto{
+		Format:    input.Format,
+		DryRun:    input.DryRun,
+		TotalRows: len(rows),
+		Rows:      make([]models.ImportRowResultDto, len(rows)),
+	}
+
+	// Rows are categorized by the rules of the group unless the import sets a category
+	var rules *models.CategorizationRuleSet
+	if input.CategoryID == nil {
+		if rules, err = s.categorizationRules(ctx, input.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	builder := importTransactionBuilder{
+		service:    s,
+		input:      input,
+		balance:    balance,
+		merchants:  map[string]*uuid.UUID{},
+		rates:      map[string]importExchangeRates{},
+		seenInFile: map[string]bool{},
+	}
+
+	// Rows to create, with the index of their row in the report
+	var pending []models.Transaction
+	var pendingRows []int
+	for i, row := range rows {
+		result := &report.Rows[i]
+		*result = models.ImportRowResultDto{
+			Line:        row.Line,
+			ExternalID:  row.ExternalID,
+			Amount:      int(row.Amount),
+			Description: row.Description,
+		}
+		if !row.TransactedAt.IsZero() {
+			result.TransactedAt = row.TransactedAt.Format(time.RFC3339)
+		}
+
+		switch {
+		case row.Err != nil:
+			result.Status, result.Reason = models.ImportRowFailed, row.Err.Error()
+		case row.Amount == 0:
+			result.Status, result.Reason = models.ImportRowSkipped, "amount is zero"
+		case alreadyImported[row.ExternalID]:
+			result.Status, result.Reason = models.ImportRowSkipped, "already imported"
+		case builder.seenInFile[row.ExternalID]:
+			result.Status, result.Reason = models.ImportRowSkipped, "duplicate of an earlier row in the file"
+		default:
+			builder.seenInFile[row.ExternalID] = true
+			tx, err := builder.build(ctx, row)
+			if err != nil {
+				result.Status, result.Reason = models.ImportRowFailed, err.Error()
+				break
+			}
+			if changes := rules.Categorize(tx); len(changes) > 0 {
+				result.RuleID = changes[0].RuleID.String()
+			}
+			result.Status = models.ImportRowPending
+			result.TransactionID = tx.ID.String()
+			if tx.MerchantID != nil {
+				result.MerchantID = tx.MerchantID.String()
+			}
+			if categoryID := tx.TransactionEntries[0].CategoryID; categoryID != nil {
+				result.CategoryID = categoryID.String()
+			}
+			pending = append(pending, *tx)
+			pendingRows = append(pendingRows, i)
+		}
+	}
+
+	if !input.DryRun {
+		for start := 0; start < len(pending); start += importChunkSize {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			end := min(start+importChunkSize, len(pending))
+			s.createImportChunk(ctx, pending[start:end], pendingRows[start:end], report)
+		}
+	}
+
+	for _, result := range report.Rows {
+		switch result.Status {
+		case models.ImportRowCreated, models.ImportRowPending:
+			report.Created++
+		case models.ImportRowSkipped:
+			report.Skipped++
+		case models.ImportRowFailed:
+			report.Failed++
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"balance_id": balance.ID.String(),
+		"format":     input.Format,
+		"dry_run":    input.DryRun,
+		"total_rows": report.TotalRows,
+		"created":    report.Created,
+		"skipped":    report.Skipped,
+		"failed":     report.Failed,
+	}).Info("Import finished")
+	return report, nil
+}
+
+// parseImport parses the file content into rows, CSV files are parsed with the column mapping of the import profile
+func (s *ServiceImpl) parseImport(ctx context.Context, input models.ImportTransactionsInput) ([]models.ImportRow, error) {
+	var rows []models.ImportRow
+	var err error
+	switch input.Format {
+	case models.ImportFormatCSV:
+		if input.ProfileID == "" {
+			return nil, fmt.Errorf("%w: profileId is required for csv files", ErrInvalidImport)
+		}
+		profile, profileErr := s.getAccessibleImportProfile(ctx, input.ProfileID, accessRead)
+		if profileErr != nil {
+			return nil, importReferenceError(profileErr)
+		}
+		rows, err = models.ParseCSVImport(input.Content, *profile)
+	case models.ImportFormatOFX, models.ImportFormatQFX:
+		rows, err = models.ParseOFXImport(input.Content)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %s", ErrInvalidImport, input.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	return rows, nil
+}
+
+// createImportChunk creates a chunk of imported transactions atomically and marks their rows as created. When the
+// chunk fails every transaction is retried on its own to find the rows that can't be created.
+func (s *ServiceImpl) createImportChunk(ctx context.Context, chunk []models.Transaction, rowIndexes []int, report *models.ImportReportDto) {
+	if _, err := s.repo.CreateTransactions(ctx, chunk); err == nil {
+		for _, i := range rowIndexes {
+			report.Rows[i].Status = models.ImportRowCreated
+		}
+		return
+	}
+
+	for j, tx := range chunk {
+		result := &report.Rows[rowIndexes[j]]
+		if _, err := s.repo.CreateTransactions(ctx, []models.Transaction{tx}); err != nil {
+			result.Status, result.Reason, result.TransactionID = models.ImportRowFailed, err.Error(), ""
+			continue
+		}
+		result.Status = models.ImportRowCreated
+	}
+}
+
+// getAccessibleImportProfile retrieves an import profile and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleImportProfile(ctx context.Context, importProfileID string, level accessLevel) (*models.ImportProfile, error) {
+	profile, err := s.repo.GetImportProfile(ctx, importProfileID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "import profile", profile.UserID, profile.GroupID, level); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// importReferenceError reports a balance, category or import profile of an import that doesn't exist as an invalid import
+func importReferenceError(err error) error {
+	if models.IsNotFound(err) {
+		return fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	return err
+}
+
+// importExchangeRates holds the exchange rates of one day, fetched once per import
+type importExchangeRates struct {
+	supportedCurrencies []string
+	rates               map[string]float64
+	rateDate            *time.Time
+}
+
+// importTransactionBuilder turns parsed rows into transactions, caching merchants and exchange rates across rows
+type importTransactionBuilder struct {
+	service    *ServiceImpl
+	input      models.ImportTransactionsInput
+	balance    *models.Balance
+	merchants  map[string]*uuid.UUID // Merchant name to ID, nil when the user has no merchant with the name
+	rates      map[string]importExchangeRates
+	seenInFile map[string]bool // External IDs of the rows processed so far
+}
+
+func (b *importTransactionBuilder) build(ctx context.Context, row models.ImportRow) (*models.Transaction, error) {
+	merchantID, err := b.merchantID(ctx, row.MerchantName)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := b.exchangeRates(ctx, row.TransactedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	txType, amount := "income", row.Amount
+	if amount < 0 {
+		txType, amount = "expense", -amount
+	}
+	externalID := row.ExternalID
+
+	tx := models.Transaction{
+		ID:           models.NewTransactionID(),
+		GroupID:      b.input.GroupID,
+		UserID:       b.input.UserID,
+		BalanceID:    b.balance.ID,
+		MerchantID:   merchantID,
+		Type:         txType,
+		ApprovedAt:   row.TransactedAt,
+		TransactedAt: row.TransactedAt,
+		ExternalID:   &externalID,
+	}
+
+	entry := models.TransactionEntry{
+		ID:            models.NewTransactionEntryID(),
+		TransactionID: tx.ID,
+		Amount:        amount,
+		CategoryID:    b.input.CategoryID,
+	}
+	if row.Description != "" {
+		description := row.Description
+		entry.Description = &description
+	}
+	entry.TransactionEntryAmounts = b.service.createTransactionEntryAmounts(
+		entry.ID,
+		entry.Amount,
+		b.balance.Currency,
+		rates.supportedCurrencies,
+		rates.rates,
+		rates.rateDate)
+	tx.TransactionEntries = []models.TransactionEntry{entry}
+	return &tx, nil
+}
+
+// merchantID resolves a merchant name of the file to an existing merchant of the user
+func (b *importTransactionBuilder) merchantID(ctx context.Context, name string) (*uuid.UUID, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	if id, ok := b.merchants[name]; ok {
+		return id, nil
+	}
+
+	merchant, err := b.service.repo.GetMerchantByNameAndUserId(ctx, name, b.input.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+	var id *uuid.UUID
+	if merchant != nil {
+		id = &merchant.ID
+	}
+	b.merchants[name] = id
+	return id, nil
+}
+
+func (b *importTransactionBuilder) exchangeRates(ctx context.Context, at time.Time) (importExchangeRates, error) {
+	day := at.UTC().Format("2006-01-02")
+	if rates, ok := b.rates[day]; ok {
+		return rates, nil
+	}
+
+	supportedCurrencies, rates, rateDate, err := b.service.getExchangeRatesAt(ctx, b.balance.Currency, at)
+	if err != nil {
+		return importExchangeRates{}, err
+	}
+	b.rates[day] = importExchangeRates{supportedCurrencies: supportedCurrencies, rates: rates, rateDate: rateDate}
+	return b.rates[day], nil
+}
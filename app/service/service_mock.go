@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/savak1990/transactions-service/app/models"
 	"github.com/stretchr/testify/mock"
@@ -84,6 +85,14 @@ func (svc *MockService) GetBalance(ctx context.Context, balanceID string) (*mode
 	return args.Get(0).(*models.Balance), args.Error(1)
 }
 
+func (svc *MockService) GetBalanceWithAmounts(ctx context.Context, balanceID string, asOf *time.Time) (*models.Balance, error) {
+	args := svc.Called(ctx, balanceID, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Balance), args.Error(1)
+}
+
 func (svc *MockService) UpdateBalance(ctx context.Context, balance models.Balance) (*models.Balance, error) {
 	args := svc.Called(ctx, balance)
 	if args.Get(0) == nil {
@@ -241,5 +250,471 @@ func (svc *MockService) GetTransactionStats(ctx context.Context, input models.Tr
 	return args.Get(0).([]models.TransactionStatsItemDto), args.Error(1)
 }
 
+func (svc *MockService) ReconvertEntryAmounts(ctx context.Context, input models.ReconvertAmountsInput, onProgress func(models.ReconvertAmountsReportDto)) (*models.ReconvertAmountsReportDto, error) {
+	args := svc.Called(ctx, input, onProgress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReconvertAmountsReportDto), args.Error(1)
+}
+
+func (svc *MockService) CreateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	args := svc.Called(ctx, group)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (svc *MockService) ListGroups(ctx context.Context) ([]models.Group, error) {
+	args := svc.Called(ctx)
+	var result []models.Group
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Group)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) GetGroup(ctx context.Context, groupID string) (*models.Group, error) {
+	args := svc.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (svc *MockService) UpdateGroup(ctx context.Context, group models.Group) (*models.Group, error) {
+	args := svc.Called(ctx, group)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (svc *MockService) DeleteGroup(ctx context.Context, groupID string) error {
+	args := svc.Called(ctx, groupID)
+	return args.Error(0)
+}
+
+func (svc *MockService) UpdateGroupMember(ctx context.Context, groupID string, userID string, role string) (*models.GroupMember, error) {
+	args := svc.Called(ctx, groupID, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupMember), args.Error(1)
+}
+
+func (svc *MockService) RemoveGroupMember(ctx context.Context, groupID string, userID string) error {
+	args := svc.Called(ctx, groupID, userID)
+	return args.Error(0)
+}
+
+func (svc *MockService) CreateGroupInvitation(ctx context.Context, invitation models.GroupInvitation) (*models.GroupInvitation, error) {
+	args := svc.Called(ctx, invitation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupInvitation), args.Error(1)
+}
+
+func (svc *MockService) ListGroupInvitations(ctx context.Context, groupID string) ([]models.GroupInvitation, error) {
+	args := svc.Called(ctx, groupID)
+	var result []models.GroupInvitation
+	if v := args.Get(0); v != nil {
+		result = v.([]models.GroupInvitation)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) RevokeGroupInvitation(ctx context.Context, groupID string, invitationID string) error {
+	args := svc.Called(ctx, groupID, invitationID)
+	return args.Error(0)
+}
+
+func (svc *MockService) ListMyGroupInvitations(ctx context.Context) ([]models.GroupInvitation, error) {
+	args := svc.Called(ctx)
+	var result []models.GroupInvitation
+	if v := args.Get(0); v != nil {
+		result = v.([]models.GroupInvitation)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) AcceptGroupInvitation(ctx context.Context, invitationID string) (*models.GroupMember, error) {
+	args := svc.Called(ctx, invitationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GroupMember), args.Error(1)
+}
+
+func (svc *MockService) DeclineGroupInvitation(ctx context.Context, invitationID string) error {
+	args := svc.Called(ctx, invitationID)
+	return args.Error(0)
+}
+
+func (svc *MockService) CreateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	args := svc.Called(ctx, budget)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (svc *MockService) GetBudget(ctx context.Context, budgetID string) (*models.Budget, error) {
+	args := svc.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (svc *MockService) ListBudgets(ctx context.Context, filter models.ListBudgetsInput) ([]models.Budget, error) {
+	args := svc.Called(ctx, filter)
+	var result []models.Budget
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Budget)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) UpdateBudget(ctx context.Context, budget models.Budget) (*models.Budget, error) {
+	args := svc.Called(ctx, budget)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
+func (svc *MockService) DeleteBudget(ctx context.Context, budgetID string) error {
+	args := svc.Called(ctx, budgetID)
+	return args.Error(0)
+}
+
+func (svc *MockService) GetBudgetStatus(ctx context.Context, budgetID string, asOf *time.Time) (*models.BudgetStatusDto, error) {
+	args := svc.Called(ctx, budgetID, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BudgetStatusDto), args.Error(1)
+}
+
+func (svc *MockService) CreateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	args := svc.Called(ctx, recurring)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTransaction), args.Error(1)
+}
+
+func (svc *MockService) GetRecurringTransaction(ctx context.Context, recurringTransactionID string) (*models.RecurringTransaction, error) {
+	args := svc.Called(ctx, recurringTransactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTransaction), args.Error(1)
+}
+
+func (svc *MockService) ListRecurringTransactions(ctx context.Context, filter models.ListRecurringTransactionsInput) ([]models.RecurringTransaction, error) {
+	args := svc.Called(ctx, filter)
+	var result []models.RecurringTransaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.RecurringTransaction)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) UpdateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	args := svc.Called(ctx, recurring)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTransaction), args.Error(1)
+}
+
+func (svc *MockService) DeleteRecurringTransaction(ctx context.Context, recurringTransactionID string) error {
+	args := svc.Called(ctx, recurringTransactionID)
+	return args.Error(0)
+}
+
+func (svc *MockService) RunRecurringTransactions(ctx context.Context, input models.RunRecurringTransactionsInput) (*models.RecurringTransactionsRunDto, error) {
+	args := svc.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringTransactionsRunDto), args.Error(1)
+}
+
+func (svc *MockService) CreateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	args := svc.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportProfile), args.Error(1)
+}
+
+func (svc *MockService) GetImportProfile(ctx context.Context, importProfileID string) (*models.ImportProfile, error) {
+	args := svc.Called(ctx, importProfileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportProfile), args.Error(1)
+}
+
+func (svc *MockService) ListImportProfiles(ctx context.Context, filter models.ListImportProfilesInput) ([]models.ImportProfile, error) {
+	args := svc.Called(ctx, filter)
+	var result []models.ImportProfile
+	if v := args.Get(0); v != nil {
+		result = v.([]models.ImportProfile)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) UpdateImportProfile(ctx context.Context, profile models.ImportProfile) (*models.ImportProfile, error) {
+	args := svc.Called(ctx, profile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportProfile), args.Error(1)
+}
+
+func (svc *MockService) DeleteImportProfile(ctx context.Context, importProfileID string) error {
+	args := svc.Called(ctx, importProfileID)
+	return args.Error(0)
+}
+
+func (svc *MockService) ImportTransactions(ctx context.Context, input models.ImportTransactionsInput) (*models.ImportReportDto, error) {
+	args := svc.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportReportDto), args.Error(1)
+}
+
+func (svc *MockService) ListDuplicateTransactions(ctx context.Context, filter models.ListDuplicateTransactionsInput) ([]models.DuplicateClusterDto, error) {
+	args := svc.Called(ctx, filter)
+	var result []models.DuplicateClusterDto
+	if v := args.Get(0); v != nil {
+		result = v.([]models.DuplicateClusterDto)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) ExportTransactionEntries(ctx context.Context, filter models.ListTransactionsInput, handle func([]models.TransactionEntry) error) error {
+	args := svc.Called(ctx, filter, handle)
+	return args.Error(0)
+}
+
+func (svc *MockService) BeginIdempotentRequest(ctx context.Context, key string, requestHash string) (*models.IdempotencyKey, error) {
+	args := svc.Called(ctx, key, requestHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (svc *MockService) CompleteIdempotentRequest(ctx context.Context, key string, requestHash string, statusCode int, responseBody string) error {
+	args := svc.Called(ctx, key, requestHash, statusCode, responseBody)
+	return args.Error(0)
+}
+
+func (svc *MockService) RenewIdempotentRequest(ctx context.Context, key string) error {
+	args := svc.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (svc *MockService) ReleaseIdempotentRequest(ctx context.Context, key string, requestHash string) error {
+	args := svc.Called(ctx, key, requestHash)
+	return args.Error(0)
+}
+
+func (svc *MockService) CreateTransfer(ctx context.Context, input models.TransferInput) (*models.Transfer, error) {
+	args := svc.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (svc *MockService) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	args := svc.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (svc *MockService) UpdateTransfer(ctx context.Context, transferID string, input models.TransferInput) (*models.Transfer, error) {
+	args := svc.Called(ctx, transferID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transfer), args.Error(1)
+}
+
+func (svc *MockService) DeleteTransfer(ctx context.Context, transferID string) error {
+	args := svc.Called(ctx, transferID)
+	return args.Error(0)
+}
+
+func (svc *MockService) GetOperation(ctx context.Context, operationID string) ([]models.Transaction, error) {
+	args := svc.Called(ctx, operationID)
+	var result []models.Transaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Transaction)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) UpdateOperation(ctx context.Context, operationID string, transactions []models.Transaction) ([]models.Transaction, error) {
+	args := svc.Called(ctx, operationID, transactions)
+	var result []models.Transaction
+	if v := args.Get(0); v != nil {
+		result = v.([]models.Transaction)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) DeleteOperation(ctx context.Context, operationID string) error {
+	args := svc.Called(ctx, operationID)
+	return args.Error(0)
+}
+
+func (svc *MockService) RestoreTransaction(ctx context.Context, transactionID string) (*models.SingleTransactionDto, error) {
+	args := svc.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SingleTransactionDto), args.Error(1)
+}
+
+func (svc *MockService) RestoreBalance(ctx context.Context, balanceID string) (*models.Balance, error) {
+	args := svc.Called(ctx, balanceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Balance), args.Error(1)
+}
+
+func (svc *MockService) RestoreCategory(ctx context.Context, categoryID string) (*models.Category, error) {
+	args := svc.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (svc *MockService) RestoreCategoryGroup(ctx context.Context, categoryGroupID string) (*models.CategoryGroup, error) {
+	args := svc.Called(ctx, categoryGroupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategoryGroup), args.Error(1)
+}
+
+func (svc *MockService) RestoreMerchant(ctx context.Context, merchantID string) (*models.Merchant, error) {
+	args := svc.Called(ctx, merchantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Merchant), args.Error(1)
+}
+
+func (svc *MockService) PurgeDeletedData(ctx context.Context, input models.PurgeDeletedDataInput) (*models.PurgeDeletedDataReportDto, error) {
+	args := svc.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurgeDeletedDataReportDto), args.Error(1)
+}
+
+func (svc *MockService) ExportUserData(ctx context.Context, userID string) (*models.UserData, error) {
+	args := svc.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserData), args.Error(1)
+}
+
+func (svc *MockService) EraseUserData(ctx context.Context, userID string) (*models.UserErasureReportDto, error) {
+	args := svc.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserErasureReportDto), args.Error(1)
+}
+
+func (svc *MockService) ListAuditEvents(ctx context.Context, filter models.ListAuditEventsInput) ([]models.AuditEvent, error) {
+	args := svc.Called(ctx, filter)
+	var result []models.AuditEvent
+	if v := args.Get(0); v != nil {
+		result = v.([]models.AuditEvent)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) GetTransactionHistory(ctx context.Context, transactionID string) ([]models.AuditEvent, error) {
+	args := svc.Called(ctx, transactionID)
+	var result []models.AuditEvent
+	if v := args.Get(0); v != nil {
+		result = v.([]models.AuditEvent)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) CreateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	args := svc.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategorizationRule), args.Error(1)
+}
+
+func (svc *MockService) GetCategorizationRule(ctx context.Context, ruleID string) (*models.CategorizationRule, error) {
+	args := svc.Called(ctx, ruleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategorizationRule), args.Error(1)
+}
+
+func (svc *MockService) ListCategorizationRules(ctx context.Context, filter models.ListCategorizationRulesInput) ([]models.CategorizationRule, error) {
+	args := svc.Called(ctx, filter)
+	var result []models.CategorizationRule
+	if v := args.Get(0); v != nil {
+		result = v.([]models.CategorizationRule)
+	}
+	return result, args.Error(1)
+}
+
+func (svc *MockService) UpdateCategorizationRule(ctx context.Context, rule models.CategorizationRule) (*models.CategorizationRule, error) {
+	args := svc.Called(ctx, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategorizationRule), args.Error(1)
+}
+
+func (svc *MockService) DeleteCategorizationRule(ctx context.Context, ruleID string) error {
+	args := svc.Called(ctx, ruleID)
+	return args.Error(0)
+}
+
+func (svc *MockService) ApplyCategorizationRules(ctx context.Context, input models.ApplyCategorizationRulesInput) (*models.CategorizationRulesReportDto, error) {
+	args := svc.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategorizationRulesReportDto), args.Error(1)
+}
+
+func (svc *MockService) GetSuggestions(ctx context.Context, input models.SuggestionsInput) (*models.SuggestionsDto, error) {
+	args := svc.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SuggestionsDto), args.Error(1)
+}
+
 // Ensure MockService implements Service
 var _ Service = (*MockService)(nil)
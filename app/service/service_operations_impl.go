@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// GetOperation retrieves all transactions of an operation
+func (s *ServiceImpl) GetOperation(ctx context.Context, operationID string) ([]models.Transaction, error) {
+	return s.getAccessibleOperation(ctx, operationID, accessRead)
+}
+
+// UpdateOperation replaces all transactions of an operation with the given ones, which keep the operation ID and get
+// new transaction IDs. The old transactions are soft deleted and the new ones created in a single database transaction.
+// Restoring a replaced transaction is refused, so it can't come back next to its replacements.
+func (s *ServiceImpl) UpdateOperation(ctx context.Context, operationID string, transactions []models.Transaction) ([]models.Transaction, error) {
+	if err := validateTransactionBatchSize(transactions); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getAccessibleOperation(ctx, operationID, accessWrite)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range existing {
+		if err := s.checkNotTransferLeg(ctx, tx.ID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	opUUID, err := uuid.Parse(operationID)
+	if err != nil {
+		return nil, &models.ValidationError{Field: "operationId", Reason: "must be a UUID"}
+	}
+	for i := range transactions {
+		transactions[i].OperationID = &opUUID
+	}
+
+	if err := s.prepareTransactions(ctx, transactions); err != nil {
+		return nil, err
+	}
+
+	// Validate move operations come in pairs
+	if err := validateMovementOperations(transactions); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ReplaceOperationTransactions(ctx, operationID, transactions)
+}
+
+// DeleteOperation deletes all transactions of an operation atomically
+func (s *ServiceImpl) DeleteOperation(ctx context.Context, operationID string) error {
+	existing, err := s.getAccessibleOperation(ctx, operationID, accessWrite)
+	if err != nil {
+		return err
+	}
+	for _, tx := range existing {
+		if err := s.checkNotTransferLeg(ctx, tx.ID.String()); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteOperation(ctx, operationID)
+}
+
+// getAccessibleOperation retrieves the transactions of an operation and checks the caller has the given access to
+// every one of them
+func (s *ServiceImpl) getAccessibleOperation(ctx context.Context, operationID string, level accessLevel) ([]models.Transaction, error) {
+	transactions, err := s.repo.GetOperationTransactions(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range transactions {
+		if err := s.authorizeAccess(ctx, "operation", tx.UserID, tx.GroupID, level); err != nil {
+			return nil, err
+		}
+	}
+	return transactions, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/repo"
+	"github.com/stretchr/testify/mock"
+)
+
+// operationFixture is an operation of a user without group with two expense transactions on one balance
+type operationFixture struct {
+	userID      uuid.UUID
+	balanceID   uuid.UUID
+	operationID uuid.UUID
+	existing    []models.Transaction
+}
+
+func newOperationFixture() operationFixture {
+	f := operationFixture{userID: uuid.New(), balanceID: uuid.New(), operationID: models.NewOperationID()}
+	f.existing = []models.Transaction{f.transaction("expense", 1000), f.transaction("expense", 2000)}
+	for i := range f.existing {
+		f.existing[i].ID = models.NewTransactionID()
+		f.existing[i].OperationID = &f.operationID
+	}
+	return f
+}
+
+func (f operationFixture) context() context.Context {
+	return principalContext(f.userID)
+}
+
+func (f operationFixture) transaction(txType string, amount int64) models.Transaction {
+	return models.Transaction{
+		UserID:       f.userID,
+		BalanceID:    f.balanceID,
+		Type:         txType,
+		TransactedAt: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		TransactionEntries: []models.TransactionEntry{
+			{ID: models.NewTransactionEntryID(), Amount: amount},
+		},
+	}
+}
+
+// expectOperation sets up the lookups of the existing operation and its transactions
+func (f operationFixture) expectOperation(mockRepo *repo.MockRepository) {
+	mockRepo.On("GetOperationTransactions", mock.Anything, f.operationID.String()).Return(f.existing, nil)
+	for _, tx := range f.existing {
+		mockRepo.On("FindTransferByTransactionID", mock.Anything, tx.ID.String()).Return(nil, nil)
+	}
+	mockRepo.On("GetBalance", mock.Anything, f.balanceID.String()).
+		Return(&models.Balance{ID: f.balanceID, UserID: f.userID, Currency: "EUR"}, nil)
+	mockRepo.On("ListCategorizationRules", mock.Anything, mock.Anything).Return(nil, nil)
+}
+
+func TestUpdateOperationReplacesTransactions(t *testing.T) {
+	f := newOperationFixture()
+	svc, mockRepo := newTestService()
+	f.expectOperation(mockRepo)
+
+	replacement := []models.Transaction{f.transaction("income", 5000)}
+	var replaced []models.Transaction
+	mockRepo.On("ReplaceOperationTransactions", mock.Anything, f.operationID.String(), mock.AnythingOfType("[]models.Transaction")).
+		Run(func(args mock.Arguments) { replaced = args.Get(2).([]models.Transaction) }).
+		Return([]models.Transaction{}, nil)
+
+	if _, err := svc.UpdateOperation(f.context(), f.operationID.String(), replacement); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(replaced) != 1 {
+		t.Fatalf("Expected 1 transaction to be stored, got %d", len(replaced))
+	}
+
+	tx := replaced[0]
+	if tx.OperationID == nil || *tx.OperationID != f.operationID {
+		t.Errorf("Expected the replacement to keep operation ID %s, got %v", f.operationID, tx.OperationID)
+	}
+	if tx.ID == uuid.Nil || tx.ID == f.existing[0].ID || tx.ID == f.existing[1].ID {
+		t.Errorf("Expected the replacement to get a new transaction ID, got %s", tx.ID)
+	}
+	if tx.TransactionEntries[0].TransactionID != tx.ID {
+		t.Errorf("Expected entries to reference transaction %s, got %s", tx.ID, tx.TransactionEntries[0].TransactionID)
+	}
+	if len(tx.TransactionEntries[0].TransactionEntryAmounts) == 0 {
+		t.Error("Expected entry amounts to be converted")
+	}
+}
+
+func TestUpdateOperationRejectsInvalidBatches(t *testing.T) {
+	f := newOperationFixture()
+
+	tests := []struct {
+		name         string
+		transactions []models.Transaction
+	}{
+		{"empty", nil},
+		{"more than 5 transactions", []models.Transaction{
+			f.transaction("expense", 1), f.transaction("expense", 2), f.transaction("expense", 3),
+			f.transaction("expense", 4), f.transaction("expense", 5), f.transaction("expense", 6),
+		}},
+		{"move_out without move_in", []models.Transaction{f.transaction("move_out", 1000), f.transaction("expense", 1000)}},
+		{"move_in without move_out", []models.Transaction{f.transaction("move_in", 1000)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo := newTestService()
+			f.expectOperation(mockRepo)
+
+			_, err := svc.UpdateOperation(f.context(), f.operationID.String(), tt.transactions)
+			assertValidationError(t, err)
+			mockRepo.AssertNotCalled(t, "ReplaceOperationTransactions", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateOperationAcceptsPairedMovement(t *testing.T) {
+	f := newOperationFixture()
+	svc, mockRepo := newTestService()
+	f.expectOperation(mockRepo)
+	mockRepo.On("ReplaceOperationTransactions", mock.Anything, f.operationID.String(), mock.AnythingOfType("[]models.Transaction")).
+		Return([]models.Transaction{}, nil)
+
+	paired := []models.Transaction{f.transaction("move_out", 1000), f.transaction("move_in", 1000)}
+	if _, err := svc.UpdateOperation(f.context(), f.operationID.String(), paired); err != nil {
+		t.Fatalf("Expected paired movement to be accepted, got %v", err)
+	}
+	mockRepo.AssertCalled(t, "ReplaceOperationTransactions", mock.Anything, f.operationID.String(), mock.Anything)
+}
+
+func TestDeleteOperation(t *testing.T) {
+	f := newOperationFixture()
+
+	t.Run("owner", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		f.expectOperation(mockRepo)
+		mockRepo.On("DeleteOperation", mock.Anything, f.operationID.String()).Return(nil).Once()
+
+		if err := svc.DeleteOperation(f.context(), f.operationID.String()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		mockRepo.AssertCalled(t, "DeleteOperation", mock.Anything, f.operationID.String())
+	})
+
+	t.Run("another user", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		f.expectOperation(mockRepo)
+
+		assertForbidden(t, svc.DeleteOperation(principalContext(uuid.New()), f.operationID.String()))
+		mockRepo.AssertNotCalled(t, "DeleteOperation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("transfer legs", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetOperationTransactions", mock.Anything, f.operationID.String()).Return(f.existing, nil)
+		mockRepo.On("FindTransferByTransactionID", mock.Anything, f.existing[0].ID.String()).
+			Return(&models.Transfer{ID: uuid.New()}, nil)
+
+		if err := svc.DeleteOperation(f.context(), f.operationID.String()); err == nil {
+			t.Fatal("Expected deleting transfer legs through the operation to be rejected")
+		}
+		mockRepo.AssertNotCalled(t, "DeleteOperation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("transfer leg after the first transaction", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetOperationTransactions", mock.Anything, f.operationID.String()).Return(f.existing, nil)
+		mockRepo.On("FindTransferByTransactionID", mock.Anything, f.existing[0].ID.String()).Return(nil, nil)
+		mockRepo.On("FindTransferByTransactionID", mock.Anything, f.existing[1].ID.String()).
+			Return(&models.Transfer{ID: uuid.New()}, nil)
+
+		if err := svc.DeleteOperation(f.context(), f.operationID.String()); !errors.Is(err, ErrTransferLeg) {
+			t.Fatalf("Expected ErrTransferLeg, got %v", err)
+		}
+		mockRepo.AssertNotCalled(t, "DeleteOperation", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateOperationRejectsTransferLegs(t *testing.T) {
+	f := newOperationFixture()
+	svc, mockRepo := newTestService()
+	mockRepo.On("GetOperationTransactions", mock.Anything, f.operationID.String()).Return(f.existing, nil)
+	mockRepo.On("FindTransferByTransactionID", mock.Anything, f.existing[0].ID.String()).Return(nil, nil)
+	mockRepo.On("FindTransferByTransactionID", mock.Anything, f.existing[1].ID.String()).
+		Return(&models.Transfer{ID: uuid.New()}, nil)
+
+	_, err := svc.UpdateOperation(f.context(), f.operationID.String(), []models.Transaction{f.transaction("expense", 1000)})
+	if !errors.Is(err, ErrTransferLeg) {
+		t.Fatalf("Expected ErrTransferLeg, got %v", err)
+	}
+	mockRepo.AssertNotCalled(t, "ReplaceOperationTransactions", mock.Anything, mock.Anything, mock.Anything)
+}
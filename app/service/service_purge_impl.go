@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// PurgeDeletedData permanently deletes transactions, entries, amounts, balances, merchants, categories and category
+// groups that were soft-deleted more than input.RetentionDays ago. Deleted rows can't be restored afterwards.
+func (s *ServiceImpl) PurgeDeletedData(ctx context.Context, input models.PurgeDeletedDataInput) (*models.PurgeDeletedDataReportDto, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	retentionDays := input.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = models.DefaultRetentionDays
+	}
+	now := input.Now.UTC()
+	if input.Now.IsZero() {
+		now = time.Now().UTC()
+	}
+	cutoff := now.AddDate(0, 0, -retentionDays)
+
+	purged, err := s.repo.PurgeDeletedData(ctx, cutoff, input.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PurgeDeletedDataReportDto{
+		DryRun:        input.DryRun,
+		RetentionDays: retentionDays,
+		Cutoff:        cutoff.Format(time.RFC3339),
+		Tables:        purged,
+	}
+	fields := log.Fields{"dry_run": input.DryRun, "cutoff": report.Cutoff}
+	for _, table := range purged {
+		report.TotalRows += table.Rows
+		fields[table.Table] = table.Rows
+	}
+	log.WithFields(fields).Info("Purged soft-deleted data")
+	return report, nil
+}
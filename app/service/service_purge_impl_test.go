@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurgeDeletedData(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	admin := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: uuid.New(), Admin: true})
+	purged := []models.PurgedTableRowsDto{{Table: "transaction", Rows: 3}, {Table: "balance", Rows: 1}}
+
+	tests := []struct {
+		name          string
+		input         models.PurgeDeletedDataInput
+		wantCutoff    time.Time
+		wantRetention int
+	}{
+		{
+			name:          "default retention",
+			input:         models.PurgeDeletedDataInput{Now: now},
+			wantCutoff:    now.AddDate(0, 0, -models.DefaultRetentionDays),
+			wantRetention: models.DefaultRetentionDays,
+		},
+		{
+			name:          "custom retention",
+			input:         models.PurgeDeletedDataInput{Now: now, RetentionDays: 7},
+			wantCutoff:    time.Date(2024, 6, 23, 12, 0, 0, 0, time.UTC),
+			wantRetention: 7,
+		},
+		{
+			name:          "dry run",
+			input:         models.PurgeDeletedDataInput{Now: now, RetentionDays: 30, DryRun: true},
+			wantCutoff:    time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC),
+			wantRetention: 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo := newTestService()
+			mockRepo.On("PurgeDeletedData", mock.Anything, tt.wantCutoff, tt.input.DryRun).Return(purged, nil)
+
+			report, err := svc.PurgeDeletedData(admin, tt.input)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			mockRepo.AssertCalled(t, "PurgeDeletedData", mock.Anything, tt.wantCutoff, tt.input.DryRun)
+			if report.Cutoff != tt.wantCutoff.Format(time.RFC3339) || report.RetentionDays != tt.wantRetention {
+				t.Errorf("Expected cutoff %s after %d days, got %s after %d days", tt.wantCutoff.Format(time.RFC3339),
+					tt.wantRetention, report.Cutoff, report.RetentionDays)
+			}
+			if report.DryRun != tt.input.DryRun || report.TotalRows != 4 || len(report.Tables) != 2 {
+				t.Errorf("Expected the purged rows to be reported, got %+v", report)
+			}
+		})
+	}
+}
+
+func TestPurgeDeletedDataRequiresAdmin(t *testing.T) {
+	svc, mockRepo := newTestService()
+
+	_, err := svc.PurgeDeletedData(principalContext(uuid.New()), models.PurgeDeletedDataInput{})
+	assertForbidden(t, err)
+	mockRepo.AssertNotCalled(t, "PurgeDeletedData", mock.Anything, mock.Anything, mock.Anything)
+}
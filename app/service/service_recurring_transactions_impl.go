@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxRecurringOccurrencesPerRun bounds the occurrences materialized per recurring transaction and run, the rest
+// is caught up by the following runs
+const maxRecurringOccurrencesPerRun = 100
+
+// ErrInvalidRecurringTemplate is returned when the balance, merchant or a category of a template doesn't exist
+var ErrInvalidRecurringTemplate error = &models.ValidationError{Reason: "invalid recurring transaction template"}
+
+func (s *ServiceImpl) CreateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	if err := s.authorizeAccess(ctx, "recurring transaction", recurring.UserID, recurring.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.checkRecurringTemplate(ctx, recurring); err != nil {
+		return nil, err
+	}
+
+	recurring.ID = models.NewRecurringTransactionID()
+	nextOccurrenceAt, err := nextRecurringOccurrence(recurring, nil)
+	if err != nil {
+		return nil, err
+	}
+	recurring.NextOccurrenceAt = nextOccurrenceAt
+	recurring.LastOccurrenceAt = nil
+	recurring.OccurrencesCount = 0
+	return s.repo.CreateRecurringTransaction(ctx, recurring)
+}
+
+func (s *ServiceImpl) GetRecurringTransaction(ctx context.Context, recurringTransactionID string) (*models.RecurringTransaction, error) {
+	return s.getAccessibleRecurringTransaction(ctx, recurringTransactionID, accessRead)
+}
+
+func (s *ServiceImpl) ListRecurringTransactions(ctx context.Context, filter models.ListRecurringTransactionsInput) ([]models.RecurringTransaction, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRecurringTransactions(ctx, filter)
+}
+
+// UpdateRecurringTransaction replaces the template and schedule, occurrences that were already materialized are
+// never generated again
+func (s *ServiceImpl) UpdateRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction) (*models.RecurringTransaction, error) {
+	existing, err := s.getAccessibleRecurringTransaction(ctx, recurring.ID.String(), accessWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "recurring transaction", recurring.UserID, recurring.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if err := s.checkRecurringTemplate(ctx, recurring); err != nil {
+		return nil, err
+	}
+
+	if recurring.NextOccurrenceAt, err = nextRecurringOccurrence(recurring, existing.LastOccurrenceAt); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateRecurringTransaction(ctx, recurring)
+}
+
+func (s *ServiceImpl) DeleteRecurringTransaction(ctx context.Context, recurringTransactionID string) error {
+	if _, err := s.getAccessibleRecurringTransaction(ctx, recurringTransactionID, accessWrite); err != nil {
+		return err
+	}
+	return s.repo.DeleteRecurringTransaction(ctx, recurringTransactionID)
+}
+
+// RunRecurringTransactions creates the transactions of all active recurring transactions with occurrences due up to
+// input.Now. Every transaction is created via CreateTransaction and tagged with its recurring transaction ID and
+// occurrence, an occurrence that already has a transaction is skipped, so runs can safely be repeated or overlap.
+func (s *ServiceImpl) RunRecurringTransactions(ctx context.Context, input models.RunRecurringTransactionsInput) (*models.RecurringTransactionsRunDto, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	now := input.Now.UTC()
+	if input.Now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	due, err := s.repo.ListDueRecurringTransactions(ctx, now, input.RecurringTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RecurringTransactionsRunDto{
+		RunAt:   now.Format(time.RFC3339),
+		DryRun:  input.DryRun,
+		Results: []models.RecurringTransactionRunResultDto{},
+	}
+	for i := range due {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		result := s.runRecurringTransaction(ctx, due[i], now, input.DryRun)
+		report.TemplatesProcessed++
+		report.TransactionsCreated += len(result.TransactionIDs)
+		report.OccurrencesSkipped += result.OccurrencesSkipped
+		if result.Error != "" {
+			report.TemplatesFailed++
+			log.WithFields(log.Fields{
+				"recurring_transaction_id": result.RecurringTransactionID,
+				"error":                    result.Error,
+			}).Error("Failed to materialize recurring transaction")
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// runRecurringTransaction materializes the due occurrences of one recurring transaction and stores the progress.
+// Processing stops at the first failed occurrence so that it is retried on the next run.
+func (s *ServiceImpl) runRecurringTransaction(ctx context.Context, recurring models.RecurringTransaction, now time.Time, dryRun bool) models.RecurringTransactionRunResultDto {
+	result := models.RecurringTransactionRunResultDto{
+		RecurringTransactionID: recurring.ID.String(),
+		Occurrences:            []string{},
+		TransactionIDs:         []string{},
+		NextOccurrenceAt:       formatRecurringTime(recurring.NextOccurrenceAt),
+	}
+
+	rule, err := models.ParseRecurrenceRule(recurring.Schedule)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var lastProcessed *time.Time
+	occurrences := rule.Between(recurring.StartAt, recurring.NextOccurrenceAt.Add(-time.Nanosecond), now, maxRecurringOccurrencesPerRun)
+	for _, occurrence := range occurrences {
+		existingID, err := s.repo.GetRecurringOccurrenceTransactionID(ctx, recurring.ID.String(), occurrence)
+		if err != nil {
+			result.Error = err.Error()
+			break
+		}
+
+		result.Occurrences = append(result.Occurrences, occurrence.Format(time.RFC3339))
+		if existingID != "" {
+			result.OccurrencesSkipped++
+		} else if !dryRun {
+			tx, err := recurring.NewOccurrenceTransaction(occurrence)
+			if err != nil {
+				result.Error = err.Error()
+				break
+			}
+			created, err := s.CreateTransaction(ctx, *tx)
+			var conflict *models.ConflictError
+			if errors.As(err, &conflict) {
+				// Another run materialized the occurrence in the meantime
+				result.OccurrencesSkipped++
+			} else if err != nil {
+				result.Error = fmt.Sprintf("failed to create transaction for occurrence %s: %v", occurrence.Format(time.RFC3339), err)
+				break
+			} else {
+				result.TransactionIDs = append(result.TransactionIDs, created.ID.String())
+				recurring.OccurrencesCount++
+			}
+		}
+
+		processed := occurrence
+		lastProcessed = &processed
+	}
+	if lastProcessed == nil {
+		return result
+	}
+
+	recurring.LastOccurrenceAt = lastProcessed
+	recurring.NextOccurrenceAt = nil
+	if next, ok := rule.Next(recurring.StartAt, *lastProcessed); ok {
+		recurring.NextOccurrenceAt = &next
+	}
+	result.NextOccurrenceAt = formatRecurringTime(recurring.NextOccurrenceAt)
+
+	if !dryRun {
+		if err := s.repo.UpdateRecurringTransactionProgress(ctx, recurring); err != nil && result.Error == "" {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// checkRecurringTemplate checks that the balance, merchant and categories of the template exist and are accessible
+func (s *ServiceImpl) checkRecurringTemplate(ctx context.Context, recurring models.RecurringTransaction) error {
+	template, err := recurring.TransactionTemplate()
+	if err != nil {
+		return err
+	}
+	tx, err := models.FromAPICreateTransaction(template)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.getAccessibleBalance(ctx, tx.BalanceID.String(), accessWrite)
+	if err == nil && tx.MerchantID != nil {
+		_, err = s.getAccessibleMerchant(ctx, tx.MerchantID.String(), accessRead)
+	}
+	for _, entry := range tx.TransactionEntries {
+		if err == nil && entry.CategoryID != nil {
+			_, err = s.getAccessibleCategory(ctx, entry.CategoryID.String(), accessRead)
+		}
+	}
+	if models.IsNotFound(err) {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurringTemplate, err)
+	}
+	return err
+}
+
+// getAccessibleRecurringTransaction retrieves a recurring transaction and checks that the caller has the given access to it
+func (s *ServiceImpl) getAccessibleRecurringTransaction(ctx context.Context, recurringTransactionID string, level accessLevel) (*models.RecurringTransaction, error) {
+	recurring, err := s.repo.GetRecurringTransaction(ctx, recurringTransactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "recurring transaction", recurring.UserID, recurring.GroupID, level); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// nextRecurringOccurrence returns the first occurrence of the schedule after the last materialized one (or the
+// first occurrence at all), nil when the schedule has ended
+func nextRecurringOccurrence(recurring models.RecurringTransaction, lastOccurrenceAt *time.Time) (*time.Time, error) {
+	rule, err := models.ParseRecurrenceRule(recurring.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	after := recurring.StartAt.Add(-time.Nanosecond)
+	if lastOccurrenceAt != nil && lastOccurrenceAt.After(after) {
+		after = *lastOccurrenceAt
+	}
+	next, ok := rule.Next(recurring.StartAt, after)
+	if !ok {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+func formatRecurringTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
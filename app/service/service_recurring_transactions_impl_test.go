@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunRecurringTransactionsSkipsOccurrencesCreatedMeanwhile(t *testing.T) {
+	userID, balanceID := uuid.New(), uuid.New()
+	startAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	recurring := models.RecurringTransaction{
+		ID:               uuid.New(),
+		UserID:           userID,
+		BalanceID:        balanceID,
+		Name:             "Rent",
+		Schedule:         "FREQ=MONTHLY",
+		StartAt:          startAt,
+		NextOccurrenceAt: &startAt,
+	}
+	if err := recurring.SetTransactionTemplate(models.CreateTransactionDto{
+		GroupID:            uuid.Nil.String(),
+		UserID:             userID.String(),
+		BalanceID:          balanceID.String(),
+		Type:               "expense",
+		TransactionEntries: []models.CreateTransactionEntryDto{{Amount: 90000}},
+	}); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	svc, mockRepo := newTestService()
+	mockRepo.On("ListDueRecurringTransactions", mock.Anything, mock.Anything, "").Return([]models.RecurringTransaction{recurring}, nil)
+	mockRepo.On("GetRecurringOccurrenceTransactionID", mock.Anything, recurring.ID.String(), startAt).Return("", nil)
+	mockRepo.On("GetBalance", mock.Anything, balanceID.String()).
+		Return(&models.Balance{ID: balanceID, UserID: userID, Currency: "EUR"}, nil)
+	mockRepo.On("ListCategorizationRules", mock.Anything, mock.Anything).Return(nil, nil)
+	// The unique occurrence index rejects the transaction, another run created it after the lookup
+	mockRepo.On("CreateTransaction", mock.Anything, mock.Anything).
+		Return(nil, &models.ConflictError{Reason: "occurrence already has a transaction"})
+	var progress models.RecurringTransaction
+	mockRepo.On("UpdateRecurringTransactionProgress", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { progress = args.Get(1).(models.RecurringTransaction) }).
+		Return(nil)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.SystemPrincipal())
+	report, err := svc.RunRecurringTransactions(ctx, models.RunRecurringTransactionsInput{Now: startAt.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.TemplatesFailed != 0 || report.TransactionsCreated != 0 || report.OccurrencesSkipped != 1 {
+		t.Errorf("Expected the occurrence to be skipped, got %+v", report)
+	}
+	if progress.LastOccurrenceAt == nil || !progress.LastOccurrenceAt.Equal(startAt) || progress.OccurrencesCount != 0 {
+		t.Errorf("Expected the progress to move past the occurrence without counting it, got %+v", progress)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+var (
+	// ErrNotDeleted is returned when restoring an entity that isn't deleted
+	ErrNotDeleted error = &models.ConflictError{Reason: "entity is not deleted"}
+	// ErrRestoreConflict is returned when restoring an entity would break uniqueness or reference a deleted entity
+	ErrRestoreConflict error = &models.ConflictError{Reason: "entity can't be restored"}
+)
+
+// RestoreTransaction reverses the soft delete of a transaction and regenerates the amounts of its entries. The
+// transactions of its operation deleted at the same time, e.g. the other leg of a movement, are restored with it.
+// A transfer leg is only restored together with the other leg and the transfer linking them.
+func (s *ServiceImpl) RestoreTransaction(ctx context.Context, transactionID string) (*models.SingleTransactionDto, error) {
+	tx, err := s.repo.GetTransactionIncludingDeleted(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "transaction", tx.UserID, tx.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if tx.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: transaction %s", ErrNotDeleted, transactionID)
+	}
+
+	transactions, err := s.repo.ListTransactionsDeletedWith(ctx, *tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTransferRestorable(ctx, transactionID, transactions); err != nil {
+		return nil, err
+	}
+	if err := s.checkOperationNotReplaced(ctx, *tx); err != nil {
+		return nil, err
+	}
+	for i := range transactions {
+		restored := &transactions[i]
+		if err := s.authorizeAccess(ctx, "transaction", restored.UserID, restored.GroupID, accessWrite); err != nil {
+			return nil, err
+		}
+		if restored.Balance == nil || restored.Balance.DeletedAt != nil {
+			return nil, fmt.Errorf("%w: balance %s of transaction %s is deleted, restore it first", ErrRestoreConflict, restored.BalanceID.String(), restored.ID.String())
+		}
+
+		// Amounts were removed on delete, convert the entries again with the rates effective at the transaction date
+		supportedCurrencies, exchangeRates, exchangeRateDate, err := s.getExchangeRatesAt(ctx, restored.Balance.Currency, restored.TransactedAt)
+		if err != nil {
+			return nil, err
+		}
+		for j := range restored.TransactionEntries {
+			entry := &restored.TransactionEntries[j]
+			entry.TransactionEntryAmounts = s.createTransactionEntryAmounts(
+				entry.ID,
+				entry.Amount,
+				restored.Balance.Currency,
+				supportedCurrencies,
+				exchangeRates,
+				exchangeRateDate)
+		}
+	}
+
+	if err := s.repo.RestoreTransactions(ctx, transactions); err != nil {
+		return nil, err
+	}
+	return s.GetTransaction(ctx, transactionID)
+}
+
+// checkTransferRestorable rejects restoring a transfer leg unless both legs of its transfer are restored together
+func (s *ServiceImpl) checkTransferRestorable(ctx context.Context, transactionID string, transactions []models.Transaction) error {
+	transfer, err := s.repo.FindTransferByTransactionID(ctx, transactionID)
+	if err != nil || transfer == nil {
+		return err
+	}
+
+	restored := make(map[uuid.UUID]bool, len(transactions))
+	for _, tx := range transactions {
+		restored[tx.ID] = true
+	}
+	if !restored[transfer.MoveOutTransactionID] || !restored[transfer.MoveInTransactionID] {
+		return fmt.Errorf("%w: transaction %s is a leg of transfer %s whose legs weren't deleted together", ErrRestoreConflict, transactionID, transfer.ID.String())
+	}
+	return nil
+}
+
+// checkOperationNotReplaced rejects restoring a transaction of an operation whose transactions were replaced when it
+// was deleted, so it isn't restored next to its replacements. Replacements are created at the deletion time of the
+// transactions they replace.
+func (s *ServiceImpl) checkOperationNotReplaced(ctx context.Context, tx models.Transaction) error {
+	if tx.OperationID == nil {
+		return nil
+	}
+
+	active, err := s.repo.GetOperationTransactions(ctx, tx.OperationID.String())
+	var notFound *models.NotFoundError
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, replacement := range active {
+		if !replacement.CreatedAt.Before(*tx.DeletedAt) {
+			return fmt.Errorf("%w: operation %s of transaction %s was replaced", ErrRestoreConflict, tx.OperationID.String(), tx.ID.String())
+		}
+	}
+	return nil
+}
+
+// RestoreBalance reverses the soft delete of a balance
+func (s *ServiceImpl) RestoreBalance(ctx context.Context, balanceID string) (*models.Balance, error) {
+	balance, err := s.getAccessibleBalance(ctx, balanceID, accessWrite)
+	if err != nil {
+		return nil, err
+	}
+	if balance.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: balance %s", ErrNotDeleted, balanceID)
+	}
+
+	if err := s.repo.RestoreBalance(ctx, balanceID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBalance(ctx, balanceID)
+}
+
+// RestoreCategory reverses the soft delete of a category, its category group must not be deleted
+func (s *ServiceImpl) RestoreCategory(ctx context.Context, categoryID string) (*models.Category, error) {
+	category, err := s.repo.GetCategoryIncludingDeleted(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "category", category.UserId, category.GroupId, accessWrite); err != nil {
+		return nil, err
+	}
+	if category.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: category %s", ErrNotDeleted, categoryID)
+	}
+
+	if category.CategoryGroupId != "" {
+		categoryGroup, err := s.repo.GetCategoryGroup(ctx, category.CategoryGroupId)
+		if err != nil {
+			return nil, err
+		}
+		if categoryGroup == nil {
+			return nil, fmt.Errorf("%w: category group %s is deleted, restore it first", ErrRestoreConflict, category.CategoryGroupId)
+		}
+	}
+
+	if err := s.repo.RestoreCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCategory(ctx, categoryID)
+}
+
+// RestoreCategoryGroup reverses the soft delete of a category group, category groups are managed by admins
+func (s *ServiceImpl) RestoreCategoryGroup(ctx context.Context, categoryGroupID string) (*models.CategoryGroup, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	categoryGroup, err := s.repo.GetCategoryGroupIncludingDeleted(ctx, categoryGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if categoryGroup.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: category group %s", ErrNotDeleted, categoryGroupID)
+	}
+
+	if err := s.repo.RestoreCategoryGroup(ctx, categoryGroupID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCategoryGroup(ctx, categoryGroupID)
+}
+
+// RestoreMerchant reverses the soft delete of a merchant unless a merchant with the same name was created since
+func (s *ServiceImpl) RestoreMerchant(ctx context.Context, merchantID string) (*models.Merchant, error) {
+	merchant, err := s.repo.GetMerchantIncludingDeleted(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "merchant", merchant.UserID, merchant.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+	if merchant.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: merchant %s", ErrNotDeleted, merchantID)
+	}
+
+	existingMerchant, err := s.repo.GetMerchantByNameAndUserId(ctx, merchant.Name, merchant.UserID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing merchant: %w", err)
+	}
+	if existingMerchant != nil {
+		return nil, fmt.Errorf("%w: merchant with name '%s' already exists for this user", ErrRestoreConflict, merchant.Name)
+	}
+
+	if err := s.repo.RestoreMerchant(ctx, merchantID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMerchant(ctx, merchantID)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/repo"
+	"github.com/stretchr/testify/mock"
+)
+
+// deletedTransaction builds a soft-deleted expense of the user on an active EUR balance
+func deletedTransaction(userID uuid.UUID) models.Transaction {
+	deletedAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	transactionID := models.NewTransactionID()
+	balanceID := uuid.New()
+	return models.Transaction{
+		ID:           transactionID,
+		UserID:       userID,
+		BalanceID:    balanceID,
+		Balance:      &models.Balance{ID: balanceID, UserID: userID, Currency: "EUR"},
+		Type:         "expense",
+		TransactedAt: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		DeletedAt:    &deletedAt,
+		TransactionEntries: []models.TransactionEntry{
+			{ID: models.NewTransactionEntryID(), TransactionID: transactionID, Amount: 1000, DeletedAt: &deletedAt},
+		},
+	}
+}
+
+// expectRestoreLookups sets up the lookups of a deleted transaction and the transactions deleted with it
+func expectRestoreLookups(mockRepo *repo.MockRepository, tx models.Transaction, deletedWith []models.Transaction) {
+	mockRepo.On("GetTransactionIncludingDeleted", mock.Anything, tx.ID.String()).Return(&tx, nil)
+	mockRepo.On("ListTransactionsDeletedWith", mock.Anything, mock.Anything).Return(deletedWith, nil)
+	mockRepo.On("FindTransferByTransactionID", mock.Anything, tx.ID.String()).Return(nil, nil)
+	if tx.OperationID != nil {
+		mockRepo.On("GetOperationTransactions", mock.Anything, tx.OperationID.String()).
+			Return(nil, &models.NotFoundError{Entity: "operation", ID: tx.OperationID.String()})
+	}
+}
+
+func TestRestoreTransaction(t *testing.T) {
+	userID := uuid.New()
+	tx := deletedTransaction(userID)
+
+	svc, mockRepo := newTestService()
+	expectRestoreLookups(mockRepo, tx, []models.Transaction{tx})
+	var restored []models.Transaction
+	mockRepo.On("RestoreTransactions", mock.Anything, mock.AnythingOfType("[]models.Transaction")).
+		Run(func(args mock.Arguments) { restored = args.Get(1).([]models.Transaction) }).
+		Return(nil)
+	mockRepo.On("GetTransaction", mock.Anything, tx.ID.String()).Return(&tx, nil)
+
+	if _, err := svc.RestoreTransaction(principalContext(userID), tx.ID.String()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(restored) != 1 {
+		t.Fatalf("Expected 1 transaction to be restored, got %d", len(restored))
+	}
+	if len(restored[0].TransactionEntries[0].TransactionEntryAmounts) == 0 {
+		t.Error("Expected entry amounts to be regenerated")
+	}
+}
+
+func TestRestoreOperationTransaction(t *testing.T) {
+	userID := uuid.New()
+	operationID := models.NewOperationID()
+	first, second := deletedTransaction(userID), deletedTransaction(userID)
+	first.OperationID, second.OperationID = &operationID, &operationID
+
+	svc, mockRepo := newTestService()
+	expectRestoreLookups(mockRepo, second, []models.Transaction{first, second})
+	var restored []models.Transaction
+	mockRepo.On("RestoreTransactions", mock.Anything, mock.AnythingOfType("[]models.Transaction")).
+		Run(func(args mock.Arguments) { restored = args.Get(1).([]models.Transaction) }).
+		Return(nil)
+	mockRepo.On("GetTransaction", mock.Anything, second.ID.String()).Return(&second, nil)
+
+	if _, err := svc.RestoreTransaction(principalContext(userID), second.ID.String()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(restored) != 2 || restored[0].ID != first.ID || restored[1].ID != second.ID {
+		t.Fatalf("Expected the transactions deleted with the operation to be restored together, got %d", len(restored))
+	}
+	for _, tx := range restored {
+		if len(tx.TransactionEntries[0].TransactionEntryAmounts) == 0 {
+			t.Errorf("Expected entry amounts of transaction %s to be regenerated", tx.ID)
+		}
+	}
+}
+
+func TestRestoreReplacedOperationTransaction(t *testing.T) {
+	userID := uuid.New()
+	operationID := models.NewOperationID()
+	replaced := deletedTransaction(userID)
+	replaced.OperationID = &operationID
+
+	// The replacement is created at the deletion time of the transaction it replaced
+	replacement := deletedTransaction(userID)
+	replacement.OperationID = &operationID
+	replacement.CreatedAt = *replaced.DeletedAt
+	replacement.DeletedAt = nil
+
+	svc, mockRepo := newTestService()
+	mockRepo.On("GetOperationTransactions", mock.Anything, operationID.String()).Return([]models.Transaction{replacement}, nil)
+	expectRestoreLookups(mockRepo, replaced, []models.Transaction{replaced})
+
+	_, err := svc.RestoreTransaction(principalContext(userID), replaced.ID.String())
+	if !errors.Is(err, ErrRestoreConflict) {
+		t.Fatalf("Expected ErrRestoreConflict, got %v", err)
+	}
+	mockRepo.AssertNotCalled(t, "RestoreTransactions", mock.Anything, mock.Anything)
+
+	// A transaction of the operation that was there before the delete doesn't block the restore
+	replacement.CreatedAt = replaced.DeletedAt.Add(-time.Hour)
+	svc, mockRepo = newTestService()
+	mockRepo.On("GetOperationTransactions", mock.Anything, operationID.String()).Return([]models.Transaction{replacement}, nil)
+	expectRestoreLookups(mockRepo, replaced, []models.Transaction{replaced})
+	mockRepo.On("RestoreTransactions", mock.Anything, mock.AnythingOfType("[]models.Transaction")).Return(nil)
+	mockRepo.On("GetTransaction", mock.Anything, replaced.ID.String()).Return(&replaced, nil)
+	if _, err := svc.RestoreTransaction(principalContext(userID), replaced.ID.String()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestRestoreTransactionRejections(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("another user", func(t *testing.T) {
+		tx := deletedTransaction(userID)
+		svc, mockRepo := newTestService()
+		expectRestoreLookups(mockRepo, tx, []models.Transaction{tx})
+
+		_, err := svc.RestoreTransaction(principalContext(uuid.New()), tx.ID.String())
+		assertForbidden(t, err)
+		mockRepo.AssertNotCalled(t, "RestoreTransactions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not deleted", func(t *testing.T) {
+		tx := deletedTransaction(userID)
+		tx.DeletedAt = nil
+		svc, mockRepo := newTestService()
+		expectRestoreLookups(mockRepo, tx, nil)
+
+		_, err := svc.RestoreTransaction(principalContext(userID), tx.ID.String())
+		if !errors.Is(err, ErrNotDeleted) {
+			t.Fatalf("Expected ErrNotDeleted, got %v", err)
+		}
+	})
+
+	t.Run("deleted balance", func(t *testing.T) {
+		tx := deletedTransaction(userID)
+		tx.Balance.DeletedAt = tx.DeletedAt
+		svc, mockRepo := newTestService()
+		expectRestoreLookups(mockRepo, tx, []models.Transaction{tx})
+
+		_, err := svc.RestoreTransaction(principalContext(userID), tx.ID.String())
+		if !errors.Is(err, ErrRestoreConflict) {
+			t.Fatalf("Expected ErrRestoreConflict, got %v", err)
+		}
+		mockRepo.AssertNotCalled(t, "RestoreTransactions", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestoreTransferLeg(t *testing.T) {
+	userID := uuid.New()
+	moveOut := deletedTransaction(userID)
+	moveOut.Type = "move_out"
+	moveIn := deletedTransaction(userID)
+	moveIn.Type = "move_in"
+	transfer := &models.Transfer{
+		ID:                   models.NewOperationID(),
+		UserID:               userID,
+		MoveOutTransactionID: moveOut.ID,
+		MoveInTransactionID:  moveIn.ID,
+		MoveOut:              &moveOut,
+		MoveIn:               &moveIn,
+	}
+	ctx := principalContext(userID)
+
+	t.Run("delete, restore and update a leg", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetTransfer", mock.Anything, transfer.ID.String()).Return(transfer, nil)
+		mockRepo.On("DeleteTransfer", mock.Anything, transfer.ID.String()).Return(nil)
+		if err := svc.DeleteTransfer(ctx, transfer.ID.String()); err != nil {
+			t.Fatalf("Expected the transfer to be deleted, got %v", err)
+		}
+
+		// Deleted transfers are still found by their legs, restoring a leg brings back both legs and the transfer
+		mockRepo.On("GetTransactionIncludingDeleted", mock.Anything, moveIn.ID.String()).Return(&moveIn, nil)
+		mockRepo.On("ListTransactionsDeletedWith", mock.Anything, mock.Anything).Return([]models.Transaction{moveOut, moveIn}, nil)
+		mockRepo.On("FindTransferByTransactionID", mock.Anything, moveIn.ID.String()).Return(transfer, nil)
+		mockRepo.On("RestoreTransactions", mock.Anything, mock.AnythingOfType("[]models.Transaction")).Return(nil)
+		mockRepo.On("GetTransaction", mock.Anything, moveIn.ID.String()).Return(&moveIn, nil)
+		if _, err := svc.RestoreTransaction(ctx, moveIn.ID.String()); err != nil {
+			t.Fatalf("Expected the transfer leg to be restored, got %v", err)
+		}
+
+		_, err := svc.UpdateTransaction(ctx, moveIn.ID.String(), models.UpdateTransactionDto{Type: "income"})
+		if !errors.Is(err, ErrTransferLeg) {
+			t.Fatalf("Expected the restored leg to stay bound to its transfer, got %v", err)
+		}
+		mockRepo.AssertNotCalled(t, "UpdateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("leg without the other leg", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetTransactionIncludingDeleted", mock.Anything, moveIn.ID.String()).Return(&moveIn, nil)
+		mockRepo.On("ListTransactionsDeletedWith", mock.Anything, mock.Anything).Return([]models.Transaction{moveIn}, nil)
+		mockRepo.On("FindTransferByTransactionID", mock.Anything, moveIn.ID.String()).Return(transfer, nil)
+
+		_, err := svc.RestoreTransaction(ctx, moveIn.ID.String())
+		if !errors.Is(err, ErrRestoreConflict) {
+			t.Fatalf("Expected ErrRestoreConflict, got %v", err)
+		}
+		mockRepo.AssertNotCalled(t, "RestoreTransactions", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestoreCategoryGroupRequiresAdmin(t *testing.T) {
+	categoryGroupID := uuid.New()
+	deletedAt := time.Now().UTC()
+	deleted := &models.CategoryGroup{ID: categoryGroupID, Name: "Food", DeletedAt: &deletedAt}
+
+	t.Run("regular user", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		mockRepo.On("GetCategoryGroupIncludingDeleted", mock.Anything, categoryGroupID.String()).Return(deleted, nil)
+
+		_, err := svc.RestoreCategoryGroup(principalContext(uuid.New()), categoryGroupID.String())
+		assertForbidden(t, err)
+		mockRepo.AssertNotCalled(t, "RestoreCategoryGroup", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		svc, mockRepo := newTestService()
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: uuid.New(), Admin: true})
+		mockRepo.On("GetCategoryGroupIncludingDeleted", mock.Anything, categoryGroupID.String()).Return(deleted, nil)
+		mockRepo.On("RestoreCategoryGroup", mock.Anything, categoryGroupID.String()).Return(nil)
+		mockRepo.On("GetCategoryGroup", mock.Anything, categoryGroupID.String()).
+			Return(&models.CategoryGroup{ID: categoryGroupID, Name: "Food"}, nil)
+
+		if _, err := svc.RestoreCategoryGroup(ctx, categoryGroupID.String()); err != nil {
+			t.Fatalf("Expected admin to restore category groups, got %v", err)
+		}
+		mockRepo.AssertCalled(t, "RestoreCategoryGroup", mock.Anything, categoryGroupID.String())
+	})
+}
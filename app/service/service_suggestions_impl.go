@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+const (
+	// suggestionHistorySize is the number of latest entries of a user that suggestions are learned from
+	suggestionHistorySize = 2000
+
+	// suggestionCacheTTL is how long the history of a user is reused, new entries are picked up after it expires
+	suggestionCacheTTL = 5 * time.Minute
+
+	// suggestionCacheSize bounds the users whose history is cached, the least recently loaded one is evicted
+	suggestionCacheSize = 1000
+)
+
+// GetSuggestions ranks the categories and merchants of the user's past entries for a new entry with the given
+// description, merchant and amount. The history of the user is cached for suggestionCacheTTL.
+func (s *ServiceImpl) GetSuggestions(ctx context.Context, input models.SuggestionsInput) (*models.SuggestionsDto, error) {
+	var err error
+	if input.UserID, _, err = s.scopeListFilter(ctx, input.UserID, ""); err != nil {
+		return nil, err
+	}
+	if input.UserID == "" {
+		return nil, &models.ValidationError{Field: "userId", Reason: "is required"}
+	}
+
+	history, err := s.suggestions.get(input.UserID, func() (*models.SuggestionHistory, error) {
+		entries, err := s.repo.ListSuggestionHistory(ctx, input.UserID, suggestionHistorySize)
+		if err != nil {
+			return nil, err
+		}
+		return models.NewSuggestionHistory(entries), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := history.Suggest(input, time.Now().UTC())
+	return &suggestions, nil
+}
+
+// suggestionCache keeps the tokenized entry history of recently active users in memory
+type suggestionCache struct {
+	mu      sync.Mutex
+	entries map[string]suggestionCacheEntry // key: user ID
+}
+
+type suggestionCacheEntry struct {
+	history  *models.SuggestionHistory
+	loadedAt time.Time
+}
+
+func newSuggestionCache() *suggestionCache {
+	return &suggestionCache{entries: make(map[string]suggestionCacheEntry)}
+}
+
+// get returns the cached history of the user, calling load if it is missing or expired. Concurrent misses of the
+// same user may load the history twice, the last one is kept.
+func (c *suggestionCache) get(userID string, load func() (*models.SuggestionHistory, error)) (*models.SuggestionHistory, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+	if ok && time.Since(entry.loadedAt) < suggestionCacheTTL {
+		return entry.history, nil
+	}
+
+	history, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[userID]; !ok && len(c.entries) >= suggestionCacheSize {
+		c.evictOldest()
+	}
+	c.entries[userID] = suggestionCacheEntry{history: history, loadedAt: time.Now()}
+	return history, nil
+}
+
+// evictOldest removes the history loaded longest ago, the caller holds the lock
+func (c *suggestionCache) evictOldest() {
+	var oldestUserID string
+	var oldest time.Time
+	for userID, entry := range c.entries {
+		if oldestUserID == "" || entry.loadedAt.Before(oldest) {
+			oldestUserID, oldest = userID, entry.loadedAt
+		}
+	}
+	delete(c.entries, oldestUserID)
+}
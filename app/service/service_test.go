@@ -1 +1,48 @@
 package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	"github.com/savak1990/transactions-service/app/repo"
+)
+
+// newTestService creates a service backed by a mock repository
+func newTestService() (*ServiceImpl, *repo.MockRepository) {
+	mockRepo := repo.NewMockRepository()
+	return NewServiceImpl(mockRepo, repo.NewExchangeRatesStaticDb()), mockRepo
+}
+
+// principalContext returns a context carrying a regular user principal that is a member of the given groups
+func principalContext(userID uuid.UUID, groupIDs ...uuid.UUID) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{UserID: userID, GroupIDs: groupIDs})
+}
+
+// testGroup builds a registered group with the given members and their roles
+func testGroup(groupID uuid.UUID, roles map[uuid.UUID]string) *models.Group {
+	group := &models.Group{ID: groupID, Name: "Household"}
+	for userID, role := range roles {
+		group.Members = append(group.Members, models.GroupMember{GroupID: groupID, UserID: userID, Role: role})
+	}
+	return group
+}
+
+func assertForbidden(t *testing.T, err error) {
+	t.Helper()
+	var forbidden *models.ForbiddenError
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("Expected ForbiddenError, got %v", err)
+	}
+}
+
+func assertValidationError(t *testing.T, err error) {
+	t.Helper()
+	var validation *models.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+}
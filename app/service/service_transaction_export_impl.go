@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// exportPageSize is the number of entries loaded per query while exporting, the maximum page size of the repository
+const exportPageSize = 100
+
+// ExportTransactionEntries passes every transaction entry matching the filter to handle, one page at a time in the
+// order of the filter. Pages are loaded with keyset pagination, so exports aren't limited in size and memory use
+// doesn't grow with the number of entries. Limit and Cursor of the filter are ignored.
+func (s *ServiceImpl) ExportTransactionEntries(ctx context.Context, filter models.ListTransactionsInput, handle func([]models.TransactionEntry) error) error {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return err
+	}
+	filter.Limit = exportPageSize
+	filter.Cursor = nil
+
+	for {
+		entries, err := s.repo.ListTransactionEntries(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			if err := handle(entries); err != nil {
+				return err
+			}
+		}
+		if len(entries) < exportPageSize {
+			return nil
+		}
+
+		cursor := models.TransactionEntryCursor(&entries[len(entries)-1], filter.SortBy, filter.Order)
+		filter.Cursor = &cursor
+	}
+}
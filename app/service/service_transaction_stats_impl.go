@@ -12,6 +12,11 @@ import (
 
 // GetTransactionStats retrieves aggregated transaction statistics
 func (s *ServiceImpl) GetTransactionStats(ctx context.Context, filter models.TransactionStatsInput) ([]models.TransactionStatsItemDto, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
+
 	// Set default display currency if not provided
 	displayCurrency := filter.DisplayCurrency
 	if displayCurrency == "" {
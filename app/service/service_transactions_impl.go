@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/savak1990/transactions-service/app/models"
+	"github.com/sirupsen/logrus"
 )
 
 // createTransactionEntryAmounts creates TransactionEntryAmount records for all supported currencies
@@ -16,6 +17,7 @@ func (s *ServiceImpl) createTransactionEntryAmounts(
 	baseCurrency string,
 	supportedCurrencies []string,
 	exchangeRates map[string]float64,
+	exchangeRateDate *time.Time,
 ) []models.TransactionEntryAmount {
 	var entryAmounts []models.TransactionEntryAmount
 
@@ -44,6 +46,7 @@ func (s *ServiceImpl) createTransactionEntryAmounts(
 			Currency:           currency,
 			Amount:             amount,
 			ExchangeRate:       exchangeRate,
+			ExchangeRateDate:   exchangeRateDate,
 		}
 		entryAmounts = append(entryAmounts, entryAmount)
 	}
@@ -51,44 +54,74 @@ func (s *ServiceImpl) createTransactionEntryAmounts(
 	return entryAmounts
 }
 
+// getExchangeRatesAt fetches supported currencies and the exchange rates effective at the given time.
+// Rates of the nearest earlier available day are used when the exact day is missing; the returned
+// rate date tells which day was actually used (nil if no rates were found).
+func (s *ServiceImpl) getExchangeRatesAt(ctx context.Context, baseCurrency string, at time.Time) ([]string, map[string]float64, *time.Time, error) {
+	supportedCurrencies, err := s.exchangeRatesDb.GetSupportedCurrencies(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get supported currencies: %w", err)
+	}
+
+	exchangeRates, rateDate, err := s.exchangeRatesDb.GetSupportedCurrenciesRatesAt(ctx, baseCurrency, at)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get exchange rates for base currency %s at %s: %w", baseCurrency, at.Format("2006-01-02"), err)
+	}
+
+	if exchangeRates == nil {
+		logrus.WithFields(logrus.Fields{
+			"base_currency": baseCurrency,
+			"date":          at.Format("2006-01-02"),
+		}).Warn("No exchange rates available on or before date, only base currency amounts will be stored")
+		return supportedCurrencies, nil, nil, nil
+	}
+
+	return supportedCurrencies, exchangeRates, &rateDate, nil
+}
+
 func (s *ServiceImpl) CreateTransaction(ctx context.Context, tx models.Transaction) (*models.Transaction, error) {
+	if err := s.authorizeAccess(ctx, "transaction", tx.UserID, tx.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+
 	tx.ID = models.NewTransactionID()
 
 	// Validate balance exists (required) and get it for currency determination
-	balance, err := s.repo.GetBalance(ctx, tx.BalanceID.String())
+	balance, err := s.getAccessibleBalance(ctx, tx.BalanceID.String(), accessWrite)
 	if err != nil {
-		return nil, fmt.Errorf("balance with ID %s not found: %w", tx.BalanceID.String(), err)
+		return nil, referenceError(err, "balanceId")
 	}
 	baseCurrency := balance.Currency
 
 	// Validate merchant exists if merchantID is provided
 	if tx.MerchantID != nil {
-		_, err := s.repo.GetMerchant(ctx, tx.MerchantID.String())
+		_, err := s.getAccessibleMerchant(ctx, tx.MerchantID.String(), accessRead)
 		if err != nil {
-			return nil, fmt.Errorf("merchant with ID %s not found: %w", tx.MerchantID.String(), err)
+			return nil, referenceError(err, "merchantId")
 		}
 	}
 
 	// Validate categories exist for all transaction entries
 	for i, entry := range tx.TransactionEntries {
 		if entry.CategoryID != nil {
-			_, err := s.repo.GetCategory(ctx, entry.CategoryID.String())
+			_, err := s.getAccessibleCategory(ctx, entry.CategoryID.String(), accessRead)
 			if err != nil {
-				return nil, fmt.Errorf("category with ID %s not found for transaction entry %d: %w", entry.CategoryID.String(), i, err)
+				return nil, referenceError(err, fmt.Sprintf("transactionEntries[%d].categoryId", i))
 			}
 		}
 	}
 
-	// Fetch supported currencies and exchange rates
-	supportedCurrencies, err := s.exchangeRatesDb.GetSupportedCurrencies(ctx)
+	// Entries without category are categorized by the rules of the group
+	rules, err := s.categorizationRules(ctx, tx.GroupID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get supported currencies: %w", err)
+		return nil, err
 	}
+	rules.Categorize(&tx)
 
-	// Fetch exchange rates for all supported currencies
-	exchangeRates, err := s.exchangeRatesDb.GetSupportedCurrenciesRates(ctx, baseCurrency)
+	// Fetch supported currencies and exchange rates effective at the transaction date
+	supportedCurrencies, exchangeRates, exchangeRateDate, err := s.getExchangeRatesAt(ctx, baseCurrency, tx.TransactedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get exchange rates for base currency %s: %w", baseCurrency, err)
+		return nil, err
 	}
 
 	// Create TransactionEntryAmount for each transaction entry and each supported currency
@@ -102,17 +135,23 @@ func (s *ServiceImpl) CreateTransaction(ctx context.Context, tx models.Transacti
 			entry.Amount,
 			baseCurrency,
 			supportedCurrencies,
-			exchangeRates)
+			exchangeRates,
+			exchangeRateDate)
 	}
 
-	return s.repo.CreateTransaction(ctx, tx)
+	created, err := s.repo.CreateTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	s.setPossibleDuplicate(ctx, created)
+	return created, nil
 }
 
 func (s *ServiceImpl) GetTransaction(ctx context.Context, transactionID string) (*models.SingleTransactionDto, error) {
 	// Get the base transaction with preloaded balance
-	tx, err := s.repo.GetTransaction(ctx, transactionID)
+	tx, err := s.getAccessibleTransaction(ctx, transactionID, accessRead)
 	if err != nil {
-		return nil, fmt.Errorf("transaction with ID %s not found: %w", transactionID, err)
+		return nil, err
 	}
 
 	// Use the preloaded balance from the transaction
@@ -151,6 +190,11 @@ func (s *ServiceImpl) GetTransaction(ctx context.Context, transactionID string)
 		dto.OperationID = tx.OperationID.String()
 	}
 
+	// Add the recurring transaction that generated the transaction if available
+	if tx.RecurringTransactionID != nil {
+		dto.RecurringTransactionID = tx.RecurringTransactionID.String()
+	}
+
 	// Convert transaction entries with category details - return error if any category not found
 	var entryDtos []models.SingleTransactionEntryDto
 	for _, entry := range tx.TransactionEntries {
@@ -170,27 +214,38 @@ func (s *ServiceImpl) GetTransaction(ctx context.Context, transactionID string)
 }
 
 func (s *ServiceImpl) ListTransactions(ctx context.Context, filter models.ListTransactionsInput) ([]models.Transaction, error) {
+	var err error
+	if filter.UserID, filter.GroupID, err = s.scopeListFilter(ctx, filter.UserID, filter.GroupID); err != nil {
+		return nil, err
+	}
 	return s.repo.ListTransactions(ctx, filter)
 }
 
 func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID string, updateDto models.UpdateTransactionDto) (*models.Transaction, error) {
 	// First, fetch the existing transaction
-	existingTx, err := s.repo.GetTransaction(ctx, transactionID)
+	existingTx, err := s.getAccessibleTransaction(ctx, transactionID, accessWrite)
 	if err != nil {
-		return nil, fmt.Errorf("transaction with ID %s not found: %w", transactionID, err)
+		return nil, err
 	}
+	if err := s.checkNotTransferLeg(ctx, transactionID); err != nil {
+		return nil, err
+	}
+
+	// Remember fields that affect currency conversion of the entries
+	originalBalanceID := existingTx.BalanceID
+	originalTransactedAt := existingTx.TransactedAt
 
 	// Validate and update BalanceID if provided (cannot be null)
 	if updateDto.BalanceID != "" {
 		balanceUUID, err := uuid.Parse(updateDto.BalanceID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid balance ID format: %w", err)
+			return nil, &models.ValidationError{Field: "balanceId", Reason: "must be a UUID"}
 		}
 
 		// Validate balance exists
-		_, err = s.repo.GetBalance(ctx, updateDto.BalanceID)
+		_, err = s.getAccessibleBalance(ctx, updateDto.BalanceID, accessWrite)
 		if err != nil {
-			return nil, fmt.Errorf("balance with ID %s not found: %w", updateDto.BalanceID, err)
+			return nil, referenceError(err, "balanceId")
 		}
 
 		existingTx.BalanceID = balanceUUID
@@ -203,13 +258,13 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 	} else {
 		merchantUUID, err := uuid.Parse(updateDto.MerchantID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid merchant ID format: %w", err)
+			return nil, &models.ValidationError{Field: "merchantId", Reason: "must be a UUID"}
 		}
 
 		// Validate merchant exists
-		_, err = s.repo.GetMerchant(ctx, updateDto.MerchantID)
+		_, err = s.getAccessibleMerchant(ctx, updateDto.MerchantID, accessRead)
 		if err != nil {
-			return nil, fmt.Errorf("merchant with ID %s not found: %w", updateDto.MerchantID, err)
+			return nil, referenceError(err, "merchantId")
 		}
 
 		existingTx.MerchantID = &merchantUUID
@@ -223,7 +278,7 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 	if updateDto.OperationID != "" {
 		operationUUID, err := uuid.Parse(updateDto.OperationID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid operation ID format: %w", err)
+			return nil, &models.ValidationError{Field: "operationId", Reason: "must be a UUID"}
 		}
 		existingTx.OperationID = &operationUUID
 	}
@@ -232,7 +287,7 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 	if updateDto.ApprovedAt != "" {
 		approvedAt, err := time.Parse(time.RFC3339, updateDto.ApprovedAt)
 		if err != nil {
-			return nil, fmt.Errorf("invalid approved_at format: %w", err)
+			return nil, &models.ValidationError{Field: "approvedAt", Reason: "must be an RFC 3339 timestamp"}
 		}
 		existingTx.ApprovedAt = approvedAt
 	}
@@ -240,7 +295,7 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 	if updateDto.TransactedAt != "" {
 		transactedAt, err := time.Parse(time.RFC3339, updateDto.TransactedAt)
 		if err != nil {
-			return nil, fmt.Errorf("invalid transacted_at format: %w", err)
+			return nil, &models.ValidationError{Field: "transactedAt", Reason: "must be an RFC 3339 timestamp"}
 		}
 		existingTx.TransactedAt = transactedAt
 	}
@@ -265,16 +320,10 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 			baseCurrency = balance.Currency
 		}
 
-		// Fetch supported currencies and exchange rates
-		supportedCurrencies, err := s.exchangeRatesDb.GetSupportedCurrencies(ctx)
+		// Fetch supported currencies and exchange rates effective at the (possibly updated) transaction date
+		supportedCurrencies, exchangeRates, exchangeRateDate, err := s.getExchangeRatesAt(ctx, baseCurrency, existingTx.TransactedAt)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get supported currencies: %w", err)
-		}
-
-		// Fetch exchange rates for all supported currencies
-		exchangeRates, err := s.exchangeRatesDb.GetSupportedCurrenciesRates(ctx, baseCurrency)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get exchange rates for base currency %s: %w", baseCurrency, err)
+			return nil, err
 		}
 
 		// Convert DTO entries to DAO entries with intelligent update logic
@@ -285,13 +334,13 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 			if entryDto.CategoryID != "" {
 				catUUID, err := uuid.Parse(entryDto.CategoryID)
 				if err != nil {
-					return nil, fmt.Errorf("invalid category ID format for entry %d: %w", i, err)
+					return nil, &models.ValidationError{Field: fmt.Sprintf("transactionEntries[%d].categoryId", i), Reason: "must be a UUID"}
 				}
 
 				// Validate category exists
-				_, err = s.repo.GetCategory(ctx, entryDto.CategoryID)
+				_, err = s.getAccessibleCategory(ctx, entryDto.CategoryID, accessRead)
 				if err != nil {
-					return nil, fmt.Errorf("category with ID %s not found for entry %d: %w", entryDto.CategoryID, i, err)
+					return nil, referenceError(err, fmt.Sprintf("transactionEntries[%d].categoryId", i))
 				}
 
 				categoryID = &catUUID
@@ -304,7 +353,7 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 				var err error
 				entryID, err = uuid.Parse(entryDto.ID)
 				if err != nil {
-					return nil, fmt.Errorf("invalid entry ID format for entry %d: %w", i, err)
+					return nil, &models.ValidationError{Field: fmt.Sprintf("transactionEntries[%d].id", i), Reason: "must be a UUID"}
 				}
 			} else {
 				// Create new entry
@@ -330,12 +379,43 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 				baseCurrency,
 				supportedCurrencies,
 				exchangeRates,
+				exchangeRateDate,
 			)
 
 			newEntries = append(newEntries, entry)
 		}
 
 		existingTx.TransactionEntries = newEntries
+	} else if existingTx.BalanceID != originalBalanceID || !existingTx.TransactedAt.Equal(originalTransactedAt) {
+		// Balance or transaction date changed without new entries: re-price the existing entries
+		// with the exchange rates effective at the new date and in the new balance currency
+		balance, err := s.repo.GetBalance(ctx, existingTx.BalanceID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balance for currency determination: %w", err)
+		}
+
+		supportedCurrencies, exchangeRates, exchangeRateDate, err := s.getExchangeRatesAt(ctx, balance.Currency, existingTx.TransactedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		var repricedEntries []models.TransactionEntry
+		for _, entry := range existingTx.TransactionEntries {
+			if entry.DeletedAt != nil {
+				continue
+			}
+			entry.TransactionEntryAmounts = s.createTransactionEntryAmounts(
+				entry.ID,
+				entry.Amount,
+				balance.Currency,
+				supportedCurrencies,
+				exchangeRates,
+				exchangeRateDate,
+			)
+			repricedEntries = append(repricedEntries, entry)
+		}
+
+		existingTx.TransactionEntries = repricedEntries
 	} else {
 		// If no transaction entries provided, clear the entries slice to avoid
 		// the repository thinking they need to be created/updated
@@ -350,5 +430,11 @@ func (s *ServiceImpl) UpdateTransaction(ctx context.Context, transactionID strin
 }
 
 func (s *ServiceImpl) DeleteTransaction(ctx context.Context, transactionID string) error {
+	if _, err := s.getAccessibleTransaction(ctx, transactionID, accessWrite); err != nil {
+		return err
+	}
+	if err := s.checkNotTransferLeg(ctx, transactionID); err != nil {
+		return err
+	}
 	return s.repo.DeleteTransaction(ctx, transactionID)
 }
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/models"
+)
+
+// ErrInvalidTransfer is returned when the balances or amounts of a transfer don't make a valid movement
+var ErrInvalidTransfer error = &models.ValidationError{Reason: "invalid transfer"}
+
+// ErrTransferLeg is returned when a transfer leg is edited or deleted as a standalone transaction
+var ErrTransferLeg error = &models.ConflictError{Reason: "transaction is a leg of a transfer, use the transfers API to change it"}
+
+// CreateTransfer creates the move_out and move_in legs of a transfer and the transfer linking them atomically
+func (s *ServiceImpl) CreateTransfer(ctx context.Context, input models.TransferInput) (*models.Transfer, error) {
+	if err := s.authorizeAccess(ctx, "transfer", input.UserID, input.GroupID, accessWrite); err != nil {
+		return nil, err
+	}
+
+	transfer := models.Transfer{
+		ID:      models.NewOperationID(),
+		GroupID: input.GroupID,
+		UserID:  input.UserID,
+	}
+	moveOut := models.Transaction{ID: models.NewTransactionID()}
+	moveIn := models.Transaction{ID: models.NewTransactionID()}
+	moveOutEntryID := models.NewTransactionEntryID()
+	moveInEntryID := models.NewTransactionEntryID()
+
+	legs, err := s.buildTransferLegs(ctx, transfer, input, moveOut, moveIn, moveOutEntryID, moveInEntryID)
+	if err != nil {
+		return nil, err
+	}
+	transfer.MoveOutTransactionID = legs[0].ID
+	transfer.MoveInTransactionID = legs[1].ID
+
+	return s.repo.CreateTransfer(ctx, transfer, legs)
+}
+
+// GetTransfer retrieves a transfer with both legs
+func (s *ServiceImpl) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	return s.getAccessibleTransfer(ctx, transferID, accessRead)
+}
+
+// UpdateTransfer replaces the balances, amounts, description and date of a transfer, both legs are updated atomically.
+// The group and user of a transfer can't be changed.
+func (s *ServiceImpl) UpdateTransfer(ctx context.Context, transferID string, input models.TransferInput) (*models.Transfer, error) {
+	existing, err := s.getAccessibleTransfer(ctx, transferID, accessWrite)
+	if err != nil {
+		return nil, err
+	}
+	input.GroupID = existing.GroupID
+	input.UserID = existing.UserID
+
+	legs, err := s.buildTransferLegs(ctx, *existing, input,
+		models.Transaction{ID: existing.MoveOutTransactionID},
+		models.Transaction{ID: existing.MoveInTransactionID},
+		transferLegEntryID(existing.MoveOut), transferLegEntryID(existing.MoveIn))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.UpdateTransfer(ctx, *existing, legs)
+}
+
+// DeleteTransfer deletes a transfer together with both legs
+func (s *ServiceImpl) DeleteTransfer(ctx context.Context, transferID string) error {
+	if _, err := s.getAccessibleTransfer(ctx, transferID, accessWrite); err != nil {
+		return err
+	}
+	return s.repo.DeleteTransfer(ctx, transferID)
+}
+
+// getAccessibleTransfer retrieves a transfer and checks the caller has the given access to its group
+func (s *ServiceImpl) getAccessibleTransfer(ctx context.Context, transferID string, level accessLevel) (*models.Transfer, error) {
+	transfer, err := s.repo.GetTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccess(ctx, "transfer", transfer.UserID, transfer.GroupID, level); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// checkNotTransferLeg rejects changes to a transaction that is a leg of a transfer, so both legs stay consistent
+func (s *ServiceImpl) checkNotTransferLeg(ctx context.Context, transactionID string) error {
+	transfer, err := s.repo.FindTransferByTransactionID(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+	if transfer != nil {
+		return fmt.Errorf("%w: transfer %s", ErrTransferLeg, transfer.ID.String())
+	}
+	return nil
+}
+
+// buildTransferLegs validates the balances and amounts of a transfer and builds its move_out and move_in legs with
+// one entry each. Amounts of the entries are converted with the exchange rates effective at the transfer date.
+func (s *ServiceImpl) buildTransferLegs(
+	ctx context.Context,
+	transfer models.Transfer,
+	input models.TransferInput,
+	moveOut, moveIn models.Transaction,
+	moveOutEntryID, moveInEntryID uuid.UUID,
+) ([]models.Transaction, error) {
+	if err := input.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransfer, err)
+	}
+
+	fromBalance, err := s.getAccessibleBalance(ctx, input.FromBalanceID.String(), accessWrite)
+	if err != nil {
+		return nil, referenceError(err, "fromBalanceId")
+	}
+	toBalance, err := s.getAccessibleBalance(ctx, input.ToBalanceID.String(), accessWrite)
+	if err != nil {
+		return nil, referenceError(err, "toBalanceId")
+	}
+	if fromBalance.GroupID != transfer.GroupID || toBalance.GroupID != transfer.GroupID {
+		return nil, fmt.Errorf("%w: both balances must belong to group %s", ErrInvalidTransfer, transfer.GroupID.String())
+	}
+
+	// Between balances of the same currency the received amount is the sent amount, otherwise it must be given
+	receivedAmount := input.Amount
+	if fromBalance.Currency == toBalance.Currency {
+		if input.ReceivedAmount != nil && *input.ReceivedAmount != input.Amount {
+			return nil, fmt.Errorf("%w: receivedAmount must equal amount between balances of the same currency", ErrInvalidTransfer)
+		}
+	} else {
+		if input.ReceivedAmount == nil {
+			return nil, fmt.Errorf("%w: receivedAmount is required between %s and %s balances", ErrInvalidTransfer, fromBalance.Currency, toBalance.Currency)
+		}
+		receivedAmount = *input.ReceivedAmount
+	}
+
+	var description *string
+	if input.Description != "" {
+		description = &input.Description
+	}
+
+	legs := []models.Transaction{moveOut, moveIn}
+	sides := []struct {
+		Type    string
+		Balance *models.Balance
+		Amount  int64
+		EntryID uuid.UUID
+	}{
+		{"move_out", fromBalance, input.Amount, moveOutEntryID},
+		{"move_in", toBalance, receivedAmount, moveInEntryID},
+	}
+	for i, side := range sides {
+		supportedCurrencies, exchangeRates, exchangeRateDate, err := s.getExchangeRatesAt(ctx, side.Balance.Currency, input.TransactedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		leg := &legs[i]
+		leg.GroupID = transfer.GroupID
+		leg.UserID = transfer.UserID
+		leg.BalanceID = side.Balance.ID
+		leg.Type = side.Type
+		leg.OperationID = &transfer.ID
+		leg.ApprovedAt = input.TransactedAt
+		leg.TransactedAt = input.TransactedAt
+		leg.TransactionEntries = []models.TransactionEntry{{
+			ID:            side.EntryID,
+			TransactionID: leg.ID,
+			Description:   description,
+			Amount:        side.Amount,
+			TransactionEntryAmounts: s.createTransactionEntryAmounts(
+				side.EntryID,
+				side.Amount,
+				side.Balance.Currency,
+				supportedCurrencies,
+				exchangeRates,
+				exchangeRateDate),
+		}}
+	}
+
+	if err := validateMovementOperations(legs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransfer, err)
+	}
+	return legs, nil
+}
+
+// transferLegEntryID returns the ID of the entry of a transfer leg, a new ID if the leg has no active entry
+func transferLegEntryID(leg *models.Transaction) uuid.UUID {
+	if leg != nil {
+		for _, entry := range leg.TransactionEntries {
+			if entry.DeletedAt == nil {
+				return entry.ID
+			}
+		}
+	}
+	return models.NewTransactionEntryID()
+}
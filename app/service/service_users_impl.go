@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/savak1990/transactions-service/app/auth"
+	"github.com/savak1990/transactions-service/app/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// ExportUserData retrieves all data owned by the user, including soft-deleted rows, for the user or an admin
+func (s *ServiceImpl) ExportUserData(ctx context.Context, userID string) (*models.UserData, error) {
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserData(ctx, userID)
+}
+
+// EraseUserData permanently deletes all data of the user across all tables in a single database transaction.
+// Balances other group members recorded transactions on are kept with an anonymised owner, as are the audit events of
+// group entities. Groups created by the user are only removed when they have no other members. The erasure is logged with the caller and the rows removed per table.
+func (s *ServiceImpl) EraseUserData(ctx context.Context, userID string) (*models.UserErasureReportDto, error) {
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	erased, err := s.repo.EraseUserData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.UserErasureReportDto{
+		UserID:   userID,
+		ErasedAt: time.Now().UTC().Format(time.RFC3339),
+		Tables:   erased,
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.UserID != uuid.Nil {
+		report.ErasedBy = principal.UserID.String()
+	}
+
+	fields := log.Fields{"user_id": userID, "erased_by": report.ErasedBy}
+	for _, table := range erased {
+		report.TotalRows += table.Rows
+		fields[table.Table] = table.Rows
+	}
+	log.WithFields(fields).Warn("Erased all data of user")
+	return report, nil
+}
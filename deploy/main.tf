@@ -88,6 +88,7 @@ locals {
   domain_name              = jsondecode(data.aws_secretsmanager_secret_version.ahorro_app.secret_string)["domain_name"]
   transactions_db_username = jsondecode(data.aws_secretsmanager_secret_version.ahorro_app.secret_string)["transactions_db_username"]
   transactions_db_password = jsondecode(data.aws_secretsmanager_secret_version.ahorro_app.secret_string)["transactions_db_password"]
+  cursor_signing_key       = jsondecode(data.aws_secretsmanager_secret_version.ahorro_app.secret_string)["transactions_cursor_signing_key"]
 }
 
 module "ahorro_transactions_service" {
@@ -107,7 +108,8 @@ module "ahorro_transactions_service" {
   exchange_rate_db_name = data.terraform_remote_state.exchange_rate_db.outputs.dynamodb_table_name
 
   # Application Configuration
-  log_level = "debug"
+  log_level          = "debug"
+  cursor_signing_key = local.cursor_signing_key
 
   # API Gateway Configuration
   api_name                    = local.full_api_name
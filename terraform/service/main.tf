@@ -45,11 +45,58 @@ resource "aws_lambda_function" "app" {
       SSL_MODE = "require"
 
       # Application configuration
-      LOG_LEVEL = var.log_level
+      LOG_LEVEL          = var.log_level
+      CURSOR_SIGNING_KEY = var.cursor_signing_key
+      RETENTION_DAYS     = tostring(var.retention_days)
+
+      # Authentication (JWT validated by the API Gateway Cognito authorizer)
+      AUTH_MODE        = "apigateway"
+      AUTH_ADMIN_GROUP = var.auth_admin_group
     }
   }
 }
 
+# Scheduled run creating the transactions of due recurring transaction occurrences
+resource "aws_cloudwatch_event_rule" "recurring_transactions" {
+  name                = "${local.lambda_name}-recurring-transactions"
+  description         = "Materializes due recurring transaction occurrences"
+  schedule_expression = var.recurring_transactions_schedule
+}
+
+resource "aws_cloudwatch_event_target" "recurring_transactions" {
+  rule = aws_cloudwatch_event_rule.recurring_transactions.name
+  arn  = aws_lambda_function.app.arn
+}
+
+resource "aws_lambda_permission" "recurring_transactions" {
+  statement_id  = "AllowRecurringTransactionsSchedule"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.app.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.recurring_transactions.arn
+}
+
+# Scheduled purge of data soft-deleted longer ago than the retention period, the rule name suffix routes the event
+# to the purge job
+resource "aws_cloudwatch_event_rule" "purge_deleted_data" {
+  name                = "${local.lambda_name}-purge-deleted-data"
+  description         = "Permanently deletes soft-deleted data past the retention period"
+  schedule_expression = var.purge_deleted_data_schedule
+}
+
+resource "aws_cloudwatch_event_target" "purge_deleted_data" {
+  rule = aws_cloudwatch_event_rule.purge_deleted_data.name
+  arn  = aws_lambda_function.app.arn
+}
+
+resource "aws_lambda_permission" "purge_deleted_data" {
+  statement_id  = "AllowPurgeDeletedDataSchedule"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.app.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.purge_deleted_data.arn
+}
+
 resource "aws_security_group" "lambda_sg" {
   name        = "${local.lambda_name}-sg"
   description = "Security group for Lambda function"
@@ -175,6 +222,7 @@ resource "aws_iam_role_policy" "lambda_dynamodb_access" {
         Effect = "Allow",
         Action = [
           "dynamodb:GetItem",
+          "dynamodb:BatchGetItem",
           "dynamodb:PutItem",
           "dynamodb:Query",
           "dynamodb:Scan",
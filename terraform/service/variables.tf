@@ -85,6 +85,12 @@ variable "exchange_rate_db_name" {
   type        = string
 }
 
+variable "cursor_signing_key" {
+  description = "Secret used to sign pagination cursors returned to clients, the service fails to start without it."
+  type        = string
+  sensitive   = true
+}
+
 variable "log_level" {
   description = "Log level for the application (debug, info, warn, error)"
   type        = string
@@ -94,3 +100,27 @@ variable "log_level" {
     error_message = "Log level must be one of: debug, info, warn, error"
   }
 }
+
+variable "auth_admin_group" {
+  description = "Cognito user pool group whose members may run admin jobs and access data of all users."
+  type        = string
+  default     = "admin"
+}
+
+variable "recurring_transactions_schedule" {
+  description = "EventBridge schedule expression of the run materializing due recurring transactions."
+  type        = string
+  default     = "rate(1 hour)"
+}
+
+variable "retention_days" {
+  description = "Days soft-deleted data is kept before the scheduled purge permanently deletes it."
+  type        = number
+  default     = 90
+}
+
+variable "purge_deleted_data_schedule" {
+  description = "EventBridge schedule expression of the purge of soft-deleted data past the retention period."
+  type        = string
+  default     = "rate(1 day)"
+}